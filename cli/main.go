@@ -7,7 +7,6 @@ package main
 
 import (
 	"context"
-	"debug/buildinfo"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -21,6 +20,7 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 
 	"go.opentelemetry.io/auto"
+	"go.opentelemetry.io/auto/internal/pkg/process"
 	"go.opentelemetry.io/auto/pipeline"
 	"go.opentelemetry.io/auto/pipeline/otelsdk"
 )
@@ -34,12 +34,21 @@ const help = `Usage of %s:
     	Executable path run by the target process
   -log-level string
     	Logging level ("debug", "info", "warn", "error")
+  -record string
+    	Path of the file to record all raw eBPF events to
+  -replay string
+    	Path of a recording to replay instead of instrumenting a target process
 
 Runs the OpenTelemetry auto-instrumentation for Go applications using eBPF.
 
 If both -target-pid and -target-exe are provided -target-exe will be ignored
 and -target-pid used.
 
+If -replay is provided, the raw eBPF events of a recording made with -record
+are processed and exported instead of instrumenting a target process. This does
+not require elevated privileges or eBPF support. Recordings may contain
+sensitive data read from the target process.
+
 Environment variable configuration:
 
 	- OTEL_GO_AUTO_TARGET_PID: PID of the target process
@@ -103,10 +112,14 @@ func main() {
 	var logLevel string
 	var targetPID int
 	var targetExe string
+	var recordPath string
+	var replayPath string
 
 	flag.StringVar(&logLevel, "log-level", "", `Logging level ("debug", "info", "warn", "error")`)
 	flag.IntVar(&targetPID, "target-pid", -1, `PID of target process`)
 	flag.StringVar(&targetExe, "target-exe", "", `Executable path run by the target process`)
+	flag.StringVar(&recordPath, "record", "", `Path of the file to record all raw eBPF events to`)
+	flag.StringVar(&replayPath, "replay", "", `Path of a recording to replay instead of instrumenting a target process`)
 
 	flag.Usage = usage
 	flag.Parse()
@@ -129,6 +142,11 @@ func main() {
 		}
 	}()
 
+	if replayPath != "" {
+		replay(ctx, logger, replayPath)
+		return
+	}
+
 	pid, err := findPID(ctx, logger, targetPID, targetExe)
 	if err != nil {
 		logger.Error("failed to find target", "error", err)
@@ -142,9 +160,11 @@ func main() {
 
 	h, err := otelsdk.NewTraceHandler(
 		ctx,
+		// The target attributes are provided first so user-provided ones
+		// have priority.
+		otelsdk.WithResourceAttributes(resourceAttrs(ctx, logger, pid)...),
 		otelsdk.WithEnv(),
 		otelsdk.WithLogger(logger),
-		otelsdk.WithResourceAttributes(resourceAttrs(logger, pid)...),
 	)
 	if err != nil {
 		logger.Error("failed to create OTel SDK handler", "error", err)
@@ -158,6 +178,18 @@ func main() {
 	}
 	instOptions = append(instOptions, auto.WithPID(pid))
 
+	if recordPath != "" {
+		f, err := os.Create(recordPath)
+		if err != nil {
+			logger.Error("failed to create recording", "error", err, "path", recordPath)
+			return
+		}
+		defer f.Close()
+
+		logger.Info("recording raw eBPF events", "path", recordPath)
+		instOptions = append(instOptions, auto.WithRecording(f))
+	}
+
 	logger.Info(
 		"building OpenTelemetry Go instrumentation ...",
 		"PID", pid,
@@ -193,6 +225,43 @@ func main() {
 	}
 }
 
+func replay(ctx context.Context, logger *slog.Logger, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		logger.Error("failed to open recording", "error", err, "path", path)
+		return
+	}
+	defer f.Close()
+
+	h, err := otelsdk.NewTraceHandler(
+		ctx,
+		otelsdk.WithEnv(),
+		otelsdk.WithLogger(logger),
+		otelsdk.WithResourceAttributes(semconv.TelemetryDistroVersionKey.String(auto.Version())),
+	)
+	if err != nil {
+		logger.Error("failed to create OTel SDK handler", "error", err)
+		return
+	}
+
+	logger.Info("replaying recording", "path", path, "version", newVersion())
+
+	handler := &pipeline.Handler{TraceHandler: h}
+	if err = auto.Replay(ctx, f, handler, auto.WithReplayLogger(logger)); err != nil {
+		logger.Error("failed to replay recording", "error", err)
+	}
+
+	logger.Info("shutting down")
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	err = h.Shutdown(ctx)
+	if err != nil {
+		logger.Error("failed to flush handler", "error", err)
+	}
+}
+
 var errNoPID = fmt.Errorf(
 	"no target: -target-pid or -target-exe not provided and the env vars %s and %s are unset",
 	envTargetPIDKey, envTargetExeKey,
@@ -246,36 +315,15 @@ func findExe(ctx context.Context, l *slog.Logger, exe string) (int, error) {
 	return pp.Poll(ctx)
 }
 
-func resourceAttrs(logger *slog.Logger, pid int) []attribute.KeyValue {
+func resourceAttrs(ctx context.Context, logger *slog.Logger, pid int) []attribute.KeyValue {
 	attrs := []attribute.KeyValue{
 		semconv.TelemetryDistroVersionKey.String(auto.Version()),
 	}
 
-	// Add additional process information for the target.
-	path := "/proc/" + strconv.Itoa(pid) + "/exe"
-	bi, err := buildinfo.ReadFile(path)
+	// Add additional process, container, and host information for the target.
+	procAttrs, err := process.Detector{ID: process.ID(pid)}.Attributes(ctx)
 	if err != nil {
-		logger.Error("failed to get Go proc build info", "error", err)
-		return attrs
-	}
-
-	attrs = append(attrs, semconv.ProcessRuntimeVersion(bi.GoVersion))
-
-	var compiler string
-	for _, setting := range bi.Settings {
-		if setting.Key == "-compiler" {
-			compiler = setting.Value
-			break
-		}
+		logger.Debug("failed to detect all target resource attributes", "error", err)
 	}
-	switch compiler {
-	case "":
-		logger.Debug("failed to identify Go compiler")
-	case "gc":
-		attrs = append(attrs, semconv.ProcessRuntimeName("go"))
-	default:
-		attrs = append(attrs, semconv.ProcessRuntimeName(compiler))
-	}
-
-	return attrs
+	return append(attrs, procAttrs...)
 }
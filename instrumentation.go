@@ -19,6 +19,8 @@ import (
 
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation"
 	dbSql "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/database/sql"
+	pgx "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/jackc/pgx"
+	goRedis "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/redis/go-redis"
 	kafkaConsumer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/segmentio/kafka-go/consumer"
 	kafkaProducer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/segmentio/kafka-go/producer"
 	autosdk "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/go.opentelemetry.io/auto/sdk"
@@ -28,10 +30,12 @@ import (
 	grpcServer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/google.golang.org/grpc/server"
 	httpClient "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/net/http/client"
 	httpServer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/net/http/server"
+	goRuntime "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/runtime"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
 	"go.opentelemetry.io/auto/internal/pkg/process"
 	"go.opentelemetry.io/auto/pipeline"
 	"go.opentelemetry.io/auto/pipeline/otelsdk"
+	"go.opentelemetry.io/auto/pipeline/tailsampling"
 )
 
 // envLogLevelKey is the key for the environment variable value containing the log level.
@@ -65,17 +69,21 @@ func NewInstrumentation(
 		return nil, err
 	}
 
-	p := []probe.Probe{
-		grpcClient.New(c.logger, Version()),
-		grpcServer.New(c.logger, Version()),
-		httpServer.New(c.logger, Version()),
-		httpClient.New(c.logger, Version()),
-		dbSql.New(c.logger, Version()),
-		kafkaProducer.New(c.logger, Version()),
-		kafkaConsumer.New(c.logger, Version()),
-		autosdk.New(c.logger),
-		otelTrace.New(c.logger),
-		otelTraceGlobal.New(c.logger),
+	if c.recorder != nil {
+		c.recorder.SetConfig(c.propagators, c.baggageAttributes)
+	}
+
+	p := newProbes(c.logger)
+	for _, pr := range p {
+		if r, ok := pr.(probe.Recordable); ok && c.recorder != nil {
+			r.SetRecorder(c.recorder)
+		}
+		if pp, ok := pr.(probe.Propagating); ok {
+			if c.propagators != nil {
+				pp.SetPropagators(c.propagators)
+			}
+			pp.SetBaggageAttributes(c.baggageAttributes)
+		}
 	}
 
 	cp := convertConfigProvider(c.cp)
@@ -87,6 +95,26 @@ func NewInstrumentation(
 	return &Instrumentation{manager: mngr, cleanup: c.handlerClose}, nil
 }
 
+// newProbes returns all the probes supported by auto-instrumentation.
+func newProbes(logger *slog.Logger) []probe.Probe {
+	return []probe.Probe{
+		grpcClient.New(logger, Version()),
+		grpcServer.New(logger, Version()),
+		httpServer.New(logger, Version()),
+		httpClient.New(logger, Version()),
+		dbSql.New(logger, Version()),
+		pgx.New(logger, Version()),
+		goRedis.New(logger, Version()),
+		goRedis.NewV8(logger, Version()),
+		kafkaProducer.New(logger, Version()),
+		kafkaConsumer.New(logger, Version()),
+		autosdk.New(logger),
+		otelTrace.New(logger),
+		otelTraceGlobal.New(logger),
+		goRuntime.New(logger),
+	}
+}
+
 // Load loads and attaches the relevant probes to the target process.
 func (i *Instrumentation) Load(ctx context.Context) error {
 	return i.manager.Load(ctx)
@@ -155,12 +183,17 @@ type InstrumentationOption interface {
 }
 
 type instConfig struct {
-	pid          process.ID
-	handler      *pipeline.Handler
-	handlerClose func()
-	logger       *slog.Logger
-	sampler      Sampler
-	cp           ConfigProvider
+	pid               process.ID
+	handler           *pipeline.Handler
+	handlerClose      func()
+	logger            *slog.Logger
+	sampler           Sampler
+	cp                ConfigProvider
+	jaegerRemote      *jaegerRemoteEnv
+	recorder          *probe.Recorder
+	tailSampling      []tailsampling.Option
+	propagators       []probe.Propagator
+	baggageAttributes []string
 }
 
 func newInstConfig(ctx context.Context, opts []InstrumentationOption) (instConfig, error) {
@@ -180,33 +213,20 @@ func newInstConfig(ctx context.Context, opts []InstrumentationOption) (instConfi
 			semconv.TelemetryDistroVersionKey.String(Version()),
 		}
 
-		// Add additional process information for the target.
-		var e error
-		bi, e := c.pid.BuildInfo()
-		if e == nil {
-			attrs = append(attrs, semconv.ProcessRuntimeVersion(bi.GoVersion))
-
-			var compiler string
-			for _, setting := range bi.Settings {
-				if setting.Key == "-compiler" {
-					compiler = setting.Value
-					break
-				}
-			}
-			switch compiler {
-			case "":
-				// Ignore empty.
-			case "gc":
-				attrs = append(attrs, semconv.ProcessRuntimeName("go"))
-			default:
-				attrs = append(attrs, semconv.ProcessRuntimeName(compiler))
-			}
+		// Add additional process information for the target. Detection
+		// errors are expected for attributes not available to the
+		// instrumentation, these attributes are skipped.
+		if c.pid >= 0 {
+			procAttrs, _ := process.Detector{ID: c.pid}.Attributes(ctx)
+			attrs = append(attrs, procAttrs...)
 		}
 
+		// The process attributes are provided first so user-provided ones
+		// have priority.
 		th, e := otelsdk.NewTraceHandler(
 			ctx,
-			otelsdk.WithEnv(),
 			otelsdk.WithResourceAttributes(attrs...),
+			otelsdk.WithEnv(),
 		)
 		err = errors.Join(err, e)
 
@@ -223,6 +243,36 @@ func newInstConfig(ctx context.Context, opts []InstrumentationOption) (instConfi
 			})
 		}
 	}
+	if c.tailSampling != nil && c.sampler != nil {
+		err = errors.Join(err, errTailSamplingSampler)
+	} else if c.tailSampling != nil && c.handler != nil && c.handler.TraceHandler == nil {
+		err = errors.Join(err, errTailSamplingTraceHandler)
+	} else if c.tailSampling != nil && c.handler != nil {
+		ts, e := tailsampling.NewTraceHandler(c.handler.TraceHandler, c.tailSampling...)
+		err = errors.Join(err, e)
+
+		if ts != nil {
+			h := *c.handler
+			h.TraceHandler = ts
+			c.handler = &h
+
+			// All traces need to be recorded for the tail sampling decision.
+			c.sampler = AlwaysOnSampler{}
+
+			handlerClose := c.handlerClose
+			c.handlerClose = sync.OnceFunc(func() {
+				ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+				defer stop()
+
+				if err := ts.Shutdown(ctx); err != nil {
+					c.logger.Error("failed to flush tail sampling", "error", err)
+				}
+				if handlerClose != nil {
+					handlerClose()
+				}
+			})
+		}
+	}
 	if c.sampler == nil {
 		c.sampler = DefaultSampler()
 	}
@@ -231,6 +281,14 @@ func newInstConfig(ctx context.Context, opts []InstrumentationOption) (instConfi
 		c.logger = newLogger(nil)
 	}
 
+	if c.cp == nil && c.jaegerRemote != nil {
+		cp, e := c.jaegerRemote.provider(c.pid, c.logger)
+		err = errors.Join(err, e)
+		if cp != nil {
+			c.cp = cp
+		}
+	}
+
 	if c.cp == nil {
 		c.cp = newNoopConfigProvider(c.sampler)
 	}
@@ -238,6 +296,14 @@ func newInstConfig(ctx context.Context, opts []InstrumentationOption) (instConfi
 	return c, err
 }
 
+// errTailSamplingSampler is returned if both a tail sampling stage and a
+// sampler are configured.
+var errTailSamplingSampler = errors.New("a sampler cannot be used with tail sampling")
+
+// errTailSamplingTraceHandler is returned if a tail sampling stage is
+// configured with a handler that does not handle traces.
+var errTailSamplingTraceHandler = errors.New("tail sampling requires a trace handler")
+
 func (c instConfig) validate() error {
 	return c.pid.Validate()
 }
@@ -276,10 +342,21 @@ var lookupEnv = os.LookupEnv
 //   - OTEL_LOG_LEVEL: sets the default logger's minimum logging level
 //   - OTEL_TRACES_SAMPLER: sets the trace sampler
 //   - OTEL_TRACES_SAMPLER_ARG: optionally sets the trace sampler argument
+//   - OTEL_PROPAGATORS: sets the comma-separated list of [Propagator]
+//   - OTEL_GO_AUTO_BAGGAGE_ATTRIBUTES: sets the comma-separated list of
+//     baggage keys set as span attributes
 //
-// This option may conflict with [WithSampler] if their respective environment
-// variable is defined. If more than one of these options are used, the last
-// one provided to an [Instrumentation] will be used.
+// If OTEL_TRACES_SAMPLER is "jaeger_remote", a [JaegerRemoteConfigProvider]
+// is used, unless [WithConfigProvider] is used. It requests the sampling
+// strategy of the service named by OTEL_SERVICE_NAME (or
+// OTEL_RESOURCE_ATTRIBUTES). OTEL_TRACES_SAMPLER_ARG is then a comma-separated
+// list of "endpoint", "pollingIntervalMs", and "initialSamplingRate" key-value
+// pairs (e.g. "endpoint=http://localhost:5778/sampling,pollingIntervalMs=5000").
+//
+// This option may conflict with [WithSampler], [WithPropagators], and
+// [WithBaggageAttributes] if their respective environment variable is
+// defined. If more than one of these options are used, the last one provided
+// to an [Instrumentation] will be used.
 //
 // If [WithLogger] is used, OTEL_LOG_LEVEL will not be used for the
 // [Instrumentation] logger. Instead, the [slog.Logger] passed to that option
@@ -300,10 +377,24 @@ func WithEnv() InstrumentationOption {
 				c.logger = newLogger(level)
 			}
 		}
-		if s, e := newSamplerFromEnv(lookupEnv); e != nil {
+		if jr, ok, e := jaegerRemoteFromEnv(lookupEnv); ok {
+			err = errors.Join(err, e)
+			c.jaegerRemote = jr
+		} else if s, e := newSamplerFromEnv(lookupEnv); e != nil {
 			err = errors.Join(err, e)
 		} else {
 			c.sampler = s
+			if s != nil {
+				c.jaegerRemote = nil
+			}
+		}
+		if p, ok, e := propagatorsFromEnv(lookupEnv); e != nil {
+			err = errors.Join(err, e)
+		} else if ok {
+			c.propagators = p
+		}
+		if keys, ok := baggageAttributesFromEnv(lookupEnv); ok {
+			c.baggageAttributes = keys
 		}
 		return c, err
 	})
@@ -316,6 +407,27 @@ func WithEnv() InstrumentationOption {
 func WithSampler(sampler Sampler) InstrumentationOption {
 	return fnOpt(func(_ context.Context, c instConfig) (instConfig, error) {
 		c.sampler = sampler
+		c.jaegerRemote = nil
+		return c, nil
+	})
+}
+
+// WithTailSampling returns an [InstrumentationOption] that will configure an
+// [Instrumentation] to sample traces after all their spans have been
+// generated, using a [tailsampling.TraceHandler] configured with options.
+//
+// The tail sampling stage is added in front of the trace handler of the
+// configured [pipeline.Handler]. Buffered traces are flushed when the
+// [Instrumentation] stops.
+//
+// When this option is used, all traces are recorded by the instrumentation
+// with [AlwaysOnSampler]. An error is returned if a [Sampler] is also
+// configured, using [WithSampler] or OTEL_TRACES_SAMPLER with [WithEnv].
+// Samplers provided by a [ConfigProvider] are not checked. An error is also
+// returned if the configured [pipeline.Handler] has no TraceHandler.
+func WithTailSampling(options ...tailsampling.Option) InstrumentationOption {
+	return fnOpt(func(_ context.Context, c instConfig) (instConfig, error) {
+		c.tailSampling = append([]tailsampling.Option{}, options...)
 		return c, nil
 	})
 }
@@ -6,13 +6,19 @@ package auto
 import (
 	"context"
 	"log/slog"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe/sampling"
 	"go.opentelemetry.io/auto/internal/pkg/process"
+	"go.opentelemetry.io/auto/pipeline"
+	"go.opentelemetry.io/auto/pipeline/tailsampling"
 )
 
 func TestWithPID(t *testing.T) {
@@ -85,6 +91,40 @@ func TestWithLogger(t *testing.T) {
 	assert.Same(t, l, c.logger)
 }
 
+func TestWithTailSampling(t *testing.T) {
+	next := &pipeline.Handler{TraceHandler: noopTraceHandler{}}
+	opts := []InstrumentationOption{
+		WithHandler(next),
+		WithTailSampling(tailsampling.WithProbability(0.1)),
+	}
+	c, err := newInstConfig(context.Background(), opts)
+	require.NoError(t, err)
+	t.Cleanup(c.handlerClose)
+
+	assert.IsType(t, &tailsampling.TraceHandler{}, c.handler.TraceHandler)
+	assert.Equal(t, AlwaysOnSampler{}, c.sampler)
+	assert.Equal(t, noopTraceHandler{}, next.TraceHandler, "original handler modified")
+
+	t.Run("Sampler", func(t *testing.T) {
+		opts := append(opts, WithSampler(AlwaysOffSampler{}))
+		_, err := newInstConfig(context.Background(), opts)
+		assert.ErrorIs(t, err, errTailSamplingSampler)
+	})
+
+	t.Run("NoTraceHandler", func(t *testing.T) {
+		opts := []InstrumentationOption{
+			WithHandler(&pipeline.Handler{}),
+			WithTailSampling(tailsampling.WithProbability(0.1)),
+		}
+		_, err := newInstConfig(context.Background(), opts)
+		assert.ErrorIs(t, err, errTailSamplingTraceHandler)
+	})
+}
+
+type noopTraceHandler struct{}
+
+func (noopTraceHandler) HandleTrace(pcommon.InstrumentationScope, string, ptrace.SpanSlice) {}
+
 func TestWithSampler(t *testing.T) {
 	t.Run("Default sampler", func(t *testing.T) {
 		c, err := newInstConfig(context.Background(), []InstrumentationOption{})
@@ -169,6 +209,262 @@ func TestWithSampler(t *testing.T) {
 	})
 }
 
+func TestRateLimitingSampler(t *testing.T) {
+	mockEnv(t, map[string]string{
+		tracesSamplerKey:    samplerNameParentBasedRateLimiting,
+		tracesSamplerArgKey: "25",
+	})
+	c, err := newInstConfig(context.Background(), []InstrumentationOption{WithEnv()})
+	require.NoError(t, err)
+	assert.Equal(t, ParentBasedSampler{
+		Root:             RateLimitingSampler{SpansPerSecond: 25},
+		RemoteSampled:    AlwaysOnSampler{},
+		RemoteNotSampled: AlwaysOffSampler{},
+		LocalSampled:     AlwaysOnSampler{},
+		LocalNotSampled:  AlwaysOffSampler{},
+	}, c.sampler)
+
+	sc, err := convertSamplerToConfig(c.sampler)
+	require.NoError(t, err)
+	pb, ok := sc.Samplers[sampling.ParentBasedID].Config.(sampling.ParentBasedConfig)
+	require.True(t, ok)
+	assert.Equal(t, sampling.RateLimitingID, pb.Root)
+
+	want, err := sampling.NewRateLimitingConfig(25)
+	require.NoError(t, err)
+	assert.Equal(t, sampling.SamplerConfig{
+		SamplerType: sampling.SamplerRateLimiting,
+		Config:      want,
+	}, sc.Samplers[sampling.RateLimitingID])
+
+	t.Run("DefaultArg", func(t *testing.T) {
+		mockEnv(t, map[string]string{tracesSamplerKey: samplerNameRateLimiting})
+		c, err := newInstConfig(context.Background(), []InstrumentationOption{WithEnv()})
+		require.NoError(t, err)
+		assert.Equal(t, RateLimitingSampler{SpansPerSecond: defaultSpansPerSecond}, c.sampler)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := convertSamplerToConfig(RateLimitingSampler{SpansPerSecond: -1})
+		assert.Error(t, err)
+	})
+}
+
+func TestProbabilitySampler(t *testing.T) {
+	mockEnv(t, map[string]string{
+		tracesSamplerKey:    samplerNameParentBasedProbability,
+		tracesSamplerArgKey: "0.25",
+	})
+	c, err := newInstConfig(context.Background(), []InstrumentationOption{WithEnv()})
+	require.NoError(t, err)
+	assert.Equal(t, ParentBasedSampler{
+		Root:             ProbabilitySampler{Probability: 0.25},
+		RemoteSampled:    AlwaysOnSampler{},
+		RemoteNotSampled: AlwaysOffSampler{},
+		LocalSampled:     AlwaysOnSampler{},
+		LocalNotSampled:  AlwaysOffSampler{},
+	}, c.sampler)
+
+	sc, err := convertSamplerToConfig(c.sampler)
+	require.NoError(t, err)
+	pb, ok := sc.Samplers[sampling.ParentBasedID].Config.(sampling.ParentBasedConfig)
+	require.True(t, ok)
+	assert.Equal(t, sampling.ProbabilityID, pb.Root)
+	assert.Equal(t, sampling.SamplerConfig{
+		SamplerType: sampling.SamplerProbability,
+		Config:      sampling.ProbabilityConfig{Threshold: 0xc0000000000000},
+	}, sc.Samplers[sampling.ProbabilityID])
+
+	t.Run("DefaultArg", func(t *testing.T) {
+		mockEnv(t, map[string]string{tracesSamplerKey: samplerNameProbability})
+		c, err := newInstConfig(context.Background(), []InstrumentationOption{WithEnv()})
+		require.NoError(t, err)
+		assert.Equal(t, ProbabilitySampler{Probability: 1}, c.sampler)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := convertSamplerToConfig(ProbabilitySampler{Probability: 1.5})
+		assert.Error(t, err)
+	})
+}
+
+func TestRuleBasedSampler(t *testing.T) {
+	s := ParentBasedSampler{
+		Root: RuleBasedSampler{
+			Rules: []SamplingRule{
+				{HTTPMethod: "GET", HTTPPath: "/healthz", Sampler: AlwaysOffSampler{}},
+				{HTTPPathPrefix: "/checkout", Sampler: AlwaysOnSampler{}},
+				{DBOperation: "SELECT", Sampler: TraceIDRatioSampler{Fraction: 0.5}},
+			},
+			Fallback: TraceIDRatioSampler{Fraction: 0.05},
+		},
+	}
+	sc, err := convertSamplerToConfig(s)
+	require.NoError(t, err)
+
+	pb, ok := sc.Samplers[sampling.ParentBasedID].Config.(sampling.ParentBasedConfig)
+	require.True(t, ok)
+	assert.Equal(t, sampling.RuleBasedID, pb.Root)
+
+	rb := sc.Samplers[sampling.RuleBasedID]
+	assert.Equal(t, sampling.SamplerRuleBased, rb.SamplerType)
+	rbConfig, ok := rb.Config.(sampling.RuleBasedConfig)
+	require.True(t, ok)
+
+	want, err := sampling.NewRuleBasedConfig([]sampling.Rule{
+		{
+			Method:   "GET",
+			Kind:     sampling.AttributeHTTPPath,
+			Match:    sampling.MatchExact,
+			Value:    "/healthz",
+			Delegate: sampling.RuleBasedDelegateID(0),
+		},
+		{
+			Kind:     sampling.AttributeHTTPPath,
+			Match:    sampling.MatchPrefix,
+			Value:    "/checkout",
+			Delegate: sampling.RuleBasedDelegateID(1),
+		},
+		{
+			Kind:     sampling.AttributeDBOperation,
+			Match:    sampling.MatchWord,
+			Value:    "select",
+			Delegate: sampling.RuleBasedDelegateID(2),
+		},
+	}, sampling.RuleBasedDelegateID(3))
+	require.NoError(t, err)
+	assert.Equal(t, want, rbConfig)
+
+	assert.Equal(t, sampling.SamplerAlwaysOff, sc.Samplers[sampling.RuleBasedDelegateID(0)].SamplerType)
+	assert.Equal(t, sampling.SamplerAlwaysOn, sc.Samplers[sampling.RuleBasedDelegateID(1)].SamplerType)
+	ratio, _ := sampling.NewTraceIDRatioConfig(0.05)
+	assert.Equal(t, sampling.SamplerConfig{
+		SamplerType: sampling.SamplerTraceIDRatio,
+		Config:      ratio,
+	}, sc.Samplers[sampling.RuleBasedDelegateID(3)])
+
+	t.Run("Invalid", func(t *testing.T) {
+		tests := map[string]RuleBasedSampler{
+			"NestedSampler": {
+				Rules: []SamplingRule{{HTTPPath: "/", Sampler: DefaultSampler()}},
+			},
+			"NilSampler": {
+				Rules: []SamplingRule{{HTTPPath: "/"}},
+			},
+			"MultipleAttributes": {
+				Rules: []SamplingRule{{
+					HTTPPath:  "/",
+					RPCMethod: "/pkg.Service/Method",
+					Sampler:   AlwaysOnSampler{},
+				}},
+			},
+			"ValueTooLong": {
+				Rules: []SamplingRule{{
+					HTTPPath: "/" + strings.Repeat("a", sampling.MaxRuleValueSize),
+					Sampler:  AlwaysOnSampler{},
+				}},
+			},
+			"TooManyRules": {
+				Rules: make([]SamplingRule, sampling.MaxRules+1),
+			},
+			"InvalidFallback": {
+				Fallback: TraceIDRatioSampler{Fraction: 2},
+			},
+		}
+		for name, s := range tests {
+			t.Run(name, func(t *testing.T) {
+				_, err := convertSamplerToConfig(s)
+				assert.Error(t, err)
+			})
+		}
+	})
+}
+
+func TestWithPropagators(t *testing.T) {
+	ctx := context.Background()
+
+	c, err := newInstConfig(ctx, nil)
+	require.NoError(t, err)
+	assert.Nil(t, c.propagators, "default propagators")
+
+	c, err = newInstConfig(ctx, []InstrumentationOption{
+		WithPropagators(PropagatorB3, PropagatorTraceContext, PropagatorJaeger),
+	})
+	require.NoError(t, err)
+	want := []probe.Propagator{
+		probe.PropagatorB3,
+		probe.PropagatorTraceContext,
+		probe.PropagatorJaeger,
+	}
+	assert.Equal(t, want, c.propagators)
+
+	c, err = newInstConfig(ctx, []InstrumentationOption{WithPropagators(PropagatorNone)})
+	require.NoError(t, err)
+	assert.Equal(t, []probe.Propagator{}, c.propagators, "none")
+
+	_, err = newInstConfig(ctx, []InstrumentationOption{WithPropagators("xray")})
+	assert.ErrorIs(t, err, errUnsupportedPropagator)
+
+	t.Run("Env", func(t *testing.T) {
+		mockEnv(t, map[string]string{envPropagatorsKey: " b3multi, baggage ,tracecontext"})
+
+		// WithEnv passed last, it should have precedence.
+		opts := []InstrumentationOption{WithPropagators(PropagatorJaeger), WithEnv()}
+		c, err := newInstConfig(ctx, opts)
+		require.NoError(t, err)
+		want := []probe.Propagator{
+			probe.PropagatorB3Multi,
+			probe.PropagatorBaggage,
+			probe.PropagatorTraceContext,
+		}
+		assert.Equal(t, want, c.propagators)
+
+		mockEnv(t, map[string]string{envPropagatorsKey: "none"})
+		c, err = newInstConfig(ctx, []InstrumentationOption{WithEnv()})
+		require.NoError(t, err)
+		assert.Equal(t, []probe.Propagator{}, c.propagators)
+
+		mockEnv(t, map[string]string{envPropagatorsKey: ""})
+		c, err = newInstConfig(ctx, []InstrumentationOption{WithEnv()})
+		require.NoError(t, err)
+		assert.Nil(t, c.propagators, "empty is unset")
+
+		mockEnv(t, map[string]string{envPropagatorsKey: "tracecontext,ottrace"})
+		_, err = newInstConfig(ctx, []InstrumentationOption{WithEnv()})
+		assert.ErrorIs(t, err, errUnsupportedPropagator)
+	})
+}
+
+func TestWithBaggageAttributes(t *testing.T) {
+	ctx := context.Background()
+
+	c, err := newInstConfig(ctx, nil)
+	require.NoError(t, err)
+	assert.Nil(t, c.baggageAttributes, "default")
+
+	c, err = newInstConfig(ctx, []InstrumentationOption{
+		WithBaggageAttributes("tenant.id", "user.id"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tenant.id", "user.id"}, c.baggageAttributes)
+
+	t.Run("Env", func(t *testing.T) {
+		mockEnv(t, map[string]string{envBaggageAttributesKey: " tenant.id,, user.id "})
+
+		// WithEnv passed last, it should have precedence.
+		opts := []InstrumentationOption{WithBaggageAttributes("other"), WithEnv()}
+		c, err := newInstConfig(ctx, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"tenant.id", "user.id"}, c.baggageAttributes)
+
+		mockEnv(t, map[string]string{})
+		opts = []InstrumentationOption{WithBaggageAttributes("other"), WithEnv()}
+		c, err = newInstConfig(ctx, opts)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"other"}, c.baggageAttributes, "unset")
+	})
+}
+
 func mockEnv(t *testing.T, env map[string]string) {
 	orig := lookupEnv
 	t.Cleanup(func() { lookupEnv = orig })
@@ -6,6 +6,7 @@
 #ifndef __VMLINUX_H__
 #define __VMLINUX_H__
 
+typedef signed char __s8;
 typedef unsigned char __u8;
 typedef short int __s16;
 typedef short unsigned int __u16;
@@ -13,6 +14,7 @@ typedef int __s32;
 typedef unsigned int __u32;
 typedef long long int __s64;
 typedef long long unsigned int __u64;
+typedef __s8 s8;
 typedef __u8 u8;
 typedef __s16 s16;
 typedef __u16 u16;
@@ -6,10 +6,14 @@
 
 #include "bpf_helpers.h"
 #include "go_types.h"
+#include "trace/span_context.h"
+#include "trace/span_state.h"
 
 // This limit is used to define the max length of the context.Context chain
 #define MAX_DISTANCE 100
 #define MAX_CONCURRENT_SPANS 1000
+// The maximum number of goroutines with a tracked span context.
+#define MAX_TRACKED_GOROUTINES 8192
 
 struct {
     __uint(type, BPF_MAP_TYPE_HASH);
@@ -27,6 +31,30 @@ struct {
     __uint(pinning, LIBBPF_PIN_BY_NAME);
 } tracked_spans_by_sc SEC(".maps");
 
+// The state of the tracked spans, inherited by their child spans.
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __type(key, struct span_context);
+    __type(value, struct span_state);
+    __uint(max_entries, MAX_CONCURRENT_SPANS);
+    __uint(pinning, LIBBPF_PIN_BY_NAME);
+} tracked_span_states_by_sc SEC(".maps");
+
+// The span context active on a goroutine, keyed by goroutine. Goroutines
+// started by a goroutine with an active span context inherit it, so spans
+// started without the context of a request are still part of its trace. The
+// span context is only used while its span is tracked in tracked_spans_by_sc.
+// The entries are deleted when the goroutine exits or when found with an
+// ended span, and the least recently used ones are evicted if goroutines exit
+// untracked.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, struct span_context);
+    __uint(max_entries, MAX_TRACKED_GOROUTINES);
+    __uint(pinning, LIBBPF_PIN_BY_NAME);
+} goroutine_to_sc SEC(".maps");
+
 static __always_inline void *get_parent_go_context(struct go_iface *go_context, void *map) {
     void *data = go_context->data;
     for (int i = 0; i < MAX_DISTANCE; i++) {
@@ -61,7 +89,55 @@ static __always_inline struct span_context *get_parent_span_context(struct go_if
     return parent_sc;
 }
 
-static __always_inline void start_tracking_span(void *contextContext, struct span_context *sc) {
+// Returns the span context active on the goroutine, NULL if there is none.
+// The span context inherited from the goroutine starting it is only returned
+// while the span it was inherited from has not ended, as long-lived
+// goroutines, like the ones of worker pools, outlive the request they were
+// started for.
+static __always_inline struct span_context *get_goroutine_span_context(void *goroutine) {
+    struct span_context *sc = bpf_map_lookup_elem(&goroutine_to_sc, &goroutine);
+    if (sc == NULL) {
+        return NULL;
+    }
+    if (bpf_map_lookup_elem(&tracked_spans_by_sc, sc) == NULL) {
+        // The span has ended, the goroutine no longer has a span context.
+        bpf_map_delete_elem(&goroutine_to_sc, &goroutine);
+        return NULL;
+    }
+    return sc;
+}
+
+// Track sc as the span context active on the goroutine.
+static __always_inline void start_tracking_goroutine_span(void *goroutine,
+                                                          struct span_context *sc) {
+    long err = bpf_map_update_elem(&goroutine_to_sc, &goroutine, sc, BPF_ANY);
+    if (err != 0) {
+        bpf_printk("Failed to update goroutine_to_sc map: %ld", err);
+    }
+}
+
+// Stop tracking sc as the span context active on the goroutine. Nothing is
+// done if another span context is active on it.
+static __always_inline void stop_tracking_goroutine_span(void *goroutine,
+                                                         struct span_context *sc) {
+    struct span_context *active = bpf_map_lookup_elem(&goroutine_to_sc, &goroutine);
+    if (active == NULL) {
+        return;
+    }
+    if (bpf_memcmp((char *)active->SpanID, (char *)sc->SpanID, SPAN_ID_SIZE)) {
+        bpf_map_delete_elem(&goroutine_to_sc, &goroutine);
+    }
+}
+
+// Returns the state of the tracked span with sc, NULL if it is not tracked.
+static __always_inline struct span_state *get_span_state(struct span_context *sc) {
+    return bpf_map_lookup_elem(&tracked_span_states_by_sc, sc);
+}
+
+// Track the span with sc started with contextContext. Its state, if not NULL,
+// is inherited by the spans started with the context.
+static __always_inline void
+start_tracking_span(void *contextContext, struct span_context *sc, struct span_state *state) {
     long err = 0;
     err = bpf_map_update_elem(&go_context_to_sc, &contextContext, sc, BPF_ANY);
     if (err != 0) {
@@ -74,6 +150,13 @@ static __always_inline void start_tracking_span(void *contextContext, struct spa
         bpf_printk("Failed to update tracked_spans_by_sc map: %ld", err);
         return;
     }
+
+    if (state != NULL) {
+        err = bpf_map_update_elem(&tracked_span_states_by_sc, sc, state, BPF_ANY);
+        if (err != 0) {
+            bpf_printk("Failed to update tracked_span_states_by_sc map: %ld", err);
+        }
+    }
 }
 
 static __always_inline void stop_tracking_span(struct span_context *sc, struct span_context *psc) {
@@ -108,6 +191,7 @@ static __always_inline void stop_tracking_span(struct span_context *sc, struct s
     }
 
     bpf_map_delete_elem(&tracked_spans_by_sc, sc);
+    bpf_map_delete_elem(&tracked_span_states_by_sc, sc);
 }
 
 //  context_pos:
@@ -0,0 +1,214 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#ifndef _GO_ERRORS_H_
+#define _GO_ERRORS_H_
+
+#include "common.h"
+#include "go_types.h"
+#include "go_net.h"
+
+#define GO_ERROR_OP_MAX_LEN 16
+#define GO_ERROR_NET_MAX_LEN 8
+#define GO_ERROR_NAME_MAX_LEN 64
+#define GO_ERROR_MSG_MAX_LEN 128
+// The maximum depth of the wrapped errors read.
+#define GO_ERROR_MAX_DEPTH 3
+// The kinds of the Go errors whose values are read. They need to match the
+// ones of the GoError type in user space.
+#define GO_ERROR_OTHER 1
+#define GO_ERROR_STRING 2                 // *errors.errorString
+#define GO_ERROR_DEADLINE_EXCEEDED 3      // context.deadlineExceededError
+#define GO_ERROR_POLL_DEADLINE_EXCEEDED 4 // *internal/poll.DeadlineExceededError
+#define GO_ERROR_HTTP 5                   // *net/http.httpError
+#define GO_ERROR_HTTP_TIMEOUT 6           // *net/http.timeoutError
+#define GO_ERROR_TLS_HANDSHAKE_TIMEOUT 7  // net/http.tlsHandshakeTimeoutError
+#define GO_ERROR_OP 8                     // *net.OpError
+#define GO_ERROR_DNS 9                    // *net.DNSError
+#define GO_ERROR_SYSCALL 10               // *os.SyscallError
+#define GO_ERROR_ERRNO 11                 // syscall.Errno
+#define GO_ERROR_TLS_ALERT 12             // crypto/tls.alert
+#define GO_ERROR_TLS_CERT 13              // *crypto/tls.CertificateVerificationError
+
+// The value of a Go error. Only the fields needed to rebuild the message of
+// the known error kinds are read. The errors wrapped by a *net.OpError and a
+// *os.SyscallError are read into the same value.
+struct go_error {
+    // The kinds of the error and of the errors it wraps, 0 past the last one.
+    // The kind of a nil error is 0.
+    u8 kinds[GO_ERROR_MAX_DEPTH];
+    // Whether a *net.DNSError or a *net/http.httpError is a timeout.
+    u8 timeout;
+    u32 port;
+    // The length of ip, 0 if the address of the *net.OpError is unknown.
+    u8 ip_len;
+    u8 padding[7];
+    // The syscall.Errno or TLS alert code.
+    u64 code;
+    char op[GO_ERROR_OP_MAX_LEN];
+    char net[GO_ERROR_NET_MAX_LEN];
+    u8 ip[16];
+    // The name looked up by a *net.DNSError or the syscall of a
+    // *os.SyscallError.
+    char name[GO_ERROR_NAME_MAX_LEN];
+    char msg[GO_ERROR_MSG_MAX_LEN];
+};
+
+// The addresses of the itabs of the known error types implementing the error
+// interface, 0 if the type is not used by the target binary.
+volatile const u64 error_string_itab;
+volatile const u64 deadline_exceeded_itab;
+volatile const u64 poll_deadline_exceeded_itab;
+volatile const u64 http_error_itab;
+volatile const u64 http_timeout_error_itab;
+volatile const u64 tls_handshake_timeout_itab;
+volatile const u64 op_error_itab;
+volatile const u64 dns_error_itab;
+volatile const u64 syscall_error_itab;
+volatile const u64 errno_itab;
+volatile const u64 tls_alert_itab;
+volatile const u64 tls_cert_error_itab;
+// The address of the itab of *net.TCPAddr implementing net.Addr.
+volatile const u64 tcp_addr_itab;
+
+// Injected offsets of the known error types fields.
+volatile const u64 op_error_op_pos;
+volatile const u64 op_error_net_pos;
+volatile const u64 op_error_addr_pos;
+volatile const u64 op_error_err_pos;
+volatile const u64 dns_error_err_pos;
+volatile const u64 dns_error_name_pos;
+volatile const u64 dns_error_is_timeout_pos;
+volatile const u64 syscall_error_syscall_pos;
+volatile const u64 syscall_error_err_pos;
+// Only used by Go versions before 1.23, where *net/http.httpError is defined.
+volatile const u64 http_error_timeout_pos;
+
+// Returns the kind of the error with the itab.
+static __always_inline u8 go_error_kind(u64 itab) {
+    if (itab == 0) {
+        return 0;
+    }
+    if (itab == error_string_itab) {
+        return GO_ERROR_STRING;
+    }
+    if (itab == deadline_exceeded_itab) {
+        return GO_ERROR_DEADLINE_EXCEEDED;
+    }
+    if (itab == poll_deadline_exceeded_itab) {
+        return GO_ERROR_POLL_DEADLINE_EXCEEDED;
+    }
+    if (itab == http_error_itab) {
+        return GO_ERROR_HTTP;
+    }
+    if (itab == http_timeout_error_itab) {
+        return GO_ERROR_HTTP_TIMEOUT;
+    }
+    if (itab == tls_handshake_timeout_itab) {
+        return GO_ERROR_TLS_HANDSHAKE_TIMEOUT;
+    }
+    if (itab == op_error_itab) {
+        return GO_ERROR_OP;
+    }
+    if (itab == dns_error_itab) {
+        return GO_ERROR_DNS;
+    }
+    if (itab == syscall_error_itab) {
+        return GO_ERROR_SYSCALL;
+    }
+    if (itab == errno_itab) {
+        return GO_ERROR_ERRNO;
+    }
+    if (itab == tls_alert_itab) {
+        return GO_ERROR_TLS_ALERT;
+    }
+    if (itab == tls_cert_error_itab) {
+        return GO_ERROR_TLS_CERT;
+    }
+    return GO_ERROR_OTHER;
+}
+
+// Read the address of the *net.OpError at op_error into err if it is a
+// *net.TCPAddr.
+static __always_inline void read_go_error_addr(void *op_error, struct go_error *err) {
+    struct go_iface addr = {0};
+    if (bpf_probe_read_user(&addr, sizeof(addr), op_error + op_error_addr_pos) != 0 ||
+        addr.type == NULL || (u64)addr.type != tcp_addr_itab) {
+        return;
+    }
+    struct go_slice ip = {0};
+    if (bpf_probe_read_user(&ip, sizeof(ip), addr.data + TCPAddr_IP_offset) != 0) {
+        return;
+    }
+    if (ip.len != 4 && ip.len != 16) {
+        return;
+    }
+    u8 ip_len = ip.len == 4 ? 4 : 16;
+    if (bpf_probe_read_user(err->ip, ip_len, ip.array) != 0) {
+        return;
+    }
+    s64 port = 0;
+    bpf_probe_read_user(&port, sizeof(port), addr.data + TCPAddr_Port_offset);
+    err->port = (u32)port;
+    err->ip_len = ip_len;
+}
+
+// Read the fields of the error of kind with the data pointer into err. The
+// error wrapped by the error is returned in cause, if any.
+static __always_inline void
+read_go_error_fields(u8 kind, void *data, struct go_error *err, struct go_iface *cause) {
+    switch (kind) {
+    case GO_ERROR_STRING:
+    case GO_ERROR_HTTP_TIMEOUT:
+        // The message is the first field of these types.
+        get_go_string_from_user_ptr(data, err->msg, sizeof(err->msg));
+        break;
+    case GO_ERROR_HTTP:
+        get_go_string_from_user_ptr(data, err->msg, sizeof(err->msg));
+        bpf_probe_read_user(&err->timeout, sizeof(err->timeout), data + http_error_timeout_pos);
+        break;
+    case GO_ERROR_OP:
+        get_go_string_from_user_ptr(data + op_error_op_pos, err->op, sizeof(err->op));
+        get_go_string_from_user_ptr(data + op_error_net_pos, err->net, sizeof(err->net));
+        read_go_error_addr(data, err);
+        bpf_probe_read_user(cause, sizeof(*cause), data + op_error_err_pos);
+        break;
+    case GO_ERROR_DNS:
+        get_go_string_from_user_ptr(data + dns_error_err_pos, err->msg, sizeof(err->msg));
+        get_go_string_from_user_ptr(data + dns_error_name_pos, err->name, sizeof(err->name));
+        bpf_probe_read_user(&err->timeout, sizeof(err->timeout), data + dns_error_is_timeout_pos);
+        break;
+    case GO_ERROR_SYSCALL:
+        get_go_string_from_user_ptr(data + syscall_error_syscall_pos, err->name, sizeof(err->name));
+        bpf_probe_read_user(cause, sizeof(*cause), data + syscall_error_err_pos);
+        break;
+    case GO_ERROR_ERRNO:
+        // Non-pointer values are stored indirectly in interfaces.
+        bpf_probe_read_user(&err->code, sizeof(err->code), data);
+        break;
+    case GO_ERROR_TLS_ALERT:
+        bpf_probe_read_user(&err->code, sizeof(u8), data);
+        break;
+    }
+}
+
+// Read the Go error interface with the itab and the data pointer into err.
+// The errors wrapped by a *net.OpError and a *os.SyscallError are read as
+// well. The kinds of err are all 0 if itab is NULL, the error is nil.
+static __always_inline void read_go_error(void *itab, void *data, struct go_error *err) {
+    struct go_iface cause = {.type = itab, .data = data};
+    for (int i = 0; i < GO_ERROR_MAX_DEPTH; i++) {
+        u8 kind = go_error_kind((u64)cause.type);
+        if (kind == 0) {
+            break;
+        }
+        err->kinds[i] = kind;
+        void *cause_data = cause.data;
+        cause.type = NULL;
+        if (cause_data != NULL) {
+            read_go_error_fields(kind, cause_data, err, &cause);
+        }
+    }
+}
+
+#endif
@@ -0,0 +1,399 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#ifndef _GO_HEADERS_H_
+#define _GO_HEADERS_H_
+
+#include "common.h"
+#include "go_types.h"
+#include "utils.h"
+
+// Captured header names longer than this are never matched.
+#define HEADER_NAME_MAX_LEN 32
+// Captured header values are truncated to this length.
+#define HEADER_VALUE_MAX_LEN 128
+#define MAX_CAPTURED_HEADERS 8
+#define MAX_HEADER_CAPTURE_CONFIGS 64
+// Maximum number of header fields of a HTTP/2 frame visited.
+#define MAX_HEADER_FIELDS 32
+
+// Flags of the header capture configuration.
+#define HEADER_CAPTURE_REQUEST 0x1
+#define HEADER_CAPTURE_RESPONSE 0x2
+#define HEADER_CAPTURE_REDACT 0x4
+
+// Bounds of the walk of a Go map of headers.
+#define MAX_HEADER_MAP_BUCKETS 8
+#define MAX_HEADER_MAP_TABLES 4
+#define MAX_HEADER_MAP_TABLE_GROUPS 8
+
+// Layout of a map[string][]string, see:
+// https://github.com/golang/go/blob/go1.23.0/src/runtime/map.go
+// https://github.com/golang/go/blob/go1.24.0/src/internal/runtime/maps/map.go
+#define GO_MAP_SLOTS 8
+#define GO_MAP_MIN_TOP_HASH 5
+#define GO_MAP_BUCKET_KEYS_OFFSET GO_MAP_SLOTS
+#define GO_MAP_BUCKET_VALUES_OFFSET                                                                \
+    (GO_MAP_BUCKET_KEYS_OFFSET + GO_MAP_SLOTS * sizeof(go_string_t))
+#define GO_MAP_BUCKET_SIZE                                                                         \
+    (GO_MAP_BUCKET_VALUES_OFFSET + GO_MAP_SLOTS * sizeof(go_slice_t) + sizeof(void *))
+#define GO_SWISS_MAP_DIR_PTR_OFFSET 16
+#define GO_SWISS_MAP_DIR_LEN_OFFSET 24
+#define GO_SWISS_TABLE_GROUPS_OFFSET 16
+#define GO_SWISS_TABLE_LENGTH_MASK_OFFSET 24
+#define GO_SWISS_SLOT_SIZE (sizeof(go_string_t) + sizeof(go_slice_t))
+#define GO_SWISS_GROUP_SIZE (sizeof(u64) + GO_MAP_SLOTS * GO_SWISS_SLOT_SIZE)
+#define GO_SWISS_CTRL_EMPTY 0x80
+
+// The lowercase name of a header to capture.
+struct header_capture_key {
+    char name[HEADER_NAME_MAX_LEN];
+};
+
+struct captured_header {
+    char name[HEADER_NAME_MAX_LEN];
+    char value[HEADER_VALUE_MAX_LEN];
+    u32 value_len;
+    u8 redacted;
+    u8 padding[3];
+};
+
+struct captured_headers {
+    u32 count;
+    u8 padding[4];
+    struct captured_header headers[MAX_CAPTURED_HEADERS];
+};
+
+// The values of the well-known headers used for span attributes.
+struct known_headers {
+    char user_agent[HEADER_VALUE_MAX_LEN];
+    char forwarded_for[HEADER_VALUE_MAX_LEN];
+    char forwarded[HEADER_VALUE_MAX_LEN];
+};
+
+// The headers captured by the probe, written by user space. The entry with an
+// empty name holds the flags of all the other entries, it is used to skip the
+// walk of the headers when nothing is captured.
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __type(key, struct header_capture_key);
+    __type(value, u8);
+    __uint(max_entries, MAX_HEADER_CAPTURE_CONFIGS + 1);
+} header_capture_map SEC(".maps");
+
+// Injected in init
+volatile const u64 buckets_ptr_pos;
+// A flag indicating whether the Go version is using swiss maps
+volatile const bool swiss_maps_used;
+
+// Read the first value of the header with the values at values_ptr into out,
+// which needs to be HEADER_VALUE_MAX_LEN bytes long. Longer values are
+// truncated. Returns the length of the value read.
+static __always_inline u32 read_first_header_value(void *values_ptr, char *out) {
+    struct go_slice values = {0};
+    if (bpf_probe_read_user(&values, sizeof(values), values_ptr) != 0 || values.len < 1) {
+        return 0;
+    }
+    struct go_string value = {0};
+    if (bpf_probe_read_user(&value, sizeof(value), values.array) != 0 || value.len < 1) {
+        return 0;
+    }
+    u32 n = value.len > HEADER_VALUE_MAX_LEN ? HEADER_VALUE_MAX_LEN : (u32)value.len;
+    if (bpf_probe_read_user(out, n, value.str) != 0) {
+        return 0;
+    }
+    return n;
+}
+
+// Read the value of the header stored in the map slot with the lowercase name
+// of length name_len into known if it is a well-known header.
+static __always_inline void
+read_known_header(char *name, u32 name_len, void *values_ptr, struct known_headers *known) {
+    if (name_len == sizeof("user-agent") - 1 && bpf_memcmp(name, "user-agent", name_len)) {
+        read_first_header_value(values_ptr, known->user_agent);
+    } else if (name_len == sizeof("x-forwarded-for") - 1 &&
+               bpf_memcmp(name, "x-forwarded-for", name_len)) {
+        read_first_header_value(values_ptr, known->forwarded_for);
+    } else if (name_len == sizeof("forwarded") - 1 && bpf_memcmp(name, "forwarded", name_len)) {
+        read_first_header_value(values_ptr, known->forwarded);
+    }
+}
+
+// Returns whether any header is configured to be captured for kind.
+static __always_inline bool header_capture_enabled(u8 kind) {
+    struct header_capture_key all = {0};
+    u8 *flags = bpf_map_lookup_elem(&header_capture_map, &all);
+    return flags != NULL && (*flags & kind);
+}
+
+// Read the name of length name_len at name_ptr into the lowercase ck. Returns
+// false if the name cannot be captured.
+static __always_inline bool
+read_header_capture_key(void *name_ptr, u64 name_len, struct header_capture_key *ck) {
+    if (name_len < 1 || name_len > HEADER_NAME_MAX_LEN) {
+        return false;
+    }
+    if (bpf_probe_read_user(ck->name, name_len, name_ptr) != 0) {
+        return false;
+    }
+    for (u32 i = 0; i < HEADER_NAME_MAX_LEN; i++) {
+        if (ck->name[i] >= 'A' && ck->name[i] <= 'Z') {
+            ck->name[i] += 'a' - 'A';
+        }
+    }
+    return true;
+}
+
+// Add the header with the lowercase name ck to captured if it is configured to
+// be captured for kind. Returns the added header, with the value to be read if
+// it is not redacted, or NULL if the header is not captured.
+static __always_inline struct captured_header *
+add_captured_header(struct header_capture_key *ck, u8 kind, struct captured_headers *captured) {
+    u32 idx = captured->count;
+    if (kind == 0 || idx >= MAX_CAPTURED_HEADERS) {
+        return NULL;
+    }
+    u8 *flags = bpf_map_lookup_elem(&header_capture_map, ck);
+    if (flags == NULL || !(*flags & kind)) {
+        return NULL;
+    }
+
+    struct captured_header *h = &captured->headers[idx];
+    __builtin_memcpy(h->name, ck->name, sizeof(h->name));
+    h->value_len = 0;
+    h->redacted = (*flags & HEADER_CAPTURE_REDACT) != 0;
+    captured->count = idx + 1;
+    return h;
+}
+
+// Visit the header stored in the map slot with the key at key_ptr and the
+// values at values_ptr. It is captured into captured if it is configured to be
+// captured for kind, and read into known if it is a well-known header and
+// known is not NULL. Only the first value of the header is read. The value of a
+// redacted header is not read.
+static __always_inline void visit_header(void *key_ptr,
+                                         void *values_ptr,
+                                         u8 kind,
+                                         struct captured_headers *captured,
+                                         struct known_headers *known) {
+    struct go_string key = {0};
+    if (bpf_probe_read_user(&key, sizeof(key), key_ptr) != 0) {
+        return;
+    }
+    struct header_capture_key ck = {0};
+    if (!read_header_capture_key(key.str, key.len, &ck)) {
+        return;
+    }
+
+    if (known != NULL) {
+        read_known_header(ck.name, key.len, values_ptr, known);
+    }
+
+    struct captured_header *h = add_captured_header(&ck, kind, captured);
+    if (h != NULL && !h->redacted) {
+        h->value_len = read_first_header_value(values_ptr, h->value);
+    }
+}
+
+// Visit the headers of the Go map m using the bucket layout used before
+// Go 1.24. Overflow buckets are not walked.
+static __always_inline void walk_bucket_map_headers(void *m,
+                                                    u8 kind,
+                                                    struct captured_headers *captured,
+                                                    struct known_headers *known) {
+    u8 log_2_bucket_count = 0;
+    if (bpf_probe_read_user(&log_2_bucket_count, sizeof(log_2_bucket_count), m + 9) != 0) {
+        return;
+    }
+    void *buckets = NULL;
+    if (bpf_probe_read_user(&buckets, sizeof(buckets), m + buckets_ptr_pos) != 0 ||
+        buckets == NULL) {
+        return;
+    }
+    u64 bucket_count = log_2_bucket_count < 8 ? 1 << log_2_bucket_count : MAX_HEADER_MAP_BUCKETS;
+    for (u64 b = 0; b < MAX_HEADER_MAP_BUCKETS; b++) {
+        if (b >= bucket_count) {
+            break;
+        }
+        void *bucket = buckets + b * GO_MAP_BUCKET_SIZE;
+        u8 tophash[GO_MAP_SLOTS];
+        if (bpf_probe_read_user(tophash, sizeof(tophash), bucket) != 0) {
+            continue;
+        }
+        for (u32 i = 0; i < GO_MAP_SLOTS; i++) {
+            // Lower values mark empty and evacuated slots.
+            if (tophash[i] < GO_MAP_MIN_TOP_HASH) {
+                continue;
+            }
+            visit_header(bucket + GO_MAP_BUCKET_KEYS_OFFSET + i * sizeof(go_string_t),
+                         bucket + GO_MAP_BUCKET_VALUES_OFFSET + i * sizeof(go_slice_t),
+                         kind,
+                         captured,
+                         known);
+        }
+    }
+}
+
+// Visit the headers of the swiss map group at group.
+static __always_inline void walk_swiss_group_headers(void *group,
+                                                     u8 kind,
+                                                     struct captured_headers *captured,
+                                                     struct known_headers *known) {
+    u64 ctrl = 0;
+    if (bpf_probe_read_user(&ctrl, sizeof(ctrl), group) != 0) {
+        return;
+    }
+    for (u32 i = 0; i < GO_MAP_SLOTS; i++) {
+        // The control byte of a full slot has its high bit cleared.
+        if ((ctrl >> (8 * i)) & GO_SWISS_CTRL_EMPTY) {
+            continue;
+        }
+        void *slot = group + sizeof(ctrl) + i * GO_SWISS_SLOT_SIZE;
+        visit_header(slot, slot + sizeof(go_string_t), kind, captured, known);
+    }
+}
+
+// Visit the headers of the Go map m using the swiss map layout used since
+// Go 1.24.
+static __always_inline void walk_swiss_map_headers(void *m,
+                                                   u8 kind,
+                                                   struct captured_headers *captured,
+                                                   struct known_headers *known) {
+    void *dir_ptr = NULL;
+    if (bpf_probe_read_user(&dir_ptr, sizeof(dir_ptr), m + GO_SWISS_MAP_DIR_PTR_OFFSET) != 0 ||
+        dir_ptr == NULL) {
+        return;
+    }
+    s64 dir_len = 0;
+    if (bpf_probe_read_user(&dir_len, sizeof(dir_len), m + GO_SWISS_MAP_DIR_LEN_OFFSET) != 0) {
+        return;
+    }
+    // Small maps have no directory, they point to a single group.
+    if (dir_len == 0) {
+        walk_swiss_group_headers(dir_ptr, kind, captured, known);
+        return;
+    }
+
+    void *prev = NULL;
+    for (s64 t = 0; t < MAX_HEADER_MAP_TABLES; t++) {
+        if (t >= dir_len) {
+            break;
+        }
+        void *table = NULL;
+        if (bpf_probe_read_user(&table, sizeof(table), dir_ptr + t * sizeof(void *)) != 0) {
+            break;
+        }
+        // A table is referenced by consecutive directory entries until it is split.
+        if (table == NULL || table == prev) {
+            continue;
+        }
+        prev = table;
+
+        void *groups = NULL;
+        u64 length_mask = 0;
+        if (bpf_probe_read_user(&groups, sizeof(groups), table + GO_SWISS_TABLE_GROUPS_OFFSET) ||
+            bpf_probe_read_user(&length_mask,
+                                sizeof(length_mask),
+                                table + GO_SWISS_TABLE_LENGTH_MASK_OFFSET)) {
+            continue;
+        }
+        for (u64 g = 0; g < MAX_HEADER_MAP_TABLE_GROUPS; g++) {
+            if (g > length_mask) {
+                break;
+            }
+            walk_swiss_group_headers(groups + g * GO_SWISS_GROUP_SIZE, kind, captured, known);
+        }
+    }
+}
+
+// Capture the headers configured for kind of the Go map[string][]string m, an
+// http.Header or a metadata.MD, into captured. Previously captured headers are
+// discarded. If known is not NULL, the values of the well-known headers are
+// read into it.
+static __always_inline void read_go_headers(void *m,
+                                            u8 kind,
+                                            struct captured_headers *captured,
+                                            struct known_headers *known) {
+    captured->count = 0;
+    if (m == NULL) {
+        return;
+    }
+
+    if (!header_capture_enabled(kind)) {
+        kind = 0;
+    }
+    if (kind == 0 && known == NULL) {
+        return;
+    }
+
+    u64 count = 0;
+    if (bpf_probe_read_user(&count, sizeof(count), m) != 0 || count == 0) {
+        return;
+    }
+    if (swiss_maps_used) {
+        walk_swiss_map_headers(m, kind, captured, known);
+    } else {
+        walk_bucket_map_headers(m, kind, captured, known);
+    }
+}
+
+// Capture the headers configured for kind of the Go map[string][]string m into
+// captured. Previously captured headers are discarded.
+static __always_inline void
+capture_go_headers(void *m, u8 kind, struct captured_headers *captured) {
+    read_go_headers(m, kind, captured, NULL);
+}
+
+// A hpack.HeaderField, used for the headers of HTTP/2 frames and for gRPC
+// metadata.
+struct hpack_header_field {
+    struct go_string name;
+    struct go_string value;
+    bool sensitive;
+};
+
+// Capture the header field at hf_ptr into captured if it is configured to be
+// captured for kind. The value of a redacted header is not read.
+static __always_inline void
+capture_header_field(void *hf_ptr, u8 kind, struct captured_headers *captured) {
+    struct hpack_header_field hf = {0};
+    if (bpf_probe_read_user(&hf, sizeof(hf), hf_ptr) != 0) {
+        return;
+    }
+    struct header_capture_key ck = {0};
+    if (!read_header_capture_key(hf.name.str, hf.name.len, &ck)) {
+        return;
+    }
+    struct captured_header *h = add_captured_header(&ck, kind, captured);
+    if (h == NULL || h->redacted || hf.value.len < 1) {
+        return;
+    }
+    u32 n = hf.value.len > HEADER_VALUE_MAX_LEN ? HEADER_VALUE_MAX_LEN : (u32)hf.value.len;
+    if (bpf_probe_read_user(h->value, n, hf.value.str) == 0) {
+        h->value_len = n;
+    }
+}
+
+// Capture the header fields configured for kind of the Go
+// []hpack.HeaderField at fields_ptr into captured. They are added to the
+// previously captured headers. Only the first MAX_HEADER_FIELDS fields are
+// visited.
+static __always_inline void
+capture_header_fields(void *fields_ptr, u8 kind, struct captured_headers *captured) {
+    if (fields_ptr == NULL || !header_capture_enabled(kind)) {
+        return;
+    }
+    struct go_slice fields = {0};
+    if (bpf_probe_read_user(&fields, sizeof(fields), fields_ptr) != 0) {
+        return;
+    }
+    for (u32 i = 0; i < MAX_HEADER_FIELDS; i++) {
+        if (i >= fields.len) {
+            break;
+        }
+        capture_header_field(
+            fields.array + i * sizeof(struct hpack_header_field), kind, captured);
+    }
+}
+
+#endif
@@ -0,0 +1,55 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#ifndef _RPC_MESSAGES_H_
+#define _RPC_MESSAGES_H_
+
+#include "common.h"
+
+// The maximum number of messages recorded for an RPC, a power of 2.
+#define MAX_RPC_MESSAGES 8
+// The length of the prefix of a gRPC message: a compressed flag followed by
+// the big-endian length of the message.
+#define GRPC_MESSAGE_PREFIX_LEN 5
+
+struct rpc_message {
+    u64 time;
+    // The sequence number of the message in its direction, starting at 1.
+    u32 id;
+    u32 size;
+    u8 sent;
+    u8 compressed;
+    u8 padding[6];
+};
+
+// The messages sent and received during an RPC. The counters keep counting
+// past the first MAX_RPC_MESSAGES messages, which are the only ones recorded.
+struct rpc_messages {
+    u32 sent;
+    u32 received;
+    struct rpc_message events[MAX_RPC_MESSAGES];
+};
+
+// Record in msgs the message sent, or received, at time with the gRPC message
+// prefix at the user space address prefix.
+static __always_inline void
+record_rpc_message(struct rpc_messages *msgs, bool sent, void *prefix, u64 time) {
+    u8 buf[GRPC_MESSAGE_PREFIX_LEN] = {0};
+    if (prefix == NULL || bpf_probe_read_user(buf, sizeof(buf), prefix) != 0) {
+        return;
+    }
+
+    u32 id = sent ? ++msgs->sent : ++msgs->received;
+    u32 n = msgs->sent + msgs->received;
+    if (n > MAX_RPC_MESSAGES) {
+        return;
+    }
+    struct rpc_message *m = &msgs->events[(n - 1) & (MAX_RPC_MESSAGES - 1)];
+    m->time = time;
+    m->id = id;
+    m->size = ((u32)buf[1] << 24) | ((u32)buf[2] << 16) | ((u32)buf[3] << 8) | (u32)buf[4];
+    m->sent = sent;
+    m->compressed = buf[0] & 1;
+}
+
+#endif
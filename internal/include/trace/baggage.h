@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#ifndef _BAGGAGE_H_
+#define _BAGGAGE_H_
+
+#include "common.h"
+#include "span_context.h"
+
+// The maximum number of bytes of a baggage header value stored. Members that
+// do not fit are dropped. Needs to be a power of 2.
+#define BAGGAGE_MAX_LEN 128
+
+// The W3C baggage list members of a request.
+struct baggage {
+    u32 len;
+    u8 padding[4];
+    char value[BAGGAGE_MAX_LEN];
+};
+
+// Copy the baggage header value of length len in buf into bg. If the value was
+// truncated, the last list member is dropped as it is incomplete.
+static __always_inline void parse_baggage(char *buf, u32 len, bool truncated, struct baggage *bg) {
+    u32 end = len;
+    if (truncated) {
+        end = 0;
+        for (u32 i = 0; i < BAGGAGE_MAX_LEN; i++) {
+            if (i >= len) {
+                break;
+            }
+            if (buf[i] == ',') {
+                end = i;
+            }
+        }
+    }
+    for (u32 i = 0; i < BAGGAGE_MAX_LEN; i++) {
+        if (i >= end) {
+            break;
+        }
+        bg->value[i] = buf[i];
+    }
+    bg->len = end;
+}
+
+// Copy the baggage header value of bg into out, which needs to be at least
+// BAGGAGE_MAX_LEN bytes. Returns the length of the value, 0 if there is no
+// baggage to propagate.
+static __always_inline u32 baggage_to_string(struct baggage *bg, char *out) {
+    if (bg->len == 0 || bg->len > BAGGAGE_MAX_LEN) {
+        return 0;
+    }
+    for (u32 i = 0; i < BAGGAGE_MAX_LEN; i++) {
+        if (i >= bg->len) {
+            break;
+        }
+        out[i] = bg->value[i];
+    }
+    return bg->len;
+}
+
+#endif
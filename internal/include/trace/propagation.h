@@ -0,0 +1,515 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#ifndef _PROPAGATION_H_
+#define _PROPAGATION_H_
+
+#include "common.h"
+#include "utils.h"
+#include "span_context.h"
+#include "tracestate.h"
+#include "span_state.h"
+#include "baggage.h"
+
+// The context propagation formats. The values match the Propagator values of
+// the probe package.
+enum propagator {
+    PROPAGATOR_NONE = 0,
+    PROPAGATOR_TRACECONTEXT = 1,
+    PROPAGATOR_B3 = 2,
+    PROPAGATOR_B3_MULTI = 3,
+    PROPAGATOR_JAEGER = 4,
+    // Baggage is propagated with the span context of the other propagators.
+    PROPAGATOR_BAGGAGE = 5,
+};
+
+// The number of propagators with a span context.
+#define PROPAGATOR_COUNT 5
+#define MAX_PROPAGATORS 5
+#define PROPAGATOR_BITS 4
+#define PROPAGATOR_MASK 0xF
+
+// The headers recognized when extracting a span context.
+enum propagation_header_kind {
+    PROPAGATION_HEADER_NONE = 0,
+    PROPAGATION_HEADER_TRACEPARENT = 1,
+    PROPAGATION_HEADER_TRACESTATE = 2,
+    PROPAGATION_HEADER_B3 = 3,
+    PROPAGATION_HEADER_B3_TRACE_ID = 4,
+    PROPAGATION_HEADER_B3_SPAN_ID = 5,
+    PROPAGATION_HEADER_B3_SAMPLED = 6,
+    PROPAGATION_HEADER_B3_FLAGS = 7,
+    PROPAGATION_HEADER_UBER_TRACE_ID = 8,
+    PROPAGATION_HEADER_BAGGAGE = 9,
+};
+
+#define PROPAGATION_HEADER_KINDS 10
+
+// The maximum length of a header key, the longest is "uber-trace-id". Injected
+// keys are followed by ": " so HTTP/1 probes can write them as is.
+#define PROPAGATION_KEY_MAX_LEN 16
+// The maximum length of a header value read, the longest values are the
+// tracestate and baggage ones. Needs to be a power of 2.
+#define PROPAGATION_VALUE_MAX_LEN TRACESTATE_MAX_LEN
+// The maximum length of a header value injected, the longest values are the
+// tracestate ones. Needs to be a power of 2.
+#define PROPAGATION_HEADER_VALUE_MAX_LEN TRACESTATE_BUF_SIZE
+// The maximum number of headers injected for a propagator.
+#define PROPAGATION_MAX_FIELDS 3
+#define MAX_PROPAGATION_HEADERS (MAX_PROPAGATORS * PROPAGATION_MAX_FIELDS)
+
+// The B3 multi headers found while extracting.
+#define B3_MULTI_TRACE_ID 0x1
+#define B3_MULTI_SPAN_ID 0x2
+#define B3_MULTI_IDS (B3_MULTI_TRACE_ID | B3_MULTI_SPAN_ID)
+
+struct propagation_value {
+    void *str;
+    u64 len;
+};
+
+// The state of a span context extraction from a set of headers.
+struct propagation_extractor {
+    // The span context extracted for each propagator, indexed by propagator.
+    struct span_context sc[PROPAGATOR_COUNT];
+    struct ot_trace_state ots;
+    struct trace_state ts;
+    struct baggage bg;
+    // The bit set of the propagators a span context was extracted for.
+    u8 found;
+    // The bit set of the B3 multi headers found.
+    u8 b3_multi;
+    u8 padding[6];
+    // The user space values of the headers found, indexed by header kind.
+    struct propagation_value values[PROPAGATION_HEADER_KINDS];
+    char buf[PROPAGATION_VALUE_MAX_LEN];
+};
+
+// A header to inject. The key is lowercase.
+struct propagation_header {
+    char key[PROPAGATION_KEY_MAX_LEN];
+    char value[PROPAGATION_HEADER_VALUE_MAX_LEN];
+    u32 key_len;
+    u32 value_len;
+};
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(struct propagation_extractor));
+    __uint(max_entries, 1);
+} propagation_extractor_storage_map SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(struct propagation_header));
+    __uint(max_entries, 1);
+} propagation_header_storage_map SEC(".maps");
+
+// The configured propagators, in priority order. Each propagator is
+// PROPAGATOR_BITS wide, starting with the least significant bits, and the
+// list ends with PROPAGATOR_NONE.
+// Injected in init
+volatile const u64 propagators;
+
+static __always_inline u8 propagator_at(u32 i) {
+    return (propagators >> (i * PROPAGATOR_BITS)) & PROPAGATOR_MASK;
+}
+
+static __always_inline bool propagator_enabled(u8 p) {
+    for (u32 i = 0; i < MAX_PROPAGATORS; i++) {
+        u8 cur = propagator_at(i);
+        if (cur == PROPAGATOR_NONE) {
+            return false;
+        }
+        if (cur == p) {
+            return true;
+        }
+    }
+    return false;
+}
+
+// Returns a zeroed extractor from the per-CPU storage, NULL on failure.
+static __always_inline struct propagation_extractor *new_propagation_extractor() {
+    u32 map_id = 0;
+    struct propagation_extractor *ex =
+        bpf_map_lookup_elem(&propagation_extractor_storage_map, &map_id);
+    if (ex == NULL) {
+        return NULL;
+    }
+    __builtin_memset(ex, 0, sizeof(*ex));
+    return ex;
+}
+
+// Returns a header from the per-CPU storage, NULL on failure.
+static __always_inline struct propagation_header *new_propagation_header() {
+    u32 map_id = 0;
+    return bpf_map_lookup_elem(&propagation_header_storage_map, &map_id);
+}
+
+static __always_inline bool
+propagation_key_equals(char *key, u32 len, const char *lower, u32 lower_len) {
+    return len == lower_len && bpf_memicmp(key, lower, lower_len) == 0;
+}
+
+// Returns the kind of the header with the key of length len, compared case
+// insensitively. Headers of propagators not configured are not recognized.
+static __always_inline u8 propagation_header_kind(char *key, u32 len) {
+    if (propagator_enabled(PROPAGATOR_TRACECONTEXT)) {
+        if (propagation_key_equals(key, len, "traceparent", 11)) {
+            return PROPAGATION_HEADER_TRACEPARENT;
+        }
+        if (propagation_key_equals(key, len, "tracestate", 10)) {
+            return PROPAGATION_HEADER_TRACESTATE;
+        }
+    }
+    if (propagator_enabled(PROPAGATOR_B3) && propagation_key_equals(key, len, "b3", 2)) {
+        return PROPAGATION_HEADER_B3;
+    }
+    if (propagator_enabled(PROPAGATOR_B3_MULTI)) {
+        if (propagation_key_equals(key, len, "x-b3-traceid", 12)) {
+            return PROPAGATION_HEADER_B3_TRACE_ID;
+        }
+        if (propagation_key_equals(key, len, "x-b3-spanid", 11)) {
+            return PROPAGATION_HEADER_B3_SPAN_ID;
+        }
+        if (propagation_key_equals(key, len, "x-b3-sampled", 12)) {
+            return PROPAGATION_HEADER_B3_SAMPLED;
+        }
+        if (propagation_key_equals(key, len, "x-b3-flags", 10)) {
+            return PROPAGATION_HEADER_B3_FLAGS;
+        }
+    }
+    if (propagator_enabled(PROPAGATOR_JAEGER) &&
+        propagation_key_equals(key, len, "uber-trace-id", 13)) {
+        return PROPAGATION_HEADER_UBER_TRACE_ID;
+    }
+    if (propagator_enabled(PROPAGATOR_BAGGAGE) && propagation_key_equals(key, len, "baggage", 7)) {
+        return PROPAGATION_HEADER_BAGGAGE;
+    }
+    return PROPAGATION_HEADER_NONE;
+}
+
+// Returns the index of the first c in buf between start and len, len if c is
+// not found.
+static __always_inline u32 propagation_index(char *buf, u32 start, u32 len, char c) {
+    for (u32 i = 0; i < PROPAGATION_VALUE_MAX_LEN; i++) {
+        if (i >= len) {
+            break;
+        }
+        if (i >= start && buf[i] == c) {
+            return i;
+        }
+    }
+    return len;
+}
+
+// Parse the hex digits of buf between start and end into the ID out of size
+// bytes. Shorter IDs are padded with leading zeros. Returns false if the
+// digits are not valid or do not fit in out.
+static __always_inline bool
+propagation_parse_id(char *buf, u32 start, u32 end, u8 *out, u32 size) {
+    if (end <= start || end - start > 2 * size || end > PROPAGATION_VALUE_MAX_LEN) {
+        return false;
+    }
+    u32 offset = 2 * size - (end - start);
+    for (u32 i = 0; i < 2 * TRACE_ID_SIZE; i++) {
+        if (i >= 2 * size) {
+            break;
+        }
+        u8 nibble = 0;
+        if (i >= offset) {
+            s8 n = hex_char_to_nibble(buf[(start + i - offset) & (PROPAGATION_VALUE_MAX_LEN - 1)]);
+            if (n < 0) {
+                return false;
+            }
+            nibble = n;
+        }
+        if (i % 2 == 0) {
+            out[i / 2] = nibble << 4;
+        } else {
+            out[i / 2] |= nibble;
+        }
+    }
+    return true;
+}
+
+// Parse the B3 single header value "{trace-id}-{span-id}[-{sampling}[-{parent}]]".
+static __always_inline bool parse_b3(char *buf, u32 len, struct span_context *sc) {
+    u32 trace_end = propagation_index(buf, 0, len, '-');
+    if (trace_end != TRACE_ID_STRING_SIZE && trace_end != TRACE_ID_STRING_SIZE / 2) {
+        return false;
+    }
+    u32 span_end = propagation_index(buf, trace_end + 1, len, '-');
+    if (span_end - trace_end - 1 != SPAN_ID_STRING_SIZE) {
+        return false;
+    }
+    if (!propagation_parse_id(buf, 0, trace_end, sc->TraceID, TRACE_ID_SIZE) ||
+        !propagation_parse_id(buf, trace_end + 1, span_end, sc->SpanID, SPAN_ID_SIZE)) {
+        return false;
+    }
+    sc->TraceFlags = 0;
+    if (span_end + 1 < len) {
+        // Debug implies sampled, a deferred decision is not sampled.
+        char sampling = buf[(span_end + 1) & (PROPAGATION_VALUE_MAX_LEN - 1)];
+        if (sampling == '1' || sampling == 'd') {
+            sc->TraceFlags = 1;
+        }
+    }
+    return true;
+}
+
+// Parse the Jaeger header value "{trace-id}:{span-id}:{parent-span-id}:{flags}".
+static __always_inline bool parse_uber_trace_id(char *buf, u32 len, struct span_context *sc) {
+    u32 trace_end = propagation_index(buf, 0, len, ':');
+    u32 span_end = propagation_index(buf, trace_end + 1, len, ':');
+    u32 parent_end = propagation_index(buf, span_end + 1, len, ':');
+    if (parent_end + 1 >= len) {
+        return false;
+    }
+    if (!propagation_parse_id(buf, 0, trace_end, sc->TraceID, TRACE_ID_SIZE) ||
+        !propagation_parse_id(buf, trace_end + 1, span_end, sc->SpanID, SPAN_ID_SIZE)) {
+        return false;
+    }
+    // The sampled flag is the least significant bit of the flags.
+    s8 flags = hex_char_to_nibble(buf[(len - 1) & (PROPAGATION_VALUE_MAX_LEN - 1)]);
+    if (flags < 0) {
+        return false;
+    }
+    sc->TraceFlags = flags & 1;
+    return true;
+}
+
+// Read the value of length len at the user space address str of a header of
+// kind and extract it into ex.
+static __always_inline void
+propagation_extract_value(struct propagation_extractor *ex, u8 kind, void *str, u64 len) {
+    if (kind == PROPAGATION_HEADER_NONE || str == NULL || len == 0) {
+        return;
+    }
+    // Only the tracestate and baggage values can be truncated.
+    if (len > PROPAGATION_VALUE_MAX_LEN && kind != PROPAGATION_HEADER_TRACESTATE &&
+        kind != PROPAGATION_HEADER_BAGGAGE) {
+        return;
+    }
+    u32 n = len > PROPAGATION_VALUE_MAX_LEN ? PROPAGATION_VALUE_MAX_LEN : (u32)len;
+    if (bpf_probe_read_user(ex->buf, n, str) != 0) {
+        return;
+    }
+
+    struct span_context *b3_multi = &ex->sc[PROPAGATOR_B3_MULTI];
+    switch (kind) {
+    case PROPAGATION_HEADER_TRACEPARENT:
+        if (n == W3C_VAL_LENGTH) {
+            w3c_string_to_span_context(ex->buf, &ex->sc[PROPAGATOR_TRACECONTEXT]);
+            ex->found |= 1 << PROPAGATOR_TRACECONTEXT;
+        }
+        break;
+    case PROPAGATION_HEADER_TRACESTATE:
+        parse_ot_trace_state(ex->buf, n, &ex->ots);
+        parse_trace_state_entries(ex->buf, n, len > n, &ex->ts);
+        break;
+    case PROPAGATION_HEADER_B3:
+        if (parse_b3(ex->buf, n, &ex->sc[PROPAGATOR_B3])) {
+            ex->found |= 1 << PROPAGATOR_B3;
+        }
+        break;
+    case PROPAGATION_HEADER_B3_TRACE_ID:
+        if ((n == TRACE_ID_STRING_SIZE || n == TRACE_ID_STRING_SIZE / 2) &&
+            propagation_parse_id(ex->buf, 0, n, b3_multi->TraceID, TRACE_ID_SIZE)) {
+            ex->b3_multi |= B3_MULTI_TRACE_ID;
+        }
+        break;
+    case PROPAGATION_HEADER_B3_SPAN_ID:
+        if (n == SPAN_ID_STRING_SIZE &&
+            propagation_parse_id(ex->buf, 0, n, b3_multi->SpanID, SPAN_ID_SIZE)) {
+            ex->b3_multi |= B3_MULTI_SPAN_ID;
+        }
+        break;
+    case PROPAGATION_HEADER_B3_SAMPLED:
+        if ((n == 1 && ex->buf[0] == '1') || (n == 4 && bpf_memcmp(ex->buf, "true", 4))) {
+            b3_multi->TraceFlags = 1;
+        }
+        break;
+    case PROPAGATION_HEADER_B3_FLAGS:
+        // Debug implies sampled.
+        if (n == 1 && ex->buf[0] == '1') {
+            b3_multi->TraceFlags = 1;
+        }
+        break;
+    case PROPAGATION_HEADER_UBER_TRACE_ID:
+        if (parse_uber_trace_id(ex->buf, n, &ex->sc[PROPAGATOR_JAEGER])) {
+            ex->found |= 1 << PROPAGATOR_JAEGER;
+        }
+        break;
+    case PROPAGATION_HEADER_BAGGAGE:
+        parse_baggage(ex->buf, n, len > n, &ex->bg);
+        break;
+    }
+}
+
+// Record the header with the key of length key_len and the value of length
+// val_len, both at user space addresses, in ex. The value is extracted by
+// propagation_extract_finish, so it needs to stay valid until then.
+static __always_inline void propagation_extract_header(
+    struct propagation_extractor *ex, void *key, u64 key_len, void *val, u64 val_len) {
+    if (key == NULL || key_len == 0 || key_len > PROPAGATION_KEY_MAX_LEN) {
+        return;
+    }
+    char k[PROPAGATION_KEY_MAX_LEN];
+    if (bpf_probe_read_user(k, key_len, key) != 0) {
+        return;
+    }
+    u8 kind = propagation_header_kind(k, key_len);
+    if (kind == PROPAGATION_HEADER_NONE || kind >= PROPAGATION_HEADER_KINDS) {
+        return;
+    }
+    ex->values[kind].str = val;
+    ex->values[kind].len = val_len;
+}
+
+// Extract the recorded headers of ex and copy the span context of the first
+// configured propagator found into psc. The tracestate is copied into state if
+// the span context is the W3C one, and the baggage whatever the propagator of
+// the span context is.
+// Returns 0 if a span context is found, negative value otherwise.
+static __always_inline long propagation_extract_finish(struct propagation_extractor *ex,
+                                                       struct span_context *psc,
+                                                       struct span_state *state) {
+    for (u8 kind = PROPAGATION_HEADER_TRACEPARENT; kind < PROPAGATION_HEADER_KINDS; kind++) {
+        propagation_extract_value(ex, kind, ex->values[kind].str, ex->values[kind].len);
+    }
+    if ((ex->b3_multi & B3_MULTI_IDS) == B3_MULTI_IDS) {
+        ex->found |= 1 << PROPAGATOR_B3_MULTI;
+    }
+
+    for (u32 i = 0; i < MAX_PROPAGATORS; i++) {
+        u8 p = propagator_at(i);
+        if (p == PROPAGATOR_NONE) {
+            break;
+        }
+        if (p >= PROPAGATOR_COUNT || !(ex->found & (1 << p))) {
+            continue;
+        }
+        __builtin_memcpy(psc, &ex->sc[p], sizeof(*psc));
+        __builtin_memset(state, 0, sizeof(*state));
+        if (p == PROPAGATOR_TRACECONTEXT) {
+            state->ots = ex->ots;
+            state->ts = ex->ts;
+        }
+        if (propagator_enabled(PROPAGATOR_BAGGAGE)) {
+            state->bg = ex->bg;
+        }
+        return 0;
+    }
+    return -1;
+}
+
+static __always_inline void
+propagation_set_key(struct propagation_header *h, const char *key, u32 len) {
+    __builtin_memcpy(h->key, key, len);
+    h->key_len = len;
+}
+
+static __always_inline u32 propagation_sampled(struct span_context *sc, char *out) {
+    out[0] = (sc->TraceFlags & 1) ? '1' : '0';
+    return 1;
+}
+
+// Fill h with the header at index of the headers to inject for sc and its
+// state, which may be NULL. The headers of the propagator configured at
+// position i are at the indexes i * PROPAGATION_MAX_FIELDS to
+// (i + 1) * PROPAGATION_MAX_FIELDS - 1.
+// Returns false if there is no header to inject at index.
+static __always_inline bool propagation_inject_header(u32 index,
+                                                      struct span_context *sc,
+                                                      struct span_state *state,
+                                                      struct propagation_header *h) {
+    u32 field = index % PROPAGATION_MAX_FIELDS;
+    u32 slot = index / PROPAGATION_MAX_FIELDS;
+    if (slot >= MAX_PROPAGATORS) {
+        return false;
+    }
+    char *out = h->value;
+    switch (propagator_at(slot)) {
+    case PROPAGATOR_TRACECONTEXT:
+        if (field == 0) {
+            propagation_set_key(h, "traceparent", 11);
+            span_context_to_w3c_string(sc, out);
+            h->value_len = W3C_VAL_LENGTH;
+            return true;
+        }
+        if (field == 1) {
+            if (state == NULL) {
+                return false;
+            }
+            h->value_len = trace_state_to_string(&state->ots, &state->ts, out);
+            if (h->value_len == 0) {
+                return false;
+            }
+            propagation_set_key(h, "tracestate", 10);
+            return true;
+        }
+        return false;
+    case PROPAGATOR_B3:
+        if (field != 0) {
+            return false;
+        }
+        // {trace-id}-{span-id}-{sampled}
+        propagation_set_key(h, "b3", 2);
+        bytes_to_hex_string(sc->TraceID, TRACE_ID_SIZE, out);
+        out += TRACE_ID_STRING_SIZE;
+        *out++ = '-';
+        bytes_to_hex_string(sc->SpanID, SPAN_ID_SIZE, out);
+        out += SPAN_ID_STRING_SIZE;
+        *out++ = '-';
+        propagation_sampled(sc, out);
+        h->value_len = TRACE_ID_STRING_SIZE + SPAN_ID_STRING_SIZE + 3;
+        return true;
+    case PROPAGATOR_B3_MULTI:
+        if (field == 0) {
+            propagation_set_key(h, "x-b3-traceid", 12);
+            bytes_to_hex_string(sc->TraceID, TRACE_ID_SIZE, out);
+            h->value_len = TRACE_ID_STRING_SIZE;
+        } else if (field == 1) {
+            propagation_set_key(h, "x-b3-spanid", 11);
+            bytes_to_hex_string(sc->SpanID, SPAN_ID_SIZE, out);
+            h->value_len = SPAN_ID_STRING_SIZE;
+        } else {
+            propagation_set_key(h, "x-b3-sampled", 12);
+            h->value_len = propagation_sampled(sc, out);
+        }
+        return true;
+    case PROPAGATOR_JAEGER:
+        if (field != 0) {
+            return false;
+        }
+        // {trace-id}:{span-id}:0:{flags}, the parent span ID is deprecated.
+        propagation_set_key(h, "uber-trace-id", 13);
+        bytes_to_hex_string(sc->TraceID, TRACE_ID_SIZE, out);
+        out += TRACE_ID_STRING_SIZE;
+        *out++ = ':';
+        bytes_to_hex_string(sc->SpanID, SPAN_ID_SIZE, out);
+        out += SPAN_ID_STRING_SIZE;
+        *out++ = ':';
+        *out++ = '0';
+        *out++ = ':';
+        propagation_sampled(sc, out);
+        h->value_len = TRACE_ID_STRING_SIZE + SPAN_ID_STRING_SIZE + 5;
+        return true;
+    case PROPAGATOR_BAGGAGE:
+        if (field != 0 || state == NULL) {
+            return false;
+        }
+        h->value_len = baggage_to_string(&state->bg, out);
+        if (h->value_len == 0) {
+            return false;
+        }
+        propagation_set_key(h, "baggage", 7);
+        return true;
+    }
+    return false;
+}
+
+#endif
@@ -5,13 +5,92 @@
 #define _SAMPLING_H_
 
 #include "common.h"
+#include "go_types.h"
 #include "span_context.h"
+#include "tracestate.h"
 
 #define MAX_SAMPLER_CONFIG_SIZE 256
 #define MAX_SAMPLERS 32
+#define MAX_SAMPLING_RULES 5
+#define SAMPLING_METHOD_SIZE 8
+#define SAMPLING_VALUE_SIZE 32
 
 typedef u32 sampler_id_t;
 
+// The kind of the probe-supplied attribute value used by rule-based sampling.
+enum sampling_attr_kind {
+    SAMPLING_ATTR_NONE = 0,
+    SAMPLING_ATTR_HTTP_PATH = 1,
+    SAMPLING_ATTR_RPC_METHOD = 2,
+    SAMPLING_ATTR_MESSAGING_DESTINATION = 3,
+    SAMPLING_ATTR_DB_OPERATION = 4,
+};
+
+enum sampling_match {
+    // The whole attribute value equals the rule value.
+    SAMPLING_MATCH_EXACT = 0,
+    // The attribute value starts with the rule value.
+    SAMPLING_MATCH_PREFIX = 1,
+    // The attribute value starts with the rule value, compared
+    // case-insensitively, followed by a non-word character. The rule value
+    // needs to be lowercase.
+    SAMPLING_MATCH_WORD = 2,
+};
+
+// Attributes supplied by a probe when starting a span. Only the first
+// SAMPLING_VALUE_SIZE bytes of the value are captured.
+typedef struct sampling_attributes {
+    u8 kind;
+    u8 value_len;
+    u8 value_truncated;
+    u8 method_len;
+    char method[SAMPLING_METHOD_SIZE];
+    char value[SAMPLING_VALUE_SIZE];
+} sampling_attributes_t;
+
+struct sampling_rule {
+    // The sampler used when the rule matches.
+    sampler_id_t delegate;
+    // The kind of attribute value matched, SAMPLING_ATTR_NONE matches any.
+    u8 kind;
+    u8 match;
+    u8 value_len;
+    // The length of the method matched, 0 matches any.
+    u8 method_len;
+    char method[SAMPLING_METHOD_SIZE];
+    char value[SAMPLING_VALUE_SIZE];
+};
+
+struct rule_based_config {
+    u32 rules_count;
+    // The sampler used when no rule matches.
+    sampler_id_t fallback;
+    struct sampling_rule rules[MAX_SAMPLING_RULES];
+};
+
+struct rate_limiting_config {
+    // The time, in nanoseconds, it takes to accrue the credit for one span.
+    // Zero means no span is sampled.
+    u64 interval_ns;
+    // The maximum credit, in nanoseconds, that can be accrued. This bounds
+    // the number of spans sampled in a burst.
+    u64 max_credit_ns;
+};
+
+// The token bucket state of a rate-limiting sampler. The tokens are stored
+// as the time credit accrued.
+struct rate_limiter_state {
+    u64 credit_ns;
+    u64 last_ns;
+};
+
+struct probability_config {
+    // The rejection threshold, a span is sampled if the randomness of its
+    // trace is greater than or equal to it. OT_MAX_THRESHOLD means no span is
+    // sampled.
+    u64 threshold;
+};
+
 struct parent_based_config {
     sampler_id_t root;
     sampler_id_t remote_parent_sampled;
@@ -27,7 +106,9 @@ enum sampler_type {
     TRACE_ID_RATIO = 2,
     PARENT_BASED = 3,
     // Custom samplers
-
+    RULE_BASED = 4,
+    RATE_LIMITING = 5,
+    PROBABILITY = 6,
 };
 
 struct sampling_config {
@@ -35,6 +116,9 @@ struct sampling_config {
     union {
         u64 sampling_rate_numerator;
         struct parent_based_config parent_based;
+        struct rule_based_config rule_based;
+        struct rate_limiting_config rate_limiting;
+        struct probability_config probability;
         char buf[MAX_SAMPLER_CONFIG_SIZE];
     } config_data;
 };
@@ -42,7 +126,11 @@ struct sampling_config {
 typedef struct sampling_parameters {
     struct span_context *psc;
     u8 *trace_id;
-    // TODO: add more fields
+    // Attributes of the span being started, may be NULL.
+    sampling_attributes_t *attrs;
+    // The "ot" tracestate values of the trace, updated by consistent
+    // probability samplers. May be NULL.
+    struct ot_trace_state *ots;
 } sampling_parameters_t;
 
 struct {
@@ -59,6 +147,16 @@ struct {
     __uint(max_entries, 1);
 } probe_active_sampler_map SEC(".maps");
 
+// The state of rate-limiting samplers, keyed by sampler ID. This is pinned so
+// all probes of the target process share the same rate limit.
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __type(key, sampler_id_t);
+    __type(value, struct rate_limiter_state);
+    __uint(max_entries, MAX_SAMPLERS);
+    __uint(pinning, LIBBPF_PIN_BY_NAME);
+} rate_limiter_map SEC(".maps");
+
 static const u8 FLAG_SAMPLED = 1;
 
 static __always_inline bool trace_flags_is_sampled(u8 flags) {
@@ -104,6 +202,243 @@ static __always_inline bool alwaysOffSampler_should_sample(struct sampling_confi
     return false;
 }
 
+// The rate-limiting sampler is a token bucket shared by all CPUs. The state is
+// updated without synchronization so concurrent decisions on different CPUs can
+// sample slightly more spans than the configured rate.
+static __always_inline bool rateLimitingSampler_should_sample(sampler_id_t sampler_id,
+                                                              struct sampling_config *config,
+                                                              sampling_parameters_t *params) {
+    struct rate_limiting_config *rl = &config->config_data.rate_limiting;
+    if (rl->interval_ns == 0) {
+        return false;
+    }
+
+    u64 now = bpf_ktime_get_ns();
+    struct rate_limiter_state *state = bpf_map_lookup_elem(&rate_limiter_map, &sampler_id);
+    if (state == NULL) {
+        // Start with a full bucket.
+        struct rate_limiter_state init = {
+            .credit_ns = rl->max_credit_ns,
+            .last_ns = now,
+        };
+        bpf_map_update_elem(&rate_limiter_map, &sampler_id, &init, BPF_NOEXIST);
+        state = bpf_map_lookup_elem(&rate_limiter_map, &sampler_id);
+        if (state == NULL) {
+            return false;
+        }
+    }
+
+    u64 credit = state->credit_ns;
+    u64 last = state->last_ns;
+    if (now > last) {
+        u64 elapsed = now - last;
+        credit = elapsed > rl->max_credit_ns ? rl->max_credit_ns : credit + elapsed;
+        state->last_ns = now;
+    }
+    if (credit > rl->max_credit_ns) {
+        credit = rl->max_credit_ns;
+    }
+
+    if (credit < rl->interval_ns) {
+        state->credit_ns = credit;
+        return false;
+    }
+    state->credit_ns = credit - rl->interval_ns;
+    return true;
+}
+
+// The consistent probability sampler compares the randomness of the trace
+// with the configured rejection threshold, as defined by the OpenTelemetry
+// probability sampling specification. The randomness is the rv value of the
+// tracestate, if present, or the least significant 56 bits of the trace ID.
+// The threshold used is recorded in the th value so the adjusted count of the
+// span can be computed.
+static __always_inline bool probabilitySampler_should_sample(struct sampling_config *config,
+                                                             sampling_parameters_t *params) {
+    u64 threshold = config->config_data.probability.threshold;
+    bool sample = false;
+    if (threshold < OT_MAX_THRESHOLD) {
+        u64 randomness = 0;
+        if (params->ots != NULL && params->ots->has_rv) {
+            randomness = params->ots->rv;
+        } else {
+            for (u32 i = TRACE_ID_SIZE - 7; i < TRACE_ID_SIZE; i++) {
+                randomness = (randomness << 8) | params->trace_id[i];
+            }
+        }
+        sample = (randomness & OT_RANDOMNESS_MASK) >= threshold;
+    }
+
+    if (params->ots != NULL) {
+        params->ots->th = sample ? threshold : 0;
+        params->ots->has_th = sample;
+    }
+    return sample;
+}
+
+// Read at most size bytes of the string str with length len into dst. The
+// number of bytes read is stored in dst_len and if str was longer in
+// truncated.
+static __always_inline void
+sampling_read_str(char *dst, u32 size, u8 *dst_len, u8 *truncated, void *str, s64 len) {
+    if (str == NULL || len <= 0) {
+        return;
+    }
+    u32 n = len > size ? size : (u32)len;
+    if (bpf_probe_read_user(dst, n, str) != 0) {
+        return;
+    }
+    *dst_len = n;
+    if (truncated != NULL) {
+        *truncated = len > size;
+    }
+}
+
+// Set the attribute value of kind to the string str with length len.
+static __always_inline void
+sampling_attrs_set_value(sampling_attributes_t *attrs, u8 kind, void *str, s64 len) {
+    attrs->kind = kind;
+    sampling_read_str(attrs->value,
+                      SAMPLING_VALUE_SIZE,
+                      &attrs->value_len,
+                      &attrs->value_truncated,
+                      str,
+                      len);
+}
+
+// Set the attribute value of kind to the Go string pointed to by go_str_ptr.
+static __always_inline void
+sampling_attrs_set_go_value(sampling_attributes_t *attrs, u8 kind, void *go_str_ptr) {
+    struct go_string str = {0};
+    if (go_str_ptr == NULL || bpf_probe_read_user(&str, sizeof(str), go_str_ptr) != 0) {
+        return;
+    }
+    sampling_attrs_set_value(attrs, kind, str.str, str.len);
+}
+
+// Set the method to the Go string pointed to by go_str_ptr. Methods longer
+// than SAMPLING_METHOD_SIZE are not set.
+static __always_inline void sampling_attrs_set_go_method(sampling_attributes_t *attrs,
+                                                         void *go_str_ptr) {
+    struct go_string str = {0};
+    if (go_str_ptr == NULL || bpf_probe_read_user(&str, sizeof(str), go_str_ptr) != 0) {
+        return;
+    }
+    if (str.len > SAMPLING_METHOD_SIZE) {
+        return;
+    }
+    sampling_read_str(
+        attrs->method, SAMPLING_METHOD_SIZE, &attrs->method_len, NULL, str.str, str.len);
+}
+
+static __always_inline bool is_word_char(char c) {
+    return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') ||
+           c == '_';
+}
+
+static __always_inline char to_lower(char c) {
+    if (c >= 'A' && c <= 'Z') {
+        return c + ('a' - 'A');
+    }
+    return c;
+}
+
+static __always_inline bool sampling_rule_matches(struct sampling_rule *rule,
+                                                  sampling_attributes_t *attrs) {
+    if (rule->method_len > 0) {
+        if (attrs == NULL || attrs->method_len != rule->method_len) {
+            return false;
+        }
+        for (u32 i = 0; i < SAMPLING_METHOD_SIZE; i++) {
+            if (i >= rule->method_len) {
+                break;
+            }
+            if (attrs->method[i] != rule->method[i]) {
+                return false;
+            }
+        }
+    }
+
+    if (rule->kind == SAMPLING_ATTR_NONE) {
+        return true;
+    }
+    if (attrs == NULL || attrs->kind != rule->kind || attrs->value_len < rule->value_len) {
+        return false;
+    }
+
+    bool fold = rule->match == SAMPLING_MATCH_WORD;
+    for (u32 i = 0; i < SAMPLING_VALUE_SIZE; i++) {
+        if (i >= rule->value_len) {
+            break;
+        }
+        char c = attrs->value[i];
+        if (fold) {
+            c = to_lower(c);
+        }
+        if (c != rule->value[i]) {
+            return false;
+        }
+    }
+
+    switch (rule->match) {
+    case SAMPLING_MATCH_EXACT:
+        return attrs->value_len == rule->value_len && !attrs->value_truncated;
+    case SAMPLING_MATCH_PREFIX:
+        return true;
+    case SAMPLING_MATCH_WORD:
+        if (attrs->value_len == rule->value_len) {
+            // The next character is unknown if the value was truncated.
+            return !attrs->value_truncated;
+        }
+        return !is_word_char(attrs->value[rule->value_len & (SAMPLING_VALUE_SIZE - 1)]);
+    default:
+        return false;
+    }
+}
+
+// Sample using the sampler with id. Only samplers that do not delegate to
+// other samplers are supported.
+static __always_inline bool base_sampler_should_sample(sampler_id_t sampler_id,
+                                                       sampling_parameters_t *params) {
+    struct sampling_config *config = bpf_map_lookup_elem(&samplers_config_map, &sampler_id);
+    if (config == NULL) {
+        bpf_printk("No sampler config found for delegate sampler\n");
+        return false;
+    }
+
+    switch (config->type) {
+    case ALWAYS_ON:
+        return alwaysOnSampler_should_sample(config, params);
+    case ALWAYS_OFF:
+        return alwaysOffSampler_should_sample(config, params);
+    case TRACE_ID_RATIO:
+        return traceIDRatioSampler_should_sample(config, params);
+    case RATE_LIMITING:
+        return rateLimitingSampler_should_sample(sampler_id, config, params);
+    case PROBABILITY:
+        return probabilitySampler_should_sample(config, params);
+    default:
+        bpf_printk("Unsupported delegate sampler type %d\n", config->type);
+        return false;
+    }
+}
+
+static __always_inline bool ruleBasedSampler_should_sample(struct sampling_config *config,
+                                                           sampling_parameters_t *params) {
+    struct rule_based_config *rb = &config->config_data.rule_based;
+    sampler_id_t sampler_id = rb->fallback;
+    for (u32 i = 0; i < MAX_SAMPLING_RULES; i++) {
+        if (i >= rb->rules_count) {
+            break;
+        }
+        if (sampling_rule_matches(&rb->rules[i], params->attrs)) {
+            sampler_id = rb->rules[i].delegate;
+            break;
+        }
+    }
+    return base_sampler_should_sample(sampler_id, params);
+}
+
 static __always_inline bool parentBasedSampler_should_sample(struct sampling_config *config,
                                                              sampling_parameters_t *params) {
     sampler_id_t sampler_id;
@@ -137,6 +472,12 @@ static __always_inline bool parentBasedSampler_should_sample(struct sampling_con
         return alwaysOffSampler_should_sample(base_config, params);
     case TRACE_ID_RATIO:
         return traceIDRatioSampler_should_sample(base_config, params);
+    case RULE_BASED:
+        return ruleBasedSampler_should_sample(base_config, params);
+    case RATE_LIMITING:
+        return rateLimitingSampler_should_sample(sampler_id, base_config, params);
+    case PROBABILITY:
+        return probabilitySampler_should_sample(base_config, params);
     default:
         return false;
     }
@@ -166,6 +507,12 @@ static __always_inline bool should_sample(sampling_parameters_t *params) {
         return traceIDRatioSampler_should_sample(config, params);
     case PARENT_BASED:
         return parentBasedSampler_should_sample(config, params);
+    case RULE_BASED:
+        return ruleBasedSampler_should_sample(config, params);
+    case RATE_LIMITING:
+        return rateLimitingSampler_should_sample(*active_sampler_id, config, params);
+    case PROBABILITY:
+        return probabilitySampler_should_sample(config, params);
     default:
         return false;
     }
@@ -11,15 +11,59 @@ struct {
     __uint(type, BPF_MAP_TYPE_PERF_EVENT_ARRAY);
 } events SEC(".maps");
 
-// Output a record to the perf buffer. If the span context is sampled, the record is outputted.
+struct span_output_config {
+    // The minimum duration, in nanoseconds, of the spans output. Shorter
+    // spans are dropped. Zero outputs all spans.
+    u64 min_duration_ns;
+};
+
+struct {
+    __uint(type, BPF_MAP_TYPE_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(struct span_output_config));
+    __uint(max_entries, 1);
+} span_output_config_map SEC(".maps");
+
+// The number of sampled spans dropped because they were shorter than the
+// minimum duration.
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(u64));
+    __uint(max_entries, 1);
+} dropped_spans_map SEC(".maps");
+
+// Returns true if a span that started at start_time and ended at end_time is
+// shorter than the configured minimum duration. The dropped span is counted.
+static __always_inline bool span_below_min_duration(u64 start_time, u64 end_time) {
+    u32 key = 0;
+    struct span_output_config *config = bpf_map_lookup_elem(&span_output_config_map, &key);
+    if (config == NULL || config->min_duration_ns == 0) {
+        return false;
+    }
+    if (end_time > start_time && end_time - start_time >= config->min_duration_ns) {
+        return false;
+    }
+
+    u64 *dropped = bpf_map_lookup_elem(&dropped_spans_map, &key);
+    if (dropped != NULL) {
+        *dropped += 1;
+    }
+    return true;
+}
+
+// Output a record to the perf buffer. If the span context is sampled, and the
+// span that started at start_time and ended at end_time is not shorter than
+// the configured minimum duration, the record is outputted. Only the output is
+// affected by the minimum duration, the span context is still propagated.
 // Returns 0 on success, negative error code on failure.
-static __always_inline long
-output_span_event(void *ctx, void *data, u64 size, struct span_context *sc) {
+static __always_inline long output_span_event(
+    void *ctx, void *data, u64 size, struct span_context *sc, u64 start_time, u64 end_time) {
     bool sampled = (sc != NULL && is_sampled(sc));
-    if (sampled) {
-        return bpf_perf_event_output(ctx, &events, BPF_F_CURRENT_CPU, data, size);
+    if (!sampled || span_below_min_duration(start_time, end_time)) {
+        return 0;
     }
-    return 0;
+    return bpf_perf_event_output(ctx, &events, BPF_F_CURRENT_CPU, data, size);
 }
 
 #endif
@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#ifndef _SPAN_STATE_H_
+#define _SPAN_STATE_H_
+
+#include "common.h"
+#include "tracestate.h"
+#include "baggage.h"
+
+// The state propagated with a span context, other than the span context
+// itself. It is extracted from incoming requests, inherited by the child
+// spans and injected in outgoing requests.
+struct span_state {
+    struct ot_trace_state ots;
+    struct trace_state ts;
+    struct baggage bg;
+};
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(struct span_state));
+    __uint(max_entries, 1);
+} span_state_storage_map SEC(".maps");
+
+// Returns a zeroed span state from the per-CPU storage, NULL on failure. It
+// is used by the probes without an event to hold the state of their spans.
+static __always_inline struct span_state *new_span_state() {
+    u32 map_id = 0;
+    struct span_state *state = bpf_map_lookup_elem(&span_state_storage_map, &map_id);
+    if (state == NULL) {
+        return NULL;
+    }
+    __builtin_memset(state, 0, sizeof(*state));
+    return state;
+}
+
+#endif
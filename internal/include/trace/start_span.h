@@ -7,6 +7,7 @@
 #include "common.h"
 #include "span_context.h"
 #include "sampling.h"
+#include "span_state.h"
 
 // function for getting the parent span context, the result is stored in the passed span context.
 // the function should return 0 if the parent span context is found, negative value otherwise.
@@ -14,33 +15,65 @@
 // this is useful for incoming requests (http, kafka, etc.) where the parent span context needs to be extracted from the
 // incoming request.
 // The handle param can be used to pass any data needed to get the parent span context.
-typedef long (*get_parent_sc_fn)(void *handle, struct span_context *psc);
+// The state of the parent span context is stored in the passed state, which is
+// zeroed by the caller if the function fails.
+typedef long (*get_parent_sc_fn)(void *handle,
+                                 struct span_context *psc,
+                                 struct span_state *state);
 
 typedef struct start_span_params {
     struct pt_regs *ctx;
     struct go_iface *go_context;
     struct span_context *psc;
     struct span_context *sc;
+    // the state of the new span, inherited from its parent and updated with
+    // the sampling decision, may be NULL.
+    struct span_state *state;
     // function for getting the parent span context, the result is stored in the passed span context.
     get_parent_sc_fn get_parent_span_context_fn;
     // argument to be passed to the get_parent_span_context_fn
     void *get_parent_span_context_arg;
+    // attributes of the span used by rule-based sampling, may be NULL.
+    sampling_attributes_t *sampling_attrs;
 } start_span_params_t;
 
 // Start a new span, setting the parent span context if found.
 // Generate a new span context for the new span. Perform sampling decision and set the TraceFlags accordingly.
 static __always_inline void start_span(start_span_params_t *params) {
+    struct span_state *state = params->state;
+    if (state == NULL) {
+        state = new_span_state();
+        if (state == NULL) {
+            return;
+        }
+    }
+
     long found_parent = -1;
+    bool found_state = false;
     if (params->get_parent_span_context_fn != NULL) {
-        found_parent =
-            params->get_parent_span_context_fn(params->get_parent_span_context_arg, params->psc);
+        found_parent = params->get_parent_span_context_fn(
+            params->get_parent_span_context_arg, params->psc, state);
+        found_state = found_parent == 0;
     } else {
         struct span_context *local_psc = get_parent_span_context(params->go_context);
+        if (local_psc == NULL && params->ctx != NULL) {
+            // The context does not hold a span, fall back to the span active
+            // on the goroutine or inherited from the goroutine starting it.
+            local_psc = get_goroutine_span_context((void *)GOROUTINE(params->ctx));
+        }
         if (local_psc != NULL) {
             found_parent = 0;
             *(params->psc) = *local_psc;
+            struct span_state *parent_state = get_span_state(params->psc);
+            if (parent_state != NULL) {
+                *state = *parent_state;
+                found_state = true;
+            }
         }
     }
+    if (!found_state) {
+        __builtin_memset(state, 0, sizeof(*state));
+    }
 
     u8 parent_trace_flags = 0;
     if (found_parent == 0) {
@@ -53,12 +86,17 @@ static __always_inline void start_span(start_span_params_t *params) {
     sampling_parameters_t sampling_params = {
         .trace_id = params->sc->TraceID,
         .psc = (found_parent == 0) ? params->psc : NULL,
+        .attrs = params->sampling_attrs,
+        .ots = &state->ots,
     };
     bool sample = should_sample(&sampling_params);
     if (sample) {
         params->sc->TraceFlags = (parent_trace_flags) | (FLAG_SAMPLED);
     } else {
         params->sc->TraceFlags = (parent_trace_flags) & (~FLAG_SAMPLED);
+        // The threshold is only propagated with sampled spans.
+        state->ots.th = 0;
+        state->ots.has_th = 0;
     }
 }
 
@@ -0,0 +1,312 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#ifndef _TRACESTATE_H_
+#define _TRACESTATE_H_
+
+#include "common.h"
+#include "utils.h"
+#include "span_context.h"
+
+// The maximum number of bytes of a tracestate header value read. Needs to be
+// a power of 2.
+#define TRACESTATE_MAX_LEN 128
+
+// The number of hex digits of the th and rv values, the values are 56 bits.
+#define OT_VALUE_DIGITS 14
+// The maximum threshold, no span is sampled with this threshold. It is not
+// representable in tracestate.
+#define OT_MAX_THRESHOLD (1ULL << 56)
+#define OT_RANDOMNESS_MASK (OT_MAX_THRESHOLD - 1)
+
+// The "ot=th:XXXXXXXXXXXXXX;rv:XXXXXXXXXXXXXX" tracestate entry.
+#define OT_ENTRY_PREFIX_LEN 3
+#define OT_VALUE_LEN (3 + OT_VALUE_DIGITS)
+#define OT_TRACESTATE_MAX_LEN (OT_ENTRY_PREFIX_LEN + OT_VALUE_LEN + 1 + OT_VALUE_LEN)
+// The size of the buffers tracestate header values are formatted into. It
+// holds the "ot" entry followed by the other stored entries. Needs to be a
+// power of 2.
+#define TRACESTATE_BUF_SIZE 256
+
+// The values of the OpenTelemetry "ot" tracestate entry used by consistent
+// probability sampling.
+struct ot_trace_state {
+    // The rejection threshold, a span is sampled if its randomness is
+    // greater than or equal to it.
+    u64 th;
+    // The explicit randomness of the trace.
+    u64 rv;
+    u8 has_th;
+    u8 has_rv;
+    u8 padding[6];
+};
+
+// The tracestate list members of a span context other than the "ot" one,
+// which is kept in a struct ot_trace_state. The members are comma separated.
+struct trace_state {
+    u32 len;
+    u8 padding[4];
+    char entries[TRACESTATE_MAX_LEN];
+};
+
+enum ot_parse_state {
+    OT_PARSE_ENTRY = 0,
+    OT_PARSE_SKIP_ENTRY = 1,
+    OT_PARSE_SUBKEY = 2,
+    OT_PARSE_SKIP_SUBKEY = 3,
+    OT_PARSE_TH = 4,
+    OT_PARSE_RV = 5,
+};
+
+static __always_inline s8 hex_char_to_nibble(char c) {
+    if (c >= '0' && c <= '9') {
+        return c - '0';
+    }
+    if (c >= 'a' && c <= 'f') {
+        return c - 'a' + 10;
+    }
+    return -1;
+}
+
+static __always_inline void
+ot_trace_state_set(struct ot_trace_state *ots, u8 state, u64 value, u32 digits) {
+    if (digits == 0 || digits > OT_VALUE_DIGITS) {
+        return;
+    }
+    if (state == OT_PARSE_TH) {
+        // Trailing zeros can be omitted from the threshold.
+        ots->th = value << (4 * (OT_VALUE_DIGITS - digits));
+        ots->has_th = 1;
+    } else if (state == OT_PARSE_RV && digits == OT_VALUE_DIGITS) {
+        ots->rv = value;
+        ots->has_rv = 1;
+    }
+}
+
+// Parse the th and rv values of the "ot" entry of the tracestate header value
+// buf of length len into ots. Invalid values are ignored.
+static __always_inline void
+parse_ot_trace_state(char *buf, u32 len, struct ot_trace_state *ots) {
+    u8 state = OT_PARSE_ENTRY;
+    u64 value = 0;
+    u32 digits = 0;
+    u32 skip = 0;
+    for (u32 i = 0; i < TRACESTATE_MAX_LEN; i++) {
+        if (i >= len) {
+            break;
+        }
+        if (skip > 0) {
+            skip--;
+            continue;
+        }
+        char c = buf[i];
+        char c1 = (i + 1 < len) ? buf[(i + 1) & (TRACESTATE_MAX_LEN - 1)] : 0;
+        char c2 = (i + 2 < len) ? buf[(i + 2) & (TRACESTATE_MAX_LEN - 1)] : 0;
+        switch (state) {
+        case OT_PARSE_ENTRY:
+            if (c == ' ' || c == '\t') {
+                break;
+            }
+            if (c == 'o' && c1 == 't' && c2 == '=') {
+                state = OT_PARSE_SUBKEY;
+                skip = 2;
+            } else {
+                state = OT_PARSE_SKIP_ENTRY;
+            }
+            break;
+        case OT_PARSE_SKIP_ENTRY:
+            if (c == ',') {
+                state = OT_PARSE_ENTRY;
+            }
+            break;
+        case OT_PARSE_SUBKEY:
+            if (c == ',') {
+                return;
+            }
+            if (c == 't' && c1 == 'h' && c2 == ':') {
+                state = OT_PARSE_TH;
+            } else if (c == 'r' && c1 == 'v' && c2 == ':') {
+                state = OT_PARSE_RV;
+            } else {
+                state = OT_PARSE_SKIP_SUBKEY;
+                break;
+            }
+            value = 0;
+            digits = 0;
+            skip = 2;
+            break;
+        case OT_PARSE_SKIP_SUBKEY:
+            if (c == ';') {
+                state = OT_PARSE_SUBKEY;
+            } else if (c == ',') {
+                return;
+            }
+            break;
+        case OT_PARSE_TH:
+        case OT_PARSE_RV:
+            if (c == ';' || c == ',') {
+                ot_trace_state_set(ots, state, value, digits);
+                if (c == ',') {
+                    return;
+                }
+                state = OT_PARSE_SUBKEY;
+                break;
+            }
+            s8 nibble = hex_char_to_nibble(c);
+            if (nibble < 0 || digits >= OT_VALUE_DIGITS) {
+                state = OT_PARSE_SKIP_SUBKEY;
+                break;
+            }
+            value = (value << 4) | (u64)nibble;
+            digits++;
+            break;
+        }
+    }
+    ot_trace_state_set(ots, state, value, digits);
+}
+
+enum trace_state_parse_state {
+    TRACE_STATE_MEMBER_START = 0,
+    TRACE_STATE_MEMBER_COPY = 1,
+    TRACE_STATE_MEMBER_SKIP = 2,
+};
+
+// Copy the list members of the tracestate header value buf of length len,
+// other than the "ot" one, into ts. The whitespace around members is removed
+// and members that do not fit are dropped. If truncated is true, buf is only
+// the start of the value and its last member is dropped.
+static __always_inline void
+parse_trace_state_entries(char *buf, u32 len, bool truncated, struct trace_state *ts) {
+    u8 state = TRACE_STATE_MEMBER_START;
+    // The length of the entries written.
+    u32 out = 0;
+    // The length of the entries before the current member and its separator.
+    u32 member_start = 0;
+    // The length of the entries up to the last non whitespace character of
+    // the current member.
+    u32 member_end = 0;
+    for (u32 i = 0; i < TRACESTATE_MAX_LEN; i++) {
+        if (i >= len) {
+            break;
+        }
+        char c = buf[i];
+        if (c == ',') {
+            if (state == TRACE_STATE_MEMBER_COPY) {
+                out = member_end;
+            }
+            state = TRACE_STATE_MEMBER_START;
+            continue;
+        }
+        if (state == TRACE_STATE_MEMBER_SKIP) {
+            continue;
+        }
+        if (state == TRACE_STATE_MEMBER_START) {
+            if (c == ' ' || c == '\t') {
+                continue;
+            }
+            char c1 = (i + 1 < len) ? buf[(i + 1) & (TRACESTATE_MAX_LEN - 1)] : 0;
+            char c2 = (i + 2 < len) ? buf[(i + 2) & (TRACESTATE_MAX_LEN - 1)] : 0;
+            if (c == 'o' && c1 == 't' && c2 == '=') {
+                state = TRACE_STATE_MEMBER_SKIP;
+                continue;
+            }
+            member_start = out;
+            if (out > 0 && out < TRACESTATE_MAX_LEN) {
+                ts->entries[out & (TRACESTATE_MAX_LEN - 1)] = ',';
+                out++;
+            }
+            state = TRACE_STATE_MEMBER_COPY;
+        }
+        if (out >= TRACESTATE_MAX_LEN) {
+            out = member_start;
+            state = TRACE_STATE_MEMBER_SKIP;
+            continue;
+        }
+        ts->entries[out & (TRACESTATE_MAX_LEN - 1)] = c;
+        out++;
+        if (c != ' ' && c != '\t') {
+            member_end = out;
+        }
+    }
+    if (state == TRACE_STATE_MEMBER_COPY) {
+        out = truncated ? member_start : member_end;
+    }
+    ts->len = out;
+}
+
+static __always_inline void u56_to_hex_string(u64 value, char *out) {
+    for (u32 i = 0; i < OT_VALUE_DIGITS; i++) {
+        out[OT_VALUE_DIGITS - 1 - i] = hex[value & 0xF];
+        value >>= 4;
+    }
+}
+
+// Returns the number of digits of the threshold th without its trailing
+// zeros, at least 1.
+static __always_inline u32 ot_threshold_digits(u64 th) {
+    u32 digits = OT_VALUE_DIGITS;
+    for (u32 i = 0; i < OT_VALUE_DIGITS - 1; i++) {
+        if ((th & 0xF) != 0) {
+            break;
+        }
+        th >>= 4;
+        digits--;
+    }
+    return digits;
+}
+
+// Format the "ot" tracestate entry of ots into out, which needs to be at
+// least OT_TRACESTATE_MAX_LEN bytes. The threshold is written without its
+// trailing zeros, as in user space, and the randomness with all its digits.
+// Returns the length of the entry, 0 if there is nothing to propagate.
+static __always_inline u32 ot_trace_state_to_string(struct ot_trace_state *ots, char *out) {
+    if (!ots->has_th && !ots->has_rv) {
+        return 0;
+    }
+    u32 n = 0;
+    out[n++] = 'o';
+    out[n++] = 't';
+    out[n++] = '=';
+    if (ots->has_th) {
+        out[n++] = 't';
+        out[n++] = 'h';
+        out[n++] = ':';
+        u56_to_hex_string(ots->th, &out[n]);
+        n += ot_threshold_digits(ots->th);
+    }
+    if (ots->has_rv) {
+        if (ots->has_th) {
+            out[n++] = ';';
+        }
+        out[n++] = 'r';
+        out[n++] = 'v';
+        out[n++] = ':';
+        u56_to_hex_string(ots->rv, &out[n]);
+        n += OT_VALUE_DIGITS;
+    }
+    return n;
+}
+
+// Format the tracestate header value of ots and ts into out, which needs to
+// be TRACESTATE_BUF_SIZE bytes. The "ot" entry is first, followed by the
+// list members of ts. Returns the length of the value, 0 if there is no
+// tracestate to propagate.
+static __always_inline u32
+trace_state_to_string(struct ot_trace_state *ots, struct trace_state *ts, char *out) {
+    u32 n = ot_trace_state_to_string(ots, out);
+    if (ts->len == 0 || ts->len > TRACESTATE_MAX_LEN) {
+        return n;
+    }
+    if (n > 0) {
+        out[n++] = ',';
+    }
+    for (u32 i = 0; i < TRACESTATE_MAX_LEN; i++) {
+        if (i >= ts->len) {
+            break;
+        }
+        out[(n + i) & (TRACESTATE_BUF_SIZE - 1)] = ts->entries[i];
+    }
+    return n + ts->len;
+}
+
+#endif
@@ -14,7 +14,8 @@
     u64 start_time;                                                                                \
     u64 end_time;                                                                                  \
     struct span_context sc;                                                                        \
-    struct span_context psc;
+    struct span_context psc;                                                                       \
+    struct span_state state;
 
 // Common flow for uprobe return:
 // 1. Find consistent key for the current uprobe context
@@ -33,7 +34,8 @@
             return 0;                                                                              \
         }                                                                                          \
         event->end_time = bpf_ktime_get_ns();                                                      \
-        output_span_event(ctx, event, sizeof(event_type), &event->sc);                             \
+        output_span_event(                                                                         \
+            ctx, event, sizeof(event_type), &event->sc, event->start_time, event->end_time);       \
         stop_tracking_span(&event->sc, &event->psc);                                               \
         bpf_map_delete_elem(&uprobe_context_map, &key);                                            \
         return 0;                                                                                  \
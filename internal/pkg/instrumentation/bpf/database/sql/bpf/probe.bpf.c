@@ -5,6 +5,7 @@
 #include "trace/span_context.h"
 #include "go_context.h"
 #include "go_types.h"
+#include "go_errors.h"
 #include "uprobe.h"
 #include "trace/start_span.h"
 
@@ -13,9 +14,75 @@ char __license[] SEC("license") = "Dual MIT/GPL";
 #define MAX_QUERY_SIZE 256
 #define MAX_CONCURRENT 50
 
+// The database/sql operations of spans.
+#define SQL_OP_QUERY 0
+#define SQL_OP_EXEC 1
+#define SQL_OP_PREPARE 2
+#define SQL_OP_BEGIN 3
+#define SQL_OP_COMMIT 4
+#define SQL_OP_ROLLBACK 5
+#define SQL_OP_PING 6
+#define SQL_OP_RAW 7
+
+// The database systems identified from the type of the driver. They need to
+// match the ones of the dbSystem type in user space.
+#define DB_SYSTEM_UNKNOWN 0
+#define DB_SYSTEM_POSTGRESQL 1
+#define DB_SYSTEM_MYSQL 2
+#define DB_SYSTEM_SQLITE 3
+
+#define MAX_DRIVER_NAME_SIZE 32
+#define MAX_DSN_SIZE 256
+
+// The kinds of the driver errors whose fields are read. They need to match
+// the ones of the driverErrorKind type in user space.
+#define SQL_ERROR_PQ 1        // *github.com/lib/pq.Error
+#define SQL_ERROR_PGCONN_V4 2 // *github.com/jackc/pgconn.PgError
+#define SQL_ERROR_PGCONN_V5 3 // *github.com/jackc/pgx/v5/pgconn.PgError
+#define SQL_ERROR_MYSQL 4     // *github.com/go-sql-driver/mysql.MySQLError
+
+#define SQL_ERROR_CODE_SIZE 8
+#define SQL_ERROR_SEVERITY_SIZE 16
+#define SQL_ERROR_MSG_SIZE 128
+
+// The arguments of the sql.Open call that returned a *DB. The data source
+// name is read in full, credentials included. It is replaced in user space by
+// one only holding the address of the server and the name of the database
+// before the event is recorded or processed.
+struct sql_db_t {
+    char driver_name[MAX_DRIVER_NAME_SIZE];
+    char dsn[MAX_DSN_SIZE];
+    // The length of the data source name, which is truncated if greater than
+    // MAX_DSN_SIZE.
+    u32 dsn_len;
+    u8 padding[4];
+};
+
+// The fields of an error returned by a driver that are needed to rebuild its
+// message and to record its status code.
+struct sql_error_t {
+    // The SQLSTATE code of the error.
+    char code[SQL_ERROR_CODE_SIZE];
+    char severity[SQL_ERROR_SEVERITY_SIZE];
+    char msg[SQL_ERROR_MSG_SIZE];
+    // The MySQL error number.
+    u16 number;
+    // The kind of the error, 0 if it is not a known driver error.
+    u8 kind;
+    u8 padding[5];
+};
+
 struct sql_request_t {
     BASE_SPAN_PROPERTIES
     char query[MAX_QUERY_SIZE];
+    // The time spent waiting for a connection from the pool, in nanoseconds.
+    u64 conn_wait;
+    u8 operation;
+    u8 db_system;
+    u8 padding[6];
+    struct sql_db_t db;
+    struct go_error error;
+    struct sql_error_t driver_error;
 };
 
 struct {
@@ -25,91 +92,557 @@ struct {
     __uint(max_entries, MAX_CONCURRENT);
 } sql_events SEC(".maps");
 
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(struct sql_request_t));
+    __uint(max_entries, 1);
+} sql_uprobe_storage_map SEC(".maps");
+
+// The arguments of the sql.Open calls in progress, keyed by goroutine.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, struct sql_db_t);
+    __uint(max_entries, MAX_CONCURRENT);
+} sql_open_args SEC(".maps");
+
+// The arguments of the sql.Open calls, keyed by the returned *DB.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, struct sql_db_t);
+    __uint(max_entries, MAX_CONCURRENT);
+} sql_dbs SEC(".maps");
+
+// The start time of the (*DB).conn calls in progress, keyed by goroutine.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, u64);
+    __uint(max_entries, MAX_CONCURRENT);
+} sql_conn_start SEC(".maps");
+
+// The time spent in (*DB).conn by goroutines without a span in progress,
+// recorded by the next span they start. The connection is acquired before the
+// operation using it when it is not made with a prepared statement.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, u64);
+    __uint(max_entries, MAX_CONCURRENT);
+} sql_conn_wait SEC(".maps");
+
 // Injected in init
 volatile const bool should_include_db_statement;
+volatile const u64 stmt_query_pos;
+volatile const u64 stmt_db_pos;
+volatile const u64 tx_db_pos;
+volatile const u64 conn_db_pos;
+volatile const u64 db_connector_pos;
+volatile const u64 dsn_connector_driver_pos;
 
-// This instrumentation attaches uprobe to the following function:
-// func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn func(error), query string, args []any)
-SEC("uprobe/queryDC")
-int uprobe_queryDC(struct pt_regs *ctx) {
-    // argument positions
-    u64 context_ptr_pos = 3;
-    u64 query_str_ptr_pos = 8;
-    u64 query_str_len_pos = 9;
+// The addresses of the itabs of the driver.Connector types of the supported
+// drivers, and of the driver.Driver types of the ones opened with a
+// dsnConnector. 0 if the type is not used by the target binary.
+volatile const u64 dsn_connector_itab;
+volatile const u64 pq_connector_itab;
+volatile const u64 pgx_v4_connector_itab;
+volatile const u64 pgx_v4_driver_connector_itab;
+volatile const u64 pgx_v5_connector_itab;
+volatile const u64 pgx_v5_driver_connector_itab;
+volatile const u64 mysql_connector_itab;
+volatile const u64 sqlite3_driver_itab;
+volatile const u64 modernc_sqlite_driver_itab;
+
+// The addresses of the itabs of the error types of the supported drivers,
+// and the offsets of their fields. The offsets are 0 if the type is not used
+// by the target binary or if they are not known. The message is never the
+// first field of these types.
+volatile const u64 pq_error_itab;
+volatile const u64 pq_error_severity_pos;
+volatile const u64 pq_error_code_pos;
+volatile const u64 pq_error_msg_pos;
+volatile const u64 pgconn_v4_error_itab;
+volatile const u64 pgconn_v4_error_severity_pos;
+volatile const u64 pgconn_v4_error_code_pos;
+volatile const u64 pgconn_v4_error_msg_pos;
+volatile const u64 pgconn_v5_error_itab;
+volatile const u64 pgconn_v5_error_severity_pos;
+volatile const u64 pgconn_v5_error_code_pos;
+volatile const u64 pgconn_v5_error_msg_pos;
+volatile const u64 mysql_error_itab;
+volatile const u64 mysql_error_number_pos;
+volatile const u64 mysql_error_sqlstate_pos;
+volatile const u64 mysql_error_msg_pos;
+
+// Returns the database system of the driver behind the connector of db.
+static __always_inline u8 get_db_system(void *db) {
+    if (db == NULL) {
+        return DB_SYSTEM_UNKNOWN;
+    }
+
+    struct go_iface connector = {0};
+    bpf_probe_read_user(&connector, sizeof(connector), (void *)(db + db_connector_pos));
+    u64 itab = (u64)connector.type;
+    if (itab == 0) {
+        return DB_SYSTEM_UNKNOWN;
+    }
+
+    if (itab == pq_connector_itab || itab == pgx_v4_connector_itab ||
+        itab == pgx_v4_driver_connector_itab || itab == pgx_v5_connector_itab ||
+        itab == pgx_v5_driver_connector_itab) {
+        return DB_SYSTEM_POSTGRESQL;
+    }
+    if (itab == mysql_connector_itab) {
+        return DB_SYSTEM_MYSQL;
+    }
+    if (itab != dsn_connector_itab || connector.data == NULL) {
+        return DB_SYSTEM_UNKNOWN;
+    }
+
+    // The driver does not implement driver.DriverContext.
+    u64 driver_itab = 0;
+    bpf_probe_read_user(
+        &driver_itab, sizeof(driver_itab), (void *)(connector.data + dsn_connector_driver_pos));
+    if (driver_itab == 0) {
+        return DB_SYSTEM_UNKNOWN;
+    }
+    if (driver_itab == sqlite3_driver_itab || driver_itab == modernc_sqlite_driver_itab) {
+        return DB_SYSTEM_SQLITE;
+    }
+    return DB_SYSTEM_UNKNOWN;
+}
 
-    struct sql_request_t sql_request = {0};
-    sql_request.start_time = bpf_ktime_get_ns();
+// Returns the *DB field at offset of the value at ptr.
+static __always_inline void *get_db(void *ptr, u64 offset) {
+    void *db = NULL;
+    bpf_probe_read_user(&db, sizeof(db), (void *)(ptr + offset));
+    return db;
+}
+
+// Start the span of an operation made with db by the goroutine of ctx. The
+// statement of the operation is the query_len bytes at query_ptr, if any.
+static __always_inline int start_sql_span(struct pt_regs *ctx,
+                                          u8 operation,
+                                          void *db,
+                                          struct go_iface *go_context,
+                                          void *query_ptr,
+                                          u64 query_len) {
+    u32 map_id = 0;
+    struct sql_request_t *sql_request = bpf_map_lookup_elem(&sql_uprobe_storage_map, &map_id);
+    if (sql_request == NULL) {
+        bpf_printk("start_sql_span: sql_request is NULL");
+        return 0;
+    }
+
+    __builtin_memset(sql_request, 0, sizeof(struct sql_request_t));
+    sql_request->start_time = bpf_ktime_get_ns();
+    sql_request->operation = operation;
+    sql_request->db_system = get_db_system(db);
+
+    struct sql_db_t *db_info = bpf_map_lookup_elem(&sql_dbs, &db);
+    if (db_info != NULL) {
+        sql_request->db = *db_info;
+    }
 
-    if (should_include_db_statement) {
+    if (should_include_db_statement && query_ptr != NULL) {
         // Read Query string
-        void *query_str_ptr = get_argument(ctx, query_str_ptr_pos);
-        u64 query_str_len = (u64)get_argument(ctx, query_str_len_pos);
-        u64 query_size = MAX_QUERY_SIZE < query_str_len ? MAX_QUERY_SIZE : query_str_len;
-        bpf_probe_read(sql_request.query, query_size, query_str_ptr);
+        u64 query_size = MAX_QUERY_SIZE < query_len ? MAX_QUERY_SIZE : query_len;
+        bpf_probe_read(sql_request->query, query_size, query_ptr);
+    }
+
+    sampling_attributes_t sampling_attrs = {0};
+    sampling_attributes_t *sampling_attrs_ptr = NULL;
+    if (query_ptr != NULL) {
+        sampling_attrs_set_value(
+            &sampling_attrs, SAMPLING_ATTR_DB_OPERATION, query_ptr, (s64)query_len);
+        sampling_attrs_ptr = &sampling_attrs;
     }
 
-    struct go_iface go_context = {0};
-    get_Go_context(ctx, 2, 0, true, &go_context);
     start_span_params_t start_span_params = {
         .ctx = ctx,
-        .go_context = &go_context,
-        .psc = &sql_request.psc,
-        .sc = &sql_request.sc,
+        .go_context = go_context,
+        .psc = &sql_request->psc,
+        .sc = &sql_request->sc,
+        .state = &sql_request->state,
         .get_parent_span_context_fn = NULL,
         .get_parent_span_context_arg = NULL,
+        .sampling_attrs = sampling_attrs_ptr,
     };
     start_span(&start_span_params);
 
     // Get key
     void *key = (void *)GOROUTINE(ctx);
 
-    bpf_map_update_elem(&sql_events, &key, &sql_request, 0);
+    u64 *conn_wait = bpf_map_lookup_elem(&sql_conn_wait, &key);
+    if (conn_wait != NULL) {
+        sql_request->conn_wait = *conn_wait;
+        bpf_map_delete_elem(&sql_conn_wait, &key);
+    }
+
+    bpf_map_update_elem(&sql_events, &key, sql_request, 0);
+    return 0;
+}
+
+// Start the span of an operation made with the *DB receiver, whose statement
+// is the Go string at query_str_ptr_pos and query_str_len_pos, and context at
+// position 2.
+static __always_inline int
+start_sql_dc_span(struct pt_regs *ctx, u8 operation, int query_str_ptr_pos, int query_str_len_pos) {
+    struct go_iface go_context = {0};
+    get_Go_context(ctx, 2, 0, true, &go_context);
+    return start_sql_span(ctx,
+                          operation,
+                          get_argument(ctx, 1),
+                          &go_context,
+                          get_argument(ctx, query_str_ptr_pos),
+                          (u64)get_argument(ctx, query_str_len_pos));
+}
+
+// Start the span of an operation made with the *Stmt receiver, with its
+// context at position 2.
+static __always_inline int start_sql_stmt_span(struct pt_regs *ctx, u8 operation) {
+    void *stmt_ptr = get_argument(ctx, 1);
+    struct go_string query = {0};
+    bpf_probe_read_user(&query, sizeof(query), (void *)(stmt_ptr + stmt_query_pos));
+
+    struct go_iface go_context = {0};
+    get_Go_context(ctx, 2, 0, true, &go_context);
+    return start_sql_span(ctx,
+                          operation,
+                          get_db(stmt_ptr, stmt_db_pos),
+                          &go_context,
+                          query.str,
+                          (u64)query.len);
+}
+
+// Read the PostgreSQL error with the data pointer and the offsets of its
+// fields into err.
+static __always_inline void read_pg_error(void *data,
+                                          u64 severity_pos,
+                                          u64 code_pos,
+                                          u64 msg_pos,
+                                          struct sql_error_t *err) {
+    get_go_string_from_user_ptr(data + severity_pos, err->severity, sizeof(err->severity));
+    get_go_string_from_user_ptr(data + code_pos, err->code, sizeof(err->code));
+    get_go_string_from_user_ptr(data + msg_pos, err->msg, sizeof(err->msg));
+}
+
+// Read the error with the itab and the data pointer into err if it is of the
+// error type of a supported driver. Returns false if it is not, or if the
+// offsets of the fields of the type are not known.
+static __always_inline bool read_sql_error(void *itab, void *data, struct sql_error_t *err) {
+    if (itab == NULL || data == NULL) {
+        return false;
+    }
+
+    u64 itab_addr = (u64)itab;
+    if (pq_error_itab != 0 && itab_addr == pq_error_itab) {
+        if (pq_error_msg_pos == 0) {
+            return false;
+        }
+        err->kind = SQL_ERROR_PQ;
+        read_pg_error(data, pq_error_severity_pos, pq_error_code_pos, pq_error_msg_pos, err);
+        return true;
+    }
+    if (pgconn_v4_error_itab != 0 && itab_addr == pgconn_v4_error_itab) {
+        if (pgconn_v4_error_msg_pos == 0) {
+            return false;
+        }
+        err->kind = SQL_ERROR_PGCONN_V4;
+        read_pg_error(data,
+                      pgconn_v4_error_severity_pos,
+                      pgconn_v4_error_code_pos,
+                      pgconn_v4_error_msg_pos,
+                      err);
+        return true;
+    }
+    if (pgconn_v5_error_itab != 0 && itab_addr == pgconn_v5_error_itab) {
+        if (pgconn_v5_error_msg_pos == 0) {
+            return false;
+        }
+        err->kind = SQL_ERROR_PGCONN_V5;
+        read_pg_error(data,
+                      pgconn_v5_error_severity_pos,
+                      pgconn_v5_error_code_pos,
+                      pgconn_v5_error_msg_pos,
+                      err);
+        return true;
+    }
+    if (mysql_error_itab != 0 && itab_addr == mysql_error_itab) {
+        if (mysql_error_msg_pos == 0) {
+            return false;
+        }
+        err->kind = SQL_ERROR_MYSQL;
+        bpf_probe_read_user(&err->number, sizeof(err->number), data + mysql_error_number_pos);
+        // The SQLSTATE is a [5]byte, only present since v1.7.0.
+        if (mysql_error_sqlstate_pos != 0) {
+            bpf_probe_read_user(err->code, 5, data + mysql_error_sqlstate_pos);
+        }
+        get_go_string_from_user_ptr(data + mysql_error_msg_pos, err->msg, sizeof(err->msg));
+        return true;
+    }
+    return false;
+}
+
+// Ends the span of the goroutine, recording the error returned at the
+// error_pos argument of the return probe.
+static __always_inline int end_sql_span(struct pt_regs *ctx, int error_pos) {
+    void *key = (void *)GOROUTINE(ctx);
+    struct sql_request_t *event = bpf_map_lookup_elem(&sql_events, &key);
+    if (event == NULL) {
+        bpf_printk("event is NULL in ret probe");
+        return 0;
+    }
+    event->end_time = bpf_ktime_get_ns();
+
+    void *err_itab = get_argument(ctx, error_pos);
+    void *err_data = get_argument(ctx, error_pos + 1);
+    if (read_sql_error(err_itab, err_data, &event->driver_error)) {
+        // The message is rebuilt from the fields of the driver error.
+        event->error.kinds[0] = GO_ERROR_OTHER;
+    } else {
+        read_go_error(err_itab, err_data, &event->error);
+    }
+
+    output_span_event(ctx, event, sizeof(*event), &event->sc, event->start_time, event->end_time);
+    stop_tracking_span(&event->sc, &event->psc);
+    bpf_map_delete_elem(&sql_events, &key);
     return 0;
 }
 
+// Defines the return probe of the name instrumented function, which returns
+// an error at the error_pos argument.
+#define SQL_UPROBE_RETURN(name, error_pos)                                                         \
+    SEC("uprobe/##name##")                                                                         \
+    int uprobe_##name##_Returns(struct pt_regs *ctx) { return end_sql_span(ctx, error_pos); }
+
+// This instrumentation attaches uprobe to the following function:
+// func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn func(error), query string, args []any)
+SEC("uprobe/queryDC")
+int uprobe_queryDC(struct pt_regs *ctx) {
+    return start_sql_dc_span(ctx, SQL_OP_QUERY, 8, 9);
+}
+
 // This instrumentation attaches uprobe to the following function:
 // func (db *DB) queryDC(ctx, txctx context.Context, dc *driverConn, releaseConn func(error), query string, args []any)
-UPROBE_RETURN(queryDC, struct sql_request_t, sql_events)
+SQL_UPROBE_RETURN(queryDC, 2)
 
 // This instrumentation attaches uprobe to the following function:
 // func (db *DB) execDC(ctx context.Context, dc *driverConn, release func(error), query string, args []any)
 SEC("uprobe/execDC")
 int uprobe_execDC(struct pt_regs *ctx) {
-    // argument positions
-    u64 context_ptr_pos = 3;
-    u64 query_str_ptr_pos = 6;
-    u64 query_str_len_pos = 7;
+    return start_sql_dc_span(ctx, SQL_OP_EXEC, 6, 7);
+}
 
-    struct sql_request_t sql_request = {0};
-    sql_request.start_time = bpf_ktime_get_ns();
+// This instrumentation attaches uprobe to the following function:
+// func (db *DB) execDC(ctx context.Context, dc *driverConn, release func(error), query string, args []any)
+SQL_UPROBE_RETURN(execDC, 3)
 
-    if (should_include_db_statement) {
-        // Read Query string
-        void *query_str_ptr = get_argument(ctx, query_str_ptr_pos);
-        u64 query_str_len = (u64)get_argument(ctx, query_str_len_pos);
-        u64 query_size = MAX_QUERY_SIZE < query_str_len ? MAX_QUERY_SIZE : query_str_len;
-        bpf_probe_read(sql_request.query, query_size, query_str_ptr);
-    }
+// This instrumentation attaches uprobe to the following function:
+// func (db *DB) prepareDC(ctx context.Context, dc *driverConn, release func(error), cg stmtConnGrabber, query string) (*Stmt, error)
+SEC("uprobe/prepareDC")
+int uprobe_prepareDC(struct pt_regs *ctx) {
+    return start_sql_dc_span(ctx, SQL_OP_PREPARE, 8, 9);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (db *DB) prepareDC(ctx context.Context, dc *driverConn, release func(error), cg stmtConnGrabber, query string) (*Stmt, error)
+SQL_UPROBE_RETURN(prepareDC, 2)
 
+// This instrumentation attaches uprobe to the following function:
+// func (s *Stmt) ExecContext(ctx context.Context, args ...any) (Result, error)
+SEC("uprobe/Stmt_ExecContext")
+int uprobe_Stmt_ExecContext(struct pt_regs *ctx) {
+    return start_sql_stmt_span(ctx, SQL_OP_EXEC);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (s *Stmt) ExecContext(ctx context.Context, args ...any) (Result, error)
+SQL_UPROBE_RETURN(Stmt_ExecContext, 3)
+
+// This instrumentation attaches uprobe to the following function:
+// func (s *Stmt) QueryContext(ctx context.Context, args ...any) (*Rows, error)
+SEC("uprobe/Stmt_QueryContext")
+int uprobe_Stmt_QueryContext(struct pt_regs *ctx) {
+    return start_sql_stmt_span(ctx, SQL_OP_QUERY);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (s *Stmt) QueryContext(ctx context.Context, args ...any) (*Rows, error)
+SQL_UPROBE_RETURN(Stmt_QueryContext, 2)
+
+// This instrumentation attaches uprobe to the following function:
+// func (db *DB) beginDC(ctx context.Context, dc *driverConn, release func(error), opts *TxOptions) (tx *Tx, err error)
+SEC("uprobe/beginDC")
+int uprobe_beginDC(struct pt_regs *ctx) {
     struct go_iface go_context = {0};
     get_Go_context(ctx, 2, 0, true, &go_context);
-    start_span_params_t start_span_params = {
-        .ctx = ctx,
-        .go_context = &go_context,
-        .psc = &sql_request.psc,
-        .sc = &sql_request.sc,
-        .get_parent_span_context_fn = NULL,
-        .get_parent_span_context_arg = NULL,
-    };
-    start_span(&start_span_params);
+    return start_sql_span(ctx, SQL_OP_BEGIN, get_argument(ctx, 1), &go_context, NULL, 0);
+}
 
-    // Get key
+// This instrumentation attaches uprobe to the following function:
+// func (db *DB) beginDC(ctx context.Context, dc *driverConn, release func(error), opts *TxOptions) (tx *Tx, err error)
+SQL_UPROBE_RETURN(beginDC, 2)
+
+// This instrumentation attaches uprobe to the following function:
+// func (tx *Tx) Commit() error
+//
+// The method is not passed a context, the parent span is the one active on
+// the goroutine.
+SEC("uprobe/Tx_Commit")
+int uprobe_Tx_Commit(struct pt_regs *ctx) {
+    struct go_iface go_context = {0};
+    return start_sql_span(
+        ctx, SQL_OP_COMMIT, get_db(get_argument(ctx, 1), tx_db_pos), &go_context, NULL, 0);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (tx *Tx) Commit() error
+SQL_UPROBE_RETURN(Tx_Commit, 1)
+
+// This instrumentation attaches uprobe to the following function:
+// func (tx *Tx) Rollback() error
+//
+// The method is not passed a context, the parent span is the one active on
+// the goroutine.
+SEC("uprobe/Tx_Rollback")
+int uprobe_Tx_Rollback(struct pt_regs *ctx) {
+    struct go_iface go_context = {0};
+    return start_sql_span(
+        ctx, SQL_OP_ROLLBACK, get_db(get_argument(ctx, 1), tx_db_pos), &go_context, NULL, 0);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (tx *Tx) Rollback() error
+SQL_UPROBE_RETURN(Tx_Rollback, 1)
+
+// This instrumentation attaches uprobe to the following function:
+// func (db *DB) PingContext(ctx context.Context) error
+SEC("uprobe/DB_PingContext")
+int uprobe_DB_PingContext(struct pt_regs *ctx) {
+    struct go_iface go_context = {0};
+    get_Go_context(ctx, 2, 0, true, &go_context);
+    return start_sql_span(ctx, SQL_OP_PING, get_argument(ctx, 1), &go_context, NULL, 0);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) PingContext(ctx context.Context) error
+SEC("uprobe/Conn_PingContext")
+int uprobe_Conn_PingContext(struct pt_regs *ctx) {
+    struct go_iface go_context = {0};
+    get_Go_context(ctx, 2, 0, true, &go_context);
+    return start_sql_span(
+        ctx, SQL_OP_PING, get_db(get_argument(ctx, 1), conn_db_pos), &go_context, NULL, 0);
+}
+
+// This instrumentation attaches uprobe to the following functions:
+// func (db *DB) PingContext(ctx context.Context) error
+// func (c *Conn) PingContext(ctx context.Context) error
+SQL_UPROBE_RETURN(PingContext, 1)
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) Raw(f func(driverConn any) error) (err error)
+//
+// The method is not passed a context, the parent span is the one active on
+// the goroutine.
+SEC("uprobe/Conn_Raw")
+int uprobe_Conn_Raw(struct pt_regs *ctx) {
+    struct go_iface go_context = {0};
+    return start_sql_span(
+        ctx, SQL_OP_RAW, get_db(get_argument(ctx, 1), conn_db_pos), &go_context, NULL, 0);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) Raw(f func(driverConn any) error) (err error)
+SQL_UPROBE_RETURN(Conn_Raw, 1)
+
+// This instrumentation attaches uprobe to the following function:
+// func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn, error)
+SEC("uprobe/DB_conn")
+int uprobe_DB_conn(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    u64 start_time = bpf_ktime_get_ns();
+    bpf_map_update_elem(&sql_conn_start, &key, &start_time, 0);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (db *DB) conn(ctx context.Context, strategy connReuseStrategy) (*driverConn, error)
+//
+// The wait time is added to the span in progress on the goroutine, made with
+// a prepared statement or pinging the database, or stored for the next span
+// started by the goroutine.
+SEC("uprobe/DB_conn")
+int uprobe_DB_conn_Returns(struct pt_regs *ctx) {
     void *key = (void *)GOROUTINE(ctx);
+    u64 *start_time = bpf_map_lookup_elem(&sql_conn_start, &key);
+    if (start_time == NULL) {
+        return 0;
+    }
+    u64 conn_wait = bpf_ktime_get_ns() - *start_time;
+    bpf_map_delete_elem(&sql_conn_start, &key);
+
+    // No connection is returned on error.
+    if (get_argument(ctx, 1) == NULL) {
+        return 0;
+    }
+
+    // The connection can be acquired more than once if it was bad.
+    struct sql_request_t *sql_request = bpf_map_lookup_elem(&sql_events, &key);
+    if (sql_request != NULL) {
+        sql_request->conn_wait += conn_wait;
+        return 0;
+    }
 
-    bpf_map_update_elem(&sql_events, &key, &sql_request, 0);
+    u64 *prev_wait = bpf_map_lookup_elem(&sql_conn_wait, &key);
+    if (prev_wait != NULL) {
+        *prev_wait += conn_wait;
+        return 0;
+    }
+    bpf_map_update_elem(&sql_conn_wait, &key, &conn_wait, 0);
     return 0;
 }
 
 // This instrumentation attaches uprobe to the following function:
-// func (db *DB) execDC(ctx context.Context, dc *driverConn, release func(error), query string, args []any)
-UPROBE_RETURN(execDC, struct sql_request_t, sql_events)
+// func Open(driverName, dataSourceName string) (*DB, error)
+SEC("uprobe/Open")
+int uprobe_Open(struct pt_regs *ctx) {
+    struct sql_db_t db_info = {0};
+
+    void *driver_name_ptr = get_argument(ctx, 1);
+    u64 driver_name_len = (u64)get_argument(ctx, 2);
+    u64 driver_name_size =
+        MAX_DRIVER_NAME_SIZE < driver_name_len ? MAX_DRIVER_NAME_SIZE : driver_name_len;
+    bpf_probe_read_user(db_info.driver_name, driver_name_size, driver_name_ptr);
+
+    void *dsn_ptr = get_argument(ctx, 3);
+    u64 dsn_len = (u64)get_argument(ctx, 4);
+    u64 dsn_size = MAX_DSN_SIZE < dsn_len ? MAX_DSN_SIZE : dsn_len;
+    bpf_probe_read_user(db_info.dsn, dsn_size, dsn_ptr);
+    db_info.dsn_len = (u32)dsn_len;
+
+    void *key = (void *)GOROUTINE(ctx);
+    bpf_map_update_elem(&sql_open_args, &key, &db_info, 0);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func Open(driverName, dataSourceName string) (*DB, error)
+SEC("uprobe/Open")
+int uprobe_Open_Returns(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    struct sql_db_t *db_info = bpf_map_lookup_elem(&sql_open_args, &key);
+    if (db_info == NULL) {
+        return 0;
+    }
+
+    // No *DB is returned on error.
+    void *db = get_argument(ctx, 1);
+    if (db != NULL) {
+        bpf_map_update_elem(&sql_dbs, &db, db_info, 0);
+    }
+    bpf_map_delete_elem(&sql_open_args, &key);
+    return 0;
+}
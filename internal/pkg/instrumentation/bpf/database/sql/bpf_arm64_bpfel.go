@@ -26,13 +26,74 @@ type bpfSpanContext struct {
 	Padding    [7]uint8
 }
 
+type bpfSpanState struct {
+	_   structs.HostLayout
+	Ots struct {
+		_       structs.HostLayout
+		Th      uint64
+		Rv      uint64
+		HasTh   uint8
+		HasRv   uint8
+		Padding [6]uint8
+	}
+	Ts struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Entries [128]int8
+	}
+	Bg struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Value   [128]int8
+	}
+}
+
+type bpfSqlDbT struct {
+	_          structs.HostLayout
+	DriverName [32]int8
+	Dsn        [256]int8
+	DsnLen     uint32
+	Padding    [4]uint8
+}
+
 type bpfSqlRequestT struct {
 	_         structs.HostLayout
 	StartTime uint64
 	EndTime   uint64
 	Sc        bpfSpanContext
 	Psc       bpfSpanContext
+	State     bpfSpanState
 	Query     [256]int8
+	ConnWait  uint64
+	Operation uint8
+	DbSystem  uint8
+	Padding   [6]uint8
+	Db        bpfSqlDbT
+	Error     struct {
+		_       structs.HostLayout
+		Kinds   [3]uint8
+		Timeout uint8
+		Port    uint32
+		IpLen   uint8
+		Padding [7]uint8
+		Code    uint64
+		Op      [16]int8
+		Net     [8]int8
+		Ip      [16]uint8
+		Name    [64]int8
+		Msg     [128]int8
+	}
+	DriverError struct {
+		_        structs.HostLayout
+		Code     [8]int8
+		Severity [16]int8
+		Msg      [128]int8
+		Number   uint16
+		Kind     uint8
+		Padding  [5]uint8
+	}
 }
 
 // loadBpf returns the embedded CollectionSpec for bpf.
@@ -77,10 +138,31 @@ type bpfSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfProgramSpecs struct {
-	UprobeExecDC          *ebpf.ProgramSpec `ebpf:"uprobe_execDC"`
-	UprobeExecDC_Returns  *ebpf.ProgramSpec `ebpf:"uprobe_execDC_Returns"`
-	UprobeQueryDC         *ebpf.ProgramSpec `ebpf:"uprobe_queryDC"`
-	UprobeQueryDC_Returns *ebpf.ProgramSpec `ebpf:"uprobe_queryDC_Returns"`
+	UprobeConnPingContext         *ebpf.ProgramSpec `ebpf:"uprobe_Conn_PingContext"`
+	UprobeConnRaw                 *ebpf.ProgramSpec `ebpf:"uprobe_Conn_Raw"`
+	UprobeConnRawReturns          *ebpf.ProgramSpec `ebpf:"uprobe_Conn_Raw_Returns"`
+	UprobeDB_PingContext          *ebpf.ProgramSpec `ebpf:"uprobe_DB_PingContext"`
+	UprobeDB_conn                 *ebpf.ProgramSpec `ebpf:"uprobe_DB_conn"`
+	UprobeDB_connReturns          *ebpf.ProgramSpec `ebpf:"uprobe_DB_conn_Returns"`
+	UprobeOpen                    *ebpf.ProgramSpec `ebpf:"uprobe_Open"`
+	UprobeOpenReturns             *ebpf.ProgramSpec `ebpf:"uprobe_Open_Returns"`
+	UprobePingContextReturns      *ebpf.ProgramSpec `ebpf:"uprobe_PingContext_Returns"`
+	UprobeStmtExecContext         *ebpf.ProgramSpec `ebpf:"uprobe_Stmt_ExecContext"`
+	UprobeStmtExecContextReturns  *ebpf.ProgramSpec `ebpf:"uprobe_Stmt_ExecContext_Returns"`
+	UprobeStmtQueryContext        *ebpf.ProgramSpec `ebpf:"uprobe_Stmt_QueryContext"`
+	UprobeStmtQueryContextReturns *ebpf.ProgramSpec `ebpf:"uprobe_Stmt_QueryContext_Returns"`
+	UprobeTxCommit                *ebpf.ProgramSpec `ebpf:"uprobe_Tx_Commit"`
+	UprobeTxCommitReturns         *ebpf.ProgramSpec `ebpf:"uprobe_Tx_Commit_Returns"`
+	UprobeTxRollback              *ebpf.ProgramSpec `ebpf:"uprobe_Tx_Rollback"`
+	UprobeTxRollbackReturns       *ebpf.ProgramSpec `ebpf:"uprobe_Tx_Rollback_Returns"`
+	UprobeBeginDC                 *ebpf.ProgramSpec `ebpf:"uprobe_beginDC"`
+	UprobeBeginDC_Returns         *ebpf.ProgramSpec `ebpf:"uprobe_beginDC_Returns"`
+	UprobeExecDC                  *ebpf.ProgramSpec `ebpf:"uprobe_execDC"`
+	UprobeExecDC_Returns          *ebpf.ProgramSpec `ebpf:"uprobe_execDC_Returns"`
+	UprobePrepareDC               *ebpf.ProgramSpec `ebpf:"uprobe_prepareDC"`
+	UprobePrepareDC_Returns       *ebpf.ProgramSpec `ebpf:"uprobe_prepareDC_Returns"`
+	UprobeQueryDC                 *ebpf.ProgramSpec `ebpf:"uprobe_queryDC"`
+	UprobeQueryDC_Returns         *ebpf.ProgramSpec `ebpf:"uprobe_queryDC_Returns"`
 }
 
 // bpfMapSpecs contains maps before they are loaded into the kernel.
@@ -88,12 +170,23 @@ type bpfProgramSpecs struct {
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfMapSpecs struct {
 	AllocMap              *ebpf.MapSpec `ebpf:"alloc_map"`
+	DroppedSpansMap       *ebpf.MapSpec `ebpf:"dropped_spans_map"`
 	Events                *ebpf.MapSpec `ebpf:"events"`
 	GoContextToSc         *ebpf.MapSpec `ebpf:"go_context_to_sc"`
+	GoroutineToSc         *ebpf.MapSpec `ebpf:"goroutine_to_sc"`
 	ProbeActiveSamplerMap *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
+	RateLimiterMap        *ebpf.MapSpec `ebpf:"rate_limiter_map"`
 	SamplersConfigMap     *ebpf.MapSpec `ebpf:"samplers_config_map"`
 	SliceArrayBuffMap     *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap   *ebpf.MapSpec `ebpf:"span_output_config_map"`
+	SpanStateStorageMap   *ebpf.MapSpec `ebpf:"span_state_storage_map"`
+	SqlConnStart          *ebpf.MapSpec `ebpf:"sql_conn_start"`
+	SqlConnWait           *ebpf.MapSpec `ebpf:"sql_conn_wait"`
+	SqlDbs                *ebpf.MapSpec `ebpf:"sql_dbs"`
 	SqlEvents             *ebpf.MapSpec `ebpf:"sql_events"`
+	SqlOpenArgs           *ebpf.MapSpec `ebpf:"sql_open_args"`
+	SqlUprobeStorageMap   *ebpf.MapSpec `ebpf:"sql_uprobe_storage_map"`
+	TrackedSpanStatesBySc *ebpf.MapSpec `ebpf:"tracked_span_states_by_sc"`
 	TrackedSpansBySc      *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
 }
 
@@ -101,11 +194,67 @@ type bpfMapSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfVariableSpecs struct {
+	HttpErrorTimeoutPos      *ebpf.VariableSpec `ebpf:"http_error_timeout_pos"`
+	TCPAddrIP_offset         *ebpf.VariableSpec `ebpf:"TCPAddr_IP_offset"`
+	TCPAddrPortOffset        *ebpf.VariableSpec `ebpf:"TCPAddr_Port_offset"`
+	ConnDbPos                *ebpf.VariableSpec `ebpf:"conn_db_pos"`
+	DbConnectorPos           *ebpf.VariableSpec `ebpf:"db_connector_pos"`
+	DeadlineExceededItab     *ebpf.VariableSpec `ebpf:"deadline_exceeded_itab"`
+	DnsErrorErrPos           *ebpf.VariableSpec `ebpf:"dns_error_err_pos"`
+	DnsErrorIsTimeoutPos     *ebpf.VariableSpec `ebpf:"dns_error_is_timeout_pos"`
+	DnsErrorItab             *ebpf.VariableSpec `ebpf:"dns_error_itab"`
+	DnsErrorNamePos          *ebpf.VariableSpec `ebpf:"dns_error_name_pos"`
+	DsnConnectorDriverPos    *ebpf.VariableSpec `ebpf:"dsn_connector_driver_pos"`
+	DsnConnectorItab         *ebpf.VariableSpec `ebpf:"dsn_connector_itab"`
 	EndAddr                  *ebpf.VariableSpec `ebpf:"end_addr"`
+	ErrnoItab                *ebpf.VariableSpec `ebpf:"errno_itab"`
+	ErrorStringItab          *ebpf.VariableSpec `ebpf:"error_string_itab"`
 	Hex                      *ebpf.VariableSpec `ebpf:"hex"`
+	HttpErrorItab            *ebpf.VariableSpec `ebpf:"http_error_itab"`
+	HttpTimeoutErrorItab     *ebpf.VariableSpec `ebpf:"http_timeout_error_itab"`
+	ModerncSqliteDriverItab  *ebpf.VariableSpec `ebpf:"modernc_sqlite_driver_itab"`
+	MysqlConnectorItab       *ebpf.VariableSpec `ebpf:"mysql_connector_itab"`
+	MysqlErrorItab           *ebpf.VariableSpec `ebpf:"mysql_error_itab"`
+	MysqlErrorMsgPos         *ebpf.VariableSpec `ebpf:"mysql_error_msg_pos"`
+	MysqlErrorNumberPos      *ebpf.VariableSpec `ebpf:"mysql_error_number_pos"`
+	MysqlErrorSqlstatePos    *ebpf.VariableSpec `ebpf:"mysql_error_sqlstate_pos"`
+	OpErrorAddrPos           *ebpf.VariableSpec `ebpf:"op_error_addr_pos"`
+	OpErrorErrPos            *ebpf.VariableSpec `ebpf:"op_error_err_pos"`
+	OpErrorItab              *ebpf.VariableSpec `ebpf:"op_error_itab"`
+	OpErrorNetPos            *ebpf.VariableSpec `ebpf:"op_error_net_pos"`
+	OpErrorOpPos             *ebpf.VariableSpec `ebpf:"op_error_op_pos"`
+	PgconnV4ErrorCodePos     *ebpf.VariableSpec `ebpf:"pgconn_v4_error_code_pos"`
+	PgconnV4ErrorItab        *ebpf.VariableSpec `ebpf:"pgconn_v4_error_itab"`
+	PgconnV4ErrorMsgPos      *ebpf.VariableSpec `ebpf:"pgconn_v4_error_msg_pos"`
+	PgconnV4ErrorSeverityPos *ebpf.VariableSpec `ebpf:"pgconn_v4_error_severity_pos"`
+	PgconnV5ErrorCodePos     *ebpf.VariableSpec `ebpf:"pgconn_v5_error_code_pos"`
+	PgconnV5ErrorItab        *ebpf.VariableSpec `ebpf:"pgconn_v5_error_itab"`
+	PgconnV5ErrorMsgPos      *ebpf.VariableSpec `ebpf:"pgconn_v5_error_msg_pos"`
+	PgconnV5ErrorSeverityPos *ebpf.VariableSpec `ebpf:"pgconn_v5_error_severity_pos"`
+	PgxV4ConnectorItab       *ebpf.VariableSpec `ebpf:"pgx_v4_connector_itab"`
+	PgxV4DriverConnectorItab *ebpf.VariableSpec `ebpf:"pgx_v4_driver_connector_itab"`
+	PgxV5ConnectorItab       *ebpf.VariableSpec `ebpf:"pgx_v5_connector_itab"`
+	PgxV5DriverConnectorItab *ebpf.VariableSpec `ebpf:"pgx_v5_driver_connector_itab"`
+	PollDeadlineExceededItab *ebpf.VariableSpec `ebpf:"poll_deadline_exceeded_itab"`
+	PqConnectorItab          *ebpf.VariableSpec `ebpf:"pq_connector_itab"`
+	PqErrorCodePos           *ebpf.VariableSpec `ebpf:"pq_error_code_pos"`
+	PqErrorItab              *ebpf.VariableSpec `ebpf:"pq_error_itab"`
+	PqErrorMsgPos            *ebpf.VariableSpec `ebpf:"pq_error_msg_pos"`
+	PqErrorSeverityPos       *ebpf.VariableSpec `ebpf:"pq_error_severity_pos"`
 	ShouldIncludeDbStatement *ebpf.VariableSpec `ebpf:"should_include_db_statement"`
+	Sqlite3DriverItab        *ebpf.VariableSpec `ebpf:"sqlite3_driver_itab"`
 	StartAddr                *ebpf.VariableSpec `ebpf:"start_addr"`
+	StmtDbPos                *ebpf.VariableSpec `ebpf:"stmt_db_pos"`
+	StmtQueryPos             *ebpf.VariableSpec `ebpf:"stmt_query_pos"`
+	SyscallErrorErrPos       *ebpf.VariableSpec `ebpf:"syscall_error_err_pos"`
+	SyscallErrorItab         *ebpf.VariableSpec `ebpf:"syscall_error_itab"`
+	SyscallErrorSyscallPos   *ebpf.VariableSpec `ebpf:"syscall_error_syscall_pos"`
+	TcpAddrItab              *ebpf.VariableSpec `ebpf:"tcp_addr_itab"`
+	TlsAlertItab             *ebpf.VariableSpec `ebpf:"tls_alert_itab"`
+	TlsCertErrorItab         *ebpf.VariableSpec `ebpf:"tls_cert_error_itab"`
+	TlsHandshakeTimeoutItab  *ebpf.VariableSpec `ebpf:"tls_handshake_timeout_itab"`
 	TotalCpus                *ebpf.VariableSpec `ebpf:"total_cpus"`
+	TxDbPos                  *ebpf.VariableSpec `ebpf:"tx_db_pos"`
 }
 
 // bpfObjects contains all objects after they have been loaded into the kernel.
@@ -129,24 +278,46 @@ func (o *bpfObjects) Close() error {
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfMaps struct {
 	AllocMap              *ebpf.Map `ebpf:"alloc_map"`
+	DroppedSpansMap       *ebpf.Map `ebpf:"dropped_spans_map"`
 	Events                *ebpf.Map `ebpf:"events"`
 	GoContextToSc         *ebpf.Map `ebpf:"go_context_to_sc"`
+	GoroutineToSc         *ebpf.Map `ebpf:"goroutine_to_sc"`
 	ProbeActiveSamplerMap *ebpf.Map `ebpf:"probe_active_sampler_map"`
+	RateLimiterMap        *ebpf.Map `ebpf:"rate_limiter_map"`
 	SamplersConfigMap     *ebpf.Map `ebpf:"samplers_config_map"`
 	SliceArrayBuffMap     *ebpf.Map `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap   *ebpf.Map `ebpf:"span_output_config_map"`
+	SpanStateStorageMap   *ebpf.Map `ebpf:"span_state_storage_map"`
+	SqlConnStart          *ebpf.Map `ebpf:"sql_conn_start"`
+	SqlConnWait           *ebpf.Map `ebpf:"sql_conn_wait"`
+	SqlDbs                *ebpf.Map `ebpf:"sql_dbs"`
 	SqlEvents             *ebpf.Map `ebpf:"sql_events"`
+	SqlOpenArgs           *ebpf.Map `ebpf:"sql_open_args"`
+	SqlUprobeStorageMap   *ebpf.Map `ebpf:"sql_uprobe_storage_map"`
+	TrackedSpanStatesBySc *ebpf.Map `ebpf:"tracked_span_states_by_sc"`
 	TrackedSpansBySc      *ebpf.Map `ebpf:"tracked_spans_by_sc"`
 }
 
 func (m *bpfMaps) Close() error {
 	return _BpfClose(
 		m.AllocMap,
+		m.DroppedSpansMap,
 		m.Events,
 		m.GoContextToSc,
+		m.GoroutineToSc,
 		m.ProbeActiveSamplerMap,
+		m.RateLimiterMap,
 		m.SamplersConfigMap,
 		m.SliceArrayBuffMap,
+		m.SpanOutputConfigMap,
+		m.SpanStateStorageMap,
+		m.SqlConnStart,
+		m.SqlConnWait,
+		m.SqlDbs,
 		m.SqlEvents,
+		m.SqlOpenArgs,
+		m.SqlUprobeStorageMap,
+		m.TrackedSpanStatesBySc,
 		m.TrackedSpansBySc,
 	)
 }
@@ -155,27 +326,125 @@ func (m *bpfMaps) Close() error {
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfVariables struct {
+	HttpErrorTimeoutPos      *ebpf.Variable `ebpf:"http_error_timeout_pos"`
+	TCPAddrIP_offset         *ebpf.Variable `ebpf:"TCPAddr_IP_offset"`
+	TCPAddrPortOffset        *ebpf.Variable `ebpf:"TCPAddr_Port_offset"`
+	ConnDbPos                *ebpf.Variable `ebpf:"conn_db_pos"`
+	DbConnectorPos           *ebpf.Variable `ebpf:"db_connector_pos"`
+	DeadlineExceededItab     *ebpf.Variable `ebpf:"deadline_exceeded_itab"`
+	DnsErrorErrPos           *ebpf.Variable `ebpf:"dns_error_err_pos"`
+	DnsErrorIsTimeoutPos     *ebpf.Variable `ebpf:"dns_error_is_timeout_pos"`
+	DnsErrorItab             *ebpf.Variable `ebpf:"dns_error_itab"`
+	DnsErrorNamePos          *ebpf.Variable `ebpf:"dns_error_name_pos"`
+	DsnConnectorDriverPos    *ebpf.Variable `ebpf:"dsn_connector_driver_pos"`
+	DsnConnectorItab         *ebpf.Variable `ebpf:"dsn_connector_itab"`
 	EndAddr                  *ebpf.Variable `ebpf:"end_addr"`
+	ErrnoItab                *ebpf.Variable `ebpf:"errno_itab"`
+	ErrorStringItab          *ebpf.Variable `ebpf:"error_string_itab"`
 	Hex                      *ebpf.Variable `ebpf:"hex"`
+	HttpErrorItab            *ebpf.Variable `ebpf:"http_error_itab"`
+	HttpTimeoutErrorItab     *ebpf.Variable `ebpf:"http_timeout_error_itab"`
+	ModerncSqliteDriverItab  *ebpf.Variable `ebpf:"modernc_sqlite_driver_itab"`
+	MysqlConnectorItab       *ebpf.Variable `ebpf:"mysql_connector_itab"`
+	MysqlErrorItab           *ebpf.Variable `ebpf:"mysql_error_itab"`
+	MysqlErrorMsgPos         *ebpf.Variable `ebpf:"mysql_error_msg_pos"`
+	MysqlErrorNumberPos      *ebpf.Variable `ebpf:"mysql_error_number_pos"`
+	MysqlErrorSqlstatePos    *ebpf.Variable `ebpf:"mysql_error_sqlstate_pos"`
+	OpErrorAddrPos           *ebpf.Variable `ebpf:"op_error_addr_pos"`
+	OpErrorErrPos            *ebpf.Variable `ebpf:"op_error_err_pos"`
+	OpErrorItab              *ebpf.Variable `ebpf:"op_error_itab"`
+	OpErrorNetPos            *ebpf.Variable `ebpf:"op_error_net_pos"`
+	OpErrorOpPos             *ebpf.Variable `ebpf:"op_error_op_pos"`
+	PgconnV4ErrorCodePos     *ebpf.Variable `ebpf:"pgconn_v4_error_code_pos"`
+	PgconnV4ErrorItab        *ebpf.Variable `ebpf:"pgconn_v4_error_itab"`
+	PgconnV4ErrorMsgPos      *ebpf.Variable `ebpf:"pgconn_v4_error_msg_pos"`
+	PgconnV4ErrorSeverityPos *ebpf.Variable `ebpf:"pgconn_v4_error_severity_pos"`
+	PgconnV5ErrorCodePos     *ebpf.Variable `ebpf:"pgconn_v5_error_code_pos"`
+	PgconnV5ErrorItab        *ebpf.Variable `ebpf:"pgconn_v5_error_itab"`
+	PgconnV5ErrorMsgPos      *ebpf.Variable `ebpf:"pgconn_v5_error_msg_pos"`
+	PgconnV5ErrorSeverityPos *ebpf.Variable `ebpf:"pgconn_v5_error_severity_pos"`
+	PgxV4ConnectorItab       *ebpf.Variable `ebpf:"pgx_v4_connector_itab"`
+	PgxV4DriverConnectorItab *ebpf.Variable `ebpf:"pgx_v4_driver_connector_itab"`
+	PgxV5ConnectorItab       *ebpf.Variable `ebpf:"pgx_v5_connector_itab"`
+	PgxV5DriverConnectorItab *ebpf.Variable `ebpf:"pgx_v5_driver_connector_itab"`
+	PollDeadlineExceededItab *ebpf.Variable `ebpf:"poll_deadline_exceeded_itab"`
+	PqConnectorItab          *ebpf.Variable `ebpf:"pq_connector_itab"`
+	PqErrorCodePos           *ebpf.Variable `ebpf:"pq_error_code_pos"`
+	PqErrorItab              *ebpf.Variable `ebpf:"pq_error_itab"`
+	PqErrorMsgPos            *ebpf.Variable `ebpf:"pq_error_msg_pos"`
+	PqErrorSeverityPos       *ebpf.Variable `ebpf:"pq_error_severity_pos"`
 	ShouldIncludeDbStatement *ebpf.Variable `ebpf:"should_include_db_statement"`
+	Sqlite3DriverItab        *ebpf.Variable `ebpf:"sqlite3_driver_itab"`
 	StartAddr                *ebpf.Variable `ebpf:"start_addr"`
+	StmtDbPos                *ebpf.Variable `ebpf:"stmt_db_pos"`
+	StmtQueryPos             *ebpf.Variable `ebpf:"stmt_query_pos"`
+	SyscallErrorErrPos       *ebpf.Variable `ebpf:"syscall_error_err_pos"`
+	SyscallErrorItab         *ebpf.Variable `ebpf:"syscall_error_itab"`
+	SyscallErrorSyscallPos   *ebpf.Variable `ebpf:"syscall_error_syscall_pos"`
+	TcpAddrItab              *ebpf.Variable `ebpf:"tcp_addr_itab"`
+	TlsAlertItab             *ebpf.Variable `ebpf:"tls_alert_itab"`
+	TlsCertErrorItab         *ebpf.Variable `ebpf:"tls_cert_error_itab"`
+	TlsHandshakeTimeoutItab  *ebpf.Variable `ebpf:"tls_handshake_timeout_itab"`
 	TotalCpus                *ebpf.Variable `ebpf:"total_cpus"`
+	TxDbPos                  *ebpf.Variable `ebpf:"tx_db_pos"`
 }
 
 // bpfPrograms contains all programs after they have been loaded into the kernel.
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfPrograms struct {
-	UprobeExecDC          *ebpf.Program `ebpf:"uprobe_execDC"`
-	UprobeExecDC_Returns  *ebpf.Program `ebpf:"uprobe_execDC_Returns"`
-	UprobeQueryDC         *ebpf.Program `ebpf:"uprobe_queryDC"`
-	UprobeQueryDC_Returns *ebpf.Program `ebpf:"uprobe_queryDC_Returns"`
+	UprobeConnPingContext         *ebpf.Program `ebpf:"uprobe_Conn_PingContext"`
+	UprobeConnRaw                 *ebpf.Program `ebpf:"uprobe_Conn_Raw"`
+	UprobeConnRawReturns          *ebpf.Program `ebpf:"uprobe_Conn_Raw_Returns"`
+	UprobeDB_PingContext          *ebpf.Program `ebpf:"uprobe_DB_PingContext"`
+	UprobeDB_conn                 *ebpf.Program `ebpf:"uprobe_DB_conn"`
+	UprobeDB_connReturns          *ebpf.Program `ebpf:"uprobe_DB_conn_Returns"`
+	UprobeOpen                    *ebpf.Program `ebpf:"uprobe_Open"`
+	UprobeOpenReturns             *ebpf.Program `ebpf:"uprobe_Open_Returns"`
+	UprobePingContextReturns      *ebpf.Program `ebpf:"uprobe_PingContext_Returns"`
+	UprobeStmtExecContext         *ebpf.Program `ebpf:"uprobe_Stmt_ExecContext"`
+	UprobeStmtExecContextReturns  *ebpf.Program `ebpf:"uprobe_Stmt_ExecContext_Returns"`
+	UprobeStmtQueryContext        *ebpf.Program `ebpf:"uprobe_Stmt_QueryContext"`
+	UprobeStmtQueryContextReturns *ebpf.Program `ebpf:"uprobe_Stmt_QueryContext_Returns"`
+	UprobeTxCommit                *ebpf.Program `ebpf:"uprobe_Tx_Commit"`
+	UprobeTxCommitReturns         *ebpf.Program `ebpf:"uprobe_Tx_Commit_Returns"`
+	UprobeTxRollback              *ebpf.Program `ebpf:"uprobe_Tx_Rollback"`
+	UprobeTxRollbackReturns       *ebpf.Program `ebpf:"uprobe_Tx_Rollback_Returns"`
+	UprobeBeginDC                 *ebpf.Program `ebpf:"uprobe_beginDC"`
+	UprobeBeginDC_Returns         *ebpf.Program `ebpf:"uprobe_beginDC_Returns"`
+	UprobeExecDC                  *ebpf.Program `ebpf:"uprobe_execDC"`
+	UprobeExecDC_Returns          *ebpf.Program `ebpf:"uprobe_execDC_Returns"`
+	UprobePrepareDC               *ebpf.Program `ebpf:"uprobe_prepareDC"`
+	UprobePrepareDC_Returns       *ebpf.Program `ebpf:"uprobe_prepareDC_Returns"`
+	UprobeQueryDC                 *ebpf.Program `ebpf:"uprobe_queryDC"`
+	UprobeQueryDC_Returns         *ebpf.Program `ebpf:"uprobe_queryDC_Returns"`
 }
 
 func (p *bpfPrograms) Close() error {
 	return _BpfClose(
+		p.UprobeConnPingContext,
+		p.UprobeConnRaw,
+		p.UprobeConnRawReturns,
+		p.UprobeDB_PingContext,
+		p.UprobeDB_conn,
+		p.UprobeDB_connReturns,
+		p.UprobeOpen,
+		p.UprobeOpenReturns,
+		p.UprobePingContextReturns,
+		p.UprobeStmtExecContext,
+		p.UprobeStmtExecContextReturns,
+		p.UprobeStmtQueryContext,
+		p.UprobeStmtQueryContextReturns,
+		p.UprobeTxCommit,
+		p.UprobeTxCommitReturns,
+		p.UprobeTxRollback,
+		p.UprobeTxRollbackReturns,
+		p.UprobeBeginDC,
+		p.UprobeBeginDC_Returns,
 		p.UprobeExecDC,
 		p.UprobeExecDC_Returns,
+		p.UprobePrepareDC,
+		p.UprobePrepareDC_Returns,
 		p.UprobeQueryDC,
 		p.UprobeQueryDC_Returns,
 	)
@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"iter"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// dbSystem is the database system of the driver used. The values need to
+// match the DB_SYSTEM_* ones of the eBPF program.
+type dbSystem uint8
+
+const (
+	dbSystemUnknown dbSystem = iota
+	dbSystemPostgreSQL
+	dbSystemMySQL
+	dbSystemSQLite
+)
+
+// String returns the db.system.name of s, empty if it is unknown.
+func (s dbSystem) String() string {
+	switch s {
+	case dbSystemPostgreSQL:
+		return semconv.DBSystemNamePostgreSQL.Value.AsString()
+	case dbSystemMySQL:
+		return semconv.DBSystemNameMySQL.Value.AsString()
+	case dbSystemSQLite:
+		return semconv.DBSystemNameSQLite.Value.AsString()
+	default:
+		return ""
+	}
+}
+
+// driverSystems are the database systems of the driver names registered by
+// the supported drivers. They are used when the type of the driver is not
+// known to the eBPF program.
+var driverSystems = map[string]dbSystem{
+	"postgres": dbSystemPostgreSQL, // github.com/lib/pq
+	"pgx":      dbSystemPostgreSQL, // github.com/jackc/pgx
+	"pgx/v5":   dbSystemPostgreSQL, // github.com/jackc/pgx/v5
+	"mysql":    dbSystemMySQL,      // github.com/go-sql-driver/mysql
+	"sqlite3":  dbSystemSQLite,     // github.com/mattn/go-sqlite3
+	"sqlite":   dbSystemSQLite,     // modernc.org/sqlite
+}
+
+// dsnInfo is the information of a data source name recorded on spans. It
+// never holds credentials.
+type dsnInfo struct {
+	address   string
+	port      int
+	namespace string
+}
+
+// parseDSN returns the dsnInfo of the data source name dsn of a driver of
+// system. The zero value is returned if dsn cannot be parsed.
+func parseDSN(system dbSystem, dsn string) dsnInfo {
+	if dsn == "" {
+		return dsnInfo{}
+	}
+
+	switch system {
+	case dbSystemPostgreSQL:
+		return parsePostgreSQLDSN(dsn)
+	case dbSystemMySQL:
+		return parseMySQLDSN(dsn)
+	case dbSystemSQLite:
+		return parseSQLiteDSN(dsn)
+	default:
+		return dsnInfo{}
+	}
+}
+
+// dsn returns a data source name of a driver of system that only holds the
+// information of i. It is parsed by parseDSN into i.
+func (i dsnInfo) dsn(system dbSystem) string {
+	if i == (dsnInfo{}) {
+		return ""
+	}
+
+	switch system {
+	case dbSystemPostgreSQL:
+		var kv []string
+		if i.address != "" {
+			kv = append(kv, "host="+postgreSQLQuote(i.address))
+		}
+		if i.port > 0 {
+			kv = append(kv, "port="+strconv.Itoa(i.port))
+		}
+		if i.namespace != "" {
+			kv = append(kv, "dbname="+postgreSQLQuote(i.namespace))
+		}
+		return strings.Join(kv, " ")
+	case dbSystemMySQL:
+		var addr string
+		switch {
+		case strings.HasPrefix(i.address, "/"):
+			addr = "unix(" + i.address + ")"
+		case i.port > 0:
+			addr = "tcp(" + net.JoinHostPort(i.address, strconv.Itoa(i.port)) + ")"
+		case i.address != "":
+			addr = "tcp(" + i.address + ")"
+		}
+		return addr + "/" + i.namespace
+	case dbSystemSQLite:
+		return i.namespace
+	default:
+		return ""
+	}
+}
+
+// postgreSQLQuote returns value quoted, if needed, to be a value of a
+// keyword/value connection string.
+func postgreSQLQuote(value string) string {
+	if !strings.ContainsAny(value, " \t\n\r'\\") {
+		return value
+	}
+	r := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	return "'" + r.Replace(value) + "'"
+}
+
+// parsePostgreSQLDSN parses the URL or keyword/value connection string dsn
+// of a PostgreSQL database.
+func parsePostgreSQLDSN(dsn string) dsnInfo {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return dsnInfo{}
+		}
+		// Only the first of multiple hosts is recorded.
+		host, _, _ := strings.Cut(u.Host, ",")
+		info := hostPort(host)
+		info.namespace = strings.TrimPrefix(u.Path, "/")
+		return info
+	}
+
+	var info dsnInfo
+	for key, value := range postgreSQLKeywords(dsn) {
+		// Only the first of multiple hosts and ports is recorded.
+		value, _, _ = strings.Cut(value, ",")
+		switch key {
+		case "host":
+			info.address = value
+		case "port":
+			info.port, _ = strconv.Atoi(value)
+		case "dbname":
+			info.namespace = value
+		}
+	}
+	return info
+}
+
+// postgreSQLKeywords returns an iterator over the keywords and values of the
+// keyword/value connection string dsn. Values can be single-quoted, with
+// quotes and backslashes escaped by a backslash.
+func postgreSQLKeywords(dsn string) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		s := dsn
+		for {
+			s = strings.TrimLeft(s, " \t\n\r")
+			key, rest, ok := strings.Cut(s, "=")
+			if !ok {
+				return
+			}
+			key = strings.TrimSpace(key)
+			rest = strings.TrimLeft(rest, " \t\n\r")
+
+			var value strings.Builder
+			if strings.HasPrefix(rest, "'") {
+				i, closed := 1, false
+				for ; i < len(rest); i++ {
+					c := rest[i]
+					if c == '\\' && i+1 < len(rest) {
+						i++
+						value.WriteByte(rest[i])
+						continue
+					}
+					if c == '\'' {
+						closed = true
+						break
+					}
+					value.WriteByte(c)
+				}
+				if !closed {
+					return
+				}
+				s = rest[i+1:]
+			} else {
+				end := strings.IndexAny(rest, " \t\n\r")
+				if end < 0 {
+					end = len(rest)
+				}
+				value.WriteString(rest[:end])
+				s = rest[end:]
+			}
+
+			if !yield(key, value.String()) {
+				return
+			}
+		}
+	}
+}
+
+// parseMySQLDSN parses the data source name dsn of the
+// github.com/go-sql-driver/mysql driver:
+//
+//	[user[:password]@][net[(addr)]]/dbname[?param1=value1&paramN=valueN]
+func parseMySQLDSN(dsn string) dsnInfo {
+	i := strings.LastIndexByte(dsn, '/')
+	if i < 0 {
+		return dsnInfo{}
+	}
+	prefix, rest := dsn[:i], dsn[i+1:]
+
+	var info dsnInfo
+	info.namespace, _, _ = strings.Cut(rest, "?")
+
+	// The credentials are before the last '@' of the prefix.
+	if at := strings.LastIndexByte(prefix, '@'); at >= 0 {
+		prefix = prefix[at+1:]
+	}
+	open := strings.IndexByte(prefix, '(')
+	if open < 0 || !strings.HasSuffix(prefix, ")") {
+		return info
+	}
+	network, addr := prefix[:open], prefix[open+1:len(prefix)-1]
+	if network == "unix" {
+		info.address = addr
+		return info
+	}
+
+	hp := hostPort(addr)
+	info.address, info.port = hp.address, hp.port
+	return info
+}
+
+// parseSQLiteDSN parses the file name or URI dsn of a SQLite database. The
+// namespace is the path of the database file, without the query parameters
+// that can hold credentials.
+func parseSQLiteDSN(dsn string) dsnInfo {
+	path, _, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+	return dsnInfo{namespace: path}
+}
+
+// hostPort returns the dsnInfo of addr, a host optionally followed by a port.
+func hostPort(addr string) dsnInfo {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return dsnInfo{address: strings.Trim(addr, "[]")}
+	}
+	port, _ := strconv.Atoi(portStr)
+	return dsnInfo{address: host, port: port}
+}
@@ -0,0 +1,150 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"golang.org/x/sys/unix"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
+	"go.opentelemetry.io/auto/internal/pkg/structfield"
+)
+
+// driverErrorKind is the kind of an error returned by a driver. The values
+// need to match the SQL_ERROR_* ones of the eBPF program.
+type driverErrorKind uint8
+
+const (
+	driverErrorUnknown driverErrorKind = iota
+	driverErrorPQ
+	driverErrorPgconnV4
+	driverErrorPgconnV5
+	driverErrorMySQL
+)
+
+// driverErrorTypes are the Go types of the known driver error kinds.
+var driverErrorTypes = map[driverErrorKind]string{
+	driverErrorPQ:       "*github.com/lib/pq.Error",
+	driverErrorPgconnV4: "*github.com/jackc/pgconn.PgError",
+	driverErrorPgconnV5: "*github.com/jackc/pgx/v5/pgconn.PgError",
+	driverErrorMySQL:    "*github.com/go-sql-driver/mysql.MySQLError",
+}
+
+// driverError is the value of an error of a known driver type, read by eBPF.
+type driverError struct {
+	Code     [8]byte
+	Severity [16]byte
+	Msg      [128]byte
+	Number   uint16
+	Kind     driverErrorKind
+	_        [5]byte
+}
+
+// Type returns the Go type of the error, or an empty string if it is unknown.
+func (e *driverError) Type() string {
+	return driverErrorTypes[e.Kind]
+}
+
+// Message returns the message of the error, as returned by its Error method.
+func (e *driverError) Message() string {
+	code := unix.ByteSliceToString(e.Code[:])
+	msg := unix.ByteSliceToString(e.Msg[:])
+	switch e.Kind {
+	case driverErrorPQ:
+		return "pq: " + msg
+	case driverErrorPgconnV4, driverErrorPgconnV5:
+		severity := unix.ByteSliceToString(e.Severity[:])
+		return severity + ": " + msg + " (SQLSTATE " + code + ")"
+	case driverErrorMySQL:
+		number := strconv.FormatUint(uint64(e.Number), 10)
+		if code != "" {
+			return "Error " + number + " (" + code + "): " + msg
+		}
+		return "Error " + number + ": " + msg
+	default:
+		return ""
+	}
+}
+
+// StatusCode returns the status code of the error reported by the database:
+// the SQLSTATE code for PostgreSQL and the error number for MySQL.
+func (e *driverError) StatusCode() string {
+	switch e.Kind {
+	case driverErrorPQ, driverErrorPgconnV4, driverErrorPgconnV5:
+		return unix.ByteSliceToString(e.Code[:])
+	case driverErrorMySQL:
+		return strconv.FormatUint(uint64(e.Number), 10)
+	default:
+		return ""
+	}
+}
+
+// recordError sets the status of span to Error and records the error of e,
+// if any, as returned by the instrumented function.
+func recordError(span ptrace.Span, e *event) {
+	if typ := e.DriverError.Type(); typ != "" {
+		probe.RecordException(span, typ, e.DriverError.Message())
+		span.Attributes().PutStr(string(semconv.ErrorTypeKey), typ)
+		if code := e.DriverError.StatusCode(); code != "" {
+			span.Attributes().PutStr(string(semconv.DBResponseStatusCodeKey), code)
+		}
+		return
+	}
+
+	e.Err.Record(span)
+}
+
+// driverErrorModules are the modules defining the known driver error kinds.
+var driverErrorModules = map[driverErrorKind]string{
+	driverErrorPQ:       "github.com/lib/pq",
+	driverErrorPgconnV4: "github.com/jackc/pgconn",
+	driverErrorPgconnV5: "github.com/jackc/pgx/v5",
+	driverErrorMySQL:    "github.com/go-sql-driver/mysql",
+}
+
+// driverErrorConsts are the [probe.Const] needed by the eBPF program to read
+// the errors of the supported drivers. The offsets of the fields not cached are
+// found in the target binary, the errors are recorded as unknown ones if they
+// are not.
+var driverErrorConsts = []probe.Const{
+	errorItab("pq_error_itab", driverErrorPQ),
+	errorField("pq_error_severity_pos", driverErrorPQ, "Severity"),
+	errorField("pq_error_code_pos", driverErrorPQ, "Code"),
+	errorField("pq_error_msg_pos", driverErrorPQ, "Message"),
+	errorItab("pgconn_v4_error_itab", driverErrorPgconnV4),
+	errorField("pgconn_v4_error_severity_pos", driverErrorPgconnV4, "Severity"),
+	errorField("pgconn_v4_error_code_pos", driverErrorPgconnV4, "Code"),
+	errorField("pgconn_v4_error_msg_pos", driverErrorPgconnV4, "Message"),
+	errorItab("pgconn_v5_error_itab", driverErrorPgconnV5),
+	errorField("pgconn_v5_error_severity_pos", driverErrorPgconnV5, "Severity"),
+	errorField("pgconn_v5_error_code_pos", driverErrorPgconnV5, "Code"),
+	errorField("pgconn_v5_error_msg_pos", driverErrorPgconnV5, "Message"),
+	errorItab("mysql_error_itab", driverErrorMySQL),
+	errorField("mysql_error_number_pos", driverErrorMySQL, "Number"),
+	errorField("mysql_error_sqlstate_pos", driverErrorMySQL, "SQLState"),
+	errorField("mysql_error_msg_pos", driverErrorMySQL, "Message"),
+}
+
+// errorItab returns the [probe.ItabConst] of the error type of kind.
+func errorItab(key string, kind driverErrorKind) probe.ItabConst {
+	return probe.ItabConst{Key: key, Type: driverErrorTypes[kind], Interface: "error"}
+}
+
+// errorField returns the [probe.StructFieldConstOptional] of the field of the
+// struct pointed to by the error type of kind.
+func errorField(key string, kind driverErrorKind, field string) probe.StructFieldConstOptional {
+	// The types are pointers to structs: *<package path>.<struct name>.
+	typ := strings.TrimPrefix(driverErrorTypes[kind], "*")
+	i := strings.LastIndexByte(typ, '.')
+	return probe.StructFieldConstOptional{
+		StructField: probe.StructFieldConst{
+			Key: key,
+			ID:  structfield.NewID(driverErrorModules[kind], typ[:i], typ[i+1:], field),
+		},
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+func newDriverError(kind driverErrorKind, severity, code, msg string, number uint16) driverError {
+	e := driverError{Kind: kind, Number: number}
+	copy(e.Severity[:], severity)
+	copy(e.Code[:], code)
+	copy(e.Msg[:], msg)
+	return e
+}
+
+func TestDriverError(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    driverError
+		msg    string
+		status string
+	}{
+		{
+			name:   "pq",
+			err:    newDriverError(driverErrorPQ, "ERROR", "42P01", `relation "users" does not exist`, 0),
+			msg:    `pq: relation "users" does not exist`,
+			status: "42P01",
+		},
+		{
+			name:   "pgconn",
+			err:    newDriverError(driverErrorPgconnV5, "ERROR", "23505", "duplicate key value", 0),
+			msg:    "ERROR: duplicate key value (SQLSTATE 23505)",
+			status: "23505",
+		},
+		{
+			name:   "mysql",
+			err:    newDriverError(driverErrorMySQL, "", "42S02", "Unknown table 'users'", 1146),
+			msg:    "Error 1146 (42S02): Unknown table 'users'",
+			status: "1146",
+		},
+		{
+			name:   "mysql without SQLSTATE",
+			err:    newDriverError(driverErrorMySQL, "", "", "Access denied", 1045),
+			msg:    "Error 1045: Access denied",
+			status: "1045",
+		},
+		{
+			name: "unknown",
+			err:  driverError{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.msg, tt.err.Message())
+			assert.Equal(t, tt.status, tt.err.StatusCode())
+		})
+	}
+}
+
+func TestProbeConvertEventError(t *testing.T) {
+	e := &event{
+		DriverError: newDriverError(driverErrorPgconnV5, "ERROR", "23505", "duplicate key value", 0),
+	}
+	e.Err.Kinds[0] = 1 // Unknown Go error.
+	got := processFn(e)
+	require.Equal(t, 1, got.Len())
+	span := got.At(0)
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, "ERROR: duplicate key value (SQLSTATE 23505)", span.Status().Message())
+	assert.Equal(t, map[string]any{
+		string(semconv.ErrorTypeKey):            "*github.com/jackc/pgx/v5/pgconn.PgError",
+		string(semconv.DBResponseStatusCodeKey): "23505",
+	}, span.Attributes().AsRaw())
+	require.Equal(t, 1, span.Events().Len())
+	assert.Equal(t, semconv.ExceptionEventName, span.Events().At(0).Name())
+
+	e = &event{}
+	e.Err.Kinds[0] = 2 // *errors.errorString
+	const msg = "sql: transaction has already been committed or rolled back"
+	copy(e.Err.Msg[:], msg)
+	got = processFn(e)
+	require.Equal(t, 1, got.Len())
+	span = got.At(0)
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, msg, span.Status().Message())
+	v, ok := span.Attributes().Get(string(semconv.ErrorTypeKey))
+	require.True(t, ok)
+	assert.Equal(t, "*errors.errorString", v.Str())
+	_, ok = span.Attributes().Get(string(semconv.DBResponseStatusCodeKey))
+	assert.False(t, ok)
+}
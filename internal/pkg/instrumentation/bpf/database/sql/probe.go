@@ -10,8 +10,12 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"slices"
 	"strconv"
+	"time"
+	"unsafe"
 
+	"github.com/cilium/ebpf/perf"
 	"github.com/xwb1989/sqlparser"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
@@ -23,6 +27,7 @@ import (
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/context"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/kernel"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
+	"go.opentelemetry.io/auto/internal/pkg/structfield"
 )
 
 //go:generate go run github.com/cilium/ebpf/cmd/bpf2go -target amd64,arm64 bpf ./bpf/probe.bpf.c
@@ -36,6 +41,10 @@ const (
 
 	// ParseDBStatementEnvVar is the environment variable to opt-in for sql query operation in the trace.
 	ParseDBStatementEnvVar = "OTEL_GO_AUTO_PARSE_DB_STATEMENT"
+
+	// SanitizeDBStatementEnvVar is the environment variable to opt-out of
+	// sql query sanitization. Sanitization is enabled by default.
+	SanitizeDBStatementEnvVar = "OTEL_GO_AUTO_SANITIZE_DB_STATEMENT"
 )
 
 // New returns a new [probe.Probe].
@@ -48,13 +57,52 @@ func New(logger *slog.Logger, version string) probe.Probe {
 		Base: probe.Base[bpfObjects, event]{
 			ID:     id,
 			Logger: logger,
-			Consts: []probe.Const{
+			Consts: slices.Concat([]probe.Const{
 				probe.AllocationConst{},
 				probe.KeyValConst{
 					Key: "should_include_db_statement",
-					Val: shouldIncludeDBStatement(),
+					Val: ShouldIncludeDBStatement(),
 				},
-			},
+				probe.StructFieldConst{
+					Key: "stmt_query_pos",
+					ID:  structfield.NewID("std", "database/sql", "Stmt", "query"),
+				},
+				probe.StructFieldConst{
+					Key: "stmt_db_pos",
+					ID:  structfield.NewID("std", "database/sql", "Stmt", "db"),
+				},
+				probe.StructFieldConst{
+					Key: "tx_db_pos",
+					ID:  structfield.NewID("std", "database/sql", "Tx", "db"),
+				},
+				probe.StructFieldConst{
+					Key: "conn_db_pos",
+					ID:  structfield.NewID("std", "database/sql", "Conn", "db"),
+				},
+				probe.StructFieldConst{
+					Key: "db_connector_pos",
+					ID:  structfield.NewID("std", "database/sql", "DB", "connector"),
+				},
+				probe.StructFieldConst{
+					Key: "dsn_connector_driver_pos",
+					ID:  structfield.NewID("std", "database/sql", "dsnConnector", "driver"),
+				},
+				connectorItab("dsn_connector_itab", "database/sql.dsnConnector"),
+				connectorItab("pq_connector_itab", "*github.com/lib/pq.Connector"),
+				connectorItab("pgx_v4_connector_itab", "github.com/jackc/pgx/v4/stdlib.connector"),
+				connectorItab(
+					"pgx_v4_driver_connector_itab",
+					"*github.com/jackc/pgx/v4/stdlib.driverConnector",
+				),
+				connectorItab("pgx_v5_connector_itab", "github.com/jackc/pgx/v5/stdlib.connector"),
+				connectorItab(
+					"pgx_v5_driver_connector_itab",
+					"*github.com/jackc/pgx/v5/stdlib.driverConnector",
+				),
+				connectorItab("mysql_connector_itab", "*github.com/go-sql-driver/mysql.connector"),
+				driverItab("sqlite3_driver_itab", "*github.com/mattn/go-sqlite3.SQLiteDriver"),
+				driverItab("modernc_sqlite_driver_itab", "*modernc.org/sqlite.Driver"),
+			}, probe.GoErrorConsts, driverErrorConsts),
 			Uprobes: []*probe.Uprobe{
 				{
 					Sym:         "database/sql.(*DB).queryDC",
@@ -68,9 +116,76 @@ func New(logger *slog.Logger, version string) probe.Probe {
 					ReturnProbe: "uprobe_execDC_Returns",
 					FailureMode: probe.FailureModeIgnore,
 				},
+				{
+					Sym:         "database/sql.(*DB).prepareDC",
+					EntryProbe:  "uprobe_prepareDC",
+					ReturnProbe: "uprobe_prepareDC_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.(*Stmt).ExecContext",
+					EntryProbe:  "uprobe_Stmt_ExecContext",
+					ReturnProbe: "uprobe_Stmt_ExecContext_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.(*Stmt).QueryContext",
+					EntryProbe:  "uprobe_Stmt_QueryContext",
+					ReturnProbe: "uprobe_Stmt_QueryContext_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.(*DB).beginDC",
+					EntryProbe:  "uprobe_beginDC",
+					ReturnProbe: "uprobe_beginDC_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.(*Tx).Commit",
+					EntryProbe:  "uprobe_Tx_Commit",
+					ReturnProbe: "uprobe_Tx_Commit_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.(*Tx).Rollback",
+					EntryProbe:  "uprobe_Tx_Rollback",
+					ReturnProbe: "uprobe_Tx_Rollback_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.(*DB).PingContext",
+					EntryProbe:  "uprobe_DB_PingContext",
+					ReturnProbe: "uprobe_PingContext_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.(*Conn).PingContext",
+					EntryProbe:  "uprobe_Conn_PingContext",
+					ReturnProbe: "uprobe_PingContext_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.(*Conn).Raw",
+					EntryProbe:  "uprobe_Conn_Raw",
+					ReturnProbe: "uprobe_Conn_Raw_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.(*DB).conn",
+					EntryProbe:  "uprobe_DB_conn",
+					ReturnProbe: "uprobe_DB_conn_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         "database/sql.Open",
+					EntryProbe:  "uprobe_Open",
+					ReturnProbe: "uprobe_Open_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
 			},
 
-			SpecFn: loadBpf,
+			SpecFn:        loadBpf,
+			ProcessRecord: processRecord,
 		},
 		Version:   version,
 		SchemaURL: semconv.SchemaURL,
@@ -78,11 +193,123 @@ func New(logger *slog.Logger, version string) probe.Probe {
 	}
 }
 
+// connectorItab returns the [probe.ItabConst] of the typ implementing
+// driver.Connector.
+func connectorItab(key, typ string) probe.ItabConst {
+	return probe.ItabConst{Key: key, Type: typ, Interface: "database/sql/driver.Connector"}
+}
+
+// driverItab returns the [probe.ItabConst] of the typ implementing
+// driver.Driver.
+func driverItab(key, typ string) probe.ItabConst {
+	return probe.ItabConst{Key: key, Type: typ, Interface: "database/sql/driver.Driver"}
+}
+
+// operation is the database/sql operation of an event.
+type operation uint8
+
+const (
+	opQuery operation = iota
+	opExec
+	opPrepare
+	opBegin
+	opCommit
+	opRollback
+	opPing
+	opRaw
+)
+
+// name returns the name of the operation, empty for the queries and
+// executions named after their statement.
+func (o operation) name() string {
+	switch o {
+	case opPrepare:
+		return "PREPARE"
+	case opBegin:
+		return "BEGIN"
+	case opCommit:
+		return "COMMIT"
+	case opRollback:
+		return "ROLLBACK"
+	case opPing:
+		return "PING"
+	case opRaw:
+		return "RAW"
+	default:
+		return ""
+	}
+}
+
+// connWaitTimeKey is the attribute key of the time spent waiting for a
+// connection from the pool, in seconds.
+const connWaitTimeKey = "db.client.connection.wait_time"
+
 // event represents an event in an SQL database
 // request-response.
 type event struct {
 	context.BaseSpanProperties
-	Query [256]byte
+	Query     [256]byte
+	ConnWait  uint64
+	Operation operation
+	DBSystem  dbSystem
+	_         [6]byte
+	DB        dbOpen
+	// Err is the error returned by the instrumented function. Only its kind
+	// is read if it is of a known driver type.
+	Err         probe.GoError
+	DriverError driverError
+}
+
+// dbOpen holds the arguments of the sql.Open call that returned the *DB used.
+// It is empty for a *DB returned by sql.OpenDB, whose connector configuration
+// is not read.
+type dbOpen struct {
+	DriverName [32]byte
+	DSN        [256]byte
+	DSNLen     uint32
+	_          [4]byte
+}
+
+// processRecord decodes the event of record. The data source name of the
+// event is replaced by one without credentials, parsed from it, before the
+// sample of record is recorded.
+func processRecord(record perf.Record) (*event, error) {
+	var zero event
+	if len(record.RawSample) < int(unsafe.Sizeof(zero)) {
+		return nil, fmt.Errorf(
+			"record size %d too small for event size %d",
+			len(record.RawSample),
+			unsafe.Sizeof(zero),
+		)
+	}
+	e := (*event)(unsafe.Pointer(&record.RawSample[0]))
+	e.DB.redact(e.system())
+	return e, nil
+}
+
+// system returns the database system of e.
+func (e *event) system() dbSystem {
+	if e.DBSystem != dbSystemUnknown {
+		return e.DBSystem
+	}
+	return driverSystems[unix.ByteSliceToString(e.DB.DriverName[:])]
+}
+
+// redact replaces the data source name of d, a driver of system, by one only
+// holding the information recorded on spans. Truncated data source names,
+// which are not parsed, are cleared.
+func (d *dbOpen) redact(system dbSystem) {
+	var dsn string
+	if d.DSNLen <= uint32(len(d.DSN)) {
+		dsn = parseDSN(system, string(d.DSN[:d.DSNLen])).dsn(system)
+	}
+	d.DSN = [len(d.DSN)]byte{}
+	if len(dsn) > len(d.DSN) {
+		// Leave it marked as truncated.
+		d.DSNLen = uint32(len(dsn))
+		return
+	}
+	d.DSNLen = uint32(copy(d.DSN[:], dsn))
 }
 
 func processFn(e *event) ptrace.SpanSlice {
@@ -99,10 +326,47 @@ func processFn(e *event) ptrace.SpanSlice {
 	if e.ParentSpanContext.SpanID.IsValid() {
 		span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
 	}
+	span.TraceState().FromRaw(e.State.TraceState())
+
+	system := e.system()
+	if name := system.String(); name != "" {
+		span.Attributes().PutStr(string(semconv.DBSystemNameKey), name)
+		// Spans of unknown operations are named after the system.
+		span.SetName(name)
+	}
+
+	// Truncated data source names are not parsed.
+	if e.DB.DSNLen <= uint32(len(e.DB.DSN)) {
+		dsn := parseDSN(system, string(e.DB.DSN[:e.DB.DSNLen]))
+		if dsn.address != "" {
+			span.Attributes().PutStr(string(semconv.ServerAddressKey), dsn.address)
+		}
+		if dsn.port > 0 {
+			span.Attributes().PutInt(string(semconv.ServerPortKey), int64(dsn.port))
+		}
+		if dsn.namespace != "" {
+			span.Attributes().PutStr(string(semconv.DBNamespaceKey), dsn.namespace)
+		}
+	}
 
 	query := unix.ByteSliceToString(e.Query[:])
 	if query != "" {
-		span.Attributes().PutStr(string(semconv.DBQueryTextKey), query)
+		text := query
+		if ShouldSanitizeDBStatement() {
+			text = sanitize(system, query)
+		}
+		span.Attributes().PutStr(string(semconv.DBQueryTextKey), text)
+	}
+
+	recordError(span, e)
+
+	if e.ConnWait > 0 {
+		span.Attributes().PutDouble(connWaitTimeKey, time.Duration(e.ConnWait).Seconds())
+	}
+
+	if name := e.Operation.name(); name != "" {
+		span.Attributes().PutStr(string(semconv.DBOperationNameKey), name)
+		span.SetName(name)
 	}
 
 	includeOperationVal := os.Getenv(ParseDBStatementEnvVar)
@@ -111,6 +375,10 @@ func processFn(e *event) ptrace.SpanSlice {
 		if err == nil && include {
 			operation, target, err := Parse(query)
 			if err == nil {
+				if name := e.Operation.name(); name != "" {
+					// The statement is prepared, not executed.
+					operation = name
+				}
 				name := ""
 				if operation != "" {
 					span.Attributes().PutStr(string(semconv.DBOperationNameKey), operation)
@@ -133,8 +401,9 @@ func processFn(e *event) ptrace.SpanSlice {
 	return spans
 }
 
-// shouldIncludeDBStatement returns if the user has configured SQL queries to be included.
-func shouldIncludeDBStatement() bool {
+// ShouldIncludeDBStatement returns if the user has configured database
+// statements to be included.
+func ShouldIncludeDBStatement() bool {
 	val := os.Getenv(IncludeDBStatementEnvVar)
 	if val != "" {
 		boolVal, err := strconv.ParseBool(val)
@@ -146,6 +415,20 @@ func shouldIncludeDBStatement() bool {
 	return false
 }
 
+// ShouldSanitizeDBStatement returns if included database statements need to
+// be sanitized. This is true unless the user has explicitly opted-out.
+func ShouldSanitizeDBStatement() bool {
+	val := os.Getenv(SanitizeDBStatementEnvVar)
+	if val != "" {
+		boolVal, err := strconv.ParseBool(val)
+		if err == nil {
+			return boolVal
+		}
+	}
+
+	return true
+}
+
 // Parse takes a SQL query string and returns the parsed query statement type
 // and table name, or an error if parsing failed.
 func Parse(query string) (string, string, error) {
@@ -6,8 +6,11 @@ package sql
 import (
 	"testing"
 	"time"
+	"unsafe"
 
+	"github.com/cilium/ebpf/perf"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -107,7 +110,7 @@ func TestProbeConvertEvent(t *testing.T) {
 		span.SetFlags(uint32(trace.FlagsSampled))
 		pdataconv.Attributes(
 			span.Attributes(),
-			semconv.DBQueryText("SELECT * FROM foo"),
+			semconv.DBQueryText("select * from foo"),
 			semconv.DBOperationName("SELECT"),
 			semconv.DBCollectionName("foo"),
 		)
@@ -115,3 +118,295 @@ func TestProbeConvertEvent(t *testing.T) {
 	}()
 	assert.Equal(t, want, got)
 }
+
+func TestProbeConvertEventNoSanitize(t *testing.T) {
+	t.Setenv(SanitizeDBStatementEnvVar, "false")
+
+	query := "SELECT * FROM foo WHERE id = 1"
+	var byteQuery [256]byte
+	copy(byteQuery[:], query)
+
+	got := processFn(&event{Query: byteQuery})
+	require.Equal(t, 1, got.Len())
+
+	v, ok := got.At(0).Attributes().Get(string(semconv.DBQueryTextKey))
+	require.True(t, ok)
+	assert.Equal(t, query, v.Str())
+}
+
+func TestProbeConvertEventOperation(t *testing.T) {
+	t.Setenv(ParseDBStatementEnvVar, "true")
+
+	tests := []struct {
+		op    operation
+		query string
+		name  string
+		attrs map[string]any
+	}{
+		{
+			op:    opExec,
+			query: "delete from foo",
+			name:  "DELETE foo",
+			attrs: map[string]any{
+				string(semconv.DBQueryTextKey):      "delete from foo",
+				string(semconv.DBOperationNameKey):  "DELETE",
+				string(semconv.DBCollectionNameKey): "foo",
+			},
+		},
+		{
+			op:    opPrepare,
+			query: "SELECT * FROM foo WHERE id = ?",
+			name:  "PREPARE foo",
+			attrs: map[string]any{
+				string(semconv.DBQueryTextKey):      "select * from foo where id = ?",
+				string(semconv.DBOperationNameKey):  "PREPARE",
+				string(semconv.DBCollectionNameKey): "foo",
+			},
+		},
+		{
+			op:    opBegin,
+			name:  "BEGIN",
+			attrs: map[string]any{string(semconv.DBOperationNameKey): "BEGIN"},
+		},
+		{
+			op:    opCommit,
+			name:  "COMMIT",
+			attrs: map[string]any{string(semconv.DBOperationNameKey): "COMMIT"},
+		},
+		{
+			op:    opRollback,
+			name:  "ROLLBACK",
+			attrs: map[string]any{string(semconv.DBOperationNameKey): "ROLLBACK"},
+		},
+		{
+			op:    opPing,
+			name:  "PING",
+			attrs: map[string]any{string(semconv.DBOperationNameKey): "PING"},
+		},
+		{
+			op:    opRaw,
+			name:  "RAW",
+			attrs: map[string]any{string(semconv.DBOperationNameKey): "RAW"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &event{Operation: tt.op}
+			copy(e.Query[:], tt.query)
+
+			got := processFn(e)
+			require.Equal(t, 1, got.Len())
+			span := got.At(0)
+			assert.Equal(t, tt.name, span.Name())
+			assert.Equal(t, tt.attrs, span.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestProbeConvertEventConnWait(t *testing.T) {
+	got := processFn(&event{
+		ConnWait:  uint64(250 * time.Millisecond),
+		Operation: opBegin,
+	})
+	require.Equal(t, 1, got.Len())
+
+	v, ok := got.At(0).Attributes().Get(connWaitTimeKey)
+	require.True(t, ok)
+	assert.Equal(t, 0.25, v.Double())
+
+	got = processFn(&event{Operation: opBegin})
+	require.Equal(t, 1, got.Len())
+	_, ok = got.At(0).Attributes().Get(connWaitTimeKey)
+	assert.False(t, ok)
+}
+
+func TestProbeConvertEventTraceState(t *testing.T) {
+	e := &event{Operation: opBegin}
+	e.State.OT = context.OTTraceState{Threshold: 0xc0000000000000, HasThreshold: 1}
+	e.State.Members.Len = uint32(copy(e.State.Members.Entries[:], "congo=t61rcWkgMzE"))
+
+	got := processFn(e)
+	require.Equal(t, 1, got.Len())
+	assert.Equal(t, "ot=th:c,congo=t61rcWkgMzE", got.At(0).TraceState().AsRaw())
+
+	got = processFn(&event{Operation: opBegin})
+	require.Equal(t, 1, got.Len())
+	assert.Empty(t, got.At(0).TraceState().AsRaw())
+}
+
+func TestProbeConvertEventDB(t *testing.T) {
+	newEvent := func(system dbSystem, driverName, dsn string) *event {
+		e := &event{Operation: opPing, DBSystem: system}
+		copy(e.DB.DriverName[:], driverName)
+		copy(e.DB.DSN[:], dsn)
+		e.DB.DSNLen = uint32(len(dsn))
+		return e
+	}
+
+	tests := []struct {
+		name  string
+		event *event
+		attrs map[string]any
+	}{
+		{
+			name:  "Connector",
+			event: newEvent(dbSystemMySQL, "", ""),
+			attrs: map[string]any{
+				string(semconv.DBSystemNameKey):    "mysql",
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+		{
+			name:  "Open",
+			event: newEvent(dbSystemPostgreSQL, "pgx", "postgres://user:secret@db:5433/orders"),
+			attrs: map[string]any{
+				string(semconv.DBSystemNameKey):    "postgresql",
+				string(semconv.ServerAddressKey):   "db",
+				string(semconv.ServerPortKey):      int64(5433),
+				string(semconv.DBNamespaceKey):     "orders",
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+		{
+			name:  "DriverName",
+			event: newEvent(dbSystemUnknown, "sqlite3", "file:orders.db?_auth_pass=secret"),
+			attrs: map[string]any{
+				string(semconv.DBSystemNameKey):    "sqlite",
+				string(semconv.DBNamespaceKey):     "orders.db",
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+		{
+			name: "Truncated",
+			event: func() *event {
+				e := newEvent(dbSystemPostgreSQL, "postgres", "host=db dbname=orders")
+				e.DB.DSNLen = uint32(len(e.DB.DSN) + 1)
+				return e
+			}(),
+			attrs: map[string]any{
+				string(semconv.DBSystemNameKey):    "postgresql",
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+		{
+			name:  "Unknown",
+			event: newEvent(dbSystemUnknown, "oracle", "oracle://user:secret@db:1521/orders"),
+			attrs: map[string]any{
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := processFn(tt.event)
+			require.Equal(t, 1, got.Len())
+			assert.Equal(t, tt.attrs, got.At(0).Attributes().AsRaw())
+		})
+	}
+}
+
+func TestProbeConvertEventSystemName(t *testing.T) {
+	got := processFn(&event{DBSystem: dbSystemPostgreSQL})
+	require.Equal(t, 1, got.Len())
+	assert.Equal(t, "postgresql", got.At(0).Name())
+
+	got = processFn(&event{})
+	require.Equal(t, 1, got.Len())
+	assert.Equal(t, "DB", got.At(0).Name())
+}
+
+func TestProcessRecord(t *testing.T) {
+	tests := []struct {
+		name       string
+		system     dbSystem
+		driverName string
+		dsn        string
+		attrs      map[string]any
+	}{
+		{
+			name:   "PostgreSQLURL",
+			system: dbSystemPostgreSQL,
+			dsn:    "postgres://user:secret@db:5433/orders?password=secret",
+			attrs: map[string]any{
+				string(semconv.DBSystemNameKey):    "postgresql",
+				string(semconv.ServerAddressKey):   "db",
+				string(semconv.ServerPortKey):      int64(5433),
+				string(semconv.DBNamespaceKey):     "orders",
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+		{
+			name:   "PostgreSQLKeywords",
+			system: dbSystemPostgreSQL,
+			dsn:    "host=/var/run/postgresql user=user password='se cret' dbname='my orders'",
+			attrs: map[string]any{
+				string(semconv.DBSystemNameKey):    "postgresql",
+				string(semconv.ServerAddressKey):   "/var/run/postgresql",
+				string(semconv.DBNamespaceKey):     "my orders",
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+		{
+			name:   "MySQL",
+			system: dbSystemMySQL,
+			dsn:    "user:secret@tcp([::1]:3307)/orders?parseTime=true",
+			attrs: map[string]any{
+				string(semconv.DBSystemNameKey):    "mysql",
+				string(semconv.ServerAddressKey):   "::1",
+				string(semconv.ServerPortKey):      int64(3307),
+				string(semconv.DBNamespaceKey):     "orders",
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+		{
+			name:       "SQLite",
+			driverName: "sqlite3",
+			dsn:        "file:orders.db?_auth_user=user&_auth_pass=secret",
+			attrs: map[string]any{
+				string(semconv.DBSystemNameKey):    "sqlite",
+				string(semconv.DBNamespaceKey):     "orders.db",
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+		{
+			name:       "Unknown",
+			driverName: "oracle",
+			dsn:        "oracle://user:secret@db:1521/orders",
+			attrs: map[string]any{
+				string(semconv.DBOperationNameKey): "PING",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := event{Operation: opPing, DBSystem: tt.system}
+			copy(e.DB.DriverName[:], tt.driverName)
+			copy(e.DB.DSN[:], tt.dsn)
+			e.DB.DSNLen = uint32(len(tt.dsn))
+			sample := make([]byte, unsafe.Sizeof(e))
+			copy(sample, unsafe.Slice((*byte)(unsafe.Pointer(&e)), unsafe.Sizeof(e)))
+
+			got, err := processRecord(perf.Record{RawSample: sample})
+			require.NoError(t, err)
+
+			// The sample is the one recorded.
+			assert.NotContains(t, string(sample), "secret")
+			assert.NotContains(t, string(sample), "user")
+
+			spans := processFn(got)
+			require.Equal(t, 1, spans.Len())
+			assert.Equal(t, tt.attrs, spans.At(0).Attributes().AsRaw())
+
+			// Replayed samples are decoded again.
+			got, err = processRecord(perf.Record{RawSample: sample})
+			require.NoError(t, err)
+			assert.Equal(t, tt.attrs, processFn(got).At(0).Attributes().AsRaw())
+		})
+	}
+
+	_, err := processRecord(perf.Record{RawSample: make([]byte, 8)})
+	assert.Error(t, err, "short sample")
+}
@@ -0,0 +1,322 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"strings"
+
+	"github.com/xwb1989/sqlparser"
+)
+
+// placeholder is the value literals are replaced with when a query is
+// sanitized.
+const placeholder = "?"
+
+// Sanitize returns query with all literal values replaced by "?" and all
+// IN-lists collapsed to a single "?". Double-quoted text is preserved as the
+// quoted identifier it is in standard SQL (e.g. PostgreSQL and SQLite). Use
+// [SanitizeMySQL] for queries where it is a string literal.
+//
+// The query is first normalized using the SQL parser. If the parser rejects
+// the query (i.e. it is a dialect the parser does not support or it has been
+// truncated), a tokenizer based redaction of the original query text is
+// returned instead. Queries containing double-quoted text are always
+// redacted by the tokenizer given the parser only supports the MySQL dialect.
+func Sanitize(query string) string {
+	if strings.IndexByte(query, '"') >= 0 {
+		return redact(query, true)
+	}
+	return normalize(query, true)
+}
+
+// SanitizeMySQL is like [Sanitize], but replaces double-quoted text as the
+// string literal it is in MySQL.
+func SanitizeMySQL(query string) string {
+	return normalize(query, false)
+}
+
+// sanitize returns the sanitized query sent to a database of system.
+func sanitize(system dbSystem, query string) string {
+	if system == dbSystemMySQL {
+		return SanitizeMySQL(query)
+	}
+	return Sanitize(query)
+}
+
+// normalize returns query normalized by the SQL parser with all literals
+// replaced. If the parser rejects query, it is redacted by the tokenizer
+// instead, keeping double-quoted text as an identifier if quotedIdents is
+// true.
+func normalize(query string, quotedIdents bool) string {
+	if query == "" {
+		return ""
+	}
+
+	stmt, err := sqlparser.Parse(query)
+	if err != nil {
+		return redact(query, quotedIdents)
+	}
+
+	buf := sqlparser.NewTrackedBuffer(sanitizeNode)
+	buf.WriteNode(stmt)
+	return buf.String()
+}
+
+// sanitizeNode is a [sqlparser.NodeFormatter] that formats literal values as
+// placeholders and collapses IN-lists.
+func sanitizeNode(buf *sqlparser.TrackedBuffer, node sqlparser.SQLNode) {
+	switch n := node.(type) {
+	case *sqlparser.SQLVal:
+		// This includes bind variables which are normalized to the same
+		// placeholder.
+		buf.WriteString(placeholder)
+	case *sqlparser.ComparisonExpr:
+		if n.Operator != sqlparser.InStr && n.Operator != sqlparser.NotInStr {
+			n.Format(buf)
+			return
+		}
+		if _, ok := n.Right.(sqlparser.ValTuple); !ok {
+			// Sub-queries and list arguments.
+			n.Format(buf)
+			return
+		}
+		buf.Myprintf("%v %s (%s)", n.Left, n.Operator, placeholder)
+		if n.Escape != nil {
+			buf.Myprintf(" escape %v", n.Escape)
+		}
+	default:
+		node.Format(buf)
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenOther tokenKind = iota
+	tokenWord
+	tokenSpace
+	tokenLiteral
+	tokenPlaceholder
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// redact returns query with all string, numeric, hex, and bit literals
+// replaced by "?", comments removed, and IN-lists collapsed. Double-quoted
+// text is kept as an identifier if quotedIdents is true, and replaced as a
+// string literal otherwise.
+func redact(query string, quotedIdents bool) string {
+	tokens := tokenize(query, quotedIdents)
+
+	var b strings.Builder
+	b.Grow(len(query))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.kind == tokenWord && strings.EqualFold(t.text, "in") {
+			if end, ok := inList(tokens, i+1); ok {
+				b.WriteString(t.text)
+				if tokens[i+1].kind == tokenSpace {
+					b.WriteString(tokens[i+1].text)
+				}
+				b.WriteString("(" + placeholder + ")")
+				i = end
+				continue
+			}
+		}
+		if t.kind == tokenLiteral {
+			b.WriteString(placeholder)
+			continue
+		}
+		b.WriteString(t.text)
+	}
+	return b.String()
+}
+
+// inList returns the index of the closing parenthesis of the IN-list starting
+// at tokens[start] and true if it only contains literals or placeholders.
+func inList(tokens []token, start int) (int, bool) {
+	i := start
+	if i < len(tokens) && tokens[i].kind == tokenSpace {
+		i++
+	}
+	if i >= len(tokens) || tokens[i].text != "(" {
+		return 0, false
+	}
+
+	var n int
+	for i++; i < len(tokens); i++ {
+		switch t := tokens[i]; t.kind {
+		case tokenLiteral, tokenPlaceholder:
+			n++
+		case tokenSpace:
+		case tokenOther:
+			switch t.text {
+			case ",":
+			case ")":
+				return i, n > 0
+			default:
+				return 0, false
+			}
+		default:
+			return 0, false
+		}
+	}
+	return 0, false
+}
+
+// tokenize splits query into tokens. Comments are dropped. Double-quoted
+// text is an identifier if quotedIdents is true, and a literal otherwise.
+func tokenize(query string, quotedIdents bool) []token {
+	var tokens []token
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case isSpace(c):
+			j := i + 1
+			for j < len(query) && isSpace(query[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenSpace, text: query[i:j]})
+			i = j
+		case c == '-' && strings.HasPrefix(query[i:], "--"):
+			j := strings.IndexByte(query[i:], '\n')
+			if j < 0 {
+				return tokens
+			}
+			i += j
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			j := strings.Index(query[i+2:], "*/")
+			if j < 0 {
+				return tokens
+			}
+			i += j + 4
+		case c == '\'':
+			tokens = append(tokens, token{kind: tokenLiteral})
+			i = skipQuoted(query, i, '\'')
+		case c == '"' && !quotedIdents:
+			tokens = append(tokens, token{kind: tokenLiteral})
+			i = skipQuoted(query, i, '"')
+		case c == '"' || c == '`':
+			// Quoted identifiers.
+			j := skipQuoted(query, i, c)
+			tokens = append(tokens, token{kind: tokenWord, text: query[i:j]})
+			i = j
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]):
+			// Positional parameters (e.g. $1).
+			j := i + 1
+			for j < len(query) && isDigit(query[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenPlaceholder, text: query[i:j]})
+			i = j
+		case c == '$':
+			// Dollar-quoted strings (e.g. $$text$$ or $tag$text$tag$).
+			j := i + 1
+			for j < len(query) && query[j] != '$' && isIdent(query[j]) {
+				j++
+			}
+			if j >= len(query) || query[j] != '$' {
+				tokens = append(tokens, token{kind: tokenOther, text: query[i:j]})
+				i = j
+				continue
+			}
+			tag := query[i : j+1]
+			k := strings.Index(query[j+1:], tag)
+			tokens = append(tokens, token{kind: tokenLiteral})
+			if k < 0 {
+				return tokens
+			}
+			i = j + 1 + k + len(tag)
+		case c == ':' && strings.HasPrefix(query[i:], "::"):
+			// Type casts (e.g. 'text'::varchar).
+			tokens = append(tokens, token{kind: tokenOther, text: "::"})
+			i += 2
+		case c == '?' || c == ':' && i+1 < len(query) && isIdent(query[i+1]):
+			j := i + 1
+			for c == ':' && j < len(query) && isIdent(query[j]) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenPlaceholder, text: query[i:j]})
+			i = j
+		case isDigit(c) || c == '.' && i+1 < len(query) && isDigit(query[i+1]):
+			tokens = append(tokens, token{kind: tokenLiteral})
+			i = skipNumber(query, i)
+		case isIdent(c):
+			j := i + 1
+			for j < len(query) && isIdent(query[j]) {
+				j++
+			}
+			if j-i == 1 && j < len(query) && query[j] == '\'' && strings.IndexByte("bBeEnNxX", c) >= 0 {
+				// Prefixed string literal (e.g. X'0A' or E'text').
+				tokens = append(tokens, token{kind: tokenLiteral})
+				i = skipQuoted(query, j, '\'')
+				continue
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: query[i:j]})
+			i = j
+		default:
+			tokens = append(tokens, token{kind: tokenOther, text: query[i : i+1]})
+			i++
+		}
+	}
+	return tokens
+}
+
+// skipQuoted returns the index after the quoted text starting at s[i]. Both
+// doubled quotes and backslash escapes are supported.
+func skipQuoted(s string, i int, quote byte) int {
+	for i++; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case quote:
+			if i+1 < len(s) && s[i+1] == quote {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+// skipNumber returns the index after the numeric literal starting at s[i].
+func skipNumber(s string, i int) int {
+	if strings.HasPrefix(s[i:], "0x") || strings.HasPrefix(s[i:], "0X") {
+		i += 2
+		for i < len(s) && isHex(s[i]) {
+			i++
+		}
+		return i
+	}
+
+	for i < len(s) {
+		switch c := s[i]; {
+		case isDigit(c), c == '.':
+			i++
+		case c == 'e' || c == 'E':
+			i++
+			if i < len(s) && (s[i] == '+' || s[i] == '-') {
+				i++
+			}
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+func isSpace(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' }
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func isHex(c byte) bool { return isDigit(c) || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F' }
+
+func isIdent(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(c) || c == '_' || c == '$' ||
+		c >= 0x80
+}
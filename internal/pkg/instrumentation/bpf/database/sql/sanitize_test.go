@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "empty",
+			query: "",
+			want:  "",
+		},
+		{
+			name:  "no literals",
+			query: "SELECT * FROM customers",
+			want:  "select * from customers",
+		},
+		{
+			name:  "string literals",
+			query: "SELECT * FROM customers WHERE email='mike@example.com'",
+			want:  "select * from customers where email = ?",
+		},
+		{
+			name:  "numeric literals",
+			query: "SELECT * FROM t WHERE id = 42 AND x = 1.5e3 AND h = 0xFF",
+			want:  "select * from t where id = ? and x = ? and h = ?",
+		},
+		{
+			name:  "bind variables",
+			query: "SELECT * FROM t WHERE id = ?",
+			want:  "select * from t where id = ?",
+		},
+		{
+			name:  "IN-list",
+			query: "SELECT * FROM customers WHERE first_name='Mike' AND last_name IN ('Santa', 'Banana')",
+			want:  "select * from customers where first_name = ? and last_name in (?)",
+		},
+		{
+			name:  "NOT IN-list",
+			query: "SELECT * FROM t WHERE id NOT IN (1,2)",
+			want:  "select * from t where id not in (?)",
+		},
+		{
+			name:  "IN sub-query",
+			query: "SELECT * FROM t WHERE a IN (SELECT b FROM c WHERE d = 1)",
+			want:  "select * from t where a in (select b from c where d = ?)",
+		},
+		{
+			name:  "insert",
+			query: "INSERT INTO contacts (first_name, age) VALUES ('Mike', 30)",
+			want:  "insert into contacts(first_name, age) values (?, ?)",
+		},
+		{
+			name:  "unsupported dialect",
+			query: "SELECT * FROM users WHERE email = $1 AND note = 'it''s' AND id IN (1, 2, 3) RETURNING id",
+			want:  "SELECT * FROM users WHERE email = $1 AND note = ? AND id IN (?) RETURNING id",
+		},
+		{
+			name:  "truncated",
+			query: "SELECT * FROM users WHERE password = 'hunter",
+			want:  "SELECT * FROM users WHERE password = ?",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Sanitize(tt.query))
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "escaped quotes",
+			query: `SELECT 'it''s', 'a\'b' FROM t`,
+			want:  "SELECT ?, ? FROM t",
+		},
+		{
+			name:  "quoted identifiers",
+			query: "SELECT \"first name\", `last name` FROM t WHERE \"id\" = 1",
+			want:  "SELECT \"first name\", `last name` FROM t WHERE \"id\" = ?",
+		},
+		{
+			name:  "identifiers with digits",
+			query: "SELECT col1 FROM t2 WHERE x_3 = 3",
+			want:  "SELECT col1 FROM t2 WHERE x_3 = ?",
+		},
+		{
+			name:  "dollar quoted",
+			query: "SELECT $$secret$$, $tag$also $ secret$tag$ FROM t",
+			want:  "SELECT ?, ? FROM t",
+		},
+		{
+			name:  "prefixed strings",
+			query: "SELECT E'x', X'0A', N'y' FROM t",
+			want:  "SELECT ?, ?, ? FROM t",
+		},
+		{
+			name:  "type cast",
+			query: "SELECT 'a'::text, :name FROM t",
+			want:  "SELECT ?::text, :name FROM t",
+		},
+		{
+			name:  "comments",
+			query: "SELECT a -- 'secret'\nFROM t /* 'secret' */ WHERE a = 10",
+			want:  "SELECT a \nFROM t  WHERE a = ?",
+		},
+		{
+			name:  "IN-list with placeholders",
+			query: "SELECT a FROM t WHERE a IN ($1, $2, $3)",
+			want:  "SELECT a FROM t WHERE a IN (?)",
+		},
+		{
+			name:  "IN-list with expressions",
+			query: "SELECT a FROM t WHERE a IN (b, 2)",
+			want:  "SELECT a FROM t WHERE a IN (b, ?)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, redact(tt.query, true))
+		})
+	}
+}
+
+func TestSanitizeQuotes(t *testing.T) {
+	query := `SELECT "Name" FROM "Users" WHERE password = "hunter2" AND id = 1`
+	assert.Equal(
+		t,
+		`SELECT "Name" FROM "Users" WHERE password = "hunter2" AND id = ?`,
+		Sanitize(query),
+	)
+	// Rejected by the parser.
+	assert.Equal(t, "SELECT ? FROM ? WHERE password = ? AND id = ?", SanitizeMySQL(query))
+
+	query = `SELECT * FROM users WHERE password = "hunter2"`
+	assert.Equal(t, "select * from users where password = ?", SanitizeMySQL(query))
+}
+
+func TestSanitizeSystem(t *testing.T) {
+	query := `SELECT * FROM t WHERE a = "b" AND c = 1`
+	assert.Equal(t, `SELECT * FROM t WHERE a = "b" AND c = ?`, sanitize(dbSystemPostgreSQL, query))
+	assert.Equal(t, `SELECT * FROM t WHERE a = "b" AND c = ?`, sanitize(dbSystemSQLite, query))
+	assert.Equal(t, `SELECT * FROM t WHERE a = "b" AND c = ?`, sanitize(dbSystemUnknown, query))
+	assert.Equal(t, "select * from t where a = ? and c = ?", sanitize(dbSystemMySQL, query))
+}
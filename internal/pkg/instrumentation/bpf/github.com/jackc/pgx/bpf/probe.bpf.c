@@ -0,0 +1,283 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#include "arguments.h"
+#include "trace/span_context.h"
+#include "go_context.h"
+#include "go_types.h"
+#include "go_errors.h"
+#include "uprobe.h"
+#include "trace/start_span.h"
+
+char __license[] SEC("license") = "Dual MIT/GPL";
+
+#define MAX_QUERY_SIZE 256
+#define MAX_CONCURRENT 50
+#define MAX_HOST_SIZE 128
+#define MAX_DATABASE_SIZE 64
+// The parts of the table identifier read: schema and table names.
+#define MAX_TABLE_PARTS 2
+#define MAX_TABLE_PART_SIZE 64
+
+#define PG_ERROR_CODE_SIZE 8
+#define PG_ERROR_SEVERITY_SIZE 16
+#define PG_ERROR_MSG_SIZE 128
+
+// The pgx operations of spans. They need to match the ones of the operation
+// type in user space.
+#define PGX_OP_QUERY 0
+#define PGX_OP_EXEC 1
+#define PGX_OP_BATCH 2
+#define PGX_OP_COPY 3
+
+// The fields of a *pgconn.PgError needed to rebuild its message and to record
+// its SQLSTATE code.
+struct pg_error_t {
+    char code[PG_ERROR_CODE_SIZE];
+    char severity[PG_ERROR_SEVERITY_SIZE];
+    char msg[PG_ERROR_MSG_SIZE];
+};
+
+struct pgx_request_t {
+    BASE_SPAN_PROPERTIES
+    char query[MAX_QUERY_SIZE];
+    // The identifier of the table copied to.
+    char table[MAX_TABLE_PARTS][MAX_TABLE_PART_SIZE];
+    char host[MAX_HOST_SIZE];
+    char database[MAX_DATABASE_SIZE];
+    // The number of queries of a batch.
+    u64 batch_size;
+    u16 port;
+    u8 operation;
+    // Whether the error returned is a *pgconn.PgError read into pg_error.
+    u8 is_pg_error;
+    u8 padding[4];
+    struct go_error error;
+    struct pg_error_t pg_error;
+};
+
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __type(key, void *);
+    __type(value, struct pgx_request_t);
+    __uint(max_entries, MAX_CONCURRENT);
+} pgx_events SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(struct pgx_request_t));
+    __uint(max_entries, 1);
+} pgx_uprobe_storage_map SEC(".maps");
+
+// Injected in init
+volatile const u64 conn_config_pos;
+volatile const u64 conn_config_config_pos;
+volatile const u64 config_host_pos;
+volatile const u64 config_port_pos;
+volatile const u64 config_database_pos;
+volatile const u64 batch_queued_queries_pos;
+
+// The address of the itab of *pgconn.PgError implementing error, 0 if it is
+// not used by the target binary, and the offsets of its fields.
+volatile const u64 pg_error_itab;
+volatile const u64 pg_error_severity_pos;
+volatile const u64 pg_error_code_pos;
+volatile const u64 pg_error_msg_pos;
+
+// Read the host, port, and database of the configuration of the *pgx.Conn
+// conn into req.
+static __always_inline void read_conn_config(void *conn, struct pgx_request_t *req) {
+    void *conn_config = NULL;
+    bpf_probe_read_user(&conn_config, sizeof(conn_config), conn + conn_config_pos);
+    if (conn_config == NULL) {
+        return;
+    }
+
+    void *config = conn_config + conn_config_config_pos;
+    get_go_string_from_user_ptr(config + config_host_pos, req->host, sizeof(req->host));
+    bpf_probe_read_user(&req->port, sizeof(req->port), config + config_port_pos);
+    get_go_string_from_user_ptr(
+        config + config_database_pos, req->database, sizeof(req->database));
+}
+
+// Returns the pgx_request_t of a new span of the operation on the *pgx.Conn
+// conn, started with the context.Context argument at index 2. The request is
+// not tracked until it is passed to track_pgx_span.
+static __always_inline struct pgx_request_t *
+start_pgx_span(struct pt_regs *ctx, u8 operation, void *query_ptr, u64 query_len) {
+    u32 map_id = 0;
+    struct pgx_request_t *req = bpf_map_lookup_elem(&pgx_uprobe_storage_map, &map_id);
+    if (req == NULL) {
+        bpf_printk("start_pgx_span: req is NULL");
+        return NULL;
+    }
+
+    __builtin_memset(req, 0, sizeof(struct pgx_request_t));
+    req->start_time = bpf_ktime_get_ns();
+    req->operation = operation;
+    read_conn_config(get_argument(ctx, 1), req);
+
+    // The query is read to derive the operation and the collection names. It
+    // is only recorded if opted-in in user space.
+    sampling_attributes_t sampling_attrs = {0};
+    sampling_attributes_t *sampling_attrs_ptr = NULL;
+    if (query_ptr != NULL) {
+        u64 query_size = MAX_QUERY_SIZE < query_len ? MAX_QUERY_SIZE : query_len;
+        bpf_probe_read_user(req->query, query_size, query_ptr);
+
+        sampling_attrs_set_value(
+            &sampling_attrs, SAMPLING_ATTR_DB_OPERATION, query_ptr, (s64)query_len);
+        sampling_attrs_ptr = &sampling_attrs;
+    }
+
+    struct go_iface go_context = {0};
+    get_Go_context(ctx, 2, 0, true, &go_context);
+    start_span_params_t start_span_params = {
+        .ctx = ctx,
+        .go_context = &go_context,
+        .psc = &req->psc,
+        .sc = &req->sc,
+        .state = &req->state,
+        .get_parent_span_context_fn = NULL,
+        .get_parent_span_context_arg = NULL,
+        .sampling_attrs = sampling_attrs_ptr,
+    };
+    start_span(&start_span_params);
+    return req;
+}
+
+// Tracks the span of req until the instrumented function returns.
+static __always_inline int track_pgx_span(struct pt_regs *ctx, struct pgx_request_t *req) {
+    void *key = (void *)GOROUTINE(ctx);
+    bpf_map_update_elem(&pgx_events, &key, req, 0);
+    return 0;
+}
+
+// Start a span of the operation with the SQL query string at the argument
+// indices query_ptr_pos and query_len_pos.
+static __always_inline int
+start_pgx_query_span(struct pt_regs *ctx, u8 operation, int query_ptr_pos, int query_len_pos) {
+    struct pgx_request_t *req = start_pgx_span(ctx,
+                                               operation,
+                                               get_argument(ctx, query_ptr_pos),
+                                               (u64)get_argument(ctx, query_len_pos));
+    if (req == NULL) {
+        return 0;
+    }
+    return track_pgx_span(ctx, req);
+}
+
+// Ends the span of the goroutine, recording the error returned at the
+// error_pos argument of the return probe. The function does not return an
+// error if error_pos is 0.
+static __always_inline int end_pgx_span(struct pt_regs *ctx, int error_pos) {
+    void *key = (void *)GOROUTINE(ctx);
+    struct pgx_request_t *req = bpf_map_lookup_elem(&pgx_events, &key);
+    if (req == NULL) {
+        bpf_printk("event is NULL in ret probe");
+        return 0;
+    }
+    req->end_time = bpf_ktime_get_ns();
+
+    void *err_itab = get_argument(ctx, error_pos);
+    void *err_data = get_argument(ctx, error_pos + 1);
+    if (pg_error_itab != 0 && (u64)err_itab == pg_error_itab && err_data != NULL &&
+        pg_error_msg_pos != 0) {
+        req->is_pg_error = 1;
+        // The message is rebuilt from the fields of the *pgconn.PgError.
+        req->error.kinds[0] = GO_ERROR_OTHER;
+        get_go_string_from_user_ptr(
+            err_data + pg_error_severity_pos, req->pg_error.severity, PG_ERROR_SEVERITY_SIZE);
+        get_go_string_from_user_ptr(
+            err_data + pg_error_code_pos, req->pg_error.code, PG_ERROR_CODE_SIZE);
+        get_go_string_from_user_ptr(
+            err_data + pg_error_msg_pos, req->pg_error.msg, PG_ERROR_MSG_SIZE);
+    } else {
+        read_go_error(err_itab, err_data, &req->error);
+    }
+
+    output_span_event(ctx, req, sizeof(*req), &req->sc, req->start_time, req->end_time);
+    stop_tracking_span(&req->sc, &req->psc);
+    bpf_map_delete_elem(&pgx_events, &key);
+    return 0;
+}
+
+// Defines the return probe of the name instrumented function, which returns
+// an error at the error_pos argument, or no error if error_pos is 0.
+#define PGX_UPROBE_RETURN(name, error_pos)                                                         \
+    SEC("uprobe/##name##")                                                                         \
+    int uprobe_##name##_Returns(struct pt_regs *ctx) { return end_pgx_span(ctx, error_pos); }
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) Query(ctx context.Context, sql string, args ...any) (Rows, error)
+SEC("uprobe/Conn_Query")
+int uprobe_Conn_Query(struct pt_regs *ctx) {
+    return start_pgx_query_span(ctx, PGX_OP_QUERY, 4, 5);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) Query(ctx context.Context, sql string, args ...any) (Rows, error)
+PGX_UPROBE_RETURN(Conn_Query, 3)
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+SEC("uprobe/Conn_Exec")
+int uprobe_Conn_Exec(struct pt_regs *ctx) {
+    return start_pgx_query_span(ctx, PGX_OP_EXEC, 4, 5);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+PGX_UPROBE_RETURN(Conn_Exec, 3)
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) SendBatch(ctx context.Context, b *Batch) (br BatchResults)
+SEC("uprobe/Conn_SendBatch")
+int uprobe_Conn_SendBatch(struct pt_regs *ctx) {
+    struct pgx_request_t *req = start_pgx_span(ctx, PGX_OP_BATCH, NULL, 0);
+    if (req == NULL) {
+        return 0;
+    }
+
+    void *batch = get_argument(ctx, 4);
+    if (batch != NULL) {
+        struct go_slice queued_queries = {0};
+        bpf_probe_read_user(
+            &queued_queries, sizeof(queued_queries), batch + batch_queued_queries_pos);
+        req->batch_size = queued_queries.len;
+    }
+    return track_pgx_span(ctx, req);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) SendBatch(ctx context.Context, b *Batch) (br BatchResults)
+//
+// The errors of the queries are returned by the BatchResults.
+PGX_UPROBE_RETURN(Conn_SendBatch, 0)
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) CopyFrom(ctx context.Context, tableName Identifier, columnNames []string, rowSrc CopyFromSource) (int64, error)
+SEC("uprobe/Conn_CopyFrom")
+int uprobe_Conn_CopyFrom(struct pt_regs *ctx) {
+    struct pgx_request_t *req = start_pgx_span(ctx, PGX_OP_COPY, NULL, 0);
+    if (req == NULL) {
+        return 0;
+    }
+
+    // The Identifier is a []string of the parts of the table name.
+    void *parts = get_argument(ctx, 4);
+    u64 parts_len = (u64)get_argument(ctx, 5);
+    for (u64 i = 0; i < MAX_TABLE_PARTS; i++) {
+        if (i >= parts_len) {
+            break;
+        }
+        get_go_string_from_user_ptr(
+            parts + i * sizeof(struct go_string), req->table[i], MAX_TABLE_PART_SIZE);
+    }
+    return track_pgx_span(ctx, req);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) CopyFrom(ctx context.Context, tableName Identifier, columnNames []string, rowSrc CopyFromSource) (int64, error)
+PGX_UPROBE_RETURN(Conn_CopyFrom, 2)
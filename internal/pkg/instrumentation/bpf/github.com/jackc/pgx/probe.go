@@ -0,0 +1,299 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package pgx provides an instrumentation probe for PostgreSQL clients using
+// the [github.com/jackc/pgx/v5] package.
+package pgx
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sys/unix"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/database/sql"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/context"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/kernel"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
+	"go.opentelemetry.io/auto/internal/pkg/structfield"
+)
+
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -target amd64,arm64 bpf ./bpf/probe.bpf.c
+
+const (
+	// pkg is the package being instrumented.
+	pkg = "github.com/jackc/pgx/v5"
+
+	// system is the database management system of the spans.
+	system = "postgresql"
+)
+
+// batchQueuedQueriesExportedVersion is the first version of pgx with the
+// exported Batch.QueuedQueries field.
+var batchQueuedQueriesExportedVersion = semver.New(5, 5, 3, "", "")
+
+// New returns a new [probe.Probe].
+func New(logger *slog.Logger, version string) probe.Probe {
+	id := probe.ID{
+		SpanKind:        trace.SpanKindClient,
+		InstrumentedPkg: pkg,
+	}
+	return &probe.SpanProducer[bpfObjects, event]{
+		Base: probe.Base[bpfObjects, event]{
+			ID:     id,
+			Logger: logger,
+			Consts: append([]probe.Const{
+				probe.AllocationConst{},
+				probe.StructFieldConst{
+					Key: "conn_config_pos",
+					ID:  structfield.NewID(pkg, pkg, "Conn", "config"),
+				},
+				probe.StructFieldConst{
+					Key: "conn_config_config_pos",
+					ID:  structfield.NewID(pkg, pkg, "ConnConfig", "Config"),
+				},
+				probe.StructFieldConst{
+					Key: "config_host_pos",
+					ID:  structfield.NewID(pkg, pkg+"/pgconn", "Config", "Host"),
+				},
+				probe.StructFieldConst{
+					Key: "config_port_pos",
+					ID:  structfield.NewID(pkg, pkg+"/pgconn", "Config", "Port"),
+				},
+				probe.StructFieldConst{
+					Key: "config_database_pos",
+					ID:  structfield.NewID(pkg, pkg+"/pgconn", "Config", "Database"),
+				},
+				probe.StructFieldConstMaxVersion{
+					StructField: probe.StructFieldConst{
+						Key: "batch_queued_queries_pos",
+						ID:  structfield.NewID(pkg, pkg, "Batch", "queuedQueries"),
+					},
+					MaxVersion: batchQueuedQueriesExportedVersion,
+				},
+				probe.StructFieldConstMinVersion{
+					StructField: probe.StructFieldConst{
+						Key: "batch_queued_queries_pos",
+						ID:  structfield.NewID(pkg, pkg, "Batch", "QueuedQueries"),
+					},
+					MinVersion: batchQueuedQueriesExportedVersion,
+				},
+				probe.ItabConst{
+					Key:       "pg_error_itab",
+					Type:      pgErrorType,
+					Interface: "error",
+				},
+				pgErrorField("pg_error_severity_pos", "Severity"),
+				pgErrorField("pg_error_code_pos", "Code"),
+				pgErrorField("pg_error_msg_pos", "Message"),
+			}, probe.GoErrorConsts...),
+			Uprobes: []*probe.Uprobe{
+				{
+					Sym:         pkg + ".(*Conn).Query",
+					EntryProbe:  "uprobe_Conn_Query",
+					ReturnProbe: "uprobe_Conn_Query_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         pkg + ".(*Conn).Exec",
+					EntryProbe:  "uprobe_Conn_Exec",
+					ReturnProbe: "uprobe_Conn_Exec_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         pkg + ".(*Conn).SendBatch",
+					EntryProbe:  "uprobe_Conn_SendBatch",
+					ReturnProbe: "uprobe_Conn_SendBatch_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         pkg + ".(*Conn).CopyFrom",
+					EntryProbe:  "uprobe_Conn_CopyFrom",
+					ReturnProbe: "uprobe_Conn_CopyFrom_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+			},
+			SpecFn: loadBpf,
+		},
+		Version:   version,
+		SchemaURL: semconv.SchemaURL,
+		ProcessFn: processFn,
+	}
+}
+
+// pgErrorField returns the [probe.StructFieldConstOptional] of the field of
+// pgconn.PgError. Errors are recorded as unknown ones if it is not found.
+func pgErrorField(key, field string) probe.StructFieldConstOptional {
+	return probe.StructFieldConstOptional{
+		StructField: probe.StructFieldConst{
+			Key: key,
+			ID:  structfield.NewID(pkg, pkg+"/pgconn", "PgError", field),
+		},
+	}
+}
+
+// operation is the pgx operation of an event. The values need to match the
+// PGX_OP_* ones of the eBPF program.
+type operation uint8
+
+const (
+	opQuery operation = iota
+	opExec
+	opBatch
+	opCopy
+)
+
+// event represents a pgx operation on a PostgreSQL database.
+type event struct {
+	context.BaseSpanProperties
+	Query [256]byte
+	// Table holds the parts of the identifier of the table copied to.
+	Table     [2][64]byte
+	Host      [128]byte
+	Database  [64]byte
+	BatchSize uint64
+	Port      uint16
+	Operation operation
+	IsPgError uint8
+	_         [4]byte
+	// Err is the error returned by the instrumented function. It is not set
+	// if the error is a *pgconn.PgError, read into PgError instead.
+	Err     probe.GoError
+	PgError pgError
+}
+
+// pgErrorType is the Go type of the errors reported by PostgreSQL servers.
+const pgErrorType = "*github.com/jackc/pgx/v5/pgconn.PgError"
+
+// pgError is the value of a *pgconn.PgError, read by eBPF.
+type pgError struct {
+	Code     [8]byte
+	Severity [16]byte
+	Msg      [128]byte
+}
+
+// Message returns the message of the error, as returned by its Error method.
+func (e *pgError) Message() string {
+	severity := unix.ByteSliceToString(e.Severity[:])
+	msg := unix.ByteSliceToString(e.Msg[:])
+	code := unix.ByteSliceToString(e.Code[:])
+	return severity + ": " + msg + " (SQLSTATE " + code + ")"
+}
+
+func processFn(e *event) ptrace.SpanSlice {
+	spans := ptrace.NewSpanSlice()
+	span := spans.AppendEmpty()
+	span.SetKind(ptrace.SpanKindClient)
+	span.SetStartTimestamp(kernel.BootOffsetToTimestamp(e.StartTime))
+	span.SetEndTimestamp(kernel.BootOffsetToTimestamp(e.EndTime))
+	span.SetTraceID(pcommon.TraceID(e.SpanContext.TraceID))
+	span.SetSpanID(pcommon.SpanID(e.SpanContext.SpanID))
+	span.SetFlags(uint32(trace.FlagsSampled))
+
+	if e.ParentSpanContext.SpanID.IsValid() {
+		span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
+	}
+	span.TraceState().FromRaw(e.State.TraceState())
+
+	attrs := span.Attributes()
+	attrs.PutStr(string(semconv.DBSystemNameKey), system)
+
+	if host := unix.ByteSliceToString(e.Host[:]); host != "" {
+		attrs.PutStr(string(semconv.ServerAddressKey), host)
+	}
+	if e.Port > 0 {
+		attrs.PutInt(string(semconv.ServerPortKey), int64(e.Port))
+	}
+	if db := unix.ByteSliceToString(e.Database[:]); db != "" {
+		attrs.PutStr(string(semconv.DBNamespaceKey), db)
+	}
+
+	query := unix.ByteSliceToString(e.Query[:])
+	var operation, collection string
+	switch e.Operation {
+	case opBatch:
+		operation = "BATCH"
+		attrs.PutInt(string(semconv.DBOperationBatchSizeKey), int64(e.BatchSize))
+	case opCopy:
+		operation = "COPY"
+		collection = tableName(e.Table)
+	default:
+		operation, collection = parse(query)
+	}
+
+	name := system
+	if operation != "" {
+		attrs.PutStr(string(semconv.DBOperationNameKey), operation)
+		name = operation
+	}
+	if collection != "" {
+		attrs.PutStr(string(semconv.DBCollectionNameKey), collection)
+		if operation != "" {
+			name += " " + collection
+		}
+	}
+	span.SetName(name)
+
+	if query != "" && sql.ShouldIncludeDBStatement() {
+		text := query
+		if sql.ShouldSanitizeDBStatement() {
+			text = sql.Sanitize(query)
+		}
+		attrs.PutStr(string(semconv.DBQueryTextKey), text)
+	}
+
+	recordError(span, e)
+
+	return spans
+}
+
+// positionalParam matches the positional parameters of PostgreSQL queries,
+// e.g. $1, which cannot be parsed.
+var positionalParam = regexp.MustCompile(`\$[0-9]+`)
+
+// parse returns the operation and collection names of query. The operation
+// is the first keyword of the query if it cannot be parsed.
+func parse(query string) (string, string) {
+	operation, collection, err := sql.Parse(positionalParam.ReplaceAllLiteralString(query, "?"))
+	if err == nil && operation != "" {
+		return operation, collection
+	}
+	keyword, _, _ := strings.Cut(strings.TrimSpace(query), " ")
+	return strings.ToUpper(keyword), ""
+}
+
+// tableName returns the name of the table identified by parts, as quoted by
+// pgx.Identifier.Sanitize.
+func tableName(parts [2][64]byte) string {
+	names := make([]string, 0, len(parts))
+	for _, p := range parts {
+		part := unix.ByteSliceToString(p[:])
+		if part == "" {
+			break
+		}
+		names = append(names, `"`+strings.ReplaceAll(part, `"`, `""`)+`"`)
+	}
+	return strings.Join(names, ".")
+}
+
+// recordError sets the status of span to Error and records the error of e,
+// if any, as returned by the instrumented function.
+func recordError(span ptrace.Span, e *event) {
+	if e.IsPgError != 0 {
+		probe.RecordException(span, pgErrorType, e.PgError.Message())
+		span.Attributes().PutStr(string(semconv.ErrorTypeKey), pgErrorType)
+		code := unix.ByteSliceToString(e.PgError.Code[:])
+		if code != "" {
+			span.Attributes().PutStr(string(semconv.DBResponseStatusCodeKey), code)
+		}
+		return
+	}
+
+	e.Err.Record(span)
+}
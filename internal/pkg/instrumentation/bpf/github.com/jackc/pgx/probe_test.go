@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pgx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/database/sql"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/context"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/kernel"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/pdataconv"
+)
+
+func newEvent(op operation, query string) *event {
+	e := &event{Operation: op, Port: 5432}
+	copy(e.Query[:], query)
+	copy(e.Host[:], "db.example.com")
+	copy(e.Database[:], "shop")
+	return e
+}
+
+func TestProbeConvertEvent(t *testing.T) {
+	t.Setenv(sql.IncludeDBStatementEnvVar, "true")
+
+	start := time.Unix(0, time.Now().UnixNano()) // No wall clock.
+	end := start.Add(1 * time.Second)
+
+	startOffset := kernel.TimeToBootOffset(start)
+	endOffset := kernel.TimeToBootOffset(end)
+
+	traceID := trace.TraceID{1}
+	spanID := trace.SpanID{1}
+
+	e := newEvent(opQuery, "SELECT * FROM customers WHERE id = 42")
+	e.BaseSpanProperties = context.BaseSpanProperties{
+		StartTime:   startOffset,
+		EndTime:     endOffset,
+		SpanContext: context.EBPFSpanContext{TraceID: traceID, SpanID: spanID},
+	}
+	got := processFn(e)
+
+	want := func() ptrace.SpanSlice {
+		spans := ptrace.NewSpanSlice()
+		span := spans.AppendEmpty()
+		span.SetName("SELECT customers")
+		span.SetKind(ptrace.SpanKindClient)
+		span.SetStartTimestamp(kernel.BootOffsetToTimestamp(startOffset))
+		span.SetEndTimestamp(kernel.BootOffsetToTimestamp(endOffset))
+		span.SetTraceID(pcommon.TraceID(traceID))
+		span.SetSpanID(pcommon.SpanID(spanID))
+		span.SetFlags(uint32(trace.FlagsSampled))
+		pdataconv.Attributes(
+			span.Attributes(),
+			semconv.DBSystemNamePostgreSQL,
+			semconv.ServerAddress("db.example.com"),
+			semconv.ServerPort(5432),
+			semconv.DBNamespace("shop"),
+			semconv.DBOperationName("SELECT"),
+			semconv.DBCollectionName("customers"),
+			semconv.DBQueryText("select * from customers where id = ?"),
+		)
+		return spans
+	}()
+	assert.Equal(t, want, got)
+}
+
+func TestProbeConvertEventOperations(t *testing.T) {
+	// The operation and collection names are recorded without the query.
+	t.Setenv(sql.IncludeDBStatementEnvVar, "false")
+
+	tests := []struct {
+		name  string
+		event *event
+		span  string
+		attrs map[string]any
+	}{
+		{
+			name:  "exec",
+			event: newEvent(opExec, "DELETE FROM orders WHERE id = $1"),
+			span:  "DELETE orders",
+			attrs: map[string]any{
+				string(semconv.DBOperationNameKey):  "DELETE",
+				string(semconv.DBCollectionNameKey): "orders",
+			},
+		},
+		{
+			name:  "exec parsed",
+			event: newEvent(opExec, "UPDATE orders SET paid = true"),
+			span:  "UPDATE orders",
+			attrs: map[string]any{
+				string(semconv.DBOperationNameKey):  "UPDATE",
+				string(semconv.DBCollectionNameKey): "orders",
+			},
+		},
+		{
+			name: "batch",
+			event: func() *event {
+				e := newEvent(opBatch, "")
+				e.BatchSize = 3
+				return e
+			}(),
+			span: "BATCH",
+			attrs: map[string]any{
+				string(semconv.DBOperationNameKey):      "BATCH",
+				string(semconv.DBOperationBatchSizeKey): int64(3),
+			},
+		},
+		{
+			name: "copy",
+			event: func() *event {
+				e := newEvent(opCopy, "")
+				copy(e.Table[0][:], "public")
+				copy(e.Table[1][:], "orders")
+				return e
+			}(),
+			span: `COPY "public"."orders"`,
+			attrs: map[string]any{
+				string(semconv.DBOperationNameKey):  "COPY",
+				string(semconv.DBCollectionNameKey): `"public"."orders"`,
+			},
+		},
+		{
+			name:  "unknown",
+			event: newEvent(opQuery, ""),
+			span:  "postgresql",
+			attrs: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := processFn(tt.event)
+			require.Equal(t, 1, got.Len())
+			span := got.At(0)
+			assert.Equal(t, tt.span, span.Name())
+
+			want := map[string]any{
+				string(semconv.DBSystemNameKey):  "postgresql",
+				string(semconv.ServerAddressKey): "db.example.com",
+				string(semconv.ServerPortKey):    int64(5432),
+				string(semconv.DBNamespaceKey):   "shop",
+			}
+			for k, v := range tt.attrs {
+				want[k] = v
+			}
+			// Queries are not included unless opted-in.
+			assert.Equal(t, want, span.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestProbeConvertEventError(t *testing.T) {
+	e := &event{IsPgError: 1}
+	copy(e.PgError.Severity[:], "ERROR")
+	copy(e.PgError.Code[:], "23505")
+	copy(e.PgError.Msg[:], "duplicate key value")
+	e.Err.Kinds[0] = 1 // Unknown Go error.
+	got := processFn(e)
+	require.Equal(t, 1, got.Len())
+	span := got.At(0)
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, "ERROR: duplicate key value (SQLSTATE 23505)", span.Status().Message())
+	assert.Equal(t, map[string]any{
+		string(semconv.DBSystemNameKey):         "postgresql",
+		string(semconv.ErrorTypeKey):            pgErrorType,
+		string(semconv.DBResponseStatusCodeKey): "23505",
+	}, span.Attributes().AsRaw())
+	require.Equal(t, 1, span.Events().Len())
+	assert.Equal(t, semconv.ExceptionEventName, span.Events().At(0).Name())
+}
@@ -0,0 +1,265 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#include "arguments.h"
+#include "trace/span_context.h"
+#include "go_context.h"
+#include "go_types.h"
+#include "go_errors.h"
+#include "uprobe.h"
+#include "trace/start_span.h"
+
+char __license[] SEC("license") = "Dual MIT/GPL";
+
+#define MAX_COMMAND_SIZE 32
+#define MAX_ADDR_SIZE 128
+#define MAX_REDIS_ERROR_SIZE 128
+#define MAX_CONCURRENT 50
+
+// The go-redis operations of spans. They need to match the ones of the
+// operation type in user space.
+#define REDIS_OP_COMMAND 0
+#define REDIS_OP_PIPELINE 1
+#define REDIS_OP_TX_PIPELINE 2
+
+struct redis_request_t {
+    BASE_SPAN_PROPERTIES
+    // The name of the command, its first argument.
+    char command[MAX_COMMAND_SIZE];
+    // The address of the server, in the host:port form.
+    char addr[MAX_ADDR_SIZE];
+    s64 db;
+    // The number of arguments of the command, including its name.
+    u64 args_count;
+    // The number of commands of a pipeline.
+    u64 batch_size;
+    u8 operation;
+    // Whether the error returned is a proto.RedisError read into redis_error.
+    u8 is_redis_error;
+    u8 padding[6];
+    struct go_error error;
+    char redis_error[MAX_REDIS_ERROR_SIZE];
+};
+
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __type(key, void *);
+    __type(value, struct redis_request_t);
+    __uint(max_entries, MAX_CONCURRENT);
+} redis_events SEC(".maps");
+
+// The number of instrumented functions called by a goroutine while the span
+// of an outer one is in progress, keyed by goroutine. These are the commands
+// initializing a new connection, e.g. HELLO or a pipeline of AUTH and SELECT,
+// when the outer command acquires it. They are not traced, they would replace
+// the span of the outer command.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, u64);
+    __uint(max_entries, MAX_CONCURRENT);
+} redis_nested_calls SEC(".maps");
+
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(struct redis_request_t));
+    __uint(max_entries, 1);
+} redis_uprobe_storage_map SEC(".maps");
+
+// Injected in init
+volatile const u64 base_client_opt_pos;
+volatile const u64 options_addr_pos;
+volatile const u64 options_db_pos;
+volatile const u64 base_cmd_args_pos;
+
+// The address of the itab of proto.RedisError implementing error, 0 if it is
+// not used by the target binary.
+volatile const u64 redis_error_itab;
+
+// Returns whether the span of an outer call is in progress for the goroutine,
+// in which case the call is counted as a nested one.
+static __always_inline bool enter_nested_call(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    if (bpf_map_lookup_elem(&redis_events, &key) == NULL) {
+        return false;
+    }
+
+    u64 *count = bpf_map_lookup_elem(&redis_nested_calls, &key);
+    u64 nested = count == NULL ? 1 : *count + 1;
+    bpf_map_update_elem(&redis_nested_calls, &key, &nested, 0);
+    return true;
+}
+
+// Returns whether the call returning is a nested one, in which case it is no
+// longer counted.
+static __always_inline bool exit_nested_call(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    u64 *count = bpf_map_lookup_elem(&redis_nested_calls, &key);
+    if (count == NULL) {
+        return false;
+    }
+    if (*count > 1) {
+        *count -= 1;
+    } else {
+        bpf_map_delete_elem(&redis_nested_calls, &key);
+    }
+    return true;
+}
+
+// Returns the redis_request_t of a new span of the operation on the
+// *baseClient receiver, started with the context.Context argument at index 2.
+// The request is not tracked until it is passed to track_redis_span.
+static __always_inline struct redis_request_t *
+start_redis_span(struct pt_regs *ctx, u8 operation, void *cmd) {
+    u32 map_id = 0;
+    struct redis_request_t *req = bpf_map_lookup_elem(&redis_uprobe_storage_map, &map_id);
+    if (req == NULL) {
+        bpf_printk("start_redis_span: req is NULL");
+        return NULL;
+    }
+
+    __builtin_memset(req, 0, sizeof(struct redis_request_t));
+    req->start_time = bpf_ktime_get_ns();
+    req->operation = operation;
+
+    void *opt = NULL;
+    bpf_probe_read_user(&opt, sizeof(opt), get_argument(ctx, 1) + base_client_opt_pos);
+    if (opt != NULL) {
+        get_go_string_from_user_ptr(opt + options_addr_pos, req->addr, sizeof(req->addr));
+        bpf_probe_read_user(&req->db, sizeof(req->db), opt + options_db_pos);
+    }
+
+    sampling_attributes_t sampling_attrs = {0};
+    sampling_attributes_t *sampling_attrs_ptr = NULL;
+    if (cmd != NULL) {
+        // All the commands embed baseCmd as their first field.
+        struct go_slice args = {0};
+        bpf_probe_read_user(&args, sizeof(args), cmd + base_cmd_args_pos);
+        req->args_count = args.len;
+
+        // The name of the command is the string of its first argument.
+        struct go_iface name_iface = {0};
+        struct go_string name = {0};
+        if (args.len > 0 && args.array != NULL) {
+            bpf_probe_read_user(&name_iface, sizeof(name_iface), args.array);
+        }
+        if (name_iface.data != NULL) {
+            bpf_probe_read_user(&name, sizeof(name), name_iface.data);
+        }
+        if (name.str != NULL && name.len > 0) {
+            u64 name_size = MAX_COMMAND_SIZE - 1 < name.len ? MAX_COMMAND_SIZE - 1 : name.len;
+            bpf_probe_read_user(req->command, name_size, name.str);
+
+            sampling_attrs_set_value(
+                &sampling_attrs, SAMPLING_ATTR_DB_OPERATION, name.str, (s64)name.len);
+            sampling_attrs_ptr = &sampling_attrs;
+        }
+    }
+
+    struct go_iface go_context = {0};
+    get_Go_context(ctx, 2, 0, true, &go_context);
+    start_span_params_t start_span_params = {
+        .ctx = ctx,
+        .go_context = &go_context,
+        .psc = &req->psc,
+        .sc = &req->sc,
+        .state = &req->state,
+        .get_parent_span_context_fn = NULL,
+        .get_parent_span_context_arg = NULL,
+        .sampling_attrs = sampling_attrs_ptr,
+    };
+    start_span(&start_span_params);
+    return req;
+}
+
+// Tracks the span of req until the instrumented function returns.
+static __always_inline int track_redis_span(struct pt_regs *ctx, struct redis_request_t *req) {
+    void *key = (void *)GOROUTINE(ctx);
+    bpf_map_update_elem(&redis_events, &key, req, 0);
+    return 0;
+}
+
+// Start the span of a pipeline of the operation, whose []Cmder commands are
+// at the argument indices 4 to 6.
+static __always_inline int start_redis_pipeline_span(struct pt_regs *ctx, u8 operation) {
+    if (enter_nested_call(ctx)) {
+        return 0;
+    }
+    struct redis_request_t *req = start_redis_span(ctx, operation, NULL);
+    if (req == NULL) {
+        return 0;
+    }
+    req->batch_size = (u64)get_argument(ctx, 5);
+    return track_redis_span(ctx, req);
+}
+
+// Ends the span of the goroutine, recording the error returned as the first
+// result of the instrumented function.
+static __always_inline int end_redis_span(struct pt_regs *ctx) {
+    if (exit_nested_call(ctx)) {
+        return 0;
+    }
+    void *key = (void *)GOROUTINE(ctx);
+    struct redis_request_t *req = bpf_map_lookup_elem(&redis_events, &key);
+    if (req == NULL) {
+        bpf_printk("event is NULL in ret probe");
+        return 0;
+    }
+    req->end_time = bpf_ktime_get_ns();
+
+    void *err_itab = get_argument(ctx, 1);
+    void *err_data = get_argument(ctx, 2);
+    if (redis_error_itab != 0 && (u64)err_itab == redis_error_itab && err_data != NULL) {
+        // A proto.RedisError is a string, its message.
+        req->is_redis_error = 1;
+        req->error.kinds[0] = GO_ERROR_OTHER;
+        get_go_string_from_user_ptr(err_data, req->redis_error, sizeof(req->redis_error));
+    } else {
+        read_go_error(err_itab, err_data, &req->error);
+    }
+
+    output_span_event(ctx, req, sizeof(*req), &req->sc, req->start_time, req->end_time);
+    stop_tracking_span(&req->sc, &req->psc);
+    bpf_map_delete_elem(&redis_events, &key);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *baseClient) process(ctx context.Context, cmd Cmder) error
+SEC("uprobe/baseClient_process")
+int uprobe_baseClient_process(struct pt_regs *ctx) {
+    if (enter_nested_call(ctx)) {
+        return 0;
+    }
+    struct redis_request_t *req = start_redis_span(ctx, REDIS_OP_COMMAND, get_argument(ctx, 5));
+    if (req == NULL) {
+        return 0;
+    }
+    return track_redis_span(ctx, req);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *baseClient) process(ctx context.Context, cmd Cmder) error
+SEC("uprobe/baseClient_process")
+int uprobe_baseClient_process_Returns(struct pt_regs *ctx) { return end_redis_span(ctx); }
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *baseClient) processPipeline(ctx context.Context, cmds []Cmder) error
+SEC("uprobe/baseClient_processPipeline")
+int uprobe_baseClient_processPipeline(struct pt_regs *ctx) {
+    return start_redis_pipeline_span(ctx, REDIS_OP_PIPELINE);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *baseClient) processTxPipeline(ctx context.Context, cmds []Cmder) error
+SEC("uprobe/baseClient_processTxPipeline")
+int uprobe_baseClient_processTxPipeline(struct pt_regs *ctx) {
+    return start_redis_pipeline_span(ctx, REDIS_OP_TX_PIPELINE);
+}
+
+// This instrumentation attaches uprobe to the following functions:
+// func (c *baseClient) processPipeline(ctx context.Context, cmds []Cmder) error
+// func (c *baseClient) processTxPipeline(ctx context.Context, cmds []Cmder) error
+SEC("uprobe/baseClient_processPipeline")
+int uprobe_baseClient_processPipeline_Returns(struct pt_regs *ctx) { return end_redis_span(ctx); }
@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis provides instrumentation probes for Redis clients using the
+// [github.com/redis/go-redis/v9] or [github.com/go-redis/redis/v8] package.
+package redis
+
+import (
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sys/unix"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/database/sql"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/context"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/kernel"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
+	"go.opentelemetry.io/auto/internal/pkg/structfield"
+)
+
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -target amd64,arm64 bpf ./bpf/probe.bpf.c
+
+const (
+	// pkgV9 is the package of the v9 client being instrumented.
+	pkgV9 = "github.com/redis/go-redis/v9"
+
+	// pkgV8 is the package of the v8 client being instrumented.
+	pkgV8 = "github.com/go-redis/redis/v8"
+
+	// system is the database management system of the spans.
+	system = "redis"
+)
+
+// New returns a new [probe.Probe] for [github.com/redis/go-redis/v9].
+func New(logger *slog.Logger, version string) probe.Probe {
+	return newProbe(logger, version, pkgV9)
+}
+
+// NewV8 returns a new [probe.Probe] for [github.com/go-redis/redis/v8].
+func NewV8(logger *slog.Logger, version string) probe.Probe {
+	return newProbe(logger, version, pkgV8)
+}
+
+// newProbe returns a new [probe.Probe] for the go-redis package pkg. The v8
+// and v9 packages share the same implementation of the instrumented client.
+func newProbe(logger *slog.Logger, version, pkg string) probe.Probe {
+	id := probe.ID{
+		SpanKind:        trace.SpanKindClient,
+		InstrumentedPkg: pkg,
+	}
+	return &probe.SpanProducer[bpfObjects, event]{
+		Base: probe.Base[bpfObjects, event]{
+			ID:     id,
+			Logger: logger,
+			Consts: append([]probe.Const{
+				probe.AllocationConst{},
+				probe.StructFieldConst{
+					Key: "base_client_opt_pos",
+					ID:  structfield.NewID(pkg, pkg, "baseClient", "opt"),
+				},
+				probe.StructFieldConst{
+					Key: "options_addr_pos",
+					ID:  structfield.NewID(pkg, pkg, "Options", "Addr"),
+				},
+				probe.StructFieldConst{
+					Key: "options_db_pos",
+					ID:  structfield.NewID(pkg, pkg, "Options", "DB"),
+				},
+				probe.StructFieldConst{
+					Key: "base_cmd_args_pos",
+					ID:  structfield.NewID(pkg, pkg, "baseCmd", "args"),
+				},
+				probe.ItabConst{
+					Key:       "redis_error_itab",
+					Type:      pkg + "/internal/proto.RedisError",
+					Interface: "error",
+				},
+			}, probe.GoErrorConsts...),
+			Uprobes: []*probe.Uprobe{
+				{
+					Sym:         pkg + ".(*baseClient).process",
+					EntryProbe:  "uprobe_baseClient_process",
+					ReturnProbe: "uprobe_baseClient_process_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         pkg + ".(*baseClient).processPipeline",
+					EntryProbe:  "uprobe_baseClient_processPipeline",
+					ReturnProbe: "uprobe_baseClient_processPipeline_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+				{
+					Sym:         pkg + ".(*baseClient).processTxPipeline",
+					EntryProbe:  "uprobe_baseClient_processTxPipeline",
+					ReturnProbe: "uprobe_baseClient_processPipeline_Returns",
+					FailureMode: probe.FailureModeIgnore,
+				},
+			},
+			SpecFn: loadBpf,
+		},
+		Version:   version,
+		SchemaURL: semconv.SchemaURL,
+		ProcessFn: processFn,
+	}
+}
+
+// operation is the go-redis operation of an event. The values need to match
+// the REDIS_OP_* ones of the eBPF program.
+type operation uint8
+
+const (
+	opCommand operation = iota
+	opPipeline
+	opTxPipeline
+)
+
+// name returns the name of the operation, empty for the commands named after
+// their own name.
+func (o operation) name() string {
+	switch o {
+	case opPipeline:
+		return "PIPELINE"
+	case opTxPipeline:
+		return "MULTI"
+	default:
+		return ""
+	}
+}
+
+// event represents a command or a pipeline of commands sent to a Redis
+// server.
+type event struct {
+	context.BaseSpanProperties
+	Command [32]byte
+	Addr    [128]byte
+	DB      int64
+	// ArgsCount is the number of arguments of the command, including its
+	// name.
+	ArgsCount    uint64
+	BatchSize    uint64
+	Operation    operation
+	IsRedisError uint8
+	_            [6]byte
+	// Err is the error returned by the instrumented function. It is not set
+	// if the error is a proto.RedisError, read into RedisError instead.
+	Err        probe.GoError
+	RedisError [128]byte
+}
+
+// nilMessage is the message of redis.Nil, the error returned when a key does
+// not exist.
+const nilMessage = "redis: nil"
+
+func processFn(e *event) ptrace.SpanSlice {
+	spans := ptrace.NewSpanSlice()
+	span := spans.AppendEmpty()
+	span.SetKind(ptrace.SpanKindClient)
+	span.SetStartTimestamp(kernel.BootOffsetToTimestamp(e.StartTime))
+	span.SetEndTimestamp(kernel.BootOffsetToTimestamp(e.EndTime))
+	span.SetTraceID(pcommon.TraceID(e.SpanContext.TraceID))
+	span.SetSpanID(pcommon.SpanID(e.SpanContext.SpanID))
+	span.SetFlags(uint32(trace.FlagsSampled))
+
+	if e.ParentSpanContext.SpanID.IsValid() {
+		span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
+	}
+	span.TraceState().FromRaw(e.State.TraceState())
+
+	attrs := span.Attributes()
+	attrs.PutStr(string(semconv.DBSystemNameKey), system)
+
+	if addr := unix.ByteSliceToString(e.Addr[:]); addr != "" {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			// Unix domain socket paths have no port.
+			host, port = addr, ""
+		}
+		attrs.PutStr(string(semconv.ServerAddressKey), host)
+		if p, err := strconv.Atoi(port); err == nil && p > 0 {
+			attrs.PutInt(string(semconv.ServerPortKey), int64(p))
+		}
+	}
+	attrs.PutStr(string(semconv.DBNamespaceKey), strconv.FormatInt(e.DB, 10))
+
+	command := strings.ToUpper(unix.ByteSliceToString(e.Command[:]))
+	name := e.Operation.name()
+	if name != "" {
+		attrs.PutInt(string(semconv.DBOperationBatchSizeKey), int64(e.BatchSize))
+	} else {
+		name = command
+	}
+	if name != "" {
+		attrs.PutStr(string(semconv.DBOperationNameKey), name)
+		span.SetName(name)
+	} else {
+		span.SetName(system)
+	}
+
+	if command != "" && sql.ShouldIncludeDBStatement() {
+		attrs.PutStr(string(semconv.DBQueryTextKey), commandText(command, e.ArgsCount))
+	}
+
+	recordError(span, e)
+
+	return spans
+}
+
+// commandText returns the text of the command with argsCount arguments,
+// including its name. The values of the arguments are never read, they are
+// replaced by "?".
+func commandText(command string, argsCount uint64) string {
+	var b strings.Builder
+	b.WriteString(command)
+	for i := uint64(1); i < argsCount; i++ {
+		b.WriteString(" ?")
+	}
+	return b.String()
+}
+
+// recordError sets the status of span to Error and records the error of e,
+// if any, as returned by the instrumented function.
+func recordError(span ptrace.Span, e *event) {
+	if e.IsRedisError != 0 {
+		msg := unix.ByteSliceToString(e.RedisError[:])
+		if msg == nilMessage {
+			// Missing keys are not errors.
+			return
+		}
+		// The errors returned by servers start with an uppercase prefix,
+		// e.g. "ERR" or "WRONGTYPE".
+		typ := semconv.ErrorTypeOther.Value.AsString()
+		prefix, _, _ := strings.Cut(msg, " ")
+		if isErrorPrefix(prefix) {
+			typ = prefix
+			span.Attributes().PutStr(string(semconv.DBResponseStatusCodeKey), prefix)
+		}
+		// The Go type of the error depends on the version of the module.
+		probe.RecordException(span, "", msg)
+		span.Attributes().PutStr(string(semconv.ErrorTypeKey), typ)
+		return
+	}
+
+	e.Err.Record(span)
+}
+
+// isErrorPrefix returns if s is the prefix of an error returned by a Redis
+// server.
+func isErrorPrefix(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
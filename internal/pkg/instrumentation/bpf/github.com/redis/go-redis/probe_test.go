@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/database/sql"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/context"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/kernel"
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/pdataconv"
+)
+
+func newEvent(op operation, command string, argsCount uint64) *event {
+	e := &event{Operation: op, DB: 2, ArgsCount: argsCount}
+	copy(e.Command[:], command)
+	copy(e.Addr[:], "cache.example.com:6379")
+	return e
+}
+
+func TestProbeConvertEvent(t *testing.T) {
+	t.Setenv(sql.IncludeDBStatementEnvVar, "true")
+
+	start := time.Unix(0, time.Now().UnixNano()) // No wall clock.
+	end := start.Add(1 * time.Second)
+
+	startOffset := kernel.TimeToBootOffset(start)
+	endOffset := kernel.TimeToBootOffset(end)
+
+	traceID := trace.TraceID{1}
+	spanID := trace.SpanID{1}
+
+	e := newEvent(opCommand, "set", 3)
+	e.BaseSpanProperties = context.BaseSpanProperties{
+		StartTime:   startOffset,
+		EndTime:     endOffset,
+		SpanContext: context.EBPFSpanContext{TraceID: traceID, SpanID: spanID},
+	}
+	got := processFn(e)
+
+	want := func() ptrace.SpanSlice {
+		spans := ptrace.NewSpanSlice()
+		span := spans.AppendEmpty()
+		span.SetName("SET")
+		span.SetKind(ptrace.SpanKindClient)
+		span.SetStartTimestamp(kernel.BootOffsetToTimestamp(startOffset))
+		span.SetEndTimestamp(kernel.BootOffsetToTimestamp(endOffset))
+		span.SetTraceID(pcommon.TraceID(traceID))
+		span.SetSpanID(pcommon.SpanID(spanID))
+		span.SetFlags(uint32(trace.FlagsSampled))
+		pdataconv.Attributes(
+			span.Attributes(),
+			semconv.DBSystemNameRedis,
+			semconv.ServerAddress("cache.example.com"),
+			semconv.ServerPort(6379),
+			semconv.DBNamespace("2"),
+			semconv.DBOperationName("SET"),
+			semconv.DBQueryText("SET ? ?"),
+		)
+		return spans
+	}()
+	assert.Equal(t, want, got)
+}
+
+func TestProbeConvertEventOperations(t *testing.T) {
+	tests := []struct {
+		name  string
+		event *event
+		span  string
+		attrs map[string]any
+	}{
+		{
+			name:  "command",
+			event: newEvent(opCommand, "get", 2),
+			span:  "GET",
+			attrs: map[string]any{
+				string(semconv.DBOperationNameKey): "GET",
+			},
+		},
+		{
+			name: "pipeline",
+			event: func() *event {
+				e := newEvent(opPipeline, "", 0)
+				e.BatchSize = 3
+				return e
+			}(),
+			span: "PIPELINE",
+			attrs: map[string]any{
+				string(semconv.DBOperationNameKey):      "PIPELINE",
+				string(semconv.DBOperationBatchSizeKey): int64(3),
+			},
+		},
+		{
+			name: "transaction",
+			event: func() *event {
+				e := newEvent(opTxPipeline, "", 0)
+				e.BatchSize = 2
+				return e
+			}(),
+			span: "MULTI",
+			attrs: map[string]any{
+				string(semconv.DBOperationNameKey):      "MULTI",
+				string(semconv.DBOperationBatchSizeKey): int64(2),
+			},
+		},
+		{
+			name:  "unknown",
+			event: newEvent(opCommand, "", 0),
+			span:  "redis",
+			attrs: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := processFn(tt.event)
+			require.Equal(t, 1, got.Len())
+			span := got.At(0)
+			assert.Equal(t, tt.span, span.Name())
+
+			want := map[string]any{
+				string(semconv.DBSystemNameKey):  "redis",
+				string(semconv.ServerAddressKey): "cache.example.com",
+				string(semconv.ServerPortKey):    int64(6379),
+				string(semconv.DBNamespaceKey):   "2",
+			}
+			for k, v := range tt.attrs {
+				want[k] = v
+			}
+			// Commands are not included unless opted-in.
+			assert.Equal(t, want, span.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestProbeConvertEventUnixSocket(t *testing.T) {
+	e := &event{}
+	copy(e.Addr[:], "/var/run/redis.sock")
+	got := processFn(e)
+	require.Equal(t, 1, got.Len())
+	attrs := got.At(0).Attributes().AsRaw()
+	assert.Equal(t, "/var/run/redis.sock", attrs[string(semconv.ServerAddressKey)])
+	assert.NotContains(t, attrs, string(semconv.ServerPortKey))
+}
+
+func TestProbeConvertEventError(t *testing.T) {
+	e := &event{IsRedisError: 1}
+	const msg = "WRONGTYPE Operation against a key holding the wrong kind of value"
+	copy(e.RedisError[:], msg)
+	got := processFn(e)
+	require.Equal(t, 1, got.Len())
+	span := got.At(0)
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, msg, span.Status().Message())
+	assert.Equal(t, map[string]any{
+		string(semconv.DBSystemNameKey):         "redis",
+		string(semconv.DBNamespaceKey):          "0",
+		string(semconv.ErrorTypeKey):            "WRONGTYPE",
+		string(semconv.DBResponseStatusCodeKey): "WRONGTYPE",
+	}, span.Attributes().AsRaw())
+	require.Equal(t, 1, span.Events().Len())
+	assert.Equal(t, semconv.ExceptionEventName, span.Events().At(0).Name())
+
+	e = &event{IsRedisError: 1}
+	copy(e.RedisError[:], "redis: nil")
+	got = processFn(e)
+	require.Equal(t, 1, got.Len())
+	assert.Equal(t, ptrace.StatusCodeUnset, got.At(0).Status().Code())
+}
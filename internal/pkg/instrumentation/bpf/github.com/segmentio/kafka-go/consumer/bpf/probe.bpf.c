@@ -8,6 +8,7 @@
 #include "uprobe.h"
 #include "trace/span_output.h"
 #include "trace/start_span.h"
+#include "trace/propagation.h"
 
 char __license[] SEC("license") = "Dual MIT/GPL";
 
@@ -73,15 +74,17 @@ volatile const u64 reader_config_group_id_pos;
 
 #define MAX_HEADERS 20
 
-static __always_inline long
-extract_span_context_from_headers(void *message, struct span_context *parent_span_context) {
+static __always_inline long extract_span_context_from_headers(
+    void *message, struct span_context *parent_span_context, struct span_state *state) {
     // Read the headers slice descriptor
     void *headers = (void *)(message + message_headers_pos);
     struct go_slice headers_slice = {0};
     bpf_probe_read(&headers_slice, sizeof(headers_slice), headers);
 
-    char key[W3C_KEY_LENGTH] = "traceparent";
-    char current_key[W3C_KEY_LENGTH];
+    struct propagation_extractor *ex = new_propagation_extractor();
+    if (ex == NULL) {
+        return -1;
+    }
 
     for (u64 i = 0; i < headers_slice.len; i++) {
         if (i >= MAX_HEADERS) {
@@ -90,20 +93,11 @@ extract_span_context_from_headers(void *message, struct span_context *parent_spa
         // Read the header
         struct kafka_header_t header = {0};
         bpf_probe_read(&header, sizeof(header), headers_slice.array + (i * sizeof(header)));
-        // Check if it is the traceparent header
-        if (header.key.len == W3C_KEY_LENGTH && header.value.len == W3C_VAL_LENGTH) {
-            bpf_probe_read_user(current_key, sizeof(current_key), header.key.str);
-            if (bpf_memcmp(key, current_key, sizeof(key))) {
-                // Found the traceparent header, extract the span context
-                char val[W3C_VAL_LENGTH];
-                bpf_probe_read(val, W3C_VAL_LENGTH, header.value.array);
-                w3c_string_to_span_context(val, parent_span_context);
-                return 0;
-            }
-        }
+        propagation_extract_header(
+            ex, header.key.str, header.key.len, header.value.array, header.value.len);
     }
 
-    return -1;
+    return propagation_extract_finish(ex, parent_span_context, state);
 }
 
 // This instrumentation attaches uprobe to the following function:
@@ -137,8 +131,14 @@ int uprobe_FetchMessage(struct pt_regs *ctx) {
                                 sizeof(kafka_request->consumer_group));
     kafka_request->end_time = bpf_ktime_get_ns();
 
-    output_span_event(ctx, kafka_request, sizeof(*kafka_request), &kafka_request->sc);
+    output_span_event(ctx,
+                      kafka_request,
+                      sizeof(*kafka_request),
+                      &kafka_request->sc,
+                      kafka_request->start_time,
+                      kafka_request->end_time);
     stop_tracking_span(&kafka_request->sc, &kafka_request->psc);
+    stop_tracking_goroutine_span(goroutine, &kafka_request->sc);
     bpf_map_delete_elem(&kafka_events, &goroutine);
 
 save_context:
@@ -170,14 +170,20 @@ int uprobe_FetchMessage_Returns(struct pt_regs *ctx) {
     struct go_iface go_context = {0};
     get_Go_context(ctx, 2, 0, true, &go_context);
 
+    sampling_attributes_t sampling_attrs = {0};
+    sampling_attrs_set_go_value(
+        &sampling_attrs, SAMPLING_ATTR_MESSAGING_DESTINATION, (void *)(message + message_topic_pos));
+
     // Get the parent span context from the message headers
     start_span_params_t start_span_params = {
         .ctx = ctx,
         .sc = &kafka_request->sc,
         .psc = &kafka_request->psc,
+        .state = &kafka_request->state,
         .go_context = &go_context,
         .get_parent_span_context_fn = extract_span_context_from_headers,
         .get_parent_span_context_arg = message,
+        .sampling_attrs = &sampling_attrs,
     };
     start_span(&start_span_params);
 
@@ -209,7 +215,9 @@ int uprobe_FetchMessage_Returns(struct pt_regs *ctx) {
     void *context_data_ptr = bpf_map_lookup_elem(&goroutine_to_go_context, &goroutine);
     if (context_data_ptr != NULL) {
         bpf_probe_read_kernel(&context_data_ptr, sizeof(context_data_ptr), context_data_ptr);
-        start_tracking_span(context_data_ptr, &kafka_request->sc);
+        start_tracking_span(context_data_ptr, &kafka_request->sc, &kafka_request->state);
+        // The goroutine span context is only used while the span is tracked.
+        start_tracking_goroutine_span(goroutine, &kafka_request->sc);
         bpf_map_delete_elem(&goroutine_to_go_context, &goroutine);
     }
 
@@ -19,6 +19,7 @@ type bpfKafkaRequestT struct {
 	EndTime       uint64
 	Sc            bpfSpanContext
 	Psc           bpfSpanContext
+	State         bpfSpanState
 	Topic         [256]int8
 	Key           [256]int8
 	ConsumerGroup [128]int8
@@ -71,6 +72,30 @@ func loadBpfObjects(obj interface{}, opts *ebpf.CollectionOptions) error {
 // bpfSpecs contains maps and programs before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
+type bpfSpanState struct {
+	_   structs.HostLayout
+	Ots struct {
+		_       structs.HostLayout
+		Th      uint64
+		Rv      uint64
+		HasTh   uint8
+		HasRv   uint8
+		Padding [6]uint8
+	}
+	Ts struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Entries [128]int8
+	}
+	Bg struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Value   [128]int8
+	}
+}
+
 type bpfSpecs struct {
 	bpfProgramSpecs
 	bpfMapSpecs
@@ -89,17 +114,25 @@ type bpfProgramSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfMapSpecs struct {
-	AllocMap               *ebpf.MapSpec `ebpf:"alloc_map"`
-	Events                 *ebpf.MapSpec `ebpf:"events"`
-	GoContextToSc          *ebpf.MapSpec `ebpf:"go_context_to_sc"`
-	GoroutineToGoContext   *ebpf.MapSpec `ebpf:"goroutine_to_go_context"`
-	KafkaEvents            *ebpf.MapSpec `ebpf:"kafka_events"`
-	KafkaReaderToConn      *ebpf.MapSpec `ebpf:"kafka_reader_to_conn"`
-	KafkaRequestStorageMap *ebpf.MapSpec `ebpf:"kafka_request_storage_map"`
-	ProbeActiveSamplerMap  *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap      *ebpf.MapSpec `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap      *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
-	TrackedSpansBySc       *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.MapSpec `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.MapSpec `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.MapSpec `ebpf:"events"`
+	GoContextToSc                  *ebpf.MapSpec `ebpf:"go_context_to_sc"`
+	GoroutineToGoContext           *ebpf.MapSpec `ebpf:"goroutine_to_go_context"`
+	GoroutineToSc                  *ebpf.MapSpec `ebpf:"goroutine_to_sc"`
+	KafkaEvents                    *ebpf.MapSpec `ebpf:"kafka_events"`
+	KafkaReaderToConn              *ebpf.MapSpec `ebpf:"kafka_reader_to_conn"`
+	KafkaRequestStorageMap         *ebpf.MapSpec `ebpf:"kafka_request_storage_map"`
+	ProbeActiveSamplerMap          *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.MapSpec `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.MapSpec `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.MapSpec `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.MapSpec `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.MapSpec `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.MapSpec `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc          *ebpf.MapSpec `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
 }
 
 // bpfVariableSpecs contains global variables before they are loaded into the kernel.
@@ -113,6 +146,7 @@ type bpfVariableSpecs struct {
 	MessageOffsetPos       *ebpf.VariableSpec `ebpf:"message_offset_pos"`
 	MessagePartitionPos    *ebpf.VariableSpec `ebpf:"message_partition_pos"`
 	MessageTopicPos        *ebpf.VariableSpec `ebpf:"message_topic_pos"`
+	Propagators            *ebpf.VariableSpec `ebpf:"propagators"`
 	ReaderConfigGroupIdPos *ebpf.VariableSpec `ebpf:"reader_config_group_id_pos"`
 	ReaderConfigPos        *ebpf.VariableSpec `ebpf:"reader_config_pos"`
 	StartAddr              *ebpf.VariableSpec `ebpf:"start_addr"`
@@ -139,31 +173,47 @@ func (o *bpfObjects) Close() error {
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfMaps struct {
-	AllocMap               *ebpf.Map `ebpf:"alloc_map"`
-	Events                 *ebpf.Map `ebpf:"events"`
-	GoContextToSc          *ebpf.Map `ebpf:"go_context_to_sc"`
-	GoroutineToGoContext   *ebpf.Map `ebpf:"goroutine_to_go_context"`
-	KafkaEvents            *ebpf.Map `ebpf:"kafka_events"`
-	KafkaReaderToConn      *ebpf.Map `ebpf:"kafka_reader_to_conn"`
-	KafkaRequestStorageMap *ebpf.Map `ebpf:"kafka_request_storage_map"`
-	ProbeActiveSamplerMap  *ebpf.Map `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap      *ebpf.Map `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap      *ebpf.Map `ebpf:"slice_array_buff_map"`
-	TrackedSpansBySc       *ebpf.Map `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.Map `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.Map `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.Map `ebpf:"events"`
+	GoContextToSc                  *ebpf.Map `ebpf:"go_context_to_sc"`
+	GoroutineToGoContext           *ebpf.Map `ebpf:"goroutine_to_go_context"`
+	GoroutineToSc                  *ebpf.Map `ebpf:"goroutine_to_sc"`
+	KafkaEvents                    *ebpf.Map `ebpf:"kafka_events"`
+	KafkaReaderToConn              *ebpf.Map `ebpf:"kafka_reader_to_conn"`
+	KafkaRequestStorageMap         *ebpf.Map `ebpf:"kafka_request_storage_map"`
+	ProbeActiveSamplerMap          *ebpf.Map `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.Map `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.Map `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.Map `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.Map `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.Map `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.Map `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.Map `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc          *ebpf.Map `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.Map `ebpf:"tracked_spans_by_sc"`
 }
 
 func (m *bpfMaps) Close() error {
 	return _BpfClose(
 		m.AllocMap,
+		m.DroppedSpansMap,
 		m.Events,
 		m.GoContextToSc,
 		m.GoroutineToGoContext,
+		m.GoroutineToSc,
 		m.KafkaEvents,
 		m.KafkaReaderToConn,
 		m.KafkaRequestStorageMap,
 		m.ProbeActiveSamplerMap,
+		m.PropagationExtractorStorageMap,
+		m.PropagationHeaderStorageMap,
+		m.RateLimiterMap,
 		m.SamplersConfigMap,
 		m.SliceArrayBuffMap,
+		m.SpanOutputConfigMap,
+		m.SpanStateStorageMap,
+		m.TrackedSpanStatesBySc,
 		m.TrackedSpansBySc,
 	)
 }
@@ -179,6 +229,7 @@ type bpfVariables struct {
 	MessageOffsetPos       *ebpf.Variable `ebpf:"message_offset_pos"`
 	MessagePartitionPos    *ebpf.Variable `ebpf:"message_partition_pos"`
 	MessageTopicPos        *ebpf.Variable `ebpf:"message_topic_pos"`
+	Propagators            *ebpf.Variable `ebpf:"propagators"`
 	ReaderConfigGroupIdPos *ebpf.Variable `ebpf:"reader_config_group_id_pos"`
 	ReaderConfigPos        *ebpf.Variable `ebpf:"reader_config_pos"`
 	StartAddr              *ebpf.Variable `ebpf:"start_addr"`
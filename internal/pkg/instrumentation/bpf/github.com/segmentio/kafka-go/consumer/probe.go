@@ -41,6 +41,7 @@ func New(logger *slog.Logger, version string) probe.Probe {
 			Logger: logger,
 			Consts: []probe.Const{
 				probe.AllocationConst{},
+				probe.PropagatorsConst{},
 				probe.StructFieldConst{
 					Key: "message_headers_pos",
 					ID: structfield.NewID(
@@ -147,6 +148,7 @@ func processFn(e *event) ptrace.SpanSlice {
 	if e.ParentSpanContext.SpanID.IsValid() {
 		span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
 	}
+	span.TraceState().FromRaw(e.State.TraceState())
 
 	pdataconv.Attributes(
 		span.Attributes(),
@@ -8,6 +8,7 @@
 #include "uprobe.h"
 #include "trace/span_output.h"
 #include "trace/start_span.h"
+#include "trace/propagation.h"
 
 char __license[] SEC("license") = "Dual MIT/GPL";
 
@@ -34,6 +35,8 @@ struct kafka_request_t {
     u64 start_time;
     u64 end_time;
     struct span_context psc;
+    // the state of the spans of all the messages
+    struct span_state state;
     // attributes per message
     struct message_attributes_t msgs[MAX_BATCH_SIZE];
     char global_topic[MAX_TOPIC_SIZE];
@@ -69,39 +72,30 @@ volatile const u64 message_time_pos;
 volatile const u64 writer_topic_pos;
 
 #ifndef NO_HEADER_PROPAGATION
-static __always_inline int build_contxet_header(struct kafka_header_t *header,
-                                                struct span_context *span_ctx) {
-    if (header == NULL || span_ctx == NULL) {
-        bpf_printk("build_contxt_header: Invalid arguments");
-        return -1;
-    }
-
-    // Prepare the key string for the user
-    char key[W3C_KEY_LENGTH] = "traceparent";
-    void *ptr = write_target_data(key, W3C_KEY_LENGTH);
+// Build the kafka header of the propagation header h.
+// Returns 0 on success, negative value on error.
+static __always_inline int build_kafka_header(struct kafka_header_t *header,
+                                              struct propagation_header *h) {
+    void *ptr = write_target_data(h->key, h->key_len);
     if (ptr == NULL) {
-        bpf_printk("build_contxt_header: Failed to write key to user");
+        bpf_printk("build_kafka_header: Failed to write key to user");
         return -1;
     }
 
     // build the go string of the key
     header->key.str = ptr;
-    header->key.len = W3C_KEY_LENGTH;
+    header->key.len = h->key_len;
 
-    // Prepare the value string for the user
-    char val[W3C_VAL_LENGTH];
-    span_context_to_w3c_string(span_ctx, val);
-    ptr = write_target_data(val, sizeof(val));
+    ptr = write_target_data(h->value, h->value_len);
     if (ptr == NULL) {
-        bpf_printk("build_contxt_header: Failed to write value to user");
+        bpf_printk("build_kafka_header: Failed to write value to user");
         return -1;
     }
 
     // build the go slice of the value
     header->value.array = ptr;
-    header->value.len = W3C_VAL_LENGTH;
-    header->value.cap = W3C_VAL_LENGTH;
-    bpf_printk("build_contxt_header success");
+    header->value.len = h->value_len;
+    header->value.cap = h->value_len;
     return 0;
 }
 
@@ -168,13 +162,25 @@ int uprobe_WriteMessages(struct pt_regs *ctx) {
 
     kafka_request->start_time = bpf_ktime_get_ns();
 
+    // Use the Writer topic if set, otherwise the topic of the first message.
+    sampling_attributes_t sampling_attrs = {0};
+    sampling_attrs_set_go_value(
+        &sampling_attrs, SAMPLING_ATTR_MESSAGING_DESTINATION, (void *)(writer + writer_topic_pos));
+    if (sampling_attrs.value_len == 0 && msgs_array_len > 0) {
+        sampling_attrs_set_go_value(&sampling_attrs,
+                                    SAMPLING_ATTR_MESSAGING_DESTINATION,
+                                    (void *)(msgs_array + message_topic_pos));
+    }
+
     start_span_params_t start_span_params = {
         .ctx = ctx,
         .go_context = &go_context,
         .psc = &kafka_request->psc,
         .sc = &kafka_request->msgs[0].sc,
+        .state = &kafka_request->state,
         .get_parent_span_context_fn = NULL,
         .get_parent_span_context_arg = NULL,
+        .sampling_attrs = &sampling_attrs,
     };
     start_span(&start_span_params);
 
@@ -185,6 +191,12 @@ int uprobe_WriteMessages(struct pt_regs *ctx) {
 
     void *msg_ptr = msgs_array;
     struct kafka_header_t header = {0};
+#ifndef NO_HEADER_PROPAGATION
+    struct propagation_header *h = new_propagation_header();
+    if (h == NULL) {
+        return 0;
+    }
+#endif
     // This is hack to get the message size. This calculation is based on the following assumptions:
     // 1. "Time" is the last field in the message struct. This looks to be correct for all the versions according to
     //      https://github.com/segmentio/kafka-go/blob/v0.2.3/message.go#L24C2-L24C6
@@ -213,13 +225,18 @@ int uprobe_WriteMessages(struct pt_regs *ctx) {
         }
 
 #ifndef NO_HEADER_PROPAGATION
-        // Build the header
-        if (build_contxet_header(&header, &kafka_request->msgs[i].sc) != 0) {
-            bpf_printk("uprobe/WriteMessages: Failed to build header");
-            return 0;
+        // Build and inject the headers of all the configured propagators
+        for (u32 j = 0; j < MAX_PROPAGATION_HEADERS; j++) {
+            if (!propagation_inject_header(
+                    j, &kafka_request->msgs[i].sc, &kafka_request->state, h)) {
+                continue;
+            }
+            if (build_kafka_header(&header, h) != 0) {
+                bpf_printk("uprobe/WriteMessages: Failed to build header");
+                return 0;
+            }
+            inject_kafka_header(msg_ptr, &header);
         }
-        // Inject the header
-        inject_kafka_header(msg_ptr, &header);
 #endif
         kafka_request->valid_messages++;
         msg_ptr = msg_ptr + msg_size;
@@ -244,7 +261,12 @@ int uprobe_WriteMessages_Returns(struct pt_regs *ctx) {
     }
     kafka_request->end_time = end_time;
 
-    output_span_event(ctx, kafka_request, sizeof(*kafka_request), &kafka_request->msgs[0].sc);
+    output_span_event(ctx,
+                      kafka_request,
+                      sizeof(*kafka_request),
+                      &kafka_request->msgs[0].sc,
+                      kafka_request->start_time,
+                      kafka_request->end_time);
     bpf_map_delete_elem(&kafka_events, &key);
     // don't need to stop tracking the span, as we don't have a context to propagate locally
     return 0;
@@ -18,6 +18,7 @@ type bpf_no_tpKafkaRequestT struct {
 	StartTime uint64
 	EndTime   uint64
 	Psc       bpf_no_tpSpanContext
+	State     bpf_no_tpSpanState
 	Msgs      [10]struct {
 		_     structs.HostLayout
 		Sc    bpf_no_tpSpanContext
@@ -73,6 +74,30 @@ func loadBpf_no_tpObjects(obj interface{}, opts *ebpf.CollectionOptions) error {
 // bpf_no_tpSpecs contains maps and programs before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
+type bpf_no_tpSpanState struct {
+	_   structs.HostLayout
+	Ots struct {
+		_       structs.HostLayout
+		Th      uint64
+		Rv      uint64
+		HasTh   uint8
+		HasRv   uint8
+		Padding [6]uint8
+	}
+	Ts struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Entries [128]int8
+	}
+	Bg struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Value   [128]int8
+	}
+}
+
 type bpf_no_tpSpecs struct {
 	bpf_no_tpProgramSpecs
 	bpf_no_tpMapSpecs
@@ -91,15 +116,23 @@ type bpf_no_tpProgramSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpf_no_tpMapSpecs struct {
-	AllocMap               *ebpf.MapSpec `ebpf:"alloc_map"`
-	Events                 *ebpf.MapSpec `ebpf:"events"`
-	GoContextToSc          *ebpf.MapSpec `ebpf:"go_context_to_sc"`
-	KafkaEvents            *ebpf.MapSpec `ebpf:"kafka_events"`
-	KafkaRequestStorageMap *ebpf.MapSpec `ebpf:"kafka_request_storage_map"`
-	ProbeActiveSamplerMap  *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap      *ebpf.MapSpec `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap      *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
-	TrackedSpansBySc       *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.MapSpec `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.MapSpec `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.MapSpec `ebpf:"events"`
+	GoContextToSc                  *ebpf.MapSpec `ebpf:"go_context_to_sc"`
+	GoroutineToSc                  *ebpf.MapSpec `ebpf:"goroutine_to_sc"`
+	KafkaEvents                    *ebpf.MapSpec `ebpf:"kafka_events"`
+	KafkaRequestStorageMap         *ebpf.MapSpec `ebpf:"kafka_request_storage_map"`
+	ProbeActiveSamplerMap          *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.MapSpec `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.MapSpec `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.MapSpec `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.MapSpec `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.MapSpec `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.MapSpec `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc          *ebpf.MapSpec `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
 }
 
 // bpf_no_tpVariableSpecs contains global variables before they are loaded into the kernel.
@@ -112,6 +145,7 @@ type bpf_no_tpVariableSpecs struct {
 	MessageKeyPos     *ebpf.VariableSpec `ebpf:"message_key_pos"`
 	MessageTimePos    *ebpf.VariableSpec `ebpf:"message_time_pos"`
 	MessageTopicPos   *ebpf.VariableSpec `ebpf:"message_topic_pos"`
+	Propagators       *ebpf.VariableSpec `ebpf:"propagators"`
 	StartAddr         *ebpf.VariableSpec `ebpf:"start_addr"`
 	TotalCpus         *ebpf.VariableSpec `ebpf:"total_cpus"`
 	WriterTopicPos    *ebpf.VariableSpec `ebpf:"writer_topic_pos"`
@@ -137,27 +171,43 @@ func (o *bpf_no_tpObjects) Close() error {
 //
 // It can be passed to loadBpf_no_tpObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpf_no_tpMaps struct {
-	AllocMap               *ebpf.Map `ebpf:"alloc_map"`
-	Events                 *ebpf.Map `ebpf:"events"`
-	GoContextToSc          *ebpf.Map `ebpf:"go_context_to_sc"`
-	KafkaEvents            *ebpf.Map `ebpf:"kafka_events"`
-	KafkaRequestStorageMap *ebpf.Map `ebpf:"kafka_request_storage_map"`
-	ProbeActiveSamplerMap  *ebpf.Map `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap      *ebpf.Map `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap      *ebpf.Map `ebpf:"slice_array_buff_map"`
-	TrackedSpansBySc       *ebpf.Map `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.Map `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.Map `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.Map `ebpf:"events"`
+	GoContextToSc                  *ebpf.Map `ebpf:"go_context_to_sc"`
+	GoroutineToSc                  *ebpf.Map `ebpf:"goroutine_to_sc"`
+	KafkaEvents                    *ebpf.Map `ebpf:"kafka_events"`
+	KafkaRequestStorageMap         *ebpf.Map `ebpf:"kafka_request_storage_map"`
+	ProbeActiveSamplerMap          *ebpf.Map `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.Map `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.Map `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.Map `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.Map `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.Map `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.Map `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.Map `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc          *ebpf.Map `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.Map `ebpf:"tracked_spans_by_sc"`
 }
 
 func (m *bpf_no_tpMaps) Close() error {
 	return _Bpf_no_tpClose(
 		m.AllocMap,
+		m.DroppedSpansMap,
 		m.Events,
 		m.GoContextToSc,
+		m.GoroutineToSc,
 		m.KafkaEvents,
 		m.KafkaRequestStorageMap,
 		m.ProbeActiveSamplerMap,
+		m.PropagationExtractorStorageMap,
+		m.PropagationHeaderStorageMap,
+		m.RateLimiterMap,
 		m.SamplersConfigMap,
 		m.SliceArrayBuffMap,
+		m.SpanOutputConfigMap,
+		m.SpanStateStorageMap,
+		m.TrackedSpanStatesBySc,
 		m.TrackedSpansBySc,
 	)
 }
@@ -172,6 +222,7 @@ type bpf_no_tpVariables struct {
 	MessageKeyPos     *ebpf.Variable `ebpf:"message_key_pos"`
 	MessageTimePos    *ebpf.Variable `ebpf:"message_time_pos"`
 	MessageTopicPos   *ebpf.Variable `ebpf:"message_topic_pos"`
+	Propagators       *ebpf.Variable `ebpf:"propagators"`
 	StartAddr         *ebpf.Variable `ebpf:"start_addr"`
 	TotalCpus         *ebpf.Variable `ebpf:"total_cpus"`
 	WriterTopicPos    *ebpf.Variable `ebpf:"writer_topic_pos"`
@@ -46,6 +46,7 @@ func New(logger *slog.Logger, version string) probe.Probe {
 			Logger: logger,
 			Consts: []probe.Const{
 				probe.AllocationConst{},
+				probe.PropagatorsConst{},
 				probe.StructFieldConst{
 					Key: "writer_topic_pos",
 					ID: structfield.NewID(
@@ -121,6 +122,8 @@ type event struct {
 	StartTime         uint64
 	EndTime           uint64
 	ParentSpanContext context.EBPFSpanContext
+	// State propagated with the span contexts of the messages
+	State context.SpanState
 	// Message specific attributes
 	Messages [10]messageAttributes
 	// Global topic for the batch
@@ -129,6 +132,12 @@ type event struct {
 	ValidMessages uint64
 }
 
+// Baggage returns the W3C baggage header value propagated with the messages
+// of e.
+func (e *event) Baggage() string {
+	return e.State.Baggage.String()
+}
+
 func processFn(e *event) ptrace.SpanSlice {
 	globalTopic := unix.ByteSliceToString(e.GlobalTopic[:])
 
@@ -179,6 +188,7 @@ func processFn(e *event) ptrace.SpanSlice {
 		if e.ParentSpanContext.SpanID.IsValid() {
 			span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
 		}
+		span.TraceState().FromRaw(e.State.TraceState())
 
 		pdataconv.Attributes(span.Attributes(), msgAttrs...)
 	}
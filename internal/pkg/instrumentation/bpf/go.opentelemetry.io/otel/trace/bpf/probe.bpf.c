@@ -86,12 +86,18 @@ int uprobe_Tracer_start(struct pt_regs *ctx) {
 
     struct otel_span_t otel_span;
     __builtin_memset(&otel_span, 0, sizeof(struct otel_span_t));
+    // The span state is not output, it is only tracked for the child spans.
+    struct span_state *state = new_span_state();
+    if (state == NULL) {
+        return 0;
+    }
 
     start_span_params_t params = {
         .ctx = ctx,
         .go_context = &go_context,
         .psc = &otel_span.psc,
         .sc = &otel_span.sc,
+        .state = state,
         .get_parent_span_context_fn = NULL,
         .get_parent_span_context_arg = NULL, // Default to new root.
     };
@@ -128,7 +134,7 @@ int uprobe_Tracer_start(struct pt_regs *ctx) {
     }
 
     bpf_map_update_elem(&active_spans_by_span_ptr, &span_ptr_val, &otel_span, 0);
-    start_tracking_span(go_context.data, &otel_span.sc);
+    start_tracking_span(go_context.data, &otel_span.sc, state);
 
     return 0;
 }
@@ -64,6 +64,30 @@ func loadBpfObjects(obj interface{}, opts *ebpf.CollectionOptions) error {
 // bpfSpecs contains maps and programs before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
+type bpfSpanState struct {
+	_   structs.HostLayout
+	Ots struct {
+		_       structs.HostLayout
+		Th      uint64
+		Rv      uint64
+		HasTh   uint8
+		HasRv   uint8
+		Padding [6]uint8
+	}
+	Ts struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Entries [128]int8
+	}
+	Bg struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Value   [128]int8
+	}
+}
+
 type bpfSpecs struct {
 	bpfProgramSpecs
 	bpfMapSpecs
@@ -85,12 +109,18 @@ type bpfProgramSpecs struct {
 type bpfMapSpecs struct {
 	ActiveSpansBySpanPtr  *ebpf.MapSpec `ebpf:"active_spans_by_span_ptr"`
 	AllocMap              *ebpf.MapSpec `ebpf:"alloc_map"`
+	DroppedSpansMap       *ebpf.MapSpec `ebpf:"dropped_spans_map"`
 	Events                *ebpf.MapSpec `ebpf:"events"`
 	GoContextToSc         *ebpf.MapSpec `ebpf:"go_context_to_sc"`
+	GoroutineToSc         *ebpf.MapSpec `ebpf:"goroutine_to_sc"`
 	NewEvent              *ebpf.MapSpec `ebpf:"new_event"`
 	ProbeActiveSamplerMap *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
+	RateLimiterMap        *ebpf.MapSpec `ebpf:"rate_limiter_map"`
 	SamplersConfigMap     *ebpf.MapSpec `ebpf:"samplers_config_map"`
 	SliceArrayBuffMap     *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap   *ebpf.MapSpec `ebpf:"span_output_config_map"`
+	SpanStateStorageMap   *ebpf.MapSpec `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc *ebpf.MapSpec `ebpf:"tracked_span_states_by_sc"`
 	TrackedSpansBySc      *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
 }
 
@@ -130,12 +160,18 @@ func (o *bpfObjects) Close() error {
 type bpfMaps struct {
 	ActiveSpansBySpanPtr  *ebpf.Map `ebpf:"active_spans_by_span_ptr"`
 	AllocMap              *ebpf.Map `ebpf:"alloc_map"`
+	DroppedSpansMap       *ebpf.Map `ebpf:"dropped_spans_map"`
 	Events                *ebpf.Map `ebpf:"events"`
 	GoContextToSc         *ebpf.Map `ebpf:"go_context_to_sc"`
+	GoroutineToSc         *ebpf.Map `ebpf:"goroutine_to_sc"`
 	NewEvent              *ebpf.Map `ebpf:"new_event"`
 	ProbeActiveSamplerMap *ebpf.Map `ebpf:"probe_active_sampler_map"`
+	RateLimiterMap        *ebpf.Map `ebpf:"rate_limiter_map"`
 	SamplersConfigMap     *ebpf.Map `ebpf:"samplers_config_map"`
 	SliceArrayBuffMap     *ebpf.Map `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap   *ebpf.Map `ebpf:"span_output_config_map"`
+	SpanStateStorageMap   *ebpf.Map `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc *ebpf.Map `ebpf:"tracked_span_states_by_sc"`
 	TrackedSpansBySc      *ebpf.Map `ebpf:"tracked_spans_by_sc"`
 }
 
@@ -143,12 +179,18 @@ func (m *bpfMaps) Close() error {
 	return _BpfClose(
 		m.ActiveSpansBySpanPtr,
 		m.AllocMap,
+		m.DroppedSpansMap,
 		m.Events,
 		m.GoContextToSc,
+		m.GoroutineToSc,
 		m.NewEvent,
 		m.ProbeActiveSamplerMap,
+		m.RateLimiterMap,
 		m.SamplersConfigMap,
 		m.SliceArrayBuffMap,
+		m.SpanOutputConfigMap,
+		m.SpanStateStorageMap,
+		m.TrackedSpanStatesBySc,
 		m.TrackedSpansBySc,
 	)
 }
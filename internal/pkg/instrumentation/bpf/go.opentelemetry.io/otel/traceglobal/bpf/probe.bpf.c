@@ -503,13 +503,14 @@ int uprobe_Start_Returns(struct pt_regs *ctx) {
         .go_context = &go_context,
         .psc = &otel_span->psc,
         .sc = &otel_span->sc,
+        .state = &otel_span->state,
         .get_parent_span_context_fn = NULL,
         .get_parent_span_context_arg = NULL,
     };
     start_span(&start_span_params);
 
     bpf_map_update_elem(&active_spans_by_span_ptr, &span_ptr_val, otel_span, 0);
-    start_tracking_span(go_context.data, &otel_span->sc);
+    start_tracking_span(go_context.data, &otel_span->sc, &otel_span->state);
 
 done:
     bpf_map_delete_elem(&tracer_id_by_context, &key);
@@ -620,7 +621,7 @@ int uprobe_End(struct pt_regs *ctx) {
     span->kind = 0;
     stop_tracking_span(&span->sc, &span->psc);
 
-    output_span_event(ctx, span, sizeof(*span), &span->sc);
+    output_span_event(ctx, span, sizeof(*span), &span->sc, span->start_time, span->end_time);
 
     bpf_map_delete_elem(&active_spans_by_span_ptr, &non_recording_span_ptr);
     return 0;
@@ -20,6 +20,7 @@ type bpfOtelSpanT struct {
 	EndTime   uint64
 	Sc        bpfSpanContext
 	Psc       bpfSpanContext
+	State     bpfSpanState
 	SpanName  bpfSpanNameT
 	Status    struct {
 		_           structs.HostLayout
@@ -63,6 +64,30 @@ type bpfSpanNameT struct {
 	Buf [64]int8
 }
 
+type bpfSpanState struct {
+	_   structs.HostLayout
+	Ots struct {
+		_       structs.HostLayout
+		Th      uint64
+		Rv      uint64
+		HasTh   uint8
+		HasRv   uint8
+		Padding [6]uint8
+	}
+	Ts struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Entries [128]int8
+	}
+	Bg struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Value   [128]int8
+	}
+}
+
 type bpfTracerIdT struct {
 	_         structs.HostLayout
 	Name      [128]int8
@@ -127,17 +152,23 @@ type bpfProgramSpecs struct {
 type bpfMapSpecs struct {
 	ActiveSpansBySpanPtr      *ebpf.MapSpec `ebpf:"active_spans_by_span_ptr"`
 	AllocMap                  *ebpf.MapSpec `ebpf:"alloc_map"`
+	DroppedSpansMap           *ebpf.MapSpec `ebpf:"dropped_spans_map"`
 	Events                    *ebpf.MapSpec `ebpf:"events"`
 	GoContextToSc             *ebpf.MapSpec `ebpf:"go_context_to_sc"`
 	GolangMapbucketStorageMap *ebpf.MapSpec `ebpf:"golang_mapbucket_storage_map"`
+	GoroutineToSc             *ebpf.MapSpec `ebpf:"goroutine_to_sc"`
 	OtelSpanStorageMap        *ebpf.MapSpec `ebpf:"otel_span_storage_map"`
 	ProbeActiveSamplerMap     *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
+	RateLimiterMap            *ebpf.MapSpec `ebpf:"rate_limiter_map"`
 	SamplersConfigMap         *ebpf.MapSpec `ebpf:"samplers_config_map"`
 	SliceArrayBuffMap         *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
 	SpanNameByContext         *ebpf.MapSpec `ebpf:"span_name_by_context"`
+	SpanOutputConfigMap       *ebpf.MapSpec `ebpf:"span_output_config_map"`
+	SpanStateStorageMap       *ebpf.MapSpec `ebpf:"span_state_storage_map"`
 	TracerIdByContext         *ebpf.MapSpec `ebpf:"tracer_id_by_context"`
 	TracerIdStorageMap        *ebpf.MapSpec `ebpf:"tracer_id_storage_map"`
 	TracerPtrToIdMap          *ebpf.MapSpec `ebpf:"tracer_ptr_to_id_map"`
+	TrackedSpanStatesBySc     *ebpf.MapSpec `ebpf:"tracked_span_states_by_sc"`
 	TrackedSpansBySc          *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
 }
 
@@ -190,17 +221,23 @@ func (o *bpfObjects) Close() error {
 type bpfMaps struct {
 	ActiveSpansBySpanPtr      *ebpf.Map `ebpf:"active_spans_by_span_ptr"`
 	AllocMap                  *ebpf.Map `ebpf:"alloc_map"`
+	DroppedSpansMap           *ebpf.Map `ebpf:"dropped_spans_map"`
 	Events                    *ebpf.Map `ebpf:"events"`
 	GoContextToSc             *ebpf.Map `ebpf:"go_context_to_sc"`
 	GolangMapbucketStorageMap *ebpf.Map `ebpf:"golang_mapbucket_storage_map"`
+	GoroutineToSc             *ebpf.Map `ebpf:"goroutine_to_sc"`
 	OtelSpanStorageMap        *ebpf.Map `ebpf:"otel_span_storage_map"`
 	ProbeActiveSamplerMap     *ebpf.Map `ebpf:"probe_active_sampler_map"`
+	RateLimiterMap            *ebpf.Map `ebpf:"rate_limiter_map"`
 	SamplersConfigMap         *ebpf.Map `ebpf:"samplers_config_map"`
 	SliceArrayBuffMap         *ebpf.Map `ebpf:"slice_array_buff_map"`
 	SpanNameByContext         *ebpf.Map `ebpf:"span_name_by_context"`
+	SpanOutputConfigMap       *ebpf.Map `ebpf:"span_output_config_map"`
+	SpanStateStorageMap       *ebpf.Map `ebpf:"span_state_storage_map"`
 	TracerIdByContext         *ebpf.Map `ebpf:"tracer_id_by_context"`
 	TracerIdStorageMap        *ebpf.Map `ebpf:"tracer_id_storage_map"`
 	TracerPtrToIdMap          *ebpf.Map `ebpf:"tracer_ptr_to_id_map"`
+	TrackedSpanStatesBySc     *ebpf.Map `ebpf:"tracked_span_states_by_sc"`
 	TrackedSpansBySc          *ebpf.Map `ebpf:"tracked_spans_by_sc"`
 }
 
@@ -208,17 +245,23 @@ func (m *bpfMaps) Close() error {
 	return _BpfClose(
 		m.ActiveSpansBySpanPtr,
 		m.AllocMap,
+		m.DroppedSpansMap,
 		m.Events,
 		m.GoContextToSc,
 		m.GolangMapbucketStorageMap,
+		m.GoroutineToSc,
 		m.OtelSpanStorageMap,
 		m.ProbeActiveSamplerMap,
+		m.RateLimiterMap,
 		m.SamplersConfigMap,
 		m.SliceArrayBuffMap,
 		m.SpanNameByContext,
+		m.SpanOutputConfigMap,
+		m.SpanStateStorageMap,
 		m.TracerIdByContext,
 		m.TracerIdStorageMap,
 		m.TracerPtrToIdMap,
+		m.TrackedSpanStatesBySc,
 		m.TrackedSpansBySc,
 	)
 }
@@ -362,6 +362,7 @@ func processFn(e *event) (pcommon.InstrumentationScope, string, ptrace.SpanSlice
 	if e.ParentSpanContext.SpanID.IsValid() {
 		span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
 	}
+	span.TraceState().FromRaw(e.State.TraceState())
 
 	setAttributes(span.Attributes(), e.Attributes)
 	setStatus(span.Status(), e.Status)
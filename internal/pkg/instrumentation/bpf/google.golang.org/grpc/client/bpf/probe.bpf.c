@@ -5,8 +5,11 @@
 #include "go_types.h"
 #include "trace/span_context.h"
 #include "go_context.h"
+#include "go_headers.h"
+#include "rpc_messages.h"
 #include "uprobe.h"
 #include "trace/start_span.h"
+#include "trace/propagation.h"
 
 char __license[] SEC("license") = "Dual MIT/GPL";
 
@@ -14,18 +17,23 @@ char __license[] SEC("license") = "Dual MIT/GPL";
 #define MAX_CONCURRENT 50
 #define MAX_ERROR_LEN 128
 
+// The HTTP/2 stream of an RPC on its *http2Client transport.
+struct grpc_stream_key {
+    void *transport;
+    u32 stream_id;
+    u8 padding[4];
+};
+
 struct grpc_request_t {
     BASE_SPAN_PROPERTIES
     char err_msg[MAX_ERROR_LEN];
     char method[MAX_SIZE];
     char target[MAX_SIZE];
     u32 status_code;
-};
-
-struct hpack_header_field {
-    struct go_string name;
-    struct go_string value;
-    bool sensitive;
+    struct grpc_stream_key stream;
+    struct rpc_messages messages;
+    struct captured_headers request_metadata;
+    struct captured_headers response_metadata;
 };
 
 struct {
@@ -42,6 +50,54 @@ struct {
     __uint(max_entries, MAX_CONCURRENT);
 } streamid_to_span_contexts SEC(".maps");
 
+struct {
+    __uint(type, BPF_MAP_TYPE_PERCPU_ARRAY);
+    __uint(key_size, sizeof(u32));
+    __uint(value_size, sizeof(struct grpc_request_t));
+    __uint(max_entries, 1);
+} grpc_storage_map SEC(".maps");
+
+// The streams being created by NewStream, by goroutine.
+struct {
+    __uint(type, BPF_MAP_TYPE_HASH);
+    __type(key, void *);
+    __type(value, struct grpc_request_t);
+    __uint(max_entries, MAX_CONCURRENT);
+} grpc_new_streams SEC(".maps");
+
+// The streams created by NewStream, by *clientStream. The streams never
+// finished by the application are evicted.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, struct grpc_request_t);
+    __uint(max_entries, MAX_CONCURRENT);
+} grpc_streams SEC(".maps");
+
+// The *clientStream sending or receiving a message, by goroutine.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, void *);
+    __uint(max_entries, MAX_CONCURRENT);
+} grpc_stream_calls SEC(".maps");
+
+// The *parser receiving a message, by goroutine.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, void *);
+    __uint(max_entries, MAX_CONCURRENT);
+} grpc_parsers SEC(".maps");
+
+// The response metadata received for the streams of the traced RPCs.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, struct grpc_stream_key);
+    __type(value, struct captured_headers);
+    __uint(max_entries, MAX_CONCURRENT);
+} grpc_response_metadata SEC(".maps");
+
 // Injected in init
 volatile const u64 clientconn_target_ptr_pos;
 volatile const u64 httpclient_nextid_pos;
@@ -51,81 +107,71 @@ volatile const u64 error_status_pos;
 volatile const u64 status_s_pos;
 volatile const u64 status_message_pos;
 volatile const u64 status_code_pos;
+volatile const u64 parser_header_pos;
+volatile const u64 frame_fields_pos;
+volatile const u64 frame_stream_id_pos;
+// The address of the itab of *transport.headerFrame implementing
+// transport.cbItem, 0 if unknown.
+volatile const u64 header_frame_itab;
+// The address of the itab of *status.Error implementing error, 0 if unknown.
+volatile const u64 status_error_itab;
 
 volatile const bool write_status_supported;
 
-// This instrumentation attaches uprobe to the following function:
-// func (cc *ClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...CallOption) error
-SEC("uprobe/ClientConn_Invoke")
-int uprobe_ClientConn_Invoke(struct pt_regs *ctx) {
-    // positions
-    u64 clientconn_pos = 1;
-    u64 method_ptr_pos = 4;
-    u64 method_len_pos = 5;
-
-    struct go_iface go_context = {0};
-    get_Go_context(ctx, 2, 0, true, &go_context);
-
-    // Get key
-    void *key = (void *)GOROUTINE(ctx);
-    void *grpcReq_ptr = bpf_map_lookup_elem(&grpc_events, &key);
-    if (grpcReq_ptr != NULL) {
-        bpf_printk("uprobe/ClientConn_Invoke already tracked with the current context");
-        return 0;
-    }
-
-    struct grpc_request_t grpcReq = {};
-    grpcReq.start_time = bpf_ktime_get_ns();
+// Start the span of the RPC of the method called on the *ClientConn at
+// clientconn_ptr with the go_context into grpcReq.
+// Returns 0 on success, negative value on error.
+static __always_inline int start_rpc_span(struct pt_regs *ctx,
+                                          struct go_iface *go_context,
+                                          void *clientconn_ptr,
+                                          void *method_ptr,
+                                          u64 method_len,
+                                          struct grpc_request_t *grpcReq) {
+    __builtin_memset(grpcReq, 0, sizeof(*grpcReq));
+    grpcReq->start_time = bpf_ktime_get_ns();
 
     // Read Method
-    void *method_ptr = get_argument(ctx, method_ptr_pos);
-    u64 method_len = (u64)get_argument(ctx, method_len_pos);
-    u64 method_size = sizeof(grpcReq.method);
+    u64 method_size = sizeof(grpcReq->method);
     method_size = method_size < method_len ? method_size : method_len;
-    bpf_probe_read(&grpcReq.method, method_size, method_ptr);
+    bpf_probe_read(&grpcReq->method, method_size, method_ptr);
 
     // Read ClientConn.Target
-    void *clientconn_ptr = get_argument(ctx, clientconn_pos);
     if (!get_go_string_from_user_ptr((void *)(clientconn_ptr + clientconn_target_ptr_pos),
-                                     grpcReq.target,
-                                     sizeof(grpcReq.target))) {
+                                     grpcReq->target,
+                                     sizeof(grpcReq->target))) {
         bpf_printk("target write failed, aborting ebpf probe");
-        return 0;
+        return -1;
     }
 
+    sampling_attributes_t sampling_attrs = {0};
+    sampling_attrs_set_value(&sampling_attrs, SAMPLING_ATTR_RPC_METHOD, method_ptr, method_len);
+
     start_span_params_t start_span_params = {
         .ctx = ctx,
-        .go_context = &go_context,
-        .psc = &grpcReq.psc,
-        .sc = &grpcReq.sc,
+        .go_context = go_context,
+        .psc = &grpcReq->psc,
+        .sc = &grpcReq->sc,
+        .state = &grpcReq->state,
         .get_parent_span_context_fn = NULL,
         .get_parent_span_context_arg = NULL,
+        .sampling_attrs = &sampling_attrs,
     };
     start_span(&start_span_params);
-
-    // Write event
-    bpf_map_update_elem(&grpc_events, &key, &grpcReq, 0);
-    start_tracking_span(go_context.data, &grpcReq.sc);
     return 0;
 }
 
-// This instrumentation attaches uprobe to the following function:
-// func (cc *ClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...CallOption) error
-SEC("uprobe/ClientConn_Invoke")
-int uprobe_ClientConn_Invoke_Returns(struct pt_regs *ctx) {
-    void *key = (void *)GOROUTINE(ctx);
-    struct grpc_request_t *grpc_span = bpf_map_lookup_elem(&grpc_events, &key);
-    if (grpc_span == NULL) {
-        bpf_printk("event is NULL in ret probe");
-        return 0;
+// Read the status of the error with the itab and the data pointer into
+// grpc_span. Nothing is read if the error is nil or not a *status.Error, or if
+// the itab of *status.Error is unknown.
+static __always_inline void
+read_status_error(void *err_itab, void *err_data, struct grpc_request_t *grpc_span) {
+    if (!write_status_supported || err_itab == NULL || err_data == NULL) {
+        return;
     }
-
-    if (!write_status_supported) {
-        goto done;
+    if (status_error_itab == 0 || (u64)err_itab != status_error_itab) {
+        return;
     }
-    // Getting the returned response (error)
     // The status code is embedded 3 layers deep:
-    // Invoke() error
     // the `error` interface concrete type here is a gRPC `internal.Error` struct
     // type Error struct {
     //   s *Status
@@ -140,14 +186,9 @@ int uprobe_ClientConn_Invoke_Returns(struct pt_regs *ctx) {
     //     Message string
     //     Details []*anypb.Any
     // }
-    void *resp_ptr = get_argument(ctx, 2);
-    if (resp_ptr == 0) {
-        // err == nil
-        goto done;
-    }
     void *status_ptr = 0;
     // get `s` (Status pointer field) from Error struct
-    bpf_probe_read_user(&status_ptr, sizeof(status_ptr), (void *)(resp_ptr + error_status_pos));
+    bpf_probe_read_user(&status_ptr, sizeof(status_ptr), (void *)(err_data + error_status_pos));
     // get `s` field from Status object pointer
     void *s_ptr = 0;
     bpf_probe_read_user(&s_ptr, sizeof(s_ptr), (void *)(status_ptr + status_s_pos));
@@ -156,15 +197,281 @@ int uprobe_ClientConn_Invoke_Returns(struct pt_regs *ctx) {
         &grpc_span->status_code, sizeof(grpc_span->status_code), (void *)(s_ptr + status_code_pos));
     get_go_string_from_user_ptr(
         (void *)(s_ptr + status_message_pos), grpc_span->err_msg, sizeof(grpc_span->err_msg));
+}
 
-done:
+// End and output grpc_span.
+static __always_inline void end_rpc_span(struct pt_regs *ctx, struct grpc_request_t *grpc_span) {
     grpc_span->end_time = bpf_ktime_get_ns();
-    output_span_event(ctx, grpc_span, sizeof(*grpc_span), &grpc_span->sc);
+    struct captured_headers *md = bpf_map_lookup_elem(&grpc_response_metadata, &grpc_span->stream);
+    if (md != NULL) {
+        grpc_span->response_metadata = *md;
+        bpf_map_delete_elem(&grpc_response_metadata, &grpc_span->stream);
+    }
+    output_span_event(ctx,
+                      grpc_span,
+                      sizeof(*grpc_span),
+                      &grpc_span->sc,
+                      grpc_span->start_time,
+                      grpc_span->end_time);
     stop_tracking_span(&grpc_span->sc, &grpc_span->psc);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (cc *ClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...CallOption) error
+SEC("uprobe/ClientConn_Invoke")
+int uprobe_ClientConn_Invoke(struct pt_regs *ctx) {
+    // positions
+    u64 clientconn_pos = 1;
+    u64 method_ptr_pos = 4;
+    u64 method_len_pos = 5;
+
+    struct go_iface go_context = {0};
+    get_Go_context(ctx, 2, 0, true, &go_context);
+
+    // Get key
+    void *key = (void *)GOROUTINE(ctx);
+    void *grpcReq_ptr = bpf_map_lookup_elem(&grpc_events, &key);
+    if (grpcReq_ptr != NULL) {
+        bpf_printk("uprobe/ClientConn_Invoke already tracked with the current context");
+        return 0;
+    }
+
+    u32 map_id = 0;
+    struct grpc_request_t *grpcReq = bpf_map_lookup_elem(&grpc_storage_map, &map_id);
+    if (grpcReq == NULL) {
+        bpf_printk("uprobe/ClientConn_Invoke: grpcReq is NULL");
+        return 0;
+    }
+
+    if (start_rpc_span(ctx,
+                       &go_context,
+                       get_argument(ctx, clientconn_pos),
+                       get_argument(ctx, method_ptr_pos),
+                       (u64)get_argument(ctx, method_len_pos),
+                       grpcReq) != 0) {
+        return 0;
+    }
+
+    // Write event
+    bpf_map_update_elem(&grpc_events, &key, grpcReq, 0);
+    start_tracking_span(go_context.data, &grpcReq->sc, &grpcReq->state);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (cc *ClientConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...CallOption) error
+SEC("uprobe/ClientConn_Invoke")
+int uprobe_ClientConn_Invoke_Returns(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    struct grpc_request_t *grpc_span = bpf_map_lookup_elem(&grpc_events, &key);
+    if (grpc_span == NULL) {
+        bpf_printk("event is NULL in ret probe");
+        return 0;
+    }
+
+    // Getting the returned response (error)
+    read_status_error(get_argument(ctx, 1), get_argument(ctx, 2), grpc_span);
+
+    end_rpc_span(ctx, grpc_span);
     bpf_map_delete_elem(&grpc_events, &key);
     return 0;
 }
 
+// This instrumentation attaches uprobe to the following function:
+// func (cc *ClientConn) NewStream(ctx context.Context, desc *StreamDesc, method string, opts ...CallOption) (ClientStream, error)
+SEC("uprobe/ClientConn_NewStream")
+int uprobe_ClientConn_NewStream(struct pt_regs *ctx) {
+    // positions
+    u64 clientconn_pos = 1;
+    u64 method_ptr_pos = 5;
+    u64 method_len_pos = 6;
+
+    struct go_iface go_context = {0};
+    get_Go_context(ctx, 2, 0, true, &go_context);
+
+    void *key = (void *)GOROUTINE(ctx);
+    void *grpcReq_ptr = bpf_map_lookup_elem(&grpc_new_streams, &key);
+    if (grpcReq_ptr != NULL) {
+        bpf_printk("uprobe/ClientConn_NewStream already tracked with the current context");
+        return 0;
+    }
+
+    u32 map_id = 0;
+    struct grpc_request_t *grpcReq = bpf_map_lookup_elem(&grpc_storage_map, &map_id);
+    if (grpcReq == NULL) {
+        bpf_printk("uprobe/ClientConn_NewStream: grpcReq is NULL");
+        return 0;
+    }
+
+    if (start_rpc_span(ctx,
+                       &go_context,
+                       get_argument(ctx, clientconn_pos),
+                       get_argument(ctx, method_ptr_pos),
+                       (u64)get_argument(ctx, method_len_pos),
+                       grpcReq) != 0) {
+        return 0;
+    }
+
+    bpf_map_update_elem(&grpc_new_streams, &key, grpcReq, 0);
+    // Tracked until the stream is finished, for the transport stream to find it.
+    start_tracking_span(go_context.data, &grpcReq->sc, &grpcReq->state);
+    return 0;
+}
+
+// Move the stream being created by the goroutine to grpc_streams with the
+// stream_ptr key.
+static __always_inline void track_new_stream(void *goroutine, void *stream_ptr) {
+    struct grpc_request_t *grpc_span = bpf_map_lookup_elem(&grpc_new_streams, &goroutine);
+    if (grpc_span == NULL) {
+        return;
+    }
+    bpf_map_update_elem(&grpc_streams, &stream_ptr, grpc_span, 0);
+    bpf_map_delete_elem(&grpc_new_streams, &goroutine);
+}
+
+// This instrumentation attaches uretprobe to the following function:
+// func newClientStream(ctx context.Context, desc *StreamDesc, cc *ClientConn, method string, opts ...CallOption) (_ ClientStream, err error)
+// The stream it returns is the *clientStream, while the one returned by
+// NewStream may be wrapped by interceptors.
+SEC("uprobe/newClientStream")
+int uprobe_newClientStream_Returns(struct pt_regs *ctx) {
+    void *stream_ptr = get_argument(ctx, 2);
+    void *err_itab = get_argument(ctx, 3);
+    if (stream_ptr == NULL || err_itab != NULL) {
+        return 0;
+    }
+    track_new_stream((void *)GOROUTINE(ctx), stream_ptr);
+    return 0;
+}
+
+// This instrumentation attaches uretprobe to the following function:
+// func (cc *ClientConn) NewStream(ctx context.Context, desc *StreamDesc, method string, opts ...CallOption) (ClientStream, error)
+SEC("uprobe/ClientConn_NewStream")
+int uprobe_ClientConn_NewStream_Returns(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    struct grpc_request_t *grpc_span = bpf_map_lookup_elem(&grpc_new_streams, &key);
+    if (grpc_span == NULL) {
+        // Already tracked by the returned *clientStream.
+        return 0;
+    }
+
+    void *stream_ptr = get_argument(ctx, 2);
+    void *err_itab = get_argument(ctx, 3);
+    if (err_itab == NULL && stream_ptr != NULL) {
+        track_new_stream(key, stream_ptr);
+        return 0;
+    }
+
+    read_status_error(err_itab, get_argument(ctx, 4), grpc_span);
+    end_rpc_span(ctx, grpc_span);
+    bpf_map_delete_elem(&grpc_new_streams, &key);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (cs *clientStream) finish(err error)
+SEC("uprobe/clientStream_finish")
+int uprobe_clientStream_finish(struct pt_regs *ctx) {
+    void *stream_ptr = get_argument(ctx, 1);
+    struct grpc_request_t *grpc_span = bpf_map_lookup_elem(&grpc_streams, &stream_ptr);
+    if (grpc_span == NULL) {
+        return 0;
+    }
+
+    // io.EOF is not a *status.Error, and ends the stream successfully.
+    read_status_error(get_argument(ctx, 2), get_argument(ctx, 3), grpc_span);
+    end_rpc_span(ctx, grpc_span);
+    bpf_map_delete_elem(&grpc_streams, &stream_ptr);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following functions:
+// func (cs *clientStream) SendMsg(m any) (err error)
+// func (cs *clientStream) RecvMsg(m any) error
+SEC("uprobe/clientStream_msg")
+int uprobe_clientStream_msg(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    void *stream_ptr = get_argument(ctx, 1);
+    bpf_map_update_elem(&grpc_stream_calls, &key, &stream_ptr, 0);
+    return 0;
+}
+
+// This instrumentation attaches uretprobe to the following functions:
+// func (cs *clientStream) SendMsg(m any) (err error)
+// func (cs *clientStream) RecvMsg(m any) error
+SEC("uprobe/clientStream_msg")
+int uprobe_clientStream_msg_Returns(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    bpf_map_delete_elem(&grpc_stream_calls, &key);
+    return 0;
+}
+
+// Returns the span of the RPC sending or receiving a message on the goroutine,
+// in the SendMsg or RecvMsg of its *clientStream: the stream, or the unary RPC
+// using it. NULL is returned for the messages of the servers of the process.
+static __always_inline struct grpc_request_t *lookup_message_span(void *goroutine) {
+    void **stream_ptr = bpf_map_lookup_elem(&grpc_stream_calls, &goroutine);
+    if (stream_ptr == NULL) {
+        return NULL;
+    }
+    void *stream = *stream_ptr;
+    struct grpc_request_t *grpc_span = bpf_map_lookup_elem(&grpc_streams, &stream);
+    if (grpc_span != NULL) {
+        return grpc_span;
+    }
+    return bpf_map_lookup_elem(&grpc_events, &goroutine);
+}
+
+// This instrumentation attaches uretprobe to the following function:
+// func prepareMsg(m any, codec baseCodec, cp Compressor, comp encoding.Compressor, ...) (hdr []byte, ...)
+SEC("uprobe/prepareMsg")
+int uprobe_prepareMsg_Returns(struct pt_regs *ctx) {
+    u64 now = bpf_ktime_get_ns();
+    struct grpc_request_t *grpc_span = lookup_message_span((void *)GOROUTINE(ctx));
+    if (grpc_span == NULL) {
+        return 0;
+    }
+    // hdr is nil if the message could not be prepared.
+    record_rpc_message(&grpc_span->messages, true, get_argument(ctx, 1), now);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (p *parser) recvMsg(maxReceiveMessageSize int) (pf payloadFormat, msg []byte, err error)
+SEC("uprobe/parser_recvMsg")
+int uprobe_parser_recvMsg(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    void *parser_ptr = get_argument(ctx, 1);
+    bpf_map_update_elem(&grpc_parsers, &key, &parser_ptr, 0);
+    return 0;
+}
+
+// This instrumentation attaches uretprobe to the following function:
+// func (p *parser) recvMsg(maxReceiveMessageSize int) (pf payloadFormat, msg []byte, err error)
+SEC("uprobe/parser_recvMsg")
+int uprobe_parser_recvMsg_Returns(struct pt_regs *ctx) {
+    u64 now = bpf_ktime_get_ns();
+    void *key = (void *)GOROUTINE(ctx);
+    void **parser_ptr = bpf_map_lookup_elem(&grpc_parsers, &key);
+    if (parser_ptr == NULL) {
+        return 0;
+    }
+    void *parser = *parser_ptr;
+    bpf_map_delete_elem(&grpc_parsers, &key);
+
+    // No message is received if err is not nil.
+    void *err_itab = get_argument(ctx, 5);
+    if (err_itab != NULL) {
+        return 0;
+    }
+    struct grpc_request_t *grpc_span = lookup_message_span(key);
+    if (grpc_span == NULL) {
+        return 0;
+    }
+    record_rpc_message(&grpc_span->messages, false, parser + parser_header_pos, now);
+    return 0;
+}
+
 // func (l *loopyWriter) headerHandler(h *headerFrame) error
 SEC("uprobe/loopyWriter_headerHandler")
 int uprobe_LoopyWriter_HeaderHandler(struct pt_regs *ctx) {
@@ -180,31 +487,47 @@ int uprobe_LoopyWriter_HeaderHandler(struct pt_regs *ctx) {
     struct span_context current_span_context = {};
     bpf_probe_read(&current_span_context, sizeof(current_span_context), sc_ptr);
 
-    char tp_key[11] = "traceparent";
-    struct go_string key_str = write_user_go_string(tp_key, sizeof(tp_key));
-    if (key_str.len == 0) {
-        bpf_printk("key write failed, aborting ebpf probe");
+    struct propagation_header *h = new_propagation_header();
+    if (h == NULL) {
         goto done;
     }
+    // The span is tracked until the call or stream is finished.
+    struct span_state *state = get_span_state(&current_span_context);
 
     // Write headers
-    char val[SPAN_CONTEXT_STRING_SIZE];
-    span_context_to_w3c_string(&current_span_context, val);
-    struct go_string val_str = write_user_go_string(val, sizeof(val));
-    if (val_str.len == 0) {
-        bpf_printk("val write failed, aborting ebpf probe");
-        goto done;
-    }
     struct hpack_header_field hf = {};
-    hf.name = key_str;
-    hf.value = val_str;
-    append_item_to_slice(&hf, sizeof(hf), (void *)(headerFrame_ptr + (headerFrame_hf_pos)));
+    for (u32 i = 0; i < MAX_PROPAGATION_HEADERS; i++) {
+        if (!propagation_inject_header(i, &current_span_context, state, h)) {
+            continue;
+        }
+        hf.name = write_user_go_string(h->key, h->key_len);
+        if (hf.name.len == 0) {
+            bpf_printk("key write failed, aborting ebpf probe");
+            goto done;
+        }
+        hf.value = write_user_go_string(h->value, h->value_len);
+        if (hf.value.len == 0) {
+            bpf_printk("val write failed, aborting ebpf probe");
+            goto done;
+        }
+        append_item_to_slice(&hf, sizeof(hf), (void *)(headerFrame_ptr + (headerFrame_hf_pos)));
+    }
 done:
     bpf_map_delete_elem(&streamid_to_span_contexts, &stream_id);
 
     return 0;
 }
 
+// Returns the span of the RPC being started on the goroutine, by Invoke or
+// NewStream, or NULL.
+static __always_inline struct grpc_request_t *lookup_call_span(void *goroutine) {
+    struct grpc_request_t *grpc_span = bpf_map_lookup_elem(&grpc_events, &goroutine);
+    if (grpc_span != NULL) {
+        return grpc_span;
+    }
+    return bpf_map_lookup_elem(&grpc_new_streams, &goroutine);
+}
+
 SEC("uprobe/http2Client_NewStream")
 // func (t *http2Client) NewStream(ctx context.Context, callHdr *CallHdr) (*Stream, error)
 int uprobe_http2Client_NewStream(struct pt_regs *ctx) {
@@ -220,5 +543,71 @@ int uprobe_http2Client_NewStream(struct pt_regs *ctx) {
         bpf_map_update_elem(&streamid_to_span_contexts, &nextid, current_span_context, 0);
     }
 
+    struct grpc_request_t *grpc_span = lookup_call_span((void *)GOROUTINE(ctx));
+    if (grpc_span == NULL) {
+        return 0;
+    }
+    grpc_span->stream.transport = httpclient_ptr;
+    grpc_span->stream.stream_id = nextid;
+    if (!header_capture_enabled(HEADER_CAPTURE_RESPONSE)) {
+        return 0;
+    }
+
+    // Track the stream for its response metadata to be captured, with the
+    // scratch response metadata of the storage map as empty value.
+    u32 map_id = 0;
+    struct grpc_request_t *storage = bpf_map_lookup_elem(&grpc_storage_map, &map_id);
+    if (storage == NULL) {
+        return 0;
+    }
+    __builtin_memset(&storage->response_metadata, 0, sizeof(storage->response_metadata));
+    bpf_map_update_elem(
+        &grpc_response_metadata, &grpc_span->stream, &storage->response_metadata, 0);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *controlBuffer) executeAndPut(f func() bool, it cbItem) (bool, error)
+// The *headerFrame of the request headers is put by the goroutine creating the
+// stream, before its stream ID is assigned.
+SEC("uprobe/controlBuffer_executeAndPut")
+int uprobe_controlBuffer_executeAndPut(struct pt_regs *ctx) {
+    void *it_itab = get_argument(ctx, 3);
+    if (header_frame_itab == 0 || (u64)it_itab != header_frame_itab) {
+        return 0;
+    }
+    struct grpc_request_t *grpc_span = lookup_call_span((void *)GOROUTINE(ctx));
+    if (grpc_span == NULL) {
+        return 0;
+    }
+    // The header frame is put again when the stream quota is exceeded.
+    grpc_span->request_metadata.count = 0;
+    void *header_frame_ptr = get_argument(ctx, 4);
+    capture_header_fields(
+        header_frame_ptr + headerFrame_hf_pos, HEADER_CAPTURE_REQUEST, &grpc_span->request_metadata);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (t *http2Client) operateHeaders(frame *http2.MetaHeadersFrame)
+// The response headers and trailers of the streams are received by the reader
+// goroutine of the transport.
+SEC("uprobe/http2Client_operateHeaders")
+int uprobe_http2Client_operateHeaders(struct pt_regs *ctx) {
+    if (!header_capture_enabled(HEADER_CAPTURE_RESPONSE)) {
+        return 0;
+    }
+    void *frame_ptr = get_argument(ctx, 2);
+    void *headers_frame = NULL;
+    bpf_probe_read_user(&headers_frame, sizeof(headers_frame), frame_ptr);
+    struct grpc_stream_key key = {.transport = get_argument(ctx, 1)};
+    bpf_probe_read_user(
+        &key.stream_id, sizeof(key.stream_id), (void *)(headers_frame + frame_stream_id_pos));
+
+    struct captured_headers *md = bpf_map_lookup_elem(&grpc_response_metadata, &key);
+    if (md == NULL) {
+        return 0;
+    }
+    capture_header_fields(frame_ptr + frame_fields_pos, HEADER_CAPTURE_RESPONSE, md);
     return 0;
 }
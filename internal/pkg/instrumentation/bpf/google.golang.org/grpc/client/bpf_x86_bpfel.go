@@ -13,16 +13,60 @@ import (
 	"github.com/cilium/ebpf"
 )
 
+type bpfCapturedHeaders struct {
+	_       structs.HostLayout
+	Count   uint32
+	Padding [4]uint8
+	Headers [8]struct {
+		_        structs.HostLayout
+		Name     [32]int8
+		Value    [128]int8
+		ValueLen uint32
+		Redacted uint8
+		Padding  [3]uint8
+	}
+}
+
 type bpfGrpcRequestT struct {
 	_          structs.HostLayout
 	StartTime  uint64
 	EndTime    uint64
 	Sc         bpfSpanContext
 	Psc        bpfSpanContext
+	State      bpfSpanState
 	ErrMsg     [128]int8
 	Method     [50]int8
 	Target     [50]int8
 	StatusCode uint32
+	Stream     bpfGrpcStreamKey
+	Messages   struct {
+		_        structs.HostLayout
+		Sent     uint32
+		Received uint32
+		Events   [8]struct {
+			_          structs.HostLayout
+			Time       uint64
+			Id         uint32
+			Size       uint32
+			Sent       uint8
+			Compressed uint8
+			Padding    [6]uint8
+		}
+	}
+	RequestMetadata  bpfCapturedHeaders
+	ResponseMetadata bpfCapturedHeaders
+}
+
+type bpfGrpcStreamKey struct {
+	_         structs.HostLayout
+	Transport uint64
+	StreamId  uint32
+	Padding   [4]uint8
+}
+
+type bpfHeaderCaptureKey struct {
+	_    structs.HostLayout
+	Name [32]int8
 }
 
 type bpfSliceArrayBuff struct {
@@ -70,6 +114,30 @@ func loadBpfObjects(obj interface{}, opts *ebpf.CollectionOptions) error {
 // bpfSpecs contains maps and programs before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
+type bpfSpanState struct {
+	_   structs.HostLayout
+	Ots struct {
+		_       structs.HostLayout
+		Th      uint64
+		Rv      uint64
+		HasTh   uint8
+		HasRv   uint8
+		Padding [6]uint8
+	}
+	Ts struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Entries [128]int8
+	}
+	Bg struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Value   [128]int8
+	}
+}
+
 type bpfSpecs struct {
 	bpfProgramSpecs
 	bpfMapSpecs
@@ -80,42 +148,76 @@ type bpfSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfProgramSpecs struct {
-	UprobeClientConnInvoke         *ebpf.ProgramSpec `ebpf:"uprobe_ClientConn_Invoke"`
-	UprobeClientConnInvokeReturns  *ebpf.ProgramSpec `ebpf:"uprobe_ClientConn_Invoke_Returns"`
-	UprobeLoopyWriterHeaderHandler *ebpf.ProgramSpec `ebpf:"uprobe_LoopyWriter_HeaderHandler"`
-	UprobeHttp2ClientNewStream     *ebpf.ProgramSpec `ebpf:"uprobe_http2Client_NewStream"`
+	UprobeClientConnInvoke           *ebpf.ProgramSpec `ebpf:"uprobe_ClientConn_Invoke"`
+	UprobeClientConnInvokeReturns    *ebpf.ProgramSpec `ebpf:"uprobe_ClientConn_Invoke_Returns"`
+	UprobeClientConnNewStream        *ebpf.ProgramSpec `ebpf:"uprobe_ClientConn_NewStream"`
+	UprobeClientConnNewStreamReturns *ebpf.ProgramSpec `ebpf:"uprobe_ClientConn_NewStream_Returns"`
+	UprobeLoopyWriterHeaderHandler   *ebpf.ProgramSpec `ebpf:"uprobe_LoopyWriter_HeaderHandler"`
+	UprobeClientStreamFinish         *ebpf.ProgramSpec `ebpf:"uprobe_clientStream_finish"`
+	UprobeClientStreamMsg            *ebpf.ProgramSpec `ebpf:"uprobe_clientStream_msg"`
+	UprobeClientStreamMsgReturns     *ebpf.ProgramSpec `ebpf:"uprobe_clientStream_msg_Returns"`
+	UprobeControlBufferExecuteAndPut *ebpf.ProgramSpec `ebpf:"uprobe_controlBuffer_executeAndPut"`
+	UprobeHttp2ClientNewStream       *ebpf.ProgramSpec `ebpf:"uprobe_http2Client_NewStream"`
+	UprobeHttp2ClientOperateHeaders  *ebpf.ProgramSpec `ebpf:"uprobe_http2Client_operateHeaders"`
+	UprobeNewClientStreamReturns     *ebpf.ProgramSpec `ebpf:"uprobe_newClientStream_Returns"`
+	UprobeParserRecvMsg              *ebpf.ProgramSpec `ebpf:"uprobe_parser_recvMsg"`
+	UprobeParserRecvMsgReturns       *ebpf.ProgramSpec `ebpf:"uprobe_parser_recvMsg_Returns"`
+	UprobePrepareMsgReturns          *ebpf.ProgramSpec `ebpf:"uprobe_prepareMsg_Returns"`
 }
 
 // bpfMapSpecs contains maps before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfMapSpecs struct {
-	AllocMap               *ebpf.MapSpec `ebpf:"alloc_map"`
-	Events                 *ebpf.MapSpec `ebpf:"events"`
-	GoContextToSc          *ebpf.MapSpec `ebpf:"go_context_to_sc"`
-	GrpcEvents             *ebpf.MapSpec `ebpf:"grpc_events"`
-	ProbeActiveSamplerMap  *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap      *ebpf.MapSpec `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap      *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
-	StreamidToSpanContexts *ebpf.MapSpec `ebpf:"streamid_to_span_contexts"`
-	TrackedSpansBySc       *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.MapSpec `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.MapSpec `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.MapSpec `ebpf:"events"`
+	GoContextToSc                  *ebpf.MapSpec `ebpf:"go_context_to_sc"`
+	GoroutineToSc                  *ebpf.MapSpec `ebpf:"goroutine_to_sc"`
+	GrpcEvents                     *ebpf.MapSpec `ebpf:"grpc_events"`
+	GrpcNewStreams                 *ebpf.MapSpec `ebpf:"grpc_new_streams"`
+	GrpcParsers                    *ebpf.MapSpec `ebpf:"grpc_parsers"`
+	GrpcResponseMetadata           *ebpf.MapSpec `ebpf:"grpc_response_metadata"`
+	GrpcStorageMap                 *ebpf.MapSpec `ebpf:"grpc_storage_map"`
+	GrpcStreamCalls                *ebpf.MapSpec `ebpf:"grpc_stream_calls"`
+	GrpcStreams                    *ebpf.MapSpec `ebpf:"grpc_streams"`
+	HeaderCaptureMap               *ebpf.MapSpec `ebpf:"header_capture_map"`
+	ProbeActiveSamplerMap          *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.MapSpec `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.MapSpec `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.MapSpec `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.MapSpec `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.MapSpec `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.MapSpec `ebpf:"span_state_storage_map"`
+	StreamidToSpanContexts         *ebpf.MapSpec `ebpf:"streamid_to_span_contexts"`
+	TrackedSpanStatesBySc          *ebpf.MapSpec `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
 }
 
 // bpfVariableSpecs contains global variables before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfVariableSpecs struct {
+	BucketsPtrPos          *ebpf.VariableSpec `ebpf:"buckets_ptr_pos"`
 	ClientconnTargetPtrPos *ebpf.VariableSpec `ebpf:"clientconn_target_ptr_pos"`
 	EndAddr                *ebpf.VariableSpec `ebpf:"end_addr"`
 	ErrorStatusPos         *ebpf.VariableSpec `ebpf:"error_status_pos"`
+	FrameFieldsPos         *ebpf.VariableSpec `ebpf:"frame_fields_pos"`
+	FrameStreamIdPos       *ebpf.VariableSpec `ebpf:"frame_stream_id_pos"`
 	HeaderFrameHfPos       *ebpf.VariableSpec `ebpf:"headerFrame_hf_pos"`
 	HeaderFrameStreamidPos *ebpf.VariableSpec `ebpf:"headerFrame_streamid_pos"`
+	HeaderFrameItab        *ebpf.VariableSpec `ebpf:"header_frame_itab"`
 	Hex                    *ebpf.VariableSpec `ebpf:"hex"`
 	HttpclientNextidPos    *ebpf.VariableSpec `ebpf:"httpclient_nextid_pos"`
+	ParserHeaderPos        *ebpf.VariableSpec `ebpf:"parser_header_pos"`
+	Propagators            *ebpf.VariableSpec `ebpf:"propagators"`
 	StartAddr              *ebpf.VariableSpec `ebpf:"start_addr"`
 	StatusCodePos          *ebpf.VariableSpec `ebpf:"status_code_pos"`
+	StatusErrorItab        *ebpf.VariableSpec `ebpf:"status_error_itab"`
 	StatusMessagePos       *ebpf.VariableSpec `ebpf:"status_message_pos"`
 	StatusS_pos            *ebpf.VariableSpec `ebpf:"status_s_pos"`
+	SwissMapsUsed          *ebpf.VariableSpec `ebpf:"swiss_maps_used"`
 	TotalCpus              *ebpf.VariableSpec `ebpf:"total_cpus"`
 	WriteStatusSupported   *ebpf.VariableSpec `ebpf:"write_status_supported"`
 }
@@ -140,27 +242,57 @@ func (o *bpfObjects) Close() error {
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfMaps struct {
-	AllocMap               *ebpf.Map `ebpf:"alloc_map"`
-	Events                 *ebpf.Map `ebpf:"events"`
-	GoContextToSc          *ebpf.Map `ebpf:"go_context_to_sc"`
-	GrpcEvents             *ebpf.Map `ebpf:"grpc_events"`
-	ProbeActiveSamplerMap  *ebpf.Map `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap      *ebpf.Map `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap      *ebpf.Map `ebpf:"slice_array_buff_map"`
-	StreamidToSpanContexts *ebpf.Map `ebpf:"streamid_to_span_contexts"`
-	TrackedSpansBySc       *ebpf.Map `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.Map `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.Map `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.Map `ebpf:"events"`
+	GoContextToSc                  *ebpf.Map `ebpf:"go_context_to_sc"`
+	GoroutineToSc                  *ebpf.Map `ebpf:"goroutine_to_sc"`
+	GrpcEvents                     *ebpf.Map `ebpf:"grpc_events"`
+	GrpcNewStreams                 *ebpf.Map `ebpf:"grpc_new_streams"`
+	GrpcParsers                    *ebpf.Map `ebpf:"grpc_parsers"`
+	GrpcResponseMetadata           *ebpf.Map `ebpf:"grpc_response_metadata"`
+	GrpcStorageMap                 *ebpf.Map `ebpf:"grpc_storage_map"`
+	GrpcStreamCalls                *ebpf.Map `ebpf:"grpc_stream_calls"`
+	GrpcStreams                    *ebpf.Map `ebpf:"grpc_streams"`
+	HeaderCaptureMap               *ebpf.Map `ebpf:"header_capture_map"`
+	ProbeActiveSamplerMap          *ebpf.Map `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.Map `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.Map `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.Map `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.Map `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.Map `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.Map `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.Map `ebpf:"span_state_storage_map"`
+	StreamidToSpanContexts         *ebpf.Map `ebpf:"streamid_to_span_contexts"`
+	TrackedSpanStatesBySc          *ebpf.Map `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.Map `ebpf:"tracked_spans_by_sc"`
 }
 
 func (m *bpfMaps) Close() error {
 	return _BpfClose(
 		m.AllocMap,
+		m.DroppedSpansMap,
 		m.Events,
 		m.GoContextToSc,
+		m.GoroutineToSc,
 		m.GrpcEvents,
+		m.GrpcNewStreams,
+		m.GrpcParsers,
+		m.GrpcResponseMetadata,
+		m.GrpcStorageMap,
+		m.GrpcStreamCalls,
+		m.GrpcStreams,
+		m.HeaderCaptureMap,
 		m.ProbeActiveSamplerMap,
+		m.PropagationExtractorStorageMap,
+		m.PropagationHeaderStorageMap,
+		m.RateLimiterMap,
 		m.SamplersConfigMap,
 		m.SliceArrayBuffMap,
+		m.SpanOutputConfigMap,
+		m.SpanStateStorageMap,
 		m.StreamidToSpanContexts,
+		m.TrackedSpanStatesBySc,
 		m.TrackedSpansBySc,
 	)
 }
@@ -169,17 +301,25 @@ func (m *bpfMaps) Close() error {
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfVariables struct {
+	BucketsPtrPos          *ebpf.Variable `ebpf:"buckets_ptr_pos"`
 	ClientconnTargetPtrPos *ebpf.Variable `ebpf:"clientconn_target_ptr_pos"`
 	EndAddr                *ebpf.Variable `ebpf:"end_addr"`
 	ErrorStatusPos         *ebpf.Variable `ebpf:"error_status_pos"`
+	FrameFieldsPos         *ebpf.Variable `ebpf:"frame_fields_pos"`
+	FrameStreamIdPos       *ebpf.Variable `ebpf:"frame_stream_id_pos"`
 	HeaderFrameHfPos       *ebpf.Variable `ebpf:"headerFrame_hf_pos"`
 	HeaderFrameStreamidPos *ebpf.Variable `ebpf:"headerFrame_streamid_pos"`
+	HeaderFrameItab        *ebpf.Variable `ebpf:"header_frame_itab"`
 	Hex                    *ebpf.Variable `ebpf:"hex"`
 	HttpclientNextidPos    *ebpf.Variable `ebpf:"httpclient_nextid_pos"`
+	ParserHeaderPos        *ebpf.Variable `ebpf:"parser_header_pos"`
+	Propagators            *ebpf.Variable `ebpf:"propagators"`
 	StartAddr              *ebpf.Variable `ebpf:"start_addr"`
 	StatusCodePos          *ebpf.Variable `ebpf:"status_code_pos"`
+	StatusErrorItab        *ebpf.Variable `ebpf:"status_error_itab"`
 	StatusMessagePos       *ebpf.Variable `ebpf:"status_message_pos"`
 	StatusS_pos            *ebpf.Variable `ebpf:"status_s_pos"`
+	SwissMapsUsed          *ebpf.Variable `ebpf:"swiss_maps_used"`
 	TotalCpus              *ebpf.Variable `ebpf:"total_cpus"`
 	WriteStatusSupported   *ebpf.Variable `ebpf:"write_status_supported"`
 }
@@ -188,18 +328,40 @@ type bpfVariables struct {
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfPrograms struct {
-	UprobeClientConnInvoke         *ebpf.Program `ebpf:"uprobe_ClientConn_Invoke"`
-	UprobeClientConnInvokeReturns  *ebpf.Program `ebpf:"uprobe_ClientConn_Invoke_Returns"`
-	UprobeLoopyWriterHeaderHandler *ebpf.Program `ebpf:"uprobe_LoopyWriter_HeaderHandler"`
-	UprobeHttp2ClientNewStream     *ebpf.Program `ebpf:"uprobe_http2Client_NewStream"`
+	UprobeClientConnInvoke           *ebpf.Program `ebpf:"uprobe_ClientConn_Invoke"`
+	UprobeClientConnInvokeReturns    *ebpf.Program `ebpf:"uprobe_ClientConn_Invoke_Returns"`
+	UprobeClientConnNewStream        *ebpf.Program `ebpf:"uprobe_ClientConn_NewStream"`
+	UprobeClientConnNewStreamReturns *ebpf.Program `ebpf:"uprobe_ClientConn_NewStream_Returns"`
+	UprobeLoopyWriterHeaderHandler   *ebpf.Program `ebpf:"uprobe_LoopyWriter_HeaderHandler"`
+	UprobeClientStreamFinish         *ebpf.Program `ebpf:"uprobe_clientStream_finish"`
+	UprobeClientStreamMsg            *ebpf.Program `ebpf:"uprobe_clientStream_msg"`
+	UprobeClientStreamMsgReturns     *ebpf.Program `ebpf:"uprobe_clientStream_msg_Returns"`
+	UprobeControlBufferExecuteAndPut *ebpf.Program `ebpf:"uprobe_controlBuffer_executeAndPut"`
+	UprobeHttp2ClientNewStream       *ebpf.Program `ebpf:"uprobe_http2Client_NewStream"`
+	UprobeHttp2ClientOperateHeaders  *ebpf.Program `ebpf:"uprobe_http2Client_operateHeaders"`
+	UprobeNewClientStreamReturns     *ebpf.Program `ebpf:"uprobe_newClientStream_Returns"`
+	UprobeParserRecvMsg              *ebpf.Program `ebpf:"uprobe_parser_recvMsg"`
+	UprobeParserRecvMsgReturns       *ebpf.Program `ebpf:"uprobe_parser_recvMsg_Returns"`
+	UprobePrepareMsgReturns          *ebpf.Program `ebpf:"uprobe_prepareMsg_Returns"`
 }
 
 func (p *bpfPrograms) Close() error {
 	return _BpfClose(
 		p.UprobeClientConnInvoke,
 		p.UprobeClientConnInvokeReturns,
+		p.UprobeClientConnNewStream,
+		p.UprobeClientConnNewStreamReturns,
 		p.UprobeLoopyWriterHeaderHandler,
+		p.UprobeClientStreamFinish,
+		p.UprobeClientStreamMsg,
+		p.UprobeClientStreamMsgReturns,
+		p.UprobeControlBufferExecuteAndPut,
 		p.UprobeHttp2ClientNewStream,
+		p.UprobeHttp2ClientOperateHeaders,
+		p.UprobeNewClientStreamReturns,
+		p.UprobeParserRecvMsg,
+		p.UprobeParserRecvMsgReturns,
+		p.UprobePrepareMsgReturns,
 	)
 }
 
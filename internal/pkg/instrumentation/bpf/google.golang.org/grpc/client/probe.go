@@ -11,6 +11,7 @@ import (
 	"log/slog"
 	"net"
 	"strconv"
+	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"github.com/cilium/ebpf"
@@ -67,6 +68,7 @@ func New(logger *slog.Logger, version string) probe.Probe {
 			Logger: logger,
 			Consts: []probe.Const{
 				probe.AllocationConst{},
+				probe.PropagatorsConst{},
 				writeStatusConst{},
 				probe.StructFieldConst{
 					Key: "clientconn_target_ptr_pos",
@@ -152,6 +154,43 @@ func New(logger *slog.Logger, version string) probe.Probe {
 					},
 					MinVersion: writeStatusMinVersion,
 				},
+				probe.ItabConst{
+					Key:       "status_error_itab",
+					Type:      "*google.golang.org/grpc/internal/status.Error",
+					Interface: "error",
+				},
+				probe.ItabConst{
+					Key:       "header_frame_itab",
+					Type:      "*google.golang.org/grpc/internal/transport.headerFrame",
+					Interface: "google.golang.org/grpc/internal/transport.cbItem",
+				},
+				probe.StructFieldConst{
+					Key: "frame_fields_pos",
+					ID: structfield.NewID(
+						"golang.org/x/net",
+						"golang.org/x/net/http2",
+						"MetaHeadersFrame",
+						"Fields",
+					),
+				},
+				probe.StructFieldConst{
+					Key: "frame_stream_id_pos",
+					ID: structfield.NewID(
+						"golang.org/x/net",
+						"golang.org/x/net/http2",
+						"FrameHeader",
+						"StreamID",
+					),
+				},
+				probe.StructFieldConst{
+					Key: "parser_header_pos",
+					ID: structfield.NewID(
+						"google.golang.org/grpc",
+						"google.golang.org/grpc",
+						"parser",
+						"header",
+					),
+				},
 			},
 			Uprobes: []*probe.Uprobe{
 				{
@@ -159,6 +198,47 @@ func New(logger *slog.Logger, version string) probe.Probe {
 					EntryProbe:  "uprobe_ClientConn_Invoke",
 					ReturnProbe: "uprobe_ClientConn_Invoke_Returns",
 				},
+				{
+					Sym:         "google.golang.org/grpc.(*ClientConn).NewStream",
+					EntryProbe:  "uprobe_ClientConn_NewStream",
+					ReturnProbe: "uprobe_ClientConn_NewStream_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "google.golang.org/grpc.newClientStream",
+					ReturnProbe: "uprobe_newClientStream_Returns",
+					FailureMode: probe.FailureModeWarn,
+					DependsOn:   []string{"google.golang.org/grpc.(*ClientConn).NewStream"},
+				},
+				{
+					Sym:         "google.golang.org/grpc.(*clientStream).finish",
+					EntryProbe:  "uprobe_clientStream_finish",
+					FailureMode: probe.FailureModeWarn,
+					DependsOn:   []string{"google.golang.org/grpc.(*ClientConn).NewStream"},
+				},
+				{
+					Sym:         "google.golang.org/grpc.(*clientStream).SendMsg",
+					EntryProbe:  "uprobe_clientStream_msg",
+					ReturnProbe: "uprobe_clientStream_msg_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "google.golang.org/grpc.(*clientStream).RecvMsg",
+					EntryProbe:  "uprobe_clientStream_msg",
+					ReturnProbe: "uprobe_clientStream_msg_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "google.golang.org/grpc.prepareMsg",
+					ReturnProbe: "uprobe_prepareMsg_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "google.golang.org/grpc.(*parser).recvMsg",
+					EntryProbe:  "uprobe_parser_recvMsg",
+					ReturnProbe: "uprobe_parser_recvMsg_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
 				{
 					Sym:        "google.golang.org/grpc/internal/transport.(*http2Client).NewStream",
 					EntryProbe: "uprobe_http2Client_NewStream",
@@ -167,6 +247,16 @@ func New(logger *slog.Logger, version string) probe.Probe {
 					Sym:        "google.golang.org/grpc/internal/transport.(*loopyWriter).headerHandler",
 					EntryProbe: "uprobe_LoopyWriter_HeaderHandler",
 				},
+				{
+					Sym:         "google.golang.org/grpc/internal/transport.(*controlBuffer).executeAndPut",
+					EntryProbe:  "uprobe_controlBuffer_executeAndPut",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "google.golang.org/grpc/internal/transport.(*http2Client).operateHeaders",
+					EntryProbe:  "uprobe_http2Client_operateHeaders",
+					FailureMode: probe.FailureModeWarn,
+				},
 			},
 			SpecFn: verifyAndLoadBpf,
 		},
@@ -189,24 +279,51 @@ func verifyAndLoadBpf() (*ebpf.CollectionSpec, error) {
 // event represents an event in the gRPC client during a gRPC request.
 type event struct {
 	context.BaseSpanProperties
-	ErrMsg     [128]byte
-	Method     [50]byte
-	Target     [50]byte
-	StatusCode int32
+	ErrMsg       [128]byte
+	Method       [50]byte
+	Target       [50]byte
+	StatusCode   int32
+	Stream       streamKey
+	Messages     probe.RPCMessages
+	ReqMetadata  probe.CapturedHeaders
+	RespMetadata probe.CapturedHeaders
 }
 
-func processFn(e *event) ptrace.SpanSlice {
-	method := unix.ByteSliceToString(e.Method[:])
-	address := unix.ByteSliceToString(e.Target[:])
+// streamKey identifies the HTTP/2 stream of an RPC in eBPF.
+type streamKey struct {
+	Transport uint64
+	StreamID  uint32
+	_         [4]byte
+}
 
-	var port int
-	host, portStr, err := net.SplitHostPort(address)
-	if err == nil {
-		port, _ = strconv.Atoi(portStr)
+// parseTarget returns the host and port of the server of the ClientConn
+// target, see https://github.com/grpc/grpc/blob/master/doc/naming.md. The
+// port is 0 if the target has none.
+func parseTarget(target string) (string, int) {
+	if scheme, path, ok := strings.Cut(target, ":"); ok &&
+		(scheme == "unix" || scheme == "unix-abstract") {
+		return strings.TrimPrefix(path, "//"), 0
+	}
+
+	if _, rest, ok := strings.Cut(target, "://"); ok {
+		// The endpoint follows the optional authority.
+		_, target, _ = strings.Cut(rest, "/")
 	} else {
-		host = address
+		target = strings.TrimPrefix(target, "dns:")
 	}
 
+	host, portStr, err := net.SplitHostPort(target)
+	if err != nil {
+		return target, 0
+	}
+	port, _ := strconv.Atoi(portStr)
+	return host, port
+}
+
+func processFn(e *event) ptrace.SpanSlice {
+	method := unix.ByteSliceToString(e.Method[:])
+	host, port := parseTarget(unix.ByteSliceToString(e.Target[:]))
+
 	attrs := []attribute.KeyValue{
 		semconv.RPCSystemKey.String("grpc"),
 		semconv.RPCServiceKey.String(method),
@@ -235,8 +352,12 @@ func processFn(e *event) ptrace.SpanSlice {
 	if e.ParentSpanContext.SpanID.IsValid() {
 		span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
 	}
+	span.TraceState().FromRaw(e.State.TraceState())
 
 	pdataconv.Attributes(span.Attributes(), attrs...)
+	e.ReqMetadata.PutAttributes(span.Attributes(), probe.GRPCRequestMetadataPrefix)
+	e.RespMetadata.PutAttributes(span.Attributes(), probe.GRPCResponseMetadataPrefix)
+	e.Messages.AddSpanEvents(span)
 
 	if writeStatus && e.StatusCode > 0 {
 		span.Status().SetCode(ptrace.StatusCodeError)
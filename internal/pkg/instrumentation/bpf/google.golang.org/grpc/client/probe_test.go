@@ -0,0 +1,35 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package grpc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		target string
+		host   string
+		port   int
+	}{
+		{target: "localhost:50051", host: "localhost", port: 50051},
+		{target: "dns:///localhost:50051", host: "localhost", port: 50051},
+		{target: "dns://8.8.8.8/example.com:443", host: "example.com", port: 443},
+		{target: "dns:example.com:443", host: "example.com", port: 443},
+		{target: "passthrough:///[::1]:8080", host: "::1", port: 8080},
+		{target: "unix:///tmp/grpc.sock", host: "/tmp/grpc.sock", port: 0},
+		{target: "unix:grpc.sock", host: "grpc.sock", port: 0},
+		{target: "xds:///svc", host: "svc", port: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			host, port := parseTarget(tt.target)
+			assert.Equal(t, tt.host, host)
+			assert.Equal(t, tt.port, port)
+		})
+	}
+}
@@ -4,10 +4,13 @@
 #include "arguments.h"
 #include "go_types.h"
 #include "go_net.h"
+#include "go_headers.h"
 #include "trace/span_context.h"
 #include "go_context.h"
+#include "rpc_messages.h"
 #include "uprobe.h"
 #include "trace/start_span.h"
+#include "trace/propagation.h"
 
 char __license[] SEC("license") = "Dual MIT/GPL";
 
@@ -15,13 +18,20 @@ char __license[] SEC("license") = "Dual MIT/GPL";
 #define MAX_CONCURRENT 50
 #define MAX_HEADERS 20
 #define MAX_HEADER_STRING 50
+#define MAX_ERROR_LEN 128
 
 struct grpc_request_t {
     BASE_SPAN_PROPERTIES
     char method[MAX_SIZE];
+    char err_msg[MAX_ERROR_LEN];
     u32 status_code;
+    u32 stream_id;
     net_addr_t local_addr;
+    net_addr_t peer_addr;
     u8 has_status;
+    struct rpc_messages messages;
+    struct captured_headers request_metadata;
+    struct captured_headers response_metadata;
 };
 
 struct {
@@ -45,11 +55,21 @@ struct {
     __uint(max_entries, 1);
 } grpc_storage_map SEC(".maps");
 
-struct hpack_header_field {
-    struct go_string name;
-    struct go_string value;
-    bool sensitive;
-};
+// The *serverStream sending or receiving a message, by goroutine.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, void *);
+    __uint(max_entries, MAX_CONCURRENT);
+} grpc_stream_calls SEC(".maps");
+
+// The *parser receiving a message, by goroutine.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, void *);
+    __uint(max_entries, MAX_CONCURRENT);
+} grpc_parsers SEC(".maps");
 
 // Injected in init
 volatile const u64 stream_method_ptr_pos;
@@ -61,13 +81,27 @@ volatile const u64 server_stream_stream_pos;
 volatile const bool is_new_frame_pos;
 volatile const u64 status_s_pos;
 volatile const u64 status_code_pos;
+volatile const u64 status_message_pos;
 volatile const u64 http2server_peer_pos;
 volatile const u64 peer_local_addr_pos;
+volatile const u64 peer_addr_pos;
+volatile const u64 headerFrame_streamid_pos;
+volatile const u64 headerFrame_hf_pos;
+volatile const u64 parser_header_pos;
+// The address of the itab of *transport.headerFrame implementing
+// transport.cbItem, 0 if unknown.
+volatile const u64 header_frame_itab;
 
 volatile const bool server_addr_supported;
 
-static __always_inline long
-dummy_extract_span_context_from_headers(void *stream_id, struct span_context *parent_span_context) {
+// The parent span context and its state are extracted from the headers by
+// the operateHeader probe, the stream may also be tracked for its request
+// metadata only.
+static __always_inline long extracted_span_context_from_headers(
+    void *stream_id, struct span_context *parent_span_context, struct span_state *state) {
+    if (bpf_is_zero(parent_span_context->TraceID, sizeof(parent_span_context->TraceID))) {
+        return -1;
+    }
     return 0;
 }
 
@@ -116,18 +150,26 @@ handleStream(struct pt_regs *ctx, void *stream_ptr, struct go_iface *go_context)
             bpf_printk("grpc:server:handleStream: failed to get grpcReq");
             return 0;
         }
+        __builtin_memset(grpcReq, 0, sizeof(*grpcReq));
     }
 
     grpcReq->start_time = bpf_ktime_get_ns();
+    grpcReq->stream_id = stream_id;
+
+    sampling_attributes_t sampling_attrs = {0};
+    sampling_attrs_set_go_value(
+        &sampling_attrs, SAMPLING_ATTR_RPC_METHOD, stream_ptr + stream_method_ptr_pos);
 
     start_span_params_t start_span_params = {
         .ctx = ctx,
         .sc = &grpcReq->sc,
         .psc = &grpcReq->psc,
+        .state = &grpcReq->state,
         .go_context = go_context,
         // The parent span context is set by operateHeader probe
-        .get_parent_span_context_fn = dummy_extract_span_context_from_headers,
+        .get_parent_span_context_fn = extracted_span_context_from_headers,
         .get_parent_span_context_arg = NULL,
+        .sampling_attrs = &sampling_attrs,
     };
     start_span(&start_span_params);
 
@@ -149,6 +191,12 @@ handleStream(struct pt_regs *ctx, void *stream_ptr, struct go_iface *go_context)
             bpf_probe_read_user(
                 &local_addr_ptr, sizeof(local_addr_ptr), get_go_interface_instance(local_addr_pos));
             get_tcp_net_addr_from_tcp_addr(ctx, &grpcReq->local_addr, (void *)(local_addr_ptr));
+
+            void *peer_addr_ptr = 0;
+            void *peer_addr_field = http2server + http2server_peer_pos + peer_addr_pos;
+            bpf_probe_read_user(
+                &peer_addr_ptr, sizeof(peer_addr_ptr), get_go_interface_instance(peer_addr_field));
+            get_tcp_net_addr_from_tcp_addr(ctx, &grpcReq->peer_addr, (void *)(peer_addr_ptr));
         } else {
             bpf_printk("grpc:server:handleStream: failed to get http2server arg");
         }
@@ -158,9 +206,12 @@ handleStream(struct pt_regs *ctx, void *stream_ptr, struct go_iface *go_context)
     rc = bpf_map_update_elem(&grpc_events, &key, grpcReq, 0);
     if (rc != 0) {
         bpf_printk("grpc:server:handleStream: failed to update event");
+        bpf_map_delete_elem(&streamid_to_grpc_events, &stream_id);
         return -4;
     }
-    start_tracking_span(go_context->data, &grpcReq->sc);
+    start_tracking_span(go_context->data, &grpcReq->sc, &grpcReq->state);
+    start_tracking_goroutine_span(key, &grpcReq->sc);
+    bpf_map_delete_elem(&streamid_to_grpc_events, &stream_id);
 
     return 0;
 }
@@ -200,6 +251,8 @@ static __always_inline int writeStatus(struct pt_regs *ctx, void *status_ptr) {
         bpf_printk("grpc:server:handleStream: failed to read status code");
         return -4;
     }
+    get_go_string_from_user_ptr(
+        (void *)(s_ptr + status_message_pos), req_ptr->err_msg, sizeof(req_ptr->err_msg));
     req_ptr->has_status = true;
 
     return 0;
@@ -295,8 +348,10 @@ lookup:
         return -5;
     }
     event->end_time = bpf_ktime_get_ns();
-    output_span_event(ctx, event, sizeof(struct grpc_request_t), &event->sc);
+    output_span_event(
+        ctx, event, sizeof(struct grpc_request_t), &event->sc, event->start_time, event->end_time);
     stop_tracking_span(&event->sc, &event->psc);
+    stop_tracking_goroutine_span(key, &event->sc);
     bpf_map_delete_elem(&grpc_events, &key);
     return 0;
 }
@@ -346,34 +401,45 @@ int uprobe_http2Server_operateHeader(struct pt_regs *ctx) {
     void *frame_ptr = is_new_frame_pos ? arg4 : arg2;
     struct go_slice header_fields = {};
     bpf_probe_read(&header_fields, sizeof(header_fields), (void *)(frame_ptr + frame_fields_pos));
-    char key[W3C_KEY_LENGTH] = "traceparent";
+    struct propagation_extractor *ex = new_propagation_extractor();
+    if (ex == NULL) {
+        return 0;
+    }
+    // The request is too large for the stack.
+    u32 zero = 0;
+    struct grpc_request_t *grpcReq = bpf_map_lookup_elem(&grpc_storage_map, &zero);
+    if (grpcReq == NULL) {
+        return 0;
+    }
+    __builtin_memset(grpcReq, 0, sizeof(*grpcReq));
+
+    bool capture_metadata = header_capture_enabled(HEADER_CAPTURE_REQUEST);
     for (s32 i = 0; i < MAX_HEADERS; i++) {
         if (i >= header_fields.len) {
             break;
         }
+        void *hf_ptr = (void *)(header_fields.array + (i * sizeof(struct hpack_header_field)));
         struct hpack_header_field hf = {};
-        long res =
-            bpf_probe_read(&hf, sizeof(hf), (void *)(header_fields.array + (i * sizeof(hf))));
-        if (hf.name.len == W3C_KEY_LENGTH && hf.value.len == W3C_VAL_LENGTH) {
-            char current_key[W3C_KEY_LENGTH];
-            bpf_probe_read(current_key, sizeof(current_key), hf.name.str);
-            if (bpf_memcmp(key, current_key, sizeof(key))) {
-                char val[W3C_VAL_LENGTH];
-                bpf_probe_read(val, W3C_VAL_LENGTH, hf.value.str);
-
-                // Get stream id
-                void *headers_frame = NULL;
-                bpf_probe_read(&headers_frame, sizeof(headers_frame), frame_ptr);
-                u32 stream_id = 0;
-                bpf_probe_read(
-                    &stream_id, sizeof(stream_id), (void *)(headers_frame + frame_stream_id_pod));
-                struct grpc_request_t grpcReq = {};
-                w3c_string_to_span_context(val, &grpcReq.psc);
-                bpf_map_update_elem(&streamid_to_grpc_events, &stream_id, &grpcReq, 0);
-            }
+        long res = bpf_probe_read(&hf, sizeof(hf), hf_ptr);
+        if (res < 0) {
+            continue;
+        }
+        propagation_extract_header(ex, hf.name.str, hf.name.len, hf.value.str, hf.value.len);
+        if (capture_metadata) {
+            capture_header_field(hf_ptr, HEADER_CAPTURE_REQUEST, &grpcReq->request_metadata);
         }
     }
 
+    if (propagation_extract_finish(ex, &grpcReq->psc, &grpcReq->state) == 0 ||
+        grpcReq->request_metadata.count > 0) {
+        // Get stream id
+        void *headers_frame = NULL;
+        bpf_probe_read(&headers_frame, sizeof(headers_frame), frame_ptr);
+        u32 stream_id = 0;
+        bpf_probe_read(&stream_id, sizeof(stream_id), (void *)(headers_frame + frame_stream_id_pod));
+        bpf_map_update_elem(&streamid_to_grpc_events, &stream_id, grpcReq, 0);
+    }
+
     return 0;
 }
 
@@ -411,3 +477,115 @@ int uprobe_http2Server_WriteStatus2(struct pt_regs *ctx) {
     void *status_ptr = get_argument(ctx, 3);
     return writeStatus(ctx, status_ptr);
 }
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *controlBuffer) executeAndPut(f func() bool, it cbItem) (bool, error)
+// The *headerFrame of the response headers and trailers of a stream are put by
+// the goroutine handling it.
+SEC("uprobe/controlBuffer_executeAndPut")
+int uprobe_controlBuffer_executeAndPut(struct pt_regs *ctx) {
+    void *it_itab = get_argument(ctx, 3);
+    if (header_frame_itab == 0 || (u64)it_itab != header_frame_itab) {
+        return 0;
+    }
+    void *key = (void *)GOROUTINE(ctx);
+    struct grpc_request_t *grpcReq = bpf_map_lookup_elem(&grpc_events, &key);
+    if (grpcReq == NULL) {
+        return 0;
+    }
+
+    // The headers of the streams of the clients used by the handler are put
+    // by the same goroutine.
+    void *header_frame_ptr = get_argument(ctx, 4);
+    u32 stream_id = 0;
+    bpf_probe_read_user(
+        &stream_id, sizeof(stream_id), (void *)(header_frame_ptr + headerFrame_streamid_pos));
+    if (stream_id != grpcReq->stream_id) {
+        return 0;
+    }
+    capture_header_fields(
+        header_frame_ptr + headerFrame_hf_pos, HEADER_CAPTURE_RESPONSE, &grpcReq->response_metadata);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following functions:
+// func (ss *serverStream) SendMsg(m any) (err error)
+// func (ss *serverStream) RecvMsg(m any) (err error)
+SEC("uprobe/serverStream_msg")
+int uprobe_serverStream_msg(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    void *stream_ptr = get_argument(ctx, 1);
+    bpf_map_update_elem(&grpc_stream_calls, &key, &stream_ptr, 0);
+    return 0;
+}
+
+// This instrumentation attaches uretprobe to the following functions:
+// func (ss *serverStream) SendMsg(m any) (err error)
+// func (ss *serverStream) RecvMsg(m any) (err error)
+SEC("uprobe/serverStream_msg")
+int uprobe_serverStream_msg_Returns(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    bpf_map_delete_elem(&grpc_stream_calls, &key);
+    return 0;
+}
+
+// Returns the event of the stream sending or receiving a message on the
+// goroutine. The messages of a stream are sent and received by the goroutine
+// handling it, in SendMsg and RecvMsg. The messages of the client RPCs made by
+// the handler are not.
+static __always_inline struct grpc_request_t *lookup_message_event(void *goroutine) {
+    if (bpf_map_lookup_elem(&grpc_stream_calls, &goroutine) == NULL) {
+        return NULL;
+    }
+    return bpf_map_lookup_elem(&grpc_events, &goroutine);
+}
+
+// This instrumentation attaches uretprobe to the following function:
+// func prepareMsg(m any, codec baseCodec, cp Compressor, comp encoding.Compressor, ...) (hdr []byte, ...)
+SEC("uprobe/prepareMsg")
+int uprobe_prepareMsg_Returns(struct pt_regs *ctx) {
+    u64 now = bpf_ktime_get_ns();
+    struct grpc_request_t *event = lookup_message_event((void *)GOROUTINE(ctx));
+    if (event == NULL) {
+        return 0;
+    }
+    // hdr is nil if the message could not be prepared.
+    record_rpc_message(&event->messages, true, get_argument(ctx, 1), now);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (p *parser) recvMsg(maxReceiveMessageSize int) (pf payloadFormat, msg []byte, err error)
+SEC("uprobe/parser_recvMsg")
+int uprobe_parser_recvMsg(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    void *parser_ptr = get_argument(ctx, 1);
+    bpf_map_update_elem(&grpc_parsers, &key, &parser_ptr, 0);
+    return 0;
+}
+
+// This instrumentation attaches uretprobe to the following function:
+// func (p *parser) recvMsg(maxReceiveMessageSize int) (pf payloadFormat, msg []byte, err error)
+SEC("uprobe/parser_recvMsg")
+int uprobe_parser_recvMsg_Returns(struct pt_regs *ctx) {
+    u64 now = bpf_ktime_get_ns();
+    void *key = (void *)GOROUTINE(ctx);
+    void **parser_ptr = bpf_map_lookup_elem(&grpc_parsers, &key);
+    if (parser_ptr == NULL) {
+        return 0;
+    }
+    void *parser = *parser_ptr;
+    bpf_map_delete_elem(&grpc_parsers, &key);
+
+    // No message is received if err is not nil.
+    void *err_itab = get_argument(ctx, 5);
+    if (err_itab != NULL) {
+        return 0;
+    }
+    struct grpc_request_t *event = lookup_message_event(key);
+    if (event == NULL) {
+        return 0;
+    }
+    record_rpc_message(&event->messages, false, parser + parser_header_pos, now);
+    return 0;
+}
@@ -19,15 +19,68 @@ type bpfGrpcRequestT struct {
 	EndTime    uint64
 	Sc         bpfSpanContext
 	Psc        bpfSpanContext
+	State      bpfSpanState
 	Method     [100]int8
+	ErrMsg     [128]int8
 	StatusCode uint32
+	StreamId   uint32
 	LocalAddr  struct {
 		_    structs.HostLayout
 		Ip   [16]uint8
 		Port uint32
 	}
+	PeerAddr struct {
+		_    structs.HostLayout
+		Ip   [16]uint8
+		Port uint32
+	}
 	HasStatus uint8
 	_         [3]byte
+	Messages  struct {
+		_        structs.HostLayout
+		Sent     uint32
+		Received uint32
+		Events   [8]struct {
+			_          structs.HostLayout
+			Time       uint64
+			Id         uint32
+			Size       uint32
+			Sent       uint8
+			Compressed uint8
+			Padding    [6]uint8
+		}
+	}
+	RequestMetadata struct {
+		_       structs.HostLayout
+		Count   uint32
+		Padding [4]uint8
+		Headers [8]struct {
+			_        structs.HostLayout
+			Name     [32]int8
+			Value    [128]int8
+			ValueLen uint32
+			Redacted uint8
+			Padding  [3]uint8
+		}
+	}
+	ResponseMetadata struct {
+		_       structs.HostLayout
+		Count   uint32
+		Padding [4]uint8
+		Headers [8]struct {
+			_        structs.HostLayout
+			Name     [32]int8
+			Value    [128]int8
+			ValueLen uint32
+			Redacted uint8
+			Padding  [3]uint8
+		}
+	}
+}
+
+type bpfHeaderCaptureKey struct {
+	_    structs.HostLayout
+	Name [32]int8
 }
 
 type bpfSliceArrayBuff struct {
@@ -75,6 +128,30 @@ func loadBpfObjects(obj interface{}, opts *ebpf.CollectionOptions) error {
 // bpfSpecs contains maps and programs before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
+type bpfSpanState struct {
+	_   structs.HostLayout
+	Ots struct {
+		_       structs.HostLayout
+		Th      uint64
+		Rv      uint64
+		HasTh   uint8
+		HasRv   uint8
+		Padding [6]uint8
+	}
+	Ts struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Entries [128]int8
+	}
+	Bg struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Value   [128]int8
+	}
+}
+
 type bpfSpecs struct {
 	bpfProgramSpecs
 	bpfMapSpecs
@@ -85,9 +162,15 @@ type bpfSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfProgramSpecs struct {
+	UprobeControlBufferExecuteAndPut *ebpf.ProgramSpec `ebpf:"uprobe_controlBuffer_executeAndPut"`
 	UprobeHttp2ServerWriteStatus     *ebpf.ProgramSpec `ebpf:"uprobe_http2Server_WriteStatus"`
 	UprobeHttp2ServerWriteStatus2    *ebpf.ProgramSpec `ebpf:"uprobe_http2Server_WriteStatus2"`
 	UprobeHttp2ServerOperateHeader   *ebpf.ProgramSpec `ebpf:"uprobe_http2Server_operateHeader"`
+	UprobeParserRecvMsg              *ebpf.ProgramSpec `ebpf:"uprobe_parser_recvMsg"`
+	UprobeParserRecvMsgReturns       *ebpf.ProgramSpec `ebpf:"uprobe_parser_recvMsg_Returns"`
+	UprobePrepareMsgReturns          *ebpf.ProgramSpec `ebpf:"uprobe_prepareMsg_Returns"`
+	UprobeServerStreamMsg            *ebpf.ProgramSpec `ebpf:"uprobe_serverStream_msg"`
+	UprobeServerStreamMsgReturns     *ebpf.ProgramSpec `ebpf:"uprobe_serverStream_msg_Returns"`
 	UprobeServerHandleStream         *ebpf.ProgramSpec `ebpf:"uprobe_server_handleStream"`
 	UprobeServerHandleStream2        *ebpf.ProgramSpec `ebpf:"uprobe_server_handleStream2"`
 	UprobeServerHandleStream2Returns *ebpf.ProgramSpec `ebpf:"uprobe_server_handleStream2_Returns"`
@@ -99,40 +182,60 @@ type bpfProgramSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfMapSpecs struct {
-	AllocMap              *ebpf.MapSpec `ebpf:"alloc_map"`
-	Events                *ebpf.MapSpec `ebpf:"events"`
-	GoContextToSc         *ebpf.MapSpec `ebpf:"go_context_to_sc"`
-	GrpcEvents            *ebpf.MapSpec `ebpf:"grpc_events"`
-	GrpcStorageMap        *ebpf.MapSpec `ebpf:"grpc_storage_map"`
-	ProbeActiveSamplerMap *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap     *ebpf.MapSpec `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap     *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
-	StreamidToGrpcEvents  *ebpf.MapSpec `ebpf:"streamid_to_grpc_events"`
-	TrackedSpansBySc      *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.MapSpec `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.MapSpec `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.MapSpec `ebpf:"events"`
+	GoContextToSc                  *ebpf.MapSpec `ebpf:"go_context_to_sc"`
+	GoroutineToSc                  *ebpf.MapSpec `ebpf:"goroutine_to_sc"`
+	GrpcEvents                     *ebpf.MapSpec `ebpf:"grpc_events"`
+	GrpcParsers                    *ebpf.MapSpec `ebpf:"grpc_parsers"`
+	GrpcStorageMap                 *ebpf.MapSpec `ebpf:"grpc_storage_map"`
+	GrpcStreamCalls                *ebpf.MapSpec `ebpf:"grpc_stream_calls"`
+	HeaderCaptureMap               *ebpf.MapSpec `ebpf:"header_capture_map"`
+	ProbeActiveSamplerMap          *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.MapSpec `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.MapSpec `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.MapSpec `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.MapSpec `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.MapSpec `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.MapSpec `ebpf:"span_state_storage_map"`
+	StreamidToGrpcEvents           *ebpf.MapSpec `ebpf:"streamid_to_grpc_events"`
+	TrackedSpanStatesBySc          *ebpf.MapSpec `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
 }
 
 // bpfVariableSpecs contains global variables before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfVariableSpecs struct {
-	TCPAddrIP_offset      *ebpf.VariableSpec `ebpf:"TCPAddr_IP_offset"`
-	TCPAddrPortOffset     *ebpf.VariableSpec `ebpf:"TCPAddr_Port_offset"`
-	EndAddr               *ebpf.VariableSpec `ebpf:"end_addr"`
-	FrameFieldsPos        *ebpf.VariableSpec `ebpf:"frame_fields_pos"`
-	FrameStreamIdPod      *ebpf.VariableSpec `ebpf:"frame_stream_id_pod"`
-	Hex                   *ebpf.VariableSpec `ebpf:"hex"`
-	Http2serverPeerPos    *ebpf.VariableSpec `ebpf:"http2server_peer_pos"`
-	IsNewFramePos         *ebpf.VariableSpec `ebpf:"is_new_frame_pos"`
-	PeerLocalAddrPos      *ebpf.VariableSpec `ebpf:"peer_local_addr_pos"`
-	ServerAddrSupported   *ebpf.VariableSpec `ebpf:"server_addr_supported"`
-	ServerStreamStreamPos *ebpf.VariableSpec `ebpf:"server_stream_stream_pos"`
-	StartAddr             *ebpf.VariableSpec `ebpf:"start_addr"`
-	StatusCodePos         *ebpf.VariableSpec `ebpf:"status_code_pos"`
-	StatusS_pos           *ebpf.VariableSpec `ebpf:"status_s_pos"`
-	StreamCtxPos          *ebpf.VariableSpec `ebpf:"stream_ctx_pos"`
-	StreamIdPos           *ebpf.VariableSpec `ebpf:"stream_id_pos"`
-	StreamMethodPtrPos    *ebpf.VariableSpec `ebpf:"stream_method_ptr_pos"`
-	TotalCpus             *ebpf.VariableSpec `ebpf:"total_cpus"`
+	Propagators            *ebpf.VariableSpec `ebpf:"propagators"`
+	TCPAddrIP_offset       *ebpf.VariableSpec `ebpf:"TCPAddr_IP_offset"`
+	TCPAddrPortOffset      *ebpf.VariableSpec `ebpf:"TCPAddr_Port_offset"`
+	BucketsPtrPos          *ebpf.VariableSpec `ebpf:"buckets_ptr_pos"`
+	EndAddr                *ebpf.VariableSpec `ebpf:"end_addr"`
+	FrameFieldsPos         *ebpf.VariableSpec `ebpf:"frame_fields_pos"`
+	FrameStreamIdPod       *ebpf.VariableSpec `ebpf:"frame_stream_id_pod"`
+	HeaderFrameHfPos       *ebpf.VariableSpec `ebpf:"headerFrame_hf_pos"`
+	HeaderFrameStreamidPos *ebpf.VariableSpec `ebpf:"headerFrame_streamid_pos"`
+	HeaderFrameItab        *ebpf.VariableSpec `ebpf:"header_frame_itab"`
+	Hex                    *ebpf.VariableSpec `ebpf:"hex"`
+	Http2serverPeerPos     *ebpf.VariableSpec `ebpf:"http2server_peer_pos"`
+	IsNewFramePos          *ebpf.VariableSpec `ebpf:"is_new_frame_pos"`
+	ParserHeaderPos        *ebpf.VariableSpec `ebpf:"parser_header_pos"`
+	PeerAddrPos            *ebpf.VariableSpec `ebpf:"peer_addr_pos"`
+	PeerLocalAddrPos       *ebpf.VariableSpec `ebpf:"peer_local_addr_pos"`
+	ServerAddrSupported    *ebpf.VariableSpec `ebpf:"server_addr_supported"`
+	ServerStreamStreamPos  *ebpf.VariableSpec `ebpf:"server_stream_stream_pos"`
+	StartAddr              *ebpf.VariableSpec `ebpf:"start_addr"`
+	StatusCodePos          *ebpf.VariableSpec `ebpf:"status_code_pos"`
+	StatusMessagePos       *ebpf.VariableSpec `ebpf:"status_message_pos"`
+	StatusS_pos            *ebpf.VariableSpec `ebpf:"status_s_pos"`
+	StreamCtxPos           *ebpf.VariableSpec `ebpf:"stream_ctx_pos"`
+	StreamIdPos            *ebpf.VariableSpec `ebpf:"stream_id_pos"`
+	StreamMethodPtrPos     *ebpf.VariableSpec `ebpf:"stream_method_ptr_pos"`
+	SwissMapsUsed          *ebpf.VariableSpec `ebpf:"swiss_maps_used"`
+	TotalCpus              *ebpf.VariableSpec `ebpf:"total_cpus"`
 }
 
 // bpfObjects contains all objects after they have been loaded into the kernel.
@@ -155,29 +258,51 @@ func (o *bpfObjects) Close() error {
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfMaps struct {
-	AllocMap              *ebpf.Map `ebpf:"alloc_map"`
-	Events                *ebpf.Map `ebpf:"events"`
-	GoContextToSc         *ebpf.Map `ebpf:"go_context_to_sc"`
-	GrpcEvents            *ebpf.Map `ebpf:"grpc_events"`
-	GrpcStorageMap        *ebpf.Map `ebpf:"grpc_storage_map"`
-	ProbeActiveSamplerMap *ebpf.Map `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap     *ebpf.Map `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap     *ebpf.Map `ebpf:"slice_array_buff_map"`
-	StreamidToGrpcEvents  *ebpf.Map `ebpf:"streamid_to_grpc_events"`
-	TrackedSpansBySc      *ebpf.Map `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.Map `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.Map `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.Map `ebpf:"events"`
+	GoContextToSc                  *ebpf.Map `ebpf:"go_context_to_sc"`
+	GoroutineToSc                  *ebpf.Map `ebpf:"goroutine_to_sc"`
+	GrpcEvents                     *ebpf.Map `ebpf:"grpc_events"`
+	GrpcParsers                    *ebpf.Map `ebpf:"grpc_parsers"`
+	GrpcStorageMap                 *ebpf.Map `ebpf:"grpc_storage_map"`
+	GrpcStreamCalls                *ebpf.Map `ebpf:"grpc_stream_calls"`
+	HeaderCaptureMap               *ebpf.Map `ebpf:"header_capture_map"`
+	ProbeActiveSamplerMap          *ebpf.Map `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.Map `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.Map `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.Map `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.Map `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.Map `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.Map `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.Map `ebpf:"span_state_storage_map"`
+	StreamidToGrpcEvents           *ebpf.Map `ebpf:"streamid_to_grpc_events"`
+	TrackedSpanStatesBySc          *ebpf.Map `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.Map `ebpf:"tracked_spans_by_sc"`
 }
 
 func (m *bpfMaps) Close() error {
 	return _BpfClose(
 		m.AllocMap,
+		m.DroppedSpansMap,
 		m.Events,
 		m.GoContextToSc,
+		m.GoroutineToSc,
 		m.GrpcEvents,
+		m.GrpcParsers,
 		m.GrpcStorageMap,
+		m.GrpcStreamCalls,
+		m.HeaderCaptureMap,
 		m.ProbeActiveSamplerMap,
+		m.PropagationExtractorStorageMap,
+		m.PropagationHeaderStorageMap,
+		m.RateLimiterMap,
 		m.SamplersConfigMap,
 		m.SliceArrayBuffMap,
+		m.SpanOutputConfigMap,
+		m.SpanStateStorageMap,
 		m.StreamidToGrpcEvents,
+		m.TrackedSpanStatesBySc,
 		m.TrackedSpansBySc,
 	)
 }
@@ -186,33 +311,48 @@ func (m *bpfMaps) Close() error {
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfVariables struct {
-	TCPAddrIP_offset      *ebpf.Variable `ebpf:"TCPAddr_IP_offset"`
-	TCPAddrPortOffset     *ebpf.Variable `ebpf:"TCPAddr_Port_offset"`
-	EndAddr               *ebpf.Variable `ebpf:"end_addr"`
-	FrameFieldsPos        *ebpf.Variable `ebpf:"frame_fields_pos"`
-	FrameStreamIdPod      *ebpf.Variable `ebpf:"frame_stream_id_pod"`
-	Hex                   *ebpf.Variable `ebpf:"hex"`
-	Http2serverPeerPos    *ebpf.Variable `ebpf:"http2server_peer_pos"`
-	IsNewFramePos         *ebpf.Variable `ebpf:"is_new_frame_pos"`
-	PeerLocalAddrPos      *ebpf.Variable `ebpf:"peer_local_addr_pos"`
-	ServerAddrSupported   *ebpf.Variable `ebpf:"server_addr_supported"`
-	ServerStreamStreamPos *ebpf.Variable `ebpf:"server_stream_stream_pos"`
-	StartAddr             *ebpf.Variable `ebpf:"start_addr"`
-	StatusCodePos         *ebpf.Variable `ebpf:"status_code_pos"`
-	StatusS_pos           *ebpf.Variable `ebpf:"status_s_pos"`
-	StreamCtxPos          *ebpf.Variable `ebpf:"stream_ctx_pos"`
-	StreamIdPos           *ebpf.Variable `ebpf:"stream_id_pos"`
-	StreamMethodPtrPos    *ebpf.Variable `ebpf:"stream_method_ptr_pos"`
-	TotalCpus             *ebpf.Variable `ebpf:"total_cpus"`
+	Propagators            *ebpf.Variable `ebpf:"propagators"`
+	TCPAddrIP_offset       *ebpf.Variable `ebpf:"TCPAddr_IP_offset"`
+	TCPAddrPortOffset      *ebpf.Variable `ebpf:"TCPAddr_Port_offset"`
+	BucketsPtrPos          *ebpf.Variable `ebpf:"buckets_ptr_pos"`
+	EndAddr                *ebpf.Variable `ebpf:"end_addr"`
+	FrameFieldsPos         *ebpf.Variable `ebpf:"frame_fields_pos"`
+	FrameStreamIdPod       *ebpf.Variable `ebpf:"frame_stream_id_pod"`
+	HeaderFrameHfPos       *ebpf.Variable `ebpf:"headerFrame_hf_pos"`
+	HeaderFrameStreamidPos *ebpf.Variable `ebpf:"headerFrame_streamid_pos"`
+	HeaderFrameItab        *ebpf.Variable `ebpf:"header_frame_itab"`
+	Hex                    *ebpf.Variable `ebpf:"hex"`
+	Http2serverPeerPos     *ebpf.Variable `ebpf:"http2server_peer_pos"`
+	IsNewFramePos          *ebpf.Variable `ebpf:"is_new_frame_pos"`
+	ParserHeaderPos        *ebpf.Variable `ebpf:"parser_header_pos"`
+	PeerAddrPos            *ebpf.Variable `ebpf:"peer_addr_pos"`
+	PeerLocalAddrPos       *ebpf.Variable `ebpf:"peer_local_addr_pos"`
+	ServerAddrSupported    *ebpf.Variable `ebpf:"server_addr_supported"`
+	ServerStreamStreamPos  *ebpf.Variable `ebpf:"server_stream_stream_pos"`
+	StartAddr              *ebpf.Variable `ebpf:"start_addr"`
+	StatusCodePos          *ebpf.Variable `ebpf:"status_code_pos"`
+	StatusMessagePos       *ebpf.Variable `ebpf:"status_message_pos"`
+	StatusS_pos            *ebpf.Variable `ebpf:"status_s_pos"`
+	StreamCtxPos           *ebpf.Variable `ebpf:"stream_ctx_pos"`
+	StreamIdPos            *ebpf.Variable `ebpf:"stream_id_pos"`
+	StreamMethodPtrPos     *ebpf.Variable `ebpf:"stream_method_ptr_pos"`
+	SwissMapsUsed          *ebpf.Variable `ebpf:"swiss_maps_used"`
+	TotalCpus              *ebpf.Variable `ebpf:"total_cpus"`
 }
 
 // bpfPrograms contains all programs after they have been loaded into the kernel.
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfPrograms struct {
+	UprobeControlBufferExecuteAndPut *ebpf.Program `ebpf:"uprobe_controlBuffer_executeAndPut"`
 	UprobeHttp2ServerWriteStatus     *ebpf.Program `ebpf:"uprobe_http2Server_WriteStatus"`
 	UprobeHttp2ServerWriteStatus2    *ebpf.Program `ebpf:"uprobe_http2Server_WriteStatus2"`
 	UprobeHttp2ServerOperateHeader   *ebpf.Program `ebpf:"uprobe_http2Server_operateHeader"`
+	UprobeParserRecvMsg              *ebpf.Program `ebpf:"uprobe_parser_recvMsg"`
+	UprobeParserRecvMsgReturns       *ebpf.Program `ebpf:"uprobe_parser_recvMsg_Returns"`
+	UprobePrepareMsgReturns          *ebpf.Program `ebpf:"uprobe_prepareMsg_Returns"`
+	UprobeServerStreamMsg            *ebpf.Program `ebpf:"uprobe_serverStream_msg"`
+	UprobeServerStreamMsgReturns     *ebpf.Program `ebpf:"uprobe_serverStream_msg_Returns"`
 	UprobeServerHandleStream         *ebpf.Program `ebpf:"uprobe_server_handleStream"`
 	UprobeServerHandleStream2        *ebpf.Program `ebpf:"uprobe_server_handleStream2"`
 	UprobeServerHandleStream2Returns *ebpf.Program `ebpf:"uprobe_server_handleStream2_Returns"`
@@ -222,9 +362,15 @@ type bpfPrograms struct {
 
 func (p *bpfPrograms) Close() error {
 	return _BpfClose(
+		p.UprobeControlBufferExecuteAndPut,
 		p.UprobeHttp2ServerWriteStatus,
 		p.UprobeHttp2ServerWriteStatus2,
 		p.UprobeHttp2ServerOperateHeader,
+		p.UprobeParserRecvMsg,
+		p.UprobeParserRecvMsgReturns,
+		p.UprobePrepareMsgReturns,
+		p.UprobeServerStreamMsg,
+		p.UprobeServerStreamMsgReturns,
 		p.UprobeServerHandleStream,
 		p.UprobeServerHandleStream2,
 		p.UprobeServerHandleStream2Returns,
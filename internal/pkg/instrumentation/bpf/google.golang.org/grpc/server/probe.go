@@ -59,6 +59,7 @@ func New(logger *slog.Logger, ver string) probe.Probe {
 			Logger: logger,
 			Consts: []probe.Const{
 				probe.AllocationConst{},
+				probe.PropagatorsConst{},
 				serverAddrConst{},
 				probe.StructFieldConst{
 					Key: "stream_method_ptr_pos",
@@ -141,6 +142,18 @@ func New(logger *slog.Logger, ver string) probe.Probe {
 					},
 					MinVersion: writeStatusMinVersion,
 				},
+				probe.StructFieldConstMinVersion{
+					StructField: probe.StructFieldConst{
+						Key: "status_message_pos",
+						ID: structfield.NewID(
+							"google.golang.org/grpc",
+							"google.golang.org/genproto/googleapis/rpc/status",
+							"Status",
+							"Message",
+						),
+					},
+					MinVersion: writeStatusMinVersion,
+				},
 				probe.StructFieldConstMinVersion{
 					StructField: probe.StructFieldConst{
 						Key: "http2server_peer_pos",
@@ -165,6 +178,41 @@ func New(logger *slog.Logger, ver string) probe.Probe {
 					},
 					MinVersion: serverAddrMinVersion,
 				},
+				probe.StructFieldConstMinVersion{
+					StructField: probe.StructFieldConst{
+						Key: "peer_addr_pos",
+						ID: structfield.NewID(
+							"google.golang.org/grpc",
+							"google.golang.org/grpc/peer",
+							"Peer",
+							"Addr",
+						),
+					},
+					MinVersion: serverAddrMinVersion,
+				},
+				probe.StructFieldConst{
+					Key: "headerFrame_hf_pos",
+					ID: structfield.NewID(
+						"google.golang.org/grpc",
+						"google.golang.org/grpc/internal/transport",
+						"headerFrame",
+						"hf",
+					),
+				},
+				probe.StructFieldConst{
+					Key: "headerFrame_streamid_pos",
+					ID: structfield.NewID(
+						"google.golang.org/grpc",
+						"google.golang.org/grpc/internal/transport",
+						"headerFrame",
+						"streamID",
+					),
+				},
+				probe.ItabConst{
+					Key:       "header_frame_itab",
+					Type:      "*google.golang.org/grpc/internal/transport.headerFrame",
+					Interface: "google.golang.org/grpc/internal/transport.cbItem",
+				},
 				probe.StructFieldConst{
 					Key: "TCPAddr_IP_offset",
 					ID:  structfield.NewID("std", "net", "TCPAddr", "IP"),
@@ -173,6 +221,15 @@ func New(logger *slog.Logger, ver string) probe.Probe {
 					Key: "TCPAddr_Port_offset",
 					ID:  structfield.NewID("std", "net", "TCPAddr", "Port"),
 				},
+				probe.StructFieldConst{
+					Key: "parser_header_pos",
+					ID: structfield.NewID(
+						"google.golang.org/grpc",
+						"google.golang.org/grpc",
+						"parser",
+						"header",
+					),
+				},
 				framePosConst{},
 			},
 			Uprobes: []*probe.Uprobe{
@@ -256,6 +313,34 @@ func New(logger *slog.Logger, ver string) probe.Probe {
 						},
 					},
 				},
+				{
+					Sym:         "google.golang.org/grpc/internal/transport.(*controlBuffer).executeAndPut",
+					EntryProbe:  "uprobe_controlBuffer_executeAndPut",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "google.golang.org/grpc.(*serverStream).SendMsg",
+					EntryProbe:  "uprobe_serverStream_msg",
+					ReturnProbe: "uprobe_serverStream_msg_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "google.golang.org/grpc.(*serverStream).RecvMsg",
+					EntryProbe:  "uprobe_serverStream_msg",
+					ReturnProbe: "uprobe_serverStream_msg_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "google.golang.org/grpc.prepareMsg",
+					ReturnProbe: "uprobe_prepareMsg_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "google.golang.org/grpc.(*parser).recvMsg",
+					EntryProbe:  "uprobe_parser_recvMsg",
+					ReturnProbe: "uprobe_parser_recvMsg_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
 			},
 			SpecFn: loadBpf,
 		},
@@ -312,10 +397,16 @@ func (w serverAddrConst) InjectOption(info *process.Info) (inject.Option, error)
 // event represents an event in the gRPC server during a gRPC request.
 type event struct {
 	context.BaseSpanProperties
-	Method     [100]byte
-	StatusCode int32
-	LocalAddr  NetAddr
-	HasStatus  uint8
+	Method       [100]byte
+	ErrMsg       [128]byte
+	StatusCode   int32
+	StreamID     uint32
+	LocalAddr    NetAddr
+	PeerAddr     NetAddr
+	HasStatus    uint8
+	Messages     probe.RPCMessages
+	ReqMetadata  probe.CapturedHeaders
+	RespMetadata probe.CapturedHeaders
 }
 
 type NetAddr struct {
@@ -344,6 +435,7 @@ func (p *processor) processFn(e *event) ptrace.SpanSlice {
 	if e.ParentSpanContext.SpanID.IsValid() {
 		span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
 	}
+	span.TraceState().FromRaw(e.State.TraceState())
 
 	attrs := []attribute.KeyValue{
 		semconv.RPCSystemKey.String("grpc"),
@@ -361,6 +453,9 @@ func (p *processor) processFn(e *event) ptrace.SpanSlice {
 			int32(codes.Unimplemented), int32(codes.Internal),
 			int32(codes.Unavailable), int32(codes.DataLoss):
 			span.Status().SetCode(ptrace.StatusCodeError)
+			if errMsg := unix.ByteSliceToString(e.ErrMsg[:]); errMsg != "" {
+				span.Status().SetMessage(errMsg)
+			}
 		}
 	}
 
@@ -370,9 +465,19 @@ func (p *processor) processFn(e *event) ptrace.SpanSlice {
 			semconv.ServerAddress(net.IP(e.LocalAddr.IP[:]).String()),
 			semconv.ServerPort(int(e.LocalAddr.Port)),
 		)
+		if e.PeerAddr.Port > 0 {
+			attrs = append(
+				attrs,
+				semconv.NetworkPeerAddress(net.IP(e.PeerAddr.IP[:]).String()),
+				semconv.NetworkPeerPort(int(e.PeerAddr.Port)),
+			)
+		}
 	}
 
 	pdataconv.Attributes(span.Attributes(), attrs...)
+	e.ReqMetadata.PutAttributes(span.Attributes(), probe.GRPCRequestMetadataPrefix)
+	e.RespMetadata.PutAttributes(span.Attributes(), probe.GRPCResponseMetadataPrefix)
+	e.Messages.AddSpanEvents(span)
 
 	return spans
 }
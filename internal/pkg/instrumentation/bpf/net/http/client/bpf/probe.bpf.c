@@ -5,9 +5,12 @@
 #include "trace/span_context.h"
 #include "go_context.h"
 #include "go_types.h"
+#include "go_headers.h"
+#include "go_errors.h"
 #include "uprobe.h"
 #include "trace/span_output.h"
 #include "trace/start_span.h"
+#include "trace/propagation.h"
 
 char __license[] SEC("license") = "Dual MIT/GPL";
 
@@ -23,12 +26,32 @@ char __license[] SEC("license") = "Dual MIT/GPL";
 #define MAX_USERNAME_SIZE 8
 #define MAX_METHOD_SIZE 16
 #define MAX_CONCURRENT 56
+#define MAX_TRACKED_REQUESTS 1024
+// The connection lifecycle phases of a request with a start and an end.
+#define CONN_PHASE_DNS 0
+#define CONN_PHASE_CONNECT 1
+#define CONN_PHASE_TLS 2
+
+struct conn_phase {
+    u64 start_time;
+    u64 end_time;
+};
+
+// The boot times of the connection lifecycle phases of a request, 0 if they
+// did not happen during the request.
+struct conn_events {
+    struct conn_phase dns;
+    struct conn_phase connect;
+    struct conn_phase tls;
+    u64 first_byte_time;
+};
 
 struct http_request_t {
     BASE_SPAN_PROPERTIES
     char host[MAX_HOSTNAME_SIZE];
     char proto[MAX_PROTO_SIZE];
     u64 status_code;
+    struct go_error error;
     char method[MAX_METHOD_SIZE];
     char path[MAX_PATH_SIZE];
     char scheme[MAX_SCHEME_SIZE];
@@ -40,6 +63,21 @@ struct http_request_t {
     char raw_fragment[MAX_RAWFRAGMENT_SIZE];
     u8 force_query;
     u8 omit_host;
+    struct captured_headers request_headers;
+    struct captured_headers response_headers;
+    struct conn_events conn;
+};
+
+// A reference to the value of http_events of a request. The start time
+// distinguishes the requests made one after the other by a goroutine.
+struct request_ref {
+    void *key;
+    u64 start_time;
+};
+
+struct conn_phase_key {
+    void *goroutine;
+    u64 phase;
 };
 
 struct {
@@ -63,6 +101,27 @@ struct {
     __uint(max_entries, MAX_CONCURRENT);
 } http_headers SEC(".maps");
 
+// The requests by context data pointer and by Request pointer. The context of
+// a request is used to find it from the functions dialing its connection, and
+// its pointer from the function reading its response. The references of the
+// requests which are done are stale and the least recently used ones are
+// evicted.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, struct request_ref);
+    __uint(max_entries, MAX_TRACKED_REQUESTS);
+} http_requests SEC(".maps");
+
+// The requests of the connection lifecycle phases in progress, by goroutine
+// and phase.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, struct conn_phase_key);
+    __type(value, struct request_ref);
+    __uint(max_entries, MAX_CONCURRENT);
+} http_conn_phases SEC(".maps");
+
 // Injected in init
 volatile const u64 method_ptr_pos;
 volatile const u64 url_ptr_pos;
@@ -70,6 +129,7 @@ volatile const u64 path_ptr_pos;
 volatile const u64 headers_ptr_pos;
 volatile const u64 ctx_ptr_pos;
 volatile const u64 status_code_pos;
+volatile const u64 response_headers_pos;
 volatile const u64 request_host_pos;
 volatile const u64 request_proto_pos;
 volatile const u64 scheme_pos;
@@ -86,6 +146,95 @@ volatile const u64 io_writer_buf_ptr_pos;
 volatile const u64 io_writer_n_pos;
 volatile const u64 url_host_pos;
 
+// Returns the request referenced by ref, NULL if it is done.
+static __always_inline struct http_request_t *lookup_request(struct request_ref *ref) {
+    if (ref == NULL || ref->key == NULL) {
+        return NULL;
+    }
+    struct http_request_t *req = bpf_map_lookup_elem(&http_events, &ref->key);
+    if (req == NULL || req->start_time != ref->start_time) {
+        return NULL;
+    }
+    return req;
+}
+
+// Reference the request with the key by ptr in http_requests. A pointer shared
+// by requests in progress, like the one of context.Background, references
+// none of them.
+static __always_inline void track_request(void *ptr, void *key, struct http_request_t *req) {
+    if (ptr == NULL) {
+        return;
+    }
+    struct request_ref ref = {.key = key, .start_time = req->start_time};
+    struct request_ref *current = bpf_map_lookup_elem(&http_requests, &ptr);
+    if (current != NULL && current->key != key && lookup_request(current) != NULL) {
+        ref.key = NULL;
+        ref.start_time = 0;
+    }
+    bpf_map_update_elem(&http_requests, &ptr, &ref, BPF_ANY);
+}
+
+// Returns the connection lifecycle phase of req.
+static __always_inline struct conn_phase *get_conn_phase(struct http_request_t *req, u64 phase) {
+    switch (phase) {
+    case CONN_PHASE_DNS:
+        return &req->conn.dns;
+    case CONN_PHASE_CONNECT:
+        return &req->conn.connect;
+    case CONN_PHASE_TLS:
+        return &req->conn.tls;
+    }
+    return NULL;
+}
+
+// Record the start of the connection lifecycle phase of the request whose
+// context is an ancestor of the context.Context argument of the probed
+// function. The first start of the phase during the request is kept.
+static __always_inline int conn_phase_start(struct pt_regs *ctx, u64 phase) {
+    struct go_iface go_context = {0};
+    get_Go_context(ctx, 2, 0, true, &go_context);
+    void *req_ctx = get_parent_go_context(&go_context, &http_requests);
+    if (req_ctx == NULL) {
+        return 0;
+    }
+    struct request_ref *ref = bpf_map_lookup_elem(&http_requests, &req_ctx);
+    struct http_request_t *req = lookup_request(ref);
+    if (req == NULL) {
+        return 0;
+    }
+    struct conn_phase *p = get_conn_phase(req, phase);
+    if (p == NULL) {
+        return 0;
+    }
+    if (p->start_time == 0) {
+        p->start_time = bpf_ktime_get_ns();
+    }
+
+    struct conn_phase_key key = {.goroutine = (void *)GOROUTINE(ctx), .phase = phase};
+    bpf_map_update_elem(&http_conn_phases, &key, ref, BPF_ANY);
+    return 0;
+}
+
+// Record the end of the connection lifecycle phase started on the goroutine.
+// The last end of the phase during the request is kept.
+static __always_inline int conn_phase_end(struct pt_regs *ctx, u64 phase) {
+    u64 end_time = bpf_ktime_get_ns();
+    struct conn_phase_key key = {.goroutine = (void *)GOROUTINE(ctx), .phase = phase};
+    struct request_ref *ref = bpf_map_lookup_elem(&http_conn_phases, &key);
+    if (ref == NULL) {
+        return 0;
+    }
+    struct http_request_t *req = lookup_request(ref);
+    if (req != NULL) {
+        struct conn_phase *p = get_conn_phase(req, phase);
+        if (p != NULL) {
+            p->end_time = end_time;
+        }
+    }
+    bpf_map_delete_elem(&http_conn_phases, &key);
+    return 0;
+}
+
 // This instrumentation attaches uprobe to the following function:
 // func net/http/transport.roundTrip(req *Request) (*Response, error)
 SEC("uprobe/Transport_roundTrip")
@@ -113,13 +262,24 @@ int uprobe_Transport_roundTrip(struct pt_regs *ctx) {
     __builtin_memset(httpReq, 0, sizeof(struct http_request_t));
     httpReq->start_time = bpf_ktime_get_ns();
 
+    sampling_attributes_t sampling_attrs = {0};
+    sampling_attrs_set_go_method(&sampling_attrs, (void *)(req_ptr + method_ptr_pos));
+    void *sampling_url_ptr = 0;
+    bpf_probe_read(&sampling_url_ptr, sizeof(sampling_url_ptr), (void *)(req_ptr + url_ptr_pos));
+    if (sampling_url_ptr != NULL) {
+        sampling_attrs_set_go_value(
+            &sampling_attrs, SAMPLING_ATTR_HTTP_PATH, (void *)(sampling_url_ptr + path_ptr_pos));
+    }
+
     start_span_params_t start_span_params = {
         .ctx = ctx,
         .go_context = &go_context,
         .psc = &httpReq->psc,
         .sc = &httpReq->sc,
+        .state = &httpReq->state,
         .get_parent_span_context_fn = NULL,
         .get_parent_span_context_arg = NULL,
+        .sampling_attrs = &sampling_attrs,
     };
     start_span(&start_span_params);
 
@@ -212,9 +372,14 @@ int uprobe_Transport_roundTrip(struct pt_regs *ctx) {
     if (headers_ptr) {
         bpf_map_update_elem(&http_headers, &headers_ptr, &key, 0);
     }
+    capture_go_headers(headers_ptr, HEADER_CAPTURE_REQUEST, &httpReq->request_headers);
 
     // Write event
     bpf_map_update_elem(&http_events, &key, httpReq, 0);
+
+    // Track the request for the connection lifecycle probes
+    track_request(go_context.data, key, httpReq);
+    track_request(req_ptr, key, httpReq);
     return 0;
 }
 
@@ -233,20 +398,129 @@ int uprobe_Transport_roundTrip_Returns(struct pt_regs *ctx) {
 
     // Getting the returned response
     void *resp_ptr = get_argument(ctx, 1);
-    // Get status code from response
-    bpf_probe_read(&http_req_span->status_code,
-                   sizeof(http_req_span->status_code),
-                   (void *)(resp_ptr + status_code_pos));
+    if (resp_ptr != NULL) {
+        // Get status code from response
+        bpf_probe_read(&http_req_span->status_code,
+                       sizeof(http_req_span->status_code),
+                       (void *)(resp_ptr + status_code_pos));
+
+        void *resp_headers_ptr = NULL;
+        bpf_probe_read(
+            &resp_headers_ptr, sizeof(resp_headers_ptr), (void *)(resp_ptr + response_headers_pos));
+        capture_go_headers(
+            resp_headers_ptr, HEADER_CAPTURE_RESPONSE, &http_req_span->response_headers);
+    }
+
+    // Getting the returned error, the span has no response if it is not nil
+    read_go_error(get_argument(ctx, 2), get_argument(ctx, 3), &http_req_span->error);
 
     http_req_span->end_time = end_time;
 
-    output_span_event(ctx, http_req_span, sizeof(*http_req_span), &http_req_span->sc);
+    output_span_event(ctx,
+                      http_req_span,
+                      sizeof(*http_req_span),
+                      &http_req_span->sc,
+                      http_req_span->start_time,
+                      http_req_span->end_time);
 
     bpf_map_delete_elem(&http_events, &key);
     return 0;
 }
 
+// This instrumentation attaches uprobe to the following function:
+// func (r *Resolver) net.lookupIPAddr(ctx context.Context, network, host string) ([]IPAddr, error)
+SEC("uprobe/Resolver_lookupIPAddr")
+int uprobe_Resolver_lookupIPAddr(struct pt_regs *ctx) {
+    return conn_phase_start(ctx, CONN_PHASE_DNS);
+}
+
+// This instrumentation attaches uretprobe to the following function:
+// func (r *Resolver) net.lookupIPAddr(ctx context.Context, network, host string) ([]IPAddr, error)
+SEC("uprobe/Resolver_lookupIPAddr")
+int uprobe_Resolver_lookupIPAddr_Returns(struct pt_regs *ctx) {
+    return conn_phase_end(ctx, CONN_PHASE_DNS);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (t *Transport) net/http.dialConn(ctx context.Context, cm connectMethod, ...) (*persistConn, error)
+SEC("uprobe/Transport_dialConn")
+int uprobe_Transport_dialConn(struct pt_regs *ctx) {
+    return conn_phase_start(ctx, CONN_PHASE_CONNECT);
+}
+
+// This instrumentation attaches uretprobe to the following function:
+// func (t *Transport) net/http.dialConn(ctx context.Context, cm connectMethod, ...) (*persistConn, error)
+SEC("uprobe/Transport_dialConn")
+int uprobe_Transport_dialConn_Returns(struct pt_regs *ctx) {
+    return conn_phase_end(ctx, CONN_PHASE_CONNECT);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (c *Conn) crypto/tls.HandshakeContext(ctx context.Context) error
+SEC("uprobe/Conn_HandshakeContext")
+int uprobe_Conn_HandshakeContext(struct pt_regs *ctx) {
+    return conn_phase_start(ctx, CONN_PHASE_TLS);
+}
+
+// This instrumentation attaches uretprobe to the following function:
+// func (c *Conn) crypto/tls.HandshakeContext(ctx context.Context) error
+SEC("uprobe/Conn_HandshakeContext")
+int uprobe_Conn_HandshakeContext_Returns(struct pt_regs *ctx) {
+    return conn_phase_end(ctx, CONN_PHASE_TLS);
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func (pc *persistConn) net/http.readResponse(rc requestAndChan, trace *httptrace.ClientTrace) (*Response, error)
+// The read loop of the connection calls it once the first byte of the
+// response is received.
+SEC("uprobe/persistConn_readResponse")
+int uprobe_persistConn_readResponse(struct pt_regs *ctx) {
+    u64 now = bpf_ktime_get_ns();
+    // The first field of rc is the *Request before Go 1.23, and the
+    // *transportRequest embedding it as its first field since.
+    void *req_ptr = get_argument(ctx, 2);
+    struct request_ref *ref = bpf_map_lookup_elem(&http_requests, &req_ptr);
+    if (ref == NULL && req_ptr != NULL) {
+        bpf_probe_read_user(&req_ptr, sizeof(req_ptr), req_ptr);
+        ref = bpf_map_lookup_elem(&http_requests, &req_ptr);
+    }
+    struct http_request_t *req = lookup_request(ref);
+    if (req != NULL && req->conn.first_byte_time == 0) {
+        req->conn.first_byte_time = now;
+    }
+    return 0;
+}
+
 #ifndef NO_HEADER_PROPAGATION
+// Write the "key: value\r\n" line of h into the buffer buf_ptr of capacity
+// size at offset *len. The len is advanced past the written line.
+// Returns 0 on success, negative value on error.
+static __always_inline long
+write_header_line(struct propagation_header *h, void *buf_ptr, s64 size, s64 *len) {
+    u32 key_len = h->key_len;
+    u32 value_len = h->value_len;
+    if (key_len == 0 || key_len > PROPAGATION_KEY_MAX_LEN - 2 || value_len == 0 ||
+        value_len > PROPAGATION_HEADER_VALUE_MAX_LEN - 2) {
+        return -1;
+    }
+    if (*len + key_len + value_len + 4 > size) {
+        return -1;
+    }
+    h->key[key_len++] = ':';
+    h->key[key_len++] = ' ';
+    h->value[value_len++] = '\r';
+    h->value[value_len++] = '\n';
+    // The len is only advanced once the whole line is written.
+    if (bpf_probe_write_user(buf_ptr + (*len & 0x0ffff), h->key, key_len)) {
+        return -1;
+    }
+    if (bpf_probe_write_user(buf_ptr + ((*len + key_len) & 0x0ffff), h->value, value_len)) {
+        return -1;
+    }
+    *len += key_len + value_len;
+    return 0;
+}
+
 // This instrumentation attaches uprobe to the following function:
 // func (h Header) net/http.Header.writeSubset(w io.Writer, exclude map[string]bool, trace *httptrace.ClientTrace) error
 SEC("uprobe/header_writeSubset")
@@ -263,8 +537,10 @@ int uprobe_writeSubset(struct pt_regs *ctx) {
 
         struct http_request_t *http_req_span = bpf_map_lookup_elem(&http_events, &key);
         if (http_req_span) {
-            char tp[W3C_VAL_LENGTH];
-            span_context_to_w3c_string(&http_req_span->sc, tp);
+            struct propagation_header *h = new_propagation_header();
+            if (h == NULL) {
+                goto done;
+            }
 
             void *buf_ptr = 0;
             bpf_probe_read(&buf_ptr,
@@ -291,23 +567,24 @@ int uprobe_writeSubset(struct pt_regs *ctx) {
                 goto done;
             }
 
-            if (len <
-                (size - W3C_VAL_LENGTH - W3C_KEY_LENGTH - 4)) { // 4 = strlen(":_") + strlen("\r\n")
-                char tp_str[W3C_KEY_LENGTH + 2 + W3C_VAL_LENGTH + 2] = "Traceparent: ";
-                char end[2] = "\r\n";
-                __builtin_memcpy(&tp_str[W3C_KEY_LENGTH + 2], tp, sizeof(tp));
-                __builtin_memcpy(&tp_str[W3C_KEY_LENGTH + 2 + W3C_VAL_LENGTH], end, sizeof(end));
-                if (bpf_probe_write_user(buf_ptr + (len & 0x0ffff), tp_str, sizeof(tp_str))) {
-                    bpf_printk("uprobe_writeSubset: Failed to write trace parent key in buffer");
-                    goto done;
+            s64 written = len;
+            for (u32 i = 0; i < MAX_PROPAGATION_HEADERS; i++) {
+                if (!propagation_inject_header(i, &http_req_span->sc, &http_req_span->state, h)) {
+                    continue;
                 }
-                len += W3C_KEY_LENGTH + 2 + W3C_VAL_LENGTH + 2;
-                if (bpf_probe_write_user(
-                        (void *)(io_writer_ptr + io_writer_n_pos), &len, sizeof(len))) {
-                    bpf_printk("uprobe_writeSubset: Failed to change io writer n");
-                    goto done;
+                if (write_header_line(h, buf_ptr, size, &len)) {
+                    bpf_printk("uprobe_writeSubset: Failed to write header in buffer");
+                    break;
                 }
             }
+            if (len == written) {
+                goto done;
+            }
+            if (bpf_probe_write_user(
+                    (void *)(io_writer_ptr + io_writer_n_pos), &len, sizeof(len))) {
+                bpf_printk("uprobe_writeSubset: Failed to change io writer n");
+                goto done;
+            }
         }
     }
 
@@ -14,26 +14,104 @@ import (
 )
 
 type bpf_no_tpHttpRequestT struct {
-	_           structs.HostLayout
-	StartTime   uint64
-	EndTime     uint64
-	Sc          bpf_no_tpSpanContext
-	Psc         bpf_no_tpSpanContext
-	Host        [128]int8
-	Proto       [8]int8
-	StatusCode  uint64
-	Method      [16]int8
-	Path        [128]int8
-	Scheme      [8]int8
-	Opaque      [8]int8
-	RawPath     [8]int8
-	Username    [8]int8
-	RawQuery    [128]int8
-	Fragment    [56]int8
-	RawFragment [56]int8
-	ForceQuery  uint8
-	OmitHost    uint8
-	_           [6]byte
+	_          structs.HostLayout
+	StartTime  uint64
+	EndTime    uint64
+	Sc         bpf_no_tpSpanContext
+	Psc        bpf_no_tpSpanContext
+	State      bpf_no_tpSpanState
+	Host       [128]int8
+	Proto      [8]int8
+	StatusCode uint64
+	Error      struct {
+		_       structs.HostLayout
+		Kinds   [3]uint8
+		Timeout uint8
+		Port    uint32
+		IpLen   uint8
+		Padding [7]uint8
+		Code    uint64
+		Op      [16]int8
+		Net     [8]int8
+		Ip      [16]uint8
+		Name    [64]int8
+		Msg     [128]int8
+	}
+	Method         [16]int8
+	Path           [128]int8
+	Scheme         [8]int8
+	Opaque         [8]int8
+	RawPath        [8]int8
+	Username       [8]int8
+	RawQuery       [128]int8
+	Fragment       [56]int8
+	RawFragment    [56]int8
+	ForceQuery     uint8
+	OmitHost       uint8
+	_              [2]byte
+	RequestHeaders struct {
+		_       structs.HostLayout
+		Count   uint32
+		Padding [4]uint8
+		Headers [8]struct {
+			_        structs.HostLayout
+			Name     [32]int8
+			Value    [128]int8
+			ValueLen uint32
+			Redacted uint8
+			Padding  [3]uint8
+		}
+	}
+	ResponseHeaders struct {
+		_       structs.HostLayout
+		Count   uint32
+		Padding [4]uint8
+		Headers [8]struct {
+			_        structs.HostLayout
+			Name     [32]int8
+			Value    [128]int8
+			ValueLen uint32
+			Redacted uint8
+			Padding  [3]uint8
+		}
+	}
+	_    [4]byte
+	Conn struct {
+		_   structs.HostLayout
+		Dns struct {
+			_         structs.HostLayout
+			StartTime uint64
+			EndTime   uint64
+		}
+		Connect struct {
+			_         structs.HostLayout
+			StartTime uint64
+			EndTime   uint64
+		}
+		Tls struct {
+			_         structs.HostLayout
+			StartTime uint64
+			EndTime   uint64
+		}
+		FirstByteTime uint64
+	}
+}
+
+type bpf_no_tpConnPhaseKey struct {
+	_         structs.HostLayout
+	Goroutine uint64
+	Phase     uint64
+}
+
+type bpf_no_tpHeaderCaptureKey struct {
+	_    structs.HostLayout
+	Name [32]int8
+}
+
+type bpf_no_tpRequestRef struct {
+	_         structs.HostLayout
+	Key       uint64
+	StartTime uint64
 }
 
 type bpf_no_tpSliceArrayBuff struct {
@@ -81,6 +159,30 @@ func loadBpf_no_tpObjects(obj interface{}, opts *ebpf.CollectionOptions) error {
 // bpf_no_tpSpecs contains maps and programs before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
+type bpf_no_tpSpanState struct {
+	_   structs.HostLayout
+	Ots struct {
+		_       structs.HostLayout
+		Th      uint64
+		Rv      uint64
+		HasTh   uint8
+		HasRv   uint8
+		Padding [6]uint8
+	}
+	Ts struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Entries [128]int8
+	}
+	Bg struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Value   [128]int8
+	}
+}
+
 type bpf_no_tpSpecs struct {
 	bpf_no_tpProgramSpecs
 	bpf_no_tpMapSpecs
@@ -91,56 +193,103 @@ type bpf_no_tpSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpf_no_tpProgramSpecs struct {
-	UprobeTransportRoundTrip        *ebpf.ProgramSpec `ebpf:"uprobe_Transport_roundTrip"`
-	UprobeTransportRoundTripReturns *ebpf.ProgramSpec `ebpf:"uprobe_Transport_roundTrip_Returns"`
-	UprobeWriteSubset               *ebpf.ProgramSpec `ebpf:"uprobe_writeSubset"`
+	UprobeConnHandshakeContext        *ebpf.ProgramSpec `ebpf:"uprobe_Conn_HandshakeContext"`
+	UprobeConnHandshakeContextReturns *ebpf.ProgramSpec `ebpf:"uprobe_Conn_HandshakeContext_Returns"`
+	UprobeResolverLookupIPAddr        *ebpf.ProgramSpec `ebpf:"uprobe_Resolver_lookupIPAddr"`
+	UprobeResolverLookupIPAddrReturns *ebpf.ProgramSpec `ebpf:"uprobe_Resolver_lookupIPAddr_Returns"`
+	UprobeTransportDialConn           *ebpf.ProgramSpec `ebpf:"uprobe_Transport_dialConn"`
+	UprobeTransportDialConnReturns    *ebpf.ProgramSpec `ebpf:"uprobe_Transport_dialConn_Returns"`
+	UprobeTransportRoundTrip          *ebpf.ProgramSpec `ebpf:"uprobe_Transport_roundTrip"`
+	UprobeTransportRoundTripReturns   *ebpf.ProgramSpec `ebpf:"uprobe_Transport_roundTrip_Returns"`
+	UprobePersistConnReadResponse     *ebpf.ProgramSpec `ebpf:"uprobe_persistConn_readResponse"`
+	UprobeWriteSubset                 *ebpf.ProgramSpec `ebpf:"uprobe_writeSubset"`
 }
 
 // bpf_no_tpMapSpecs contains maps before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpf_no_tpMapSpecs struct {
-	AllocMap                   *ebpf.MapSpec `ebpf:"alloc_map"`
-	Events                     *ebpf.MapSpec `ebpf:"events"`
-	GoContextToSc              *ebpf.MapSpec `ebpf:"go_context_to_sc"`
-	HttpClientUprobeStorageMap *ebpf.MapSpec `ebpf:"http_client_uprobe_storage_map"`
-	HttpEvents                 *ebpf.MapSpec `ebpf:"http_events"`
-	HttpHeaders                *ebpf.MapSpec `ebpf:"http_headers"`
-	ProbeActiveSamplerMap      *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap          *ebpf.MapSpec `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap          *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
-	TrackedSpansBySc           *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.MapSpec `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.MapSpec `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.MapSpec `ebpf:"events"`
+	GoContextToSc                  *ebpf.MapSpec `ebpf:"go_context_to_sc"`
+	GoroutineToSc                  *ebpf.MapSpec `ebpf:"goroutine_to_sc"`
+	HeaderCaptureMap               *ebpf.MapSpec `ebpf:"header_capture_map"`
+	HttpClientUprobeStorageMap     *ebpf.MapSpec `ebpf:"http_client_uprobe_storage_map"`
+	HttpConnPhases                 *ebpf.MapSpec `ebpf:"http_conn_phases"`
+	HttpEvents                     *ebpf.MapSpec `ebpf:"http_events"`
+	HttpHeaders                    *ebpf.MapSpec `ebpf:"http_headers"`
+	HttpRequests                   *ebpf.MapSpec `ebpf:"http_requests"`
+	ProbeActiveSamplerMap          *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.MapSpec `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.MapSpec `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.MapSpec `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.MapSpec `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.MapSpec `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.MapSpec `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc          *ebpf.MapSpec `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
 }
 
 // bpf_no_tpVariableSpecs contains global variables before they are loaded into the kernel.
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpf_no_tpVariableSpecs struct {
-	CtxPtrPos         *ebpf.VariableSpec `ebpf:"ctx_ptr_pos"`
-	EndAddr           *ebpf.VariableSpec `ebpf:"end_addr"`
-	ForceQueryPos     *ebpf.VariableSpec `ebpf:"force_query_pos"`
-	FragmentPos       *ebpf.VariableSpec `ebpf:"fragment_pos"`
-	HeadersPtrPos     *ebpf.VariableSpec `ebpf:"headers_ptr_pos"`
-	Hex               *ebpf.VariableSpec `ebpf:"hex"`
-	IoWriterBufPtrPos *ebpf.VariableSpec `ebpf:"io_writer_buf_ptr_pos"`
-	IoWriterN_pos     *ebpf.VariableSpec `ebpf:"io_writer_n_pos"`
-	MethodPtrPos      *ebpf.VariableSpec `ebpf:"method_ptr_pos"`
-	OmitHostPos       *ebpf.VariableSpec `ebpf:"omit_host_pos"`
-	OpaquePos         *ebpf.VariableSpec `ebpf:"opaque_pos"`
-	PathPtrPos        *ebpf.VariableSpec `ebpf:"path_ptr_pos"`
-	RawFragmentPos    *ebpf.VariableSpec `ebpf:"raw_fragment_pos"`
-	RawPathPos        *ebpf.VariableSpec `ebpf:"raw_path_pos"`
-	RawQueryPos       *ebpf.VariableSpec `ebpf:"raw_query_pos"`
-	RequestHostPos    *ebpf.VariableSpec `ebpf:"request_host_pos"`
-	RequestProtoPos   *ebpf.VariableSpec `ebpf:"request_proto_pos"`
-	SchemePos         *ebpf.VariableSpec `ebpf:"scheme_pos"`
-	StartAddr         *ebpf.VariableSpec `ebpf:"start_addr"`
-	StatusCodePos     *ebpf.VariableSpec `ebpf:"status_code_pos"`
-	TotalCpus         *ebpf.VariableSpec `ebpf:"total_cpus"`
-	UrlHostPos        *ebpf.VariableSpec `ebpf:"url_host_pos"`
-	UrlPtrPos         *ebpf.VariableSpec `ebpf:"url_ptr_pos"`
-	UserPtrPos        *ebpf.VariableSpec `ebpf:"user_ptr_pos"`
-	UsernamePos       *ebpf.VariableSpec `ebpf:"username_pos"`
+	BucketsPtrPos            *ebpf.VariableSpec `ebpf:"buckets_ptr_pos"`
+	CtxPtrPos                *ebpf.VariableSpec `ebpf:"ctx_ptr_pos"`
+	DeadlineExceededItab     *ebpf.VariableSpec `ebpf:"deadline_exceeded_itab"`
+	DnsErrorErrPos           *ebpf.VariableSpec `ebpf:"dns_error_err_pos"`
+	DnsErrorIsTimeoutPos     *ebpf.VariableSpec `ebpf:"dns_error_is_timeout_pos"`
+	DnsErrorItab             *ebpf.VariableSpec `ebpf:"dns_error_itab"`
+	DnsErrorNamePos          *ebpf.VariableSpec `ebpf:"dns_error_name_pos"`
+	EndAddr                  *ebpf.VariableSpec `ebpf:"end_addr"`
+	ErrnoItab                *ebpf.VariableSpec `ebpf:"errno_itab"`
+	ErrorStringItab          *ebpf.VariableSpec `ebpf:"error_string_itab"`
+	ForceQueryPos            *ebpf.VariableSpec `ebpf:"force_query_pos"`
+	FragmentPos              *ebpf.VariableSpec `ebpf:"fragment_pos"`
+	HeadersPtrPos            *ebpf.VariableSpec `ebpf:"headers_ptr_pos"`
+	Hex                      *ebpf.VariableSpec `ebpf:"hex"`
+	HttpErrorItab            *ebpf.VariableSpec `ebpf:"http_error_itab"`
+	HttpErrorTimeoutPos      *ebpf.VariableSpec `ebpf:"http_error_timeout_pos"`
+	HttpTimeoutErrorItab     *ebpf.VariableSpec `ebpf:"http_timeout_error_itab"`
+	IoWriterBufPtrPos        *ebpf.VariableSpec `ebpf:"io_writer_buf_ptr_pos"`
+	IoWriterN_pos            *ebpf.VariableSpec `ebpf:"io_writer_n_pos"`
+	MethodPtrPos             *ebpf.VariableSpec `ebpf:"method_ptr_pos"`
+	OmitHostPos              *ebpf.VariableSpec `ebpf:"omit_host_pos"`
+	OpErrorAddrPos           *ebpf.VariableSpec `ebpf:"op_error_addr_pos"`
+	OpErrorErrPos            *ebpf.VariableSpec `ebpf:"op_error_err_pos"`
+	OpErrorItab              *ebpf.VariableSpec `ebpf:"op_error_itab"`
+	OpErrorNetPos            *ebpf.VariableSpec `ebpf:"op_error_net_pos"`
+	OpErrorOpPos             *ebpf.VariableSpec `ebpf:"op_error_op_pos"`
+	OpaquePos                *ebpf.VariableSpec `ebpf:"opaque_pos"`
+	PathPtrPos               *ebpf.VariableSpec `ebpf:"path_ptr_pos"`
+	PollDeadlineExceededItab *ebpf.VariableSpec `ebpf:"poll_deadline_exceeded_itab"`
+	Propagators              *ebpf.VariableSpec `ebpf:"propagators"`
+	RawFragmentPos           *ebpf.VariableSpec `ebpf:"raw_fragment_pos"`
+	RawPathPos               *ebpf.VariableSpec `ebpf:"raw_path_pos"`
+	RawQueryPos              *ebpf.VariableSpec `ebpf:"raw_query_pos"`
+	RequestHostPos           *ebpf.VariableSpec `ebpf:"request_host_pos"`
+	RequestProtoPos          *ebpf.VariableSpec `ebpf:"request_proto_pos"`
+	ResponseHeadersPos       *ebpf.VariableSpec `ebpf:"response_headers_pos"`
+	SchemePos                *ebpf.VariableSpec `ebpf:"scheme_pos"`
+	StartAddr                *ebpf.VariableSpec `ebpf:"start_addr"`
+	StatusCodePos            *ebpf.VariableSpec `ebpf:"status_code_pos"`
+	SwissMapsUsed            *ebpf.VariableSpec `ebpf:"swiss_maps_used"`
+	SyscallErrorErrPos       *ebpf.VariableSpec `ebpf:"syscall_error_err_pos"`
+	SyscallErrorItab         *ebpf.VariableSpec `ebpf:"syscall_error_itab"`
+	SyscallErrorSyscallPos   *ebpf.VariableSpec `ebpf:"syscall_error_syscall_pos"`
+	TCPAddrIP_offset         *ebpf.VariableSpec `ebpf:"TCPAddr_IP_offset"`
+	TCPAddrPortOffset        *ebpf.VariableSpec `ebpf:"TCPAddr_Port_offset"`
+	TcpAddrItab              *ebpf.VariableSpec `ebpf:"tcp_addr_itab"`
+	TlsAlertItab             *ebpf.VariableSpec `ebpf:"tls_alert_itab"`
+	TlsCertErrorItab         *ebpf.VariableSpec `ebpf:"tls_cert_error_itab"`
+	TlsHandshakeTimeoutItab  *ebpf.VariableSpec `ebpf:"tls_handshake_timeout_itab"`
+	TotalCpus                *ebpf.VariableSpec `ebpf:"total_cpus"`
+	UrlHostPos               *ebpf.VariableSpec `ebpf:"url_host_pos"`
+	UrlPtrPos                *ebpf.VariableSpec `ebpf:"url_ptr_pos"`
+	UserPtrPos               *ebpf.VariableSpec `ebpf:"user_ptr_pos"`
+	UsernamePos              *ebpf.VariableSpec `ebpf:"username_pos"`
 }
 
 // bpf_no_tpObjects contains all objects after they have been loaded into the kernel.
@@ -163,29 +312,51 @@ func (o *bpf_no_tpObjects) Close() error {
 //
 // It can be passed to loadBpf_no_tpObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpf_no_tpMaps struct {
-	AllocMap                   *ebpf.Map `ebpf:"alloc_map"`
-	Events                     *ebpf.Map `ebpf:"events"`
-	GoContextToSc              *ebpf.Map `ebpf:"go_context_to_sc"`
-	HttpClientUprobeStorageMap *ebpf.Map `ebpf:"http_client_uprobe_storage_map"`
-	HttpEvents                 *ebpf.Map `ebpf:"http_events"`
-	HttpHeaders                *ebpf.Map `ebpf:"http_headers"`
-	ProbeActiveSamplerMap      *ebpf.Map `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap          *ebpf.Map `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap          *ebpf.Map `ebpf:"slice_array_buff_map"`
-	TrackedSpansBySc           *ebpf.Map `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.Map `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.Map `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.Map `ebpf:"events"`
+	GoContextToSc                  *ebpf.Map `ebpf:"go_context_to_sc"`
+	GoroutineToSc                  *ebpf.Map `ebpf:"goroutine_to_sc"`
+	HeaderCaptureMap               *ebpf.Map `ebpf:"header_capture_map"`
+	HttpClientUprobeStorageMap     *ebpf.Map `ebpf:"http_client_uprobe_storage_map"`
+	HttpConnPhases                 *ebpf.Map `ebpf:"http_conn_phases"`
+	HttpEvents                     *ebpf.Map `ebpf:"http_events"`
+	HttpHeaders                    *ebpf.Map `ebpf:"http_headers"`
+	HttpRequests                   *ebpf.Map `ebpf:"http_requests"`
+	ProbeActiveSamplerMap          *ebpf.Map `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.Map `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.Map `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.Map `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.Map `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.Map `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.Map `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.Map `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc          *ebpf.Map `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.Map `ebpf:"tracked_spans_by_sc"`
 }
 
 func (m *bpf_no_tpMaps) Close() error {
 	return _Bpf_no_tpClose(
 		m.AllocMap,
+		m.DroppedSpansMap,
 		m.Events,
 		m.GoContextToSc,
+		m.GoroutineToSc,
+		m.HeaderCaptureMap,
 		m.HttpClientUprobeStorageMap,
+		m.HttpConnPhases,
 		m.HttpEvents,
 		m.HttpHeaders,
+		m.HttpRequests,
 		m.ProbeActiveSamplerMap,
+		m.PropagationExtractorStorageMap,
+		m.PropagationHeaderStorageMap,
+		m.RateLimiterMap,
 		m.SamplersConfigMap,
 		m.SliceArrayBuffMap,
+		m.SpanOutputConfigMap,
+		m.SpanStateStorageMap,
+		m.TrackedSpanStatesBySc,
 		m.TrackedSpansBySc,
 	)
 }
@@ -194,46 +365,89 @@ func (m *bpf_no_tpMaps) Close() error {
 //
 // It can be passed to loadBpf_no_tpObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpf_no_tpVariables struct {
-	CtxPtrPos         *ebpf.Variable `ebpf:"ctx_ptr_pos"`
-	EndAddr           *ebpf.Variable `ebpf:"end_addr"`
-	ForceQueryPos     *ebpf.Variable `ebpf:"force_query_pos"`
-	FragmentPos       *ebpf.Variable `ebpf:"fragment_pos"`
-	HeadersPtrPos     *ebpf.Variable `ebpf:"headers_ptr_pos"`
-	Hex               *ebpf.Variable `ebpf:"hex"`
-	IoWriterBufPtrPos *ebpf.Variable `ebpf:"io_writer_buf_ptr_pos"`
-	IoWriterN_pos     *ebpf.Variable `ebpf:"io_writer_n_pos"`
-	MethodPtrPos      *ebpf.Variable `ebpf:"method_ptr_pos"`
-	OmitHostPos       *ebpf.Variable `ebpf:"omit_host_pos"`
-	OpaquePos         *ebpf.Variable `ebpf:"opaque_pos"`
-	PathPtrPos        *ebpf.Variable `ebpf:"path_ptr_pos"`
-	RawFragmentPos    *ebpf.Variable `ebpf:"raw_fragment_pos"`
-	RawPathPos        *ebpf.Variable `ebpf:"raw_path_pos"`
-	RawQueryPos       *ebpf.Variable `ebpf:"raw_query_pos"`
-	RequestHostPos    *ebpf.Variable `ebpf:"request_host_pos"`
-	RequestProtoPos   *ebpf.Variable `ebpf:"request_proto_pos"`
-	SchemePos         *ebpf.Variable `ebpf:"scheme_pos"`
-	StartAddr         *ebpf.Variable `ebpf:"start_addr"`
-	StatusCodePos     *ebpf.Variable `ebpf:"status_code_pos"`
-	TotalCpus         *ebpf.Variable `ebpf:"total_cpus"`
-	UrlHostPos        *ebpf.Variable `ebpf:"url_host_pos"`
-	UrlPtrPos         *ebpf.Variable `ebpf:"url_ptr_pos"`
-	UserPtrPos        *ebpf.Variable `ebpf:"user_ptr_pos"`
-	UsernamePos       *ebpf.Variable `ebpf:"username_pos"`
+	BucketsPtrPos            *ebpf.Variable `ebpf:"buckets_ptr_pos"`
+	CtxPtrPos                *ebpf.Variable `ebpf:"ctx_ptr_pos"`
+	DeadlineExceededItab     *ebpf.Variable `ebpf:"deadline_exceeded_itab"`
+	DnsErrorErrPos           *ebpf.Variable `ebpf:"dns_error_err_pos"`
+	DnsErrorIsTimeoutPos     *ebpf.Variable `ebpf:"dns_error_is_timeout_pos"`
+	DnsErrorItab             *ebpf.Variable `ebpf:"dns_error_itab"`
+	DnsErrorNamePos          *ebpf.Variable `ebpf:"dns_error_name_pos"`
+	EndAddr                  *ebpf.Variable `ebpf:"end_addr"`
+	ErrnoItab                *ebpf.Variable `ebpf:"errno_itab"`
+	ErrorStringItab          *ebpf.Variable `ebpf:"error_string_itab"`
+	ForceQueryPos            *ebpf.Variable `ebpf:"force_query_pos"`
+	FragmentPos              *ebpf.Variable `ebpf:"fragment_pos"`
+	HeadersPtrPos            *ebpf.Variable `ebpf:"headers_ptr_pos"`
+	Hex                      *ebpf.Variable `ebpf:"hex"`
+	HttpErrorItab            *ebpf.Variable `ebpf:"http_error_itab"`
+	HttpErrorTimeoutPos      *ebpf.Variable `ebpf:"http_error_timeout_pos"`
+	HttpTimeoutErrorItab     *ebpf.Variable `ebpf:"http_timeout_error_itab"`
+	IoWriterBufPtrPos        *ebpf.Variable `ebpf:"io_writer_buf_ptr_pos"`
+	IoWriterN_pos            *ebpf.Variable `ebpf:"io_writer_n_pos"`
+	MethodPtrPos             *ebpf.Variable `ebpf:"method_ptr_pos"`
+	OmitHostPos              *ebpf.Variable `ebpf:"omit_host_pos"`
+	OpErrorAddrPos           *ebpf.Variable `ebpf:"op_error_addr_pos"`
+	OpErrorErrPos            *ebpf.Variable `ebpf:"op_error_err_pos"`
+	OpErrorItab              *ebpf.Variable `ebpf:"op_error_itab"`
+	OpErrorNetPos            *ebpf.Variable `ebpf:"op_error_net_pos"`
+	OpErrorOpPos             *ebpf.Variable `ebpf:"op_error_op_pos"`
+	OpaquePos                *ebpf.Variable `ebpf:"opaque_pos"`
+	PathPtrPos               *ebpf.Variable `ebpf:"path_ptr_pos"`
+	PollDeadlineExceededItab *ebpf.Variable `ebpf:"poll_deadline_exceeded_itab"`
+	Propagators              *ebpf.Variable `ebpf:"propagators"`
+	RawFragmentPos           *ebpf.Variable `ebpf:"raw_fragment_pos"`
+	RawPathPos               *ebpf.Variable `ebpf:"raw_path_pos"`
+	RawQueryPos              *ebpf.Variable `ebpf:"raw_query_pos"`
+	RequestHostPos           *ebpf.Variable `ebpf:"request_host_pos"`
+	RequestProtoPos          *ebpf.Variable `ebpf:"request_proto_pos"`
+	ResponseHeadersPos       *ebpf.Variable `ebpf:"response_headers_pos"`
+	SchemePos                *ebpf.Variable `ebpf:"scheme_pos"`
+	StartAddr                *ebpf.Variable `ebpf:"start_addr"`
+	StatusCodePos            *ebpf.Variable `ebpf:"status_code_pos"`
+	SwissMapsUsed            *ebpf.Variable `ebpf:"swiss_maps_used"`
+	SyscallErrorErrPos       *ebpf.Variable `ebpf:"syscall_error_err_pos"`
+	SyscallErrorItab         *ebpf.Variable `ebpf:"syscall_error_itab"`
+	SyscallErrorSyscallPos   *ebpf.Variable `ebpf:"syscall_error_syscall_pos"`
+	TCPAddrIP_offset         *ebpf.Variable `ebpf:"TCPAddr_IP_offset"`
+	TCPAddrPortOffset        *ebpf.Variable `ebpf:"TCPAddr_Port_offset"`
+	TcpAddrItab              *ebpf.Variable `ebpf:"tcp_addr_itab"`
+	TlsAlertItab             *ebpf.Variable `ebpf:"tls_alert_itab"`
+	TlsCertErrorItab         *ebpf.Variable `ebpf:"tls_cert_error_itab"`
+	TlsHandshakeTimeoutItab  *ebpf.Variable `ebpf:"tls_handshake_timeout_itab"`
+	TotalCpus                *ebpf.Variable `ebpf:"total_cpus"`
+	UrlHostPos               *ebpf.Variable `ebpf:"url_host_pos"`
+	UrlPtrPos                *ebpf.Variable `ebpf:"url_ptr_pos"`
+	UserPtrPos               *ebpf.Variable `ebpf:"user_ptr_pos"`
+	UsernamePos              *ebpf.Variable `ebpf:"username_pos"`
 }
 
 // bpf_no_tpPrograms contains all programs after they have been loaded into the kernel.
 //
 // It can be passed to loadBpf_no_tpObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpf_no_tpPrograms struct {
-	UprobeTransportRoundTrip        *ebpf.Program `ebpf:"uprobe_Transport_roundTrip"`
-	UprobeTransportRoundTripReturns *ebpf.Program `ebpf:"uprobe_Transport_roundTrip_Returns"`
-	UprobeWriteSubset               *ebpf.Program `ebpf:"uprobe_writeSubset"`
+	UprobeConnHandshakeContext        *ebpf.Program `ebpf:"uprobe_Conn_HandshakeContext"`
+	UprobeConnHandshakeContextReturns *ebpf.Program `ebpf:"uprobe_Conn_HandshakeContext_Returns"`
+	UprobeResolverLookupIPAddr        *ebpf.Program `ebpf:"uprobe_Resolver_lookupIPAddr"`
+	UprobeResolverLookupIPAddrReturns *ebpf.Program `ebpf:"uprobe_Resolver_lookupIPAddr_Returns"`
+	UprobeTransportDialConn           *ebpf.Program `ebpf:"uprobe_Transport_dialConn"`
+	UprobeTransportDialConnReturns    *ebpf.Program `ebpf:"uprobe_Transport_dialConn_Returns"`
+	UprobeTransportRoundTrip          *ebpf.Program `ebpf:"uprobe_Transport_roundTrip"`
+	UprobeTransportRoundTripReturns   *ebpf.Program `ebpf:"uprobe_Transport_roundTrip_Returns"`
+	UprobePersistConnReadResponse     *ebpf.Program `ebpf:"uprobe_persistConn_readResponse"`
+	UprobeWriteSubset                 *ebpf.Program `ebpf:"uprobe_writeSubset"`
 }
 
 func (p *bpf_no_tpPrograms) Close() error {
 	return _Bpf_no_tpClose(
+		p.UprobeConnHandshakeContext,
+		p.UprobeConnHandshakeContextReturns,
+		p.UprobeResolverLookupIPAddr,
+		p.UprobeResolverLookupIPAddrReturns,
+		p.UprobeTransportDialConn,
+		p.UprobeTransportDialConnReturns,
 		p.UprobeTransportRoundTrip,
 		p.UprobeTransportRoundTripReturns,
+		p.UprobePersistConnReadResponse,
 		p.UprobeWriteSubset,
 	)
 }
@@ -9,6 +9,7 @@ import (
 	"log/slog"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/cilium/ebpf"
@@ -33,6 +34,25 @@ import (
 const (
 	// pkg is the package being instrumented.
 	pkg = "net/http"
+
+	// roundTripSym is the symbol of the function starting the spans.
+	roundTripSym = "net/http.(*Transport).roundTrip"
+
+	// ConnectionEventsEnvVar is the environment variable to opt-in to
+	// recording the connection lifecycle of client requests as span events.
+	ConnectionEventsEnvVar = "OTEL_GO_AUTO_HTTP_CLIENT_CONNECTION_EVENTS"
+)
+
+// Names of the connection lifecycle span events and attribute.
+const (
+	eventDNSStart     = "http.dns.start"
+	eventDNSDone      = "http.dns.done"
+	eventConnectStart = "http.connect.start"
+	eventConnectDone  = "http.connect.done"
+	eventTLSStart     = "http.tls.start"
+	eventTLSDone      = "http.tls.done"
+	eventFirstByte    = "http.receive.first_byte"
+	connReusedKey     = "http.conn.reused"
 )
 
 // New returns a new [probe.Probe].
@@ -44,7 +64,7 @@ func New(logger *slog.Logger, version string) probe.Probe {
 
 	uprobes := []*probe.Uprobe{
 		{
-			Sym:         "net/http.(*Transport).roundTrip",
+			Sym:         roundTripSym,
 			EntryProbe:  "uprobe_Transport_roundTrip",
 			ReturnProbe: "uprobe_Transport_roundTrip_Returns",
 		},
@@ -60,17 +80,34 @@ func New(logger *slog.Logger, version string) probe.Probe {
 				// We mark this probe as dependent on roundTrip, so we don't accidentally
 				// enable this bpf program, if the executable has compiled in writeSubset,
 				// but doesn't have any http roundTrip.
-				DependsOn: []string{"net/http.(*Transport).roundTrip"},
+				DependsOn: []string{roundTripSym},
 			},
 		)
 	}
 
+	redactor, err := http.RedactorFromEnv()
+	if err != nil {
+		logger.Error("invalid URL redaction configuration", "error", err)
+	}
+
+	var connEvents bool
+	if v, ok := os.LookupEnv(ConnectionEventsEnvVar); ok {
+		connEvents, err = strconv.ParseBool(v)
+		if err != nil {
+			logger.Error("invalid environment variable value", "name", ConnectionEventsEnvVar, "value", v, "error", err)
+		}
+	}
+	if connEvents {
+		uprobes = append(uprobes, connectionUprobes()...)
+	}
+
 	return &probe.SpanProducer[bpfObjects, event]{
 		Base: probe.Base[bpfObjects, event]{
 			ID:     id,
 			Logger: logger,
-			Consts: []probe.Const{
+			Consts: append([]probe.Const{
 				probe.AllocationConst{},
+				probe.PropagatorsConst{},
 				probe.StructFieldConst{
 					Key: "method_ptr_pos",
 					ID:  structfield.NewID("std", "net/http", "Request", "Method"),
@@ -95,6 +132,18 @@ func New(logger *slog.Logger, version string) probe.Probe {
 					Key: "status_code_pos",
 					ID:  structfield.NewID("std", "net/http", "Response", "StatusCode"),
 				},
+				probe.StructFieldConst{
+					Key: "response_headers_pos",
+					ID:  structfield.NewID("std", "net/http", "Response", "Header"),
+				},
+				probe.StructFieldConstMaxVersion{
+					StructField: probe.StructFieldConst{
+						Key: "buckets_ptr_pos",
+						ID:  structfield.NewID("std", "runtime", "hmap", "buckets"),
+					},
+					MaxVersion: probe.GoSwissMapsVersion,
+				},
+				probe.SwissMapsConst{},
 				probe.StructFieldConst{
 					Key: "request_host_pos",
 					ID:  structfield.NewID("std", "net/http", "Request", "Host"),
@@ -155,13 +204,50 @@ func New(logger *slog.Logger, version string) probe.Probe {
 					Key: "url_host_pos",
 					ID:  structfield.NewID("std", "net/url", "URL", "Host"),
 				},
-			},
+			}, probe.GoErrorConsts...),
 			Uprobes: uprobes,
 			SpecFn:  verifyAndLoadBpf,
 		},
 		Version:   version,
 		SchemaURL: semconv.SchemaURL,
-		ProcessFn: processFn,
+		ProcessFn: func(e *event) ptrace.SpanSlice {
+			return processFn(redactor, connEvents, e)
+		},
+	}
+}
+
+// connectionUprobes returns the uprobes recording the connection lifecycle
+// of the requests. A request is found from the context passed to the
+// functions establishing its connection, so they depend on roundTrip.
+func connectionUprobes() []*probe.Uprobe {
+	return []*probe.Uprobe{
+		{
+			Sym:         "net.(*Resolver).lookupIPAddr",
+			EntryProbe:  "uprobe_Resolver_lookupIPAddr",
+			ReturnProbe: "uprobe_Resolver_lookupIPAddr_Returns",
+			FailureMode: probe.FailureModeWarn,
+			DependsOn:   []string{roundTripSym},
+		},
+		{
+			Sym:         "net/http.(*Transport).dialConn",
+			EntryProbe:  "uprobe_Transport_dialConn",
+			ReturnProbe: "uprobe_Transport_dialConn_Returns",
+			FailureMode: probe.FailureModeWarn,
+			DependsOn:   []string{roundTripSym},
+		},
+		{
+			Sym:         "crypto/tls.(*Conn).HandshakeContext",
+			EntryProbe:  "uprobe_Conn_HandshakeContext",
+			ReturnProbe: "uprobe_Conn_HandshakeContext_Returns",
+			FailureMode: probe.FailureModeWarn,
+			DependsOn:   []string{roundTripSym},
+		},
+		{
+			Sym:         "net/http.(*persistConn).readResponse",
+			EntryProbe:  "uprobe_persistConn_readResponse",
+			FailureMode: probe.FailureModeWarn,
+			DependsOn:   []string{roundTripSym},
+		},
 	}
 }
 
@@ -182,6 +268,7 @@ type event struct {
 	Host        [128]byte
 	Proto       [8]byte
 	StatusCode  uint64
+	Err         probe.GoError
 	Method      [16]byte
 	Path        [128]byte
 	Scheme      [8]byte
@@ -193,9 +280,63 @@ type event struct {
 	RawFragment [56]byte
 	ForceQuery  uint8
 	OmitHost    uint8
+	_           [2]byte
+	ReqHeaders  probe.CapturedHeaders
+	RespHeaders probe.CapturedHeaders
+	Conn        connEvents
+}
+
+// connPhase is a connection lifecycle phase of a request. Its times are 0 if
+// it did not happen during the request.
+type connPhase struct {
+	StartTime uint64
+	EndTime   uint64
+}
+
+// connEvents are the boot times of the connection lifecycle of a request.
+type connEvents struct {
+	DNS           connPhase
+	Connect       connPhase
+	TLS           connPhase
+	FirstByteTime uint64
+}
+
+// addSpanEvents adds the phases of the connection lifecycle which happened
+// to span as events, and whether the connection was reused as an attribute.
+// The connection is reused if the request did not dial one.
+func (c *connEvents) addSpanEvents(span ptrace.Span) {
+	span.Attributes().PutBool(connReusedKey, c.Connect.StartTime == 0)
+
+	addPhase := func(p connPhase, start, done string) {
+		addConnEvent(span, start, p.StartTime)
+		if p.StartTime != 0 {
+			addConnEvent(span, done, p.EndTime)
+		}
+	}
+	addPhase(c.Connect, eventConnectStart, eventConnectDone)
+	addPhase(c.DNS, eventDNSStart, eventDNSDone)
+	addPhase(c.TLS, eventTLSStart, eventTLSDone)
+	addConnEvent(span, eventFirstByte, c.FirstByteTime)
+	span.Events().Sort(func(a, b ptrace.SpanEvent) bool {
+		return a.Timestamp() < b.Timestamp()
+	})
+}
+
+// addConnEvent adds the event with the name at the boot time t to span,
+// unless t is 0.
+func addConnEvent(span ptrace.Span, name string, t uint64) {
+	if t == 0 {
+		return
+	}
+	event := span.Events().AppendEmpty()
+	event.SetName(name)
+	event.SetTimestamp(kernel.BootOffsetToTimestamp(t))
 }
 
-func processFn(e *event) ptrace.SpanSlice {
+// processFn converts e into a span. All URL attributes of the span are
+// redacted using r. The connection lifecycle is added to the span if
+// connEvents is true.
+func processFn(r *http.Redactor, connEvents bool, e *event) ptrace.SpanSlice {
 	method := unix.ByteSliceToString(e.Method[:])
 	path := unix.ByteSliceToString(e.Path[:])
 	scheme := unix.ByteSliceToString(e.Scheme[:])
@@ -223,13 +364,15 @@ func processFn(e *event) ptrace.SpanSlice {
 	}
 	attrs := []attribute.KeyValue{
 		semconv.HTTPRequestMethodKey.String(method),
-		semconv.HTTPResponseStatusCodeKey.Int(
-			int(e.StatusCode),
-		), //nolint:gosec  // Bound checked.
 	}
-
-	if path != "" {
-		attrs = append(attrs, semconv.URLPath(path))
+	// The status code is not set if the round trip failed.
+	if e.StatusCode != 0 {
+		attrs = append(attrs, semconv.HTTPResponseStatusCodeKey.Int(
+			int(e.StatusCode), //nolint:gosec  // Bound checked.
+		))
+	}
+	if e.Err.Valid() {
+		attrs = append(attrs, http.TransportErrorType(&e.Err))
 	}
 
 	urlObj := &url.URL{
@@ -245,6 +388,11 @@ func processFn(e *event) ptrace.SpanSlice {
 		ForceQuery:  forceQuery,
 		OmitHost:    omitHost,
 	}
+	r.URL(urlObj)
+
+	if urlObj.Path != "" {
+		attrs = append(attrs, semconv.URLPath(urlObj.Path))
+	}
 
 	fullURL := urlObj.String()
 	attrs = append(attrs, semconv.URLFull(fullURL))
@@ -283,10 +431,18 @@ func processFn(e *event) ptrace.SpanSlice {
 	if e.ParentSpanContext.SpanID.IsValid() {
 		span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
 	}
+	span.TraceState().FromRaw(e.State.TraceState())
 
 	pdataconv.Attributes(span.Attributes(), attrs...)
+	e.ReqHeaders.PutAttributes(span.Attributes(), "http.request.header.")
+	e.RespHeaders.PutAttributes(span.Attributes(), "http.response.header.")
+	if connEvents {
+		e.Conn.addSpanEvents(span)
+	}
 
-	if e.StatusCode >= 400 && e.StatusCode < 600 {
+	if e.Err.Valid() {
+		e.Err.RecordException(span)
+	} else if e.StatusCode >= 400 && e.StatusCode < 600 {
 		span.Status().SetCode(ptrace.StatusCodeError)
 	}
 
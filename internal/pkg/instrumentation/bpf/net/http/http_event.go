@@ -17,6 +17,8 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 	"golang.org/x/sys/unix"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
 )
 
 func ServerAddressPortAttributes(host []byte) (addr, port attribute.KeyValue) {
@@ -57,6 +59,120 @@ func NetPeerAddressPortAttributes(host []byte) (addr, port attribute.KeyValue) {
 	return addr, port
 }
 
+// Values of the error.type attribute of the transport errors of HTTP clients.
+const (
+	ErrorTypeTimeout  = "timeout"
+	ErrorTypeCanceled = "canceled"
+	ErrorTypeTLS      = "tls"
+	ErrorTypeDial     = "dial"
+)
+
+// TransportErrorType returns the error.type attribute of the error e returned
+// by the transport of an HTTP client. Errors that are not timeouts, canceled
+// requests, TLS or dial errors have the type _OTHER.
+func TransportErrorType(e *probe.GoError) attribute.KeyValue {
+	switch {
+	case e.IsTimeout():
+		return semconv.ErrorTypeKey.String(ErrorTypeTimeout)
+	case e.IsCanceled():
+		return semconv.ErrorTypeKey.String(ErrorTypeCanceled)
+	case e.IsTLS():
+		return semconv.ErrorTypeKey.String(ErrorTypeTLS)
+	case e.OpErrorOp() == "dial":
+		return semconv.ErrorTypeKey.String(ErrorTypeDial)
+	}
+	return semconv.ErrorTypeOther
+}
+
+// knownMethods are the HTTP methods defined by RFC 9110 and RFC 5789.
+var knownMethods = map[string]struct{}{
+	"CONNECT": {},
+	"DELETE":  {},
+	"GET":     {},
+	"HEAD":    {},
+	"OPTIONS": {},
+	"PATCH":   {},
+	"POST":    {},
+	"PUT":     {},
+	"TRACE":   {},
+}
+
+// MethodAttributes returns the http.request.method attribute of method and,
+// if method is not a known HTTP method, the http.request.method_original
+// attribute holding it. The returned name is the method to use in span names,
+// "HTTP" for unknown methods.
+func MethodAttributes(method string) (name string, attrs []attribute.KeyValue) {
+	if _, ok := knownMethods[method]; ok {
+		return method, []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(method)}
+	}
+	return "HTTP", []attribute.KeyValue{
+		semconv.HTTPRequestMethodOther,
+		semconv.HTTPRequestMethodOriginal(method),
+	}
+}
+
+// ClientAddressPortAttributes returns the client.address and client.port
+// attributes of a server request. The address of the original client is
+// taken from the first element of the forwarded (Forwarded) or forwardedFor
+// (X-Forwarded-For) header values, in that order. If neither is set, the
+// address and port of the peer remoteAddr are used.
+func ClientAddressPortAttributes(
+	forwarded, forwardedFor, remoteAddr []byte,
+) (addr, port attribute.KeyValue) {
+	if host := forwardedClient(unix.ByteSliceToString(forwarded)); host != "" {
+		return semconv.ClientAddress(host), port
+	}
+	if host := forwardedForClient(unix.ByteSliceToString(forwardedFor)); host != "" {
+		return semconv.ClientAddress(host), port
+	}
+
+	hostString := unix.ByteSliceToString(remoteAddr)
+	if h, p, err := net.SplitHostPort(hostString); err == nil {
+		hostString = h
+		if portI, err := strconv.Atoi(p); err == nil {
+			port = semconv.ClientPort(portI)
+		}
+	}
+	if hostString != "" {
+		addr = semconv.ClientAddress(hostString)
+	}
+	return addr, port
+}
+
+// forwardedClient returns the host of the "for" parameter of the first
+// element of the Forwarded header value v (RFC 7239). An empty string is
+// returned if it is not set, unknown or obfuscated.
+func forwardedClient(v string) string {
+	first, _, _ := strings.Cut(v, ",")
+	for pair := range strings.SplitSeq(first, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || !strings.EqualFold(key, "for") {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		if h, _, err := net.SplitHostPort(value); err == nil {
+			value = h
+		}
+		value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+		if value == "" || strings.EqualFold(value, "unknown") || strings.HasPrefix(value, "_") {
+			return ""
+		}
+		return value
+	}
+	return ""
+}
+
+// forwardedForClient returns the host of the first address of the
+// X-Forwarded-For header value v.
+func forwardedForClient(v string) string {
+	first, _, _ := strings.Cut(v, ",")
+	first = strings.TrimSpace(first)
+	if h, _, err := net.SplitHostPort(first); err == nil {
+		first = h
+	}
+	return first
+}
+
 var (
 	// ErrEmptyPattern is returned when the input pattern is empty.
 	ErrEmptyPattern = errors.New("empty pattern")
@@ -6,6 +6,12 @@ package http // nolint:revive  // Internal package name.
 import (
 	"errors"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
 )
 
 // TestParsePattern tests the ParsePattern function with various inputs.
@@ -66,3 +72,134 @@ func TestParsePattern(t *testing.T) {
 		})
 	}
 }
+
+func TestMethodAttributes(t *testing.T) {
+	name, attrs := MethodAttributes("GET")
+	assert.Equal(t, "GET", name)
+	assert.Equal(t, []attribute.KeyValue{semconv.HTTPRequestMethodGet}, attrs)
+
+	name, attrs = MethodAttributes("PURGE")
+	assert.Equal(t, "HTTP", name)
+	assert.Equal(t, []attribute.KeyValue{
+		semconv.HTTPRequestMethodOther,
+		semconv.HTTPRequestMethodOriginal("PURGE"),
+	}, attrs)
+
+	name, _ = MethodAttributes("get")
+	assert.Equal(t, "HTTP", name)
+}
+
+func TestClientAddressPortAttributes(t *testing.T) {
+	tests := []struct {
+		name         string
+		forwarded    string
+		forwardedFor string
+		remoteAddr   string
+		addr, port   attribute.KeyValue
+	}{
+		{
+			name:       "RemoteAddr",
+			remoteAddr: "10.0.0.1:8080",
+			addr:       semconv.ClientAddress("10.0.0.1"),
+			port:       semconv.ClientPort(8080),
+		},
+		{
+			name:         "X-Forwarded-For",
+			forwardedFor: "203.0.113.195, 70.41.3.18, 150.172.238.178",
+			remoteAddr:   "10.0.0.1:8080",
+			addr:         semconv.ClientAddress("203.0.113.195"),
+		},
+		{
+			name:         "Forwarded",
+			forwarded:    `for="[2001:db8:cafe::17]:4711";proto=https, for=10.0.0.2`,
+			forwardedFor: "203.0.113.195",
+			remoteAddr:   "10.0.0.1:8080",
+			addr:         semconv.ClientAddress("2001:db8:cafe::17"),
+		},
+		{
+			name:         "Forwarded obfuscated",
+			forwarded:    "for=_hidden;by=10.0.0.3",
+			forwardedFor: "203.0.113.195",
+			remoteAddr:   "10.0.0.1:8080",
+			addr:         semconv.ClientAddress("203.0.113.195"),
+		},
+		{
+			name:       "Forwarded unknown",
+			forwarded:  "proto=http;for=unknown",
+			remoteAddr: "10.0.0.1",
+			addr:       semconv.ClientAddress("10.0.0.1"),
+		},
+		{
+			name: "Empty",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			addr, port := ClientAddressPortAttributes(
+				[]byte(tc.forwarded),
+				[]byte(tc.forwardedFor),
+				[]byte(tc.remoteAddr),
+			)
+			assert.Equal(t, tc.addr, addr)
+			assert.Equal(t, tc.port, port)
+		})
+	}
+}
+
+func TestTransportErrorType(t *testing.T) {
+	// Kinds of the probe.GoError read by eBPF.
+	const (
+		kindOther    = 1
+		kindString   = 2
+		kindDeadline = 3
+		kindOp       = 8
+		kindDNS      = 9
+		kindTLSCert  = 13
+	)
+	goError := func(op, msg string, kinds ...uint8) *probe.GoError {
+		var e probe.GoError
+		copy(e.Kinds[:], kinds)
+		copy(e.Op[:], op)
+		copy(e.Msg[:], msg)
+		return &e
+	}
+
+	tests := []struct {
+		name string
+		err  *probe.GoError
+		want attribute.KeyValue
+	}{
+		{
+			name: "timeout",
+			err:  goError("", "", kindDeadline),
+			want: semconv.ErrorTypeKey.String(ErrorTypeTimeout),
+		},
+		{
+			name: "canceled",
+			err:  goError("", "context canceled", kindString),
+			want: semconv.ErrorTypeKey.String(ErrorTypeCanceled),
+		},
+		{
+			name: "TLS",
+			err:  goError("", "", kindTLSCert),
+			want: semconv.ErrorTypeKey.String(ErrorTypeTLS),
+		},
+		{
+			name: "dial",
+			err:  goError("dial", "no such host", kindOp, kindDNS),
+			want: semconv.ErrorTypeKey.String(ErrorTypeDial),
+		},
+		{
+			name: "other",
+			err:  goError("read", "", kindOp, kindOther),
+			want: semconv.ErrorTypeOther,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, TransportErrorType(tc.err))
+		})
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package http // nolint:revive  // Internal package name.
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// KeepUserinfoEnvVar is the environment variable to opt-out of redacting
+	// the userinfo of captured URLs.
+	KeepUserinfoEnvVar = "OTEL_GO_AUTO_HTTP_KEEP_USERINFO"
+
+	// DropQueryEnvVar is the environment variable to opt-in to dropping the
+	// query of captured URLs.
+	DropQueryEnvVar = "OTEL_GO_AUTO_HTTP_DROP_QUERY"
+
+	// QueryAllowlistEnvVar is the environment variable containing a
+	// comma-separated list of query parameters to keep in captured URLs. If
+	// set, all other query parameters are dropped.
+	QueryAllowlistEnvVar = "OTEL_GO_AUTO_HTTP_QUERY_ALLOWLIST"
+
+	// RedactQueryParamsEnvVar is the environment variable containing a
+	// comma-separated list of query parameters, in addition to the default
+	// ones, whose values are redacted in captured URLs.
+	RedactQueryParamsEnvVar = "OTEL_GO_AUTO_HTTP_REDACT_QUERY_PARAMS"
+
+	// RedactPathPatternsEnvVar is the environment variable containing a
+	// comma-separated list of regular expressions. Path segments of captured
+	// URLs fully matching any of these expressions are redacted.
+	RedactPathPatternsEnvVar = "OTEL_GO_AUTO_HTTP_REDACT_PATH_PATTERNS"
+)
+
+// Redacted is the value redacted URL components are replaced with.
+const Redacted = "REDACTED"
+
+// defaultRedactedQueryParams are the query parameters whose values are
+// redacted by default.
+//
+// https://opentelemetry.io/docs/specs/semconv/registry/attributes/url/#url-full
+var defaultRedactedQueryParams = []string{
+	"AWSAccessKeyId",
+	"Signature",
+	"sig",
+	"X-Goog-Signature",
+}
+
+// Redactor redacts personally identifiable information from captured URL
+// components.
+//
+// The zero value redacts nothing. Use [DefaultRedactor] for the defaults
+// recommended by OpenTelemetry semantic conventions.
+type Redactor struct {
+	// RedactUserinfo determines if the userinfo of a URL is replaced with
+	// [Redacted].
+	RedactUserinfo bool
+	// DropQuery determines if the query of a URL is removed.
+	DropQuery bool
+	// QueryAllowlist is the set of query parameters that are kept. If empty,
+	// all query parameters are kept.
+	QueryAllowlist map[string]struct{}
+	// RedactQueryParams is the set of query parameters whose values are
+	// replaced with [Redacted].
+	RedactQueryParams map[string]struct{}
+	// PathPatterns are the patterns path segments are matched against. Path
+	// segments fully matching any pattern are replaced with [Redacted].
+	PathPatterns []*regexp.Regexp
+}
+
+// DefaultRedactor returns a [Redactor] that redacts userinfo and the values of
+// query parameters known to contain credentials.
+func DefaultRedactor() *Redactor {
+	r := &Redactor{
+		RedactUserinfo:    true,
+		RedactQueryParams: make(map[string]struct{}, len(defaultRedactedQueryParams)),
+	}
+	for _, p := range defaultRedactedQueryParams {
+		r.RedactQueryParams[p] = struct{}{}
+	}
+	return r
+}
+
+// RedactorFromEnv returns a [Redactor] configured by the DefaultRedactor
+// defaults and the values of the KeepUserinfoEnvVar, DropQueryEnvVar,
+// QueryAllowlistEnvVar, RedactQueryParamsEnvVar, and RedactPathPatternsEnvVar
+// environment variables.
+//
+// If any of the environment variable values are invalid, an error is returned
+// along with a Redactor configured with all valid values.
+func RedactorFromEnv() (*Redactor, error) {
+	r := DefaultRedactor()
+
+	var err error
+	if v, ok := os.LookupEnv(KeepUserinfoEnvVar); ok {
+		keep, e := strconv.ParseBool(v)
+		if e != nil {
+			err = errors.Join(err, fmt.Errorf("invalid %s value %q: %w", KeepUserinfoEnvVar, v, e))
+		} else {
+			r.RedactUserinfo = !keep
+		}
+	}
+
+	if v, ok := os.LookupEnv(DropQueryEnvVar); ok {
+		drop, e := strconv.ParseBool(v)
+		if e != nil {
+			err = errors.Join(err, fmt.Errorf("invalid %s value %q: %w", DropQueryEnvVar, v, e))
+		} else {
+			r.DropQuery = drop
+		}
+	}
+
+	for _, p := range splitList(os.Getenv(QueryAllowlistEnvVar)) {
+		if r.QueryAllowlist == nil {
+			r.QueryAllowlist = make(map[string]struct{})
+		}
+		r.QueryAllowlist[p] = struct{}{}
+	}
+
+	for _, p := range splitList(os.Getenv(RedactQueryParamsEnvVar)) {
+		r.RedactQueryParams[p] = struct{}{}
+	}
+
+	for _, p := range splitList(os.Getenv(RedactPathPatternsEnvVar)) {
+		re, e := regexp.Compile(`^(?:` + p + `)$`)
+		if e != nil {
+			err = errors.Join(err, fmt.Errorf("invalid %s pattern %q: %w", RedactPathPatternsEnvVar, p, e))
+			continue
+		}
+		r.PathPatterns = append(r.PathPatterns, re)
+	}
+
+	return r, err
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// URL redacts the userinfo, query, and path of u in place.
+func (r *Redactor) URL(u *url.URL) {
+	if r == nil || u == nil {
+		return
+	}
+
+	if r.RedactUserinfo && u.User != nil {
+		u.User = url.UserPassword(Redacted, Redacted)
+	}
+
+	u.RawQuery = r.Query(u.RawQuery)
+	if u.RawQuery == "" && r.DropQuery {
+		u.ForceQuery = false
+	}
+
+	if p := r.Path(u.Path); p != u.Path {
+		u.Path, u.RawPath = p, ""
+	}
+}
+
+// Query returns the raw URL query rawQuery with all dropped query parameters
+// removed and all redacted query parameter values replaced with [Redacted].
+// The order of the kept parameters is preserved.
+func (r *Redactor) Query(rawQuery string) string {
+	if r == nil || rawQuery == "" {
+		return rawQuery
+	}
+	if r.DropQuery {
+		return ""
+	}
+	if len(r.QueryAllowlist) == 0 && len(r.RedactQueryParams) == 0 {
+		return rawQuery
+	}
+
+	var b strings.Builder
+	b.Grow(len(rawQuery))
+	for param := range strings.SplitSeq(rawQuery, "&") {
+		if param == "" {
+			continue
+		}
+		rawKey, _, hasValue := strings.Cut(param, "=")
+		key, err := url.QueryUnescape(rawKey)
+		if err != nil {
+			key = rawKey
+		}
+
+		if len(r.QueryAllowlist) > 0 {
+			if _, ok := r.QueryAllowlist[key]; !ok {
+				continue
+			}
+		}
+
+		if b.Len() > 0 {
+			b.WriteByte('&')
+		}
+		if _, ok := r.RedactQueryParams[key]; ok && hasValue {
+			b.WriteString(rawKey)
+			b.WriteByte('=')
+			b.WriteString(Redacted)
+			continue
+		}
+		b.WriteString(param)
+	}
+	return b.String()
+}
+
+// Path returns path with all segments fully matching one of the configured
+// patterns replaced with [Redacted].
+func (r *Redactor) Path(path string) string {
+	if r == nil || len(r.PathPatterns) == 0 || path == "" {
+		return path
+	}
+
+	segments := strings.Split(path, "/")
+	var changed bool
+	for i, seg := range segments {
+		if seg == "" {
+			continue
+		}
+		for _, re := range r.PathPatterns {
+			if re.MatchString(seg) {
+				segments[i] = Redacted
+				changed = true
+				break
+			}
+		}
+	}
+	if !changed {
+		return path
+	}
+	return strings.Join(segments, "/")
+}
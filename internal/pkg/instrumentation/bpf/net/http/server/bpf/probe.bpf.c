@@ -5,19 +5,22 @@
 #include "trace/span_context.h"
 #include "go_context.h"
 #include "go_types.h"
+#include "go_headers.h"
 #include "uprobe.h"
 #include "trace/span_output.h"
 #include "trace/start_span.h"
+#include "trace/propagation.h"
 
 char __license[] SEC("license") = "Dual MIT/GPL";
 
 #define PATH_MAX_LEN 128
 #define MAX_BUCKETS 8
-#define METHOD_MAX_LEN 8
+#define METHOD_MAX_LEN 16
 #define MAX_CONCURRENT 50
 #define REMOTE_ADDR_MAX_LEN 256
 #define HOST_MAX_LEN 256
 #define PROTO_MAX_LEN 8
+#define RAW_QUERY_MAX_LEN 128
 
 struct http_server_span_t {
     BASE_SPAN_PROPERTIES
@@ -28,6 +31,14 @@ struct http_server_span_t {
     char remote_addr[REMOTE_ADDR_MAX_LEN];
     char host[HOST_MAX_LEN];
     char proto[PROTO_MAX_LEN];
+    char raw_query[RAW_QUERY_MAX_LEN];
+    struct known_headers known_headers;
+    s64 request_content_length;
+    s64 response_body_size;
+    u8 tls;
+    u8 padding[7];
+    struct captured_headers request_headers;
+    struct captured_headers response_headers;
 };
 
 struct uprobe_data_t {
@@ -36,6 +47,8 @@ struct uprobe_data_t {
     // saving the response pointer in the entry probe
     // and using it in the return probe
     u64 resp_ptr;
+    // the response headers map set by the handler, if it requested it
+    u64 resp_headers_ptr;
 };
 
 MAP_BUCKET_DEFINITION(go_string_t, go_slice_t)
@@ -50,7 +63,7 @@ struct {
 struct {
     __uint(type, BPF_MAP_TYPE_LRU_HASH);
     __type(key, void *);
-    __type(value, struct span_context);
+    __type(value, struct propagation_extractor);
     __uint(max_entries, MAX_CONCURRENT);
 } http_server_context_headers SEC(".maps");
 
@@ -74,12 +87,15 @@ volatile const u64 url_ptr_pos;
 volatile const u64 path_ptr_pos;
 volatile const u64 ctx_ptr_pos;
 volatile const u64 headers_ptr_pos;
-volatile const u64 buckets_ptr_pos;
 volatile const u64 req_ptr_pos;
 volatile const u64 status_code_pos;
 volatile const u64 remote_addr_pos;
 volatile const u64 host_pos;
 volatile const u64 proto_pos;
+volatile const u64 raw_query_pos;
+volatile const u64 req_tls_pos;
+volatile const u64 req_content_length_pos;
+volatile const u64 resp_written_pos;
 
 // A flag indicating whether the pattern field is public in the http Request struct
 volatile const bool pattern_path_public_supported;
@@ -89,15 +105,14 @@ volatile const bool pattern_path_supported;
 volatile const u64 req_pattern_pos;
 volatile const u64 req_pat_pos;
 volatile const u64 pat_str_pos;
-// A flag indicating whether the Go version is using swiss maps
-volatile const bool swiss_maps_used;
 
-// Extracts the span context from the request headers by looking for the 'traceparent' header.
+// Extracts the span context from the request headers of the configured propagators.
 // Fills the parent_span_context with the extracted span context.
 // Returns 0 on success, negative value on error.
 static __always_inline long
 extract_context_from_req_headers_go_map(void *headers_ptr_ptr,
-                                        struct span_context *parent_span_context) {
+                                        struct span_context *parent_span_context,
+                                        struct span_state *state) {
     void *headers_ptr;
     long res;
     res = bpf_probe_read(&headers_ptr, sizeof(headers_ptr), headers_ptr_ptr);
@@ -130,6 +145,10 @@ extract_context_from_req_headers_go_map(void *headers_ptr_ptr,
     if (!map_value) {
         return -1;
     }
+    struct propagation_extractor *ex = new_propagation_extractor();
+    if (ex == NULL) {
+        return -1;
+    }
 
     for (u64 j = 0; j < MAX_BUCKETS; j++) {
         if (j >= bucket_count) {
@@ -146,58 +165,40 @@ extract_context_from_req_headers_go_map(void *headers_ptr_ptr,
             if (map_value->tophash[i] == 0) {
                 continue;
             }
-            if (map_value->keys[i].len != W3C_KEY_LENGTH) {
-                continue;
-            }
-            char current_header_key[W3C_KEY_LENGTH];
-            bpf_probe_read(current_header_key, sizeof(current_header_key), map_value->keys[i].str);
-            if (!bpf_memcmp(current_header_key, "traceparent", W3C_KEY_LENGTH) &&
-                !bpf_memcmp(current_header_key, "Traceparent", W3C_KEY_LENGTH)) {
-                continue;
-            }
-            void *traceparent_header_value_ptr = map_value->values[i].array;
-            struct go_string traceparent_header_value_go_str;
-            res = bpf_probe_read(&traceparent_header_value_go_str,
-                                 sizeof(traceparent_header_value_go_str),
-                                 traceparent_header_value_ptr);
+            // Only the first value of each header is used.
+            struct go_string header_value_go_str;
+            res = bpf_probe_read(
+                &header_value_go_str, sizeof(header_value_go_str), map_value->values[i].array);
             if (res < 0) {
-                return -1;
-            }
-            if (traceparent_header_value_go_str.len != W3C_VAL_LENGTH) {
                 continue;
             }
-            char traceparent_header_value[W3C_VAL_LENGTH];
-            res = bpf_probe_read(&traceparent_header_value,
-                                 sizeof(traceparent_header_value),
-                                 traceparent_header_value_go_str.str);
-            if (res < 0) {
-                return res;
-            }
-            w3c_string_to_span_context(traceparent_header_value, parent_span_context);
-            return 0;
+            propagation_extract_header(ex,
+                                       map_value->keys[i].str,
+                                       map_value->keys[i].len,
+                                       header_value_go_str.str,
+                                       header_value_go_str.len);
         }
     }
-    return -1;
+    return propagation_extract_finish(ex, parent_span_context, state);
 }
 
 static __always_inline long
-extract_context_from_req_headers_pre_parsed(void *key, struct span_context *parent_span_context) {
-    struct span_context *parsed_header_context =
-        bpf_map_lookup_elem(&http_server_context_headers, &key);
-    if (!parsed_header_context) {
+extract_context_from_req_headers_pre_parsed(void *key,
+                                            struct span_context *parent_span_context,
+                                            struct span_state *state) {
+    struct propagation_extractor *ex = bpf_map_lookup_elem(&http_server_context_headers, &key);
+    if (!ex) {
         return -1;
     }
-
-    __builtin_memcpy(parent_span_context, parsed_header_context, sizeof(struct span_context));
-    return 0;
+    return propagation_extract_finish(ex, parent_span_context, state);
 }
 
-static __always_inline long
-extract_context_from_req_headers(void *key, struct span_context *parent_span_context) {
+static __always_inline long extract_context_from_req_headers(
+    void *key, struct span_context *parent_span_context, struct span_state *state) {
     if (swiss_maps_used) {
-        return extract_context_from_req_headers_pre_parsed(key, parent_span_context);
+        return extract_context_from_req_headers_pre_parsed(key, parent_span_context, state);
     }
-    return extract_context_from_req_headers_go_map(key, parent_span_context);
+    return extract_context_from_req_headers_go_map(key, parent_span_context, state);
 }
 
 static __always_inline void
@@ -240,12 +241,24 @@ int uprobe_serverHandler_ServeHTTP(struct pt_regs *ctx) {
 
     // Propagate context
     void *req_ptr = get_argument(ctx, 4);
+
+    sampling_attributes_t sampling_attrs = {0};
+    sampling_attrs_set_go_method(&sampling_attrs, (void *)(req_ptr + method_ptr_pos));
+    void *url_ptr = 0;
+    bpf_probe_read(&url_ptr, sizeof(url_ptr), (void *)(req_ptr + url_ptr_pos));
+    if (url_ptr != NULL) {
+        sampling_attrs_set_go_value(
+            &sampling_attrs, SAMPLING_ATTR_HTTP_PATH, (void *)(url_ptr + path_ptr_pos));
+    }
+
     start_span_params_t start_span_params = {
         .ctx = ctx,
         .go_context = &go_context,
         .psc = &http_server_span->psc,
         .sc = &http_server_span->sc,
+        .state = &http_server_span->state,
         .get_parent_span_context_fn = extract_context_from_req_headers,
+        .sampling_attrs = &sampling_attrs,
     };
 
     // If Go is using swiss maps, we currently rely on the uretprobe setup
@@ -259,8 +272,16 @@ int uprobe_serverHandler_ServeHTTP(struct pt_regs *ctx) {
 
     start_span(&start_span_params);
 
+    void *headers_ptr = NULL;
+    bpf_probe_read(&headers_ptr, sizeof(headers_ptr), (void *)(req_ptr + headers_ptr_pos));
+    read_go_headers(headers_ptr,
+                    HEADER_CAPTURE_REQUEST,
+                    &http_server_span->request_headers,
+                    &http_server_span->known_headers);
+
     bpf_map_update_elem(&http_server_uprobes, &key, uprobe_data, 0);
-    start_tracking_span(go_context.data, &http_server_span->sc);
+    start_tracking_span(go_context.data, &http_server_span->sc, &http_server_span->state);
+    start_tracking_goroutine_span((void *)GOROUTINE(ctx), &http_server_span->sc);
     return 0;
 }
 
@@ -333,15 +354,40 @@ int uprobe_serverHandler_ServeHTTP_Returns(struct pt_regs *ctx) {
                    http_server_span->proto,
                    sizeof(http_server_span->proto),
                    "proto from Request.Proto");
+    read_go_string(url_ptr,
+                   raw_query_pos,
+                   http_server_span->raw_query,
+                   sizeof(http_server_span->raw_query),
+                   "raw query from Request.URL");
+
+    void *tls_ptr = NULL;
+    bpf_probe_read(&tls_ptr, sizeof(tls_ptr), (void *)(req_ptr + req_tls_pos));
+    http_server_span->tls = tls_ptr != NULL;
+    bpf_probe_read(&http_server_span->request_content_length,
+                   sizeof(http_server_span->request_content_length),
+                   (void *)(req_ptr + req_content_length_pos));
+    bpf_probe_read(&http_server_span->response_body_size,
+                   sizeof(http_server_span->response_body_size),
+                   (void *)(resp_ptr + resp_written_pos));
 
     // status code
     bpf_probe_read(&http_server_span->status_code,
                    sizeof(http_server_span->status_code),
                    (void *)(resp_ptr + status_code_pos));
 
-    output_span_event(ctx, http_server_span, sizeof(*http_server_span), &http_server_span->sc);
+    capture_go_headers((void *)uprobe_data->resp_headers_ptr,
+                       HEADER_CAPTURE_RESPONSE,
+                       &http_server_span->response_headers);
+
+    output_span_event(ctx,
+                      http_server_span,
+                      sizeof(*http_server_span),
+                      &http_server_span->sc,
+                      http_server_span->start_time,
+                      http_server_span->end_time);
 
     stop_tracking_span(&http_server_span->sc, &http_server_span->psc);
+    stop_tracking_goroutine_span((void *)GOROUTINE(ctx), &http_server_span->sc);
     bpf_map_delete_elem(&http_server_uprobes, &key);
     bpf_map_delete_elem(&http_server_context_headers, &key);
     return 0;
@@ -356,16 +402,59 @@ int uprobe_textproto_Reader_readContinuedLineSlice_Returns(struct pt_regs *ctx)
     u64 len = (u64)GO_PARAM2(ctx);
     u8 *buf = (u8 *)GO_PARAM1(ctx);
 
-    if (len >= (W3C_KEY_LENGTH + W3C_VAL_LENGTH + 2)) {
-        u8 temp[W3C_KEY_LENGTH + W3C_VAL_LENGTH + 2];
-        bpf_probe_read(temp, sizeof(temp), buf);
+    // The line is "Key: value", read enough of it for the longest key.
+    char temp[PROPAGATION_KEY_MAX_LEN];
+    u32 n = len > sizeof(temp) ? sizeof(temp) : (u32)len;
+    if (n == 0 || bpf_probe_read_user(temp, n, buf) != 0) {
+        return 0;
+    }
+    u32 key_len = propagation_index(temp, 0, n, ':');
+    if (key_len >= n) {
+        return 0;
+    }
+    u8 kind = propagation_header_kind(temp, key_len);
+    if (kind == PROPAGATION_HEADER_NONE) {
+        return 0;
+    }
+    u32 val_pos = key_len + 1;
+    for (u32 i = 0; i < sizeof(temp); i++) {
+        if (val_pos >= n || temp[val_pos & (sizeof(temp) - 1)] != ' ') {
+            break;
+        }
+        val_pos++;
+    }
+    if (val_pos >= len) {
+        return 0;
+    }
 
-        if (!bpf_memicmp((const char *)temp, "traceparent: ", W3C_KEY_LENGTH + 2)) {
-            struct span_context parent_span_context = {};
-            w3c_string_to_span_context((char *)(temp + W3C_KEY_LENGTH + 2), &parent_span_context);
-            bpf_map_update_elem(&http_server_context_headers, &key, &parent_span_context, BPF_ANY);
+    // The line buffer is reused for the following lines, the value is extracted
+    // now and the result stored until the request is served.
+    struct propagation_extractor *ex = bpf_map_lookup_elem(&http_server_context_headers, &key);
+    if (ex == NULL) {
+        struct propagation_extractor *zero = new_propagation_extractor();
+        if (zero == NULL) {
+            return 0;
+        }
+        bpf_map_update_elem(&http_server_context_headers, &key, zero, BPF_ANY);
+        ex = bpf_map_lookup_elem(&http_server_context_headers, &key);
+        if (ex == NULL) {
+            return 0;
         }
     }
+    propagation_extract_value(ex, kind, buf + val_pos, len - val_pos);
+    return 0;
+}
 
+// This instrumentation attaches uprobe to the following function:
+// func (w *response) Header() Header
+// The returned map holds the response headers set by the handler.
+SEC("uprobe/response_Header")
+int uprobe_response_Header_Returns(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    struct uprobe_data_t *uprobe_data = bpf_map_lookup_elem(&http_server_uprobes, &key);
+    if (uprobe_data == NULL) {
+        return 0;
+    }
+    uprobe_data->resp_headers_ptr = (u64)get_argument(ctx, 1);
     return 0;
 }
@@ -13,6 +13,11 @@ import (
 	"github.com/cilium/ebpf"
 )
 
+type bpfHeaderCaptureKey struct {
+	_    structs.HostLayout
+	Name [32]int8
+}
+
 type bpfSliceArrayBuff struct {
 	_    structs.HostLayout
 	Buff [1024]uint8
@@ -26,23 +31,86 @@ type bpfSpanContext struct {
 	Padding    [7]uint8
 }
 
+type bpfSpanState struct {
+	_   structs.HostLayout
+	Ots struct {
+		_       structs.HostLayout
+		Th      uint64
+		Rv      uint64
+		HasTh   uint8
+		HasRv   uint8
+		Padding [6]uint8
+	}
+	Ts struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Entries [128]int8
+	}
+	Bg struct {
+		_       structs.HostLayout
+		Len     uint32
+		Padding [4]uint8
+		Value   [128]int8
+	}
+}
+
 type bpfUprobeDataT struct {
 	_    structs.HostLayout
 	Span struct {
-		_           structs.HostLayout
-		StartTime   uint64
-		EndTime     uint64
-		Sc          bpfSpanContext
-		Psc         bpfSpanContext
-		StatusCode  uint64
-		Method      [8]int8
-		Path        [128]int8
-		PathPattern [128]int8
-		RemoteAddr  [256]int8
-		Host        [256]int8
-		Proto       [8]int8
+		_            structs.HostLayout
+		StartTime    uint64
+		EndTime      uint64
+		Sc           bpfSpanContext
+		Psc          bpfSpanContext
+		State        bpfSpanState
+		StatusCode   uint64
+		Method       [16]int8
+		Path         [128]int8
+		PathPattern  [128]int8
+		RemoteAddr   [256]int8
+		Host         [256]int8
+		Proto        [8]int8
+		RawQuery     [128]int8
+		KnownHeaders struct {
+			_            structs.HostLayout
+			UserAgent    [128]int8
+			ForwardedFor [128]int8
+			Forwarded    [128]int8
+		}
+		RequestContentLength int64
+		ResponseBodySize     int64
+		Tls                  uint8
+		Padding              [7]uint8
+		RequestHeaders       struct {
+			_       structs.HostLayout
+			Count   uint32
+			Padding [4]uint8
+			Headers [8]struct {
+				_        structs.HostLayout
+				Name     [32]int8
+				Value    [128]int8
+				ValueLen uint32
+				Redacted uint8
+				Padding  [3]uint8
+			}
+		}
+		ResponseHeaders struct {
+			_       structs.HostLayout
+			Count   uint32
+			Padding [4]uint8
+			Headers [8]struct {
+				_        structs.HostLayout
+				Name     [32]int8
+				Value    [128]int8
+				ValueLen uint32
+				Redacted uint8
+				Padding  [3]uint8
+			}
+		}
 	}
-	RespPtr uint64
+	RespPtr        uint64
+	RespHeadersPtr uint64
 }
 
 // loadBpf returns the embedded CollectionSpec for bpf.
@@ -87,6 +155,7 @@ type bpfSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfProgramSpecs struct {
+	UprobeResponseHeaderReturns                        *ebpf.ProgramSpec `ebpf:"uprobe_response_Header_Returns"`
 	UprobeServerHandlerServeHTTP                       *ebpf.ProgramSpec `ebpf:"uprobe_serverHandler_ServeHTTP"`
 	UprobeServerHandlerServeHTTP_Returns               *ebpf.ProgramSpec `ebpf:"uprobe_serverHandler_ServeHTTP_Returns"`
 	UprobeTextprotoReaderReadContinuedLineSliceReturns *ebpf.ProgramSpec `ebpf:"uprobe_textproto_Reader_readContinuedLineSlice_Returns"`
@@ -96,17 +165,26 @@ type bpfProgramSpecs struct {
 //
 // It can be passed ebpf.CollectionSpec.Assign.
 type bpfMapSpecs struct {
-	AllocMap                   *ebpf.MapSpec `ebpf:"alloc_map"`
-	Events                     *ebpf.MapSpec `ebpf:"events"`
-	GoContextToSc              *ebpf.MapSpec `ebpf:"go_context_to_sc"`
-	GolangMapbucketStorageMap  *ebpf.MapSpec `ebpf:"golang_mapbucket_storage_map"`
-	HttpServerContextHeaders   *ebpf.MapSpec `ebpf:"http_server_context_headers"`
-	HttpServerUprobeStorageMap *ebpf.MapSpec `ebpf:"http_server_uprobe_storage_map"`
-	HttpServerUprobes          *ebpf.MapSpec `ebpf:"http_server_uprobes"`
-	ProbeActiveSamplerMap      *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap          *ebpf.MapSpec `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap          *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
-	TrackedSpansBySc           *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.MapSpec `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.MapSpec `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.MapSpec `ebpf:"events"`
+	GoContextToSc                  *ebpf.MapSpec `ebpf:"go_context_to_sc"`
+	GolangMapbucketStorageMap      *ebpf.MapSpec `ebpf:"golang_mapbucket_storage_map"`
+	GoroutineToSc                  *ebpf.MapSpec `ebpf:"goroutine_to_sc"`
+	HeaderCaptureMap               *ebpf.MapSpec `ebpf:"header_capture_map"`
+	HttpServerContextHeaders       *ebpf.MapSpec `ebpf:"http_server_context_headers"`
+	HttpServerUprobeStorageMap     *ebpf.MapSpec `ebpf:"http_server_uprobe_storage_map"`
+	HttpServerUprobes              *ebpf.MapSpec `ebpf:"http_server_uprobes"`
+	ProbeActiveSamplerMap          *ebpf.MapSpec `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.MapSpec `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.MapSpec `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.MapSpec `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.MapSpec `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.MapSpec `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.MapSpec `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.MapSpec `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc          *ebpf.MapSpec `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.MapSpec `ebpf:"tracked_spans_by_sc"`
 }
 
 // bpfVariableSpecs contains global variables before they are loaded into the kernel.
@@ -124,11 +202,16 @@ type bpfVariableSpecs struct {
 	PathPtrPos                 *ebpf.VariableSpec `ebpf:"path_ptr_pos"`
 	PatternPathPublicSupported *ebpf.VariableSpec `ebpf:"pattern_path_public_supported"`
 	PatternPathSupported       *ebpf.VariableSpec `ebpf:"pattern_path_supported"`
+	Propagators                *ebpf.VariableSpec `ebpf:"propagators"`
 	ProtoPos                   *ebpf.VariableSpec `ebpf:"proto_pos"`
+	RawQueryPos                *ebpf.VariableSpec `ebpf:"raw_query_pos"`
 	RemoteAddrPos              *ebpf.VariableSpec `ebpf:"remote_addr_pos"`
+	ReqContentLengthPos        *ebpf.VariableSpec `ebpf:"req_content_length_pos"`
 	ReqPatPos                  *ebpf.VariableSpec `ebpf:"req_pat_pos"`
 	ReqPatternPos              *ebpf.VariableSpec `ebpf:"req_pattern_pos"`
 	ReqPtrPos                  *ebpf.VariableSpec `ebpf:"req_ptr_pos"`
+	ReqTlsPos                  *ebpf.VariableSpec `ebpf:"req_tls_pos"`
+	RespWrittenPos             *ebpf.VariableSpec `ebpf:"resp_written_pos"`
 	StartAddr                  *ebpf.VariableSpec `ebpf:"start_addr"`
 	StatusCodePos              *ebpf.VariableSpec `ebpf:"status_code_pos"`
 	SwissMapsUsed              *ebpf.VariableSpec `ebpf:"swiss_maps_used"`
@@ -156,31 +239,49 @@ func (o *bpfObjects) Close() error {
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfMaps struct {
-	AllocMap                   *ebpf.Map `ebpf:"alloc_map"`
-	Events                     *ebpf.Map `ebpf:"events"`
-	GoContextToSc              *ebpf.Map `ebpf:"go_context_to_sc"`
-	GolangMapbucketStorageMap  *ebpf.Map `ebpf:"golang_mapbucket_storage_map"`
-	HttpServerContextHeaders   *ebpf.Map `ebpf:"http_server_context_headers"`
-	HttpServerUprobeStorageMap *ebpf.Map `ebpf:"http_server_uprobe_storage_map"`
-	HttpServerUprobes          *ebpf.Map `ebpf:"http_server_uprobes"`
-	ProbeActiveSamplerMap      *ebpf.Map `ebpf:"probe_active_sampler_map"`
-	SamplersConfigMap          *ebpf.Map `ebpf:"samplers_config_map"`
-	SliceArrayBuffMap          *ebpf.Map `ebpf:"slice_array_buff_map"`
-	TrackedSpansBySc           *ebpf.Map `ebpf:"tracked_spans_by_sc"`
+	AllocMap                       *ebpf.Map `ebpf:"alloc_map"`
+	DroppedSpansMap                *ebpf.Map `ebpf:"dropped_spans_map"`
+	Events                         *ebpf.Map `ebpf:"events"`
+	GoContextToSc                  *ebpf.Map `ebpf:"go_context_to_sc"`
+	GolangMapbucketStorageMap      *ebpf.Map `ebpf:"golang_mapbucket_storage_map"`
+	GoroutineToSc                  *ebpf.Map `ebpf:"goroutine_to_sc"`
+	HeaderCaptureMap               *ebpf.Map `ebpf:"header_capture_map"`
+	HttpServerContextHeaders       *ebpf.Map `ebpf:"http_server_context_headers"`
+	HttpServerUprobeStorageMap     *ebpf.Map `ebpf:"http_server_uprobe_storage_map"`
+	HttpServerUprobes              *ebpf.Map `ebpf:"http_server_uprobes"`
+	ProbeActiveSamplerMap          *ebpf.Map `ebpf:"probe_active_sampler_map"`
+	PropagationExtractorStorageMap *ebpf.Map `ebpf:"propagation_extractor_storage_map"`
+	PropagationHeaderStorageMap    *ebpf.Map `ebpf:"propagation_header_storage_map"`
+	RateLimiterMap                 *ebpf.Map `ebpf:"rate_limiter_map"`
+	SamplersConfigMap              *ebpf.Map `ebpf:"samplers_config_map"`
+	SliceArrayBuffMap              *ebpf.Map `ebpf:"slice_array_buff_map"`
+	SpanOutputConfigMap            *ebpf.Map `ebpf:"span_output_config_map"`
+	SpanStateStorageMap            *ebpf.Map `ebpf:"span_state_storage_map"`
+	TrackedSpanStatesBySc          *ebpf.Map `ebpf:"tracked_span_states_by_sc"`
+	TrackedSpansBySc               *ebpf.Map `ebpf:"tracked_spans_by_sc"`
 }
 
 func (m *bpfMaps) Close() error {
 	return _BpfClose(
 		m.AllocMap,
+		m.DroppedSpansMap,
 		m.Events,
 		m.GoContextToSc,
 		m.GolangMapbucketStorageMap,
+		m.GoroutineToSc,
+		m.HeaderCaptureMap,
 		m.HttpServerContextHeaders,
 		m.HttpServerUprobeStorageMap,
 		m.HttpServerUprobes,
 		m.ProbeActiveSamplerMap,
+		m.PropagationExtractorStorageMap,
+		m.PropagationHeaderStorageMap,
+		m.RateLimiterMap,
 		m.SamplersConfigMap,
 		m.SliceArrayBuffMap,
+		m.SpanOutputConfigMap,
+		m.SpanStateStorageMap,
+		m.TrackedSpanStatesBySc,
 		m.TrackedSpansBySc,
 	)
 }
@@ -200,11 +301,16 @@ type bpfVariables struct {
 	PathPtrPos                 *ebpf.Variable `ebpf:"path_ptr_pos"`
 	PatternPathPublicSupported *ebpf.Variable `ebpf:"pattern_path_public_supported"`
 	PatternPathSupported       *ebpf.Variable `ebpf:"pattern_path_supported"`
+	Propagators                *ebpf.Variable `ebpf:"propagators"`
 	ProtoPos                   *ebpf.Variable `ebpf:"proto_pos"`
+	RawQueryPos                *ebpf.Variable `ebpf:"raw_query_pos"`
 	RemoteAddrPos              *ebpf.Variable `ebpf:"remote_addr_pos"`
+	ReqContentLengthPos        *ebpf.Variable `ebpf:"req_content_length_pos"`
 	ReqPatPos                  *ebpf.Variable `ebpf:"req_pat_pos"`
 	ReqPatternPos              *ebpf.Variable `ebpf:"req_pattern_pos"`
 	ReqPtrPos                  *ebpf.Variable `ebpf:"req_ptr_pos"`
+	ReqTlsPos                  *ebpf.Variable `ebpf:"req_tls_pos"`
+	RespWrittenPos             *ebpf.Variable `ebpf:"resp_written_pos"`
 	StartAddr                  *ebpf.Variable `ebpf:"start_addr"`
 	StatusCodePos              *ebpf.Variable `ebpf:"status_code_pos"`
 	SwissMapsUsed              *ebpf.Variable `ebpf:"swiss_maps_used"`
@@ -216,6 +322,7 @@ type bpfVariables struct {
 //
 // It can be passed to loadBpfObjects or ebpf.CollectionSpec.LoadAndAssign.
 type bpfPrograms struct {
+	UprobeResponseHeaderReturns                        *ebpf.Program `ebpf:"uprobe_response_Header_Returns"`
 	UprobeServerHandlerServeHTTP                       *ebpf.Program `ebpf:"uprobe_serverHandler_ServeHTTP"`
 	UprobeServerHandlerServeHTTP_Returns               *ebpf.Program `ebpf:"uprobe_serverHandler_ServeHTTP_Returns"`
 	UprobeTextprotoReaderReadContinuedLineSliceReturns *ebpf.Program `ebpf:"uprobe_textproto_Reader_readContinuedLineSlice_Returns"`
@@ -223,6 +330,7 @@ type bpfPrograms struct {
 
 func (p *bpfPrograms) Close() error {
 	return _BpfClose(
+		p.UprobeResponseHeaderReturns,
 		p.UprobeServerHandlerServeHTTP,
 		p.UprobeServerHandlerServeHTTP_Returns,
 		p.UprobeTextprotoReaderReadContinuedLineSliceReturns,
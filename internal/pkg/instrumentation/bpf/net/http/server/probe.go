@@ -6,12 +6,13 @@ package server
 
 import (
 	"log/slog"
+	"os"
+	"strconv"
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
-	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sys/unix"
@@ -31,22 +32,23 @@ import (
 // pkg is the package being instrumented.
 const pkg = "net/http"
 
-var (
-	goMapsVersion = semver.New(1, 24, 0, "", "")
-
-	goWithSwissMaps = probe.PackageConstraints{
-		Package: "std",
-		Constraints: func() *semver.Constraints {
-			c, err := semver.NewConstraint(">= " + goMapsVersion.String())
-			if err != nil {
-				panic(err)
-			}
-			return c
-		}(),
-		// Don't warn, we have a backup path.
-		FailureMode: probe.FailureModeIgnore,
-	}
-)
+// QueryEnvVar is the environment variable to opt-in to recording the query of
+// server request URLs as the url.query attribute. The query is redacted
+// according to the URL redaction configuration.
+const QueryEnvVar = "OTEL_GO_AUTO_HTTP_SERVER_QUERY"
+
+var goWithSwissMaps = probe.PackageConstraints{
+	Package: "std",
+	Constraints: func() *semver.Constraints {
+		c, err := semver.NewConstraint(">= " + probe.GoSwissMapsVersion.String())
+		if err != nil {
+			panic(err)
+		}
+		return c
+	}(),
+	// Don't warn, we have a backup path.
+	FailureMode: probe.FailureModeIgnore,
+}
 
 // New returns a new [probe.Probe].
 func New(logger *slog.Logger, version string) probe.Probe {
@@ -54,11 +56,26 @@ func New(logger *slog.Logger, version string) probe.Probe {
 		SpanKind:        trace.SpanKindServer,
 		InstrumentedPkg: pkg,
 	}
+
+	redactor, err := http.RedactorFromEnv()
+	if err != nil {
+		logger.Error("invalid URL redaction configuration", "error", err)
+	}
+
+	var recordQuery bool
+	if v, ok := os.LookupEnv(QueryEnvVar); ok {
+		recordQuery, err = strconv.ParseBool(v)
+		if err != nil {
+			logger.Error("invalid environment variable value", "name", QueryEnvVar, "value", v, "error", err)
+		}
+	}
+
 	return &probe.SpanProducer[bpfObjects, event]{
 		Base: probe.Base[bpfObjects, event]{
 			ID:     id,
 			Logger: logger,
 			Consts: []probe.Const{
+				probe.PropagatorsConst{},
 				probe.StructFieldConst{
 					Key: "method_ptr_pos",
 					ID:  structfield.NewID("std", "net/http", "Request", "Method"),
@@ -75,6 +92,10 @@ func New(logger *slog.Logger, version string) probe.Probe {
 					Key: "path_ptr_pos",
 					ID:  structfield.NewID("std", "net/url", "URL", "Path"),
 				},
+				probe.StructFieldConst{
+					Key: "raw_query_pos",
+					ID:  structfield.NewID("std", "net/url", "URL", "RawQuery"),
+				},
 				probe.StructFieldConst{
 					Key: "headers_ptr_pos",
 					ID:  structfield.NewID("std", "net/http", "Request", "Header"),
@@ -92,7 +113,7 @@ func New(logger *slog.Logger, version string) probe.Probe {
 						Key: "buckets_ptr_pos",
 						ID:  structfield.NewID("std", "runtime", "hmap", "buckets"),
 					},
-					MaxVersion: goMapsVersion,
+					MaxVersion: probe.GoSwissMapsVersion,
 				},
 				probe.StructFieldConst{
 					Key: "remote_addr_pos",
@@ -106,6 +127,18 @@ func New(logger *slog.Logger, version string) probe.Probe {
 					Key: "proto_pos",
 					ID:  structfield.NewID("std", "net/http", "Request", "Proto"),
 				},
+				probe.StructFieldConst{
+					Key: "req_tls_pos",
+					ID:  structfield.NewID("std", "net/http", "Request", "TLS"),
+				},
+				probe.StructFieldConst{
+					Key: "req_content_length_pos",
+					ID:  structfield.NewID("std", "net/http", "Request", "ContentLength"),
+				},
+				probe.StructFieldConst{
+					Key: "resp_written_pos",
+					ID:  structfield.NewID("std", "net/http", "response", "written"),
+				},
 				probe.StructFieldConstMinVersion{
 					StructField: probe.StructFieldConst{
 						Key: "req_pattern_pos",
@@ -129,7 +162,7 @@ func New(logger *slog.Logger, version string) probe.Probe {
 				},
 				patternPathPublicSupportedConst{},
 				patternPathSupportedConst{},
-				swissMapsUsedConst{},
+				probe.SwissMapsConst{},
 			},
 			Uprobes: []*probe.Uprobe{
 				{
@@ -145,12 +178,20 @@ func New(logger *slog.Logger, version string) probe.Probe {
 					},
 					DependsOn: []string{"net/http.serverHandler.ServeHTTP"},
 				},
+				{
+					Sym:         "net/http.(*response).Header",
+					ReturnProbe: "uprobe_response_Header_Returns",
+					DependsOn:   []string{"net/http.serverHandler.ServeHTTP"},
+					FailureMode: probe.FailureModeWarn,
+				},
 			},
 			SpecFn: loadBpf,
 		},
 		Version:   version,
 		SchemaURL: semconv.SchemaURL,
-		ProcessFn: processFn,
+		ProcessFn: func(e *event) ptrace.SpanSlice {
+			return processFn(redactor, recordQuery, e)
+		},
 	}
 }
 
@@ -178,29 +219,33 @@ func (c patternPathSupportedConst) InjectOption(info *process.Info) (inject.Opti
 	return inject.WithKeyValue("pattern_path_supported", isPatternPathSupported), nil
 }
 
-type swissMapsUsedConst struct{}
-
-func (c swissMapsUsedConst) InjectOption(info *process.Info) (inject.Option, error) {
-	isUsingGoSwissMaps := info.GoVersion.GreaterThanEqual(goMapsVersion)
-	return inject.WithKeyValue("swiss_maps_used", isUsingGoSwissMaps), nil
-}
-
 // event represents an event in an HTTP server during an HTTP
 // request-response.
 type event struct {
 	context.BaseSpanProperties
-	StatusCode  uint64
-	Method      [8]byte
-	Path        [128]byte
-	PathPattern [128]byte
-	RemoteAddr  [256]byte
-	Host        [256]byte
-	Proto       [8]byte
+	StatusCode           uint64
+	Method               [16]byte
+	Path                 [128]byte
+	PathPattern          [128]byte
+	RemoteAddr           [256]byte
+	Host                 [256]byte
+	Proto                [8]byte
+	RawQuery             [128]byte
+	KnownHeaders         probe.KnownHeaders
+	RequestContentLength int64
+	ResponseBodySize     int64
+	TLS                  uint8
+	_                    [7]byte
+	ReqHeaders           probe.CapturedHeaders
+	RespHeaders          probe.CapturedHeaders
 }
 
-func processFn(e *event) ptrace.SpanSlice {
-	path := unix.ByteSliceToString(e.Path[:])
-	method := unix.ByteSliceToString(e.Method[:])
+// processFn converts e into a span. The URL path and query attributes of the
+// span are redacted using r. The query is only recorded if recordQuery is
+// true.
+func processFn(r *http.Redactor, recordQuery bool, e *event) ptrace.SpanSlice {
+	path := r.Path(unix.ByteSliceToString(e.Path[:]))
+	method, methodAttrs := http.MethodAttributes(unix.ByteSliceToString(e.Method[:]))
 	patternPath := unix.ByteSliceToString(e.PathPattern[:])
 
 	isValidPatternPath := true
@@ -216,15 +261,38 @@ func processFn(e *event) ptrace.SpanSlice {
 	if e.StatusCode > maxStatus {
 		e.StatusCode = 0
 	}
-	attrs := []attribute.KeyValue{
-		semconv.HTTPRequestMethodKey.String(method),
-		semconv.URLPath(path),
-		semconv.HTTPResponseStatusCodeKey.Int(
-			int(e.StatusCode),
-		), //nolint:gosec  // Bound checked.
+	attrs := append(methodAttrs, semconv.URLPath(path))
+	if e.TLS != 0 {
+		attrs = append(attrs, semconv.URLScheme("https"))
+	} else {
+		attrs = append(attrs, semconv.URLScheme("http"))
+	}
+	if recordQuery {
+		if query := r.Query(unix.ByteSliceToString(e.RawQuery[:])); query != "" {
+			attrs = append(attrs, semconv.URLQuery(query))
+		}
+	}
+	attrs = append(attrs, semconv.HTTPResponseStatusCodeKey.Int(
+		int(e.StatusCode), //nolint:gosec  // Bound checked.
+	))
+	if e.StatusCode >= 500 {
+		attrs = append(attrs, semconv.ErrorTypeKey.String(strconv.FormatUint(e.StatusCode, 10)))
+	}
+
+	// Address and port of the original client, possibly behind proxies.
+	clientAddr, clientPort := http.ClientAddressPortAttributes(
+		e.KnownHeaders.Forwarded[:],
+		e.KnownHeaders.ForwardedFor[:],
+		e.RemoteAddr[:],
+	)
+	if clientAddr.Valid() {
+		attrs = append(attrs, clientAddr)
+	}
+	if clientPort.Valid() {
+		attrs = append(attrs, clientPort)
 	}
 
-	// Client address and port
+	// Address and port of the peer of the connection.
 	peerAddr, peerPort := http.NetPeerAddressPortAttributes(e.RemoteAddr[:])
 	if peerAddr.Valid() {
 		attrs = append(attrs, peerAddr)
@@ -252,6 +320,16 @@ func processFn(e *event) ptrace.SpanSlice {
 		}
 	}
 
+	if ua := unix.ByteSliceToString(e.KnownHeaders.UserAgent[:]); ua != "" {
+		attrs = append(attrs, semconv.UserAgentOriginal(ua))
+	}
+	if e.RequestContentLength > 0 {
+		attrs = append(attrs, semconv.HTTPRequestBodySize(int(e.RequestContentLength)))
+	}
+	if e.ResponseBodySize > 0 {
+		attrs = append(attrs, semconv.HTTPResponseBodySize(int(e.ResponseBodySize)))
+	}
+
 	spanName := method
 	if isPatternPathSupported && isValidPatternPath {
 		spanName = spanName + " " + patternPath
@@ -271,10 +349,13 @@ func processFn(e *event) ptrace.SpanSlice {
 	if e.ParentSpanContext.SpanID.IsValid() {
 		span.SetParentSpanID(pcommon.SpanID(e.ParentSpanContext.SpanID))
 	}
+	span.TraceState().FromRaw(e.State.TraceState())
 
 	pdataconv.Attributes(span.Attributes(), attrs...)
+	e.ReqHeaders.PutAttributes(span.Attributes(), "http.request.header.")
+	e.RespHeaders.PutAttributes(span.Attributes(), "http.response.header.")
 
-	if e.StatusCode >= 500 && e.StatusCode < 600 {
+	if e.StatusCode >= 500 {
 		span.Status().SetCode(ptrace.StatusCodeError)
 	}
 
@@ -14,6 +14,7 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 	"go.opentelemetry.io/otel/trace"
 
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/net/http"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/context"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/kernel"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/pdataconv"
@@ -44,7 +45,7 @@ func TestProbeConvertEvent(t *testing.T) {
 				},
 				StatusCode: 200,
 				// "GET"
-				Method: [8]byte{0x47, 0x45, 0x54},
+				Method: [16]byte{0x47, 0x45, 0x54},
 				// "/foo/bar"
 				Path: [128]byte{0x2f, 0x66, 0x6f, 0x6f, 0x2f, 0x62, 0x61, 0x72},
 				// "www.google.com:8080"
@@ -74,7 +75,10 @@ func TestProbeConvertEvent(t *testing.T) {
 					span.Attributes(),
 					semconv.HTTPRequestMethodKey.String("GET"),
 					semconv.URLPath("/foo/bar"),
+					semconv.URLScheme("http"),
 					semconv.HTTPResponseStatusCodeKey.Int(200),
+					semconv.ClientAddress("www.google.com"),
+					semconv.ClientPort(8080),
 					semconv.NetworkPeerAddress("www.google.com"),
 					semconv.NetworkPeerPort(8080),
 					semconv.ServerAddress("localhost"),
@@ -95,7 +99,7 @@ func TestProbeConvertEvent(t *testing.T) {
 				},
 				StatusCode: 200,
 				// "GET"
-				Method: [8]byte{0x47, 0x45, 0x54},
+				Method: [16]byte{0x47, 0x45, 0x54},
 				// "/foo/bar"
 				Path: [128]byte{0x2f, 0x66, 0x6f, 0x6f, 0x2f, 0x62, 0x61, 0x72},
 				// "www.google.com:8080"
@@ -125,7 +129,10 @@ func TestProbeConvertEvent(t *testing.T) {
 					span.Attributes(),
 					semconv.HTTPRequestMethodKey.String("GET"),
 					semconv.URLPath("/foo/bar"),
+					semconv.URLScheme("http"),
 					semconv.HTTPResponseStatusCodeKey.Int(200),
+					semconv.ClientAddress("www.google.com"),
+					semconv.ClientPort(8080),
 					semconv.NetworkPeerAddress("www.google.com"),
 					semconv.NetworkPeerPort(8080),
 					semconv.ServerAddress("localhost"),
@@ -147,7 +154,7 @@ func TestProbeConvertEvent(t *testing.T) {
 				},
 				StatusCode: 400,
 				// "GET"
-				Method: [8]byte{0x47, 0x45, 0x54},
+				Method: [16]byte{0x47, 0x45, 0x54},
 				// "/foo/bar"
 				Path: [128]byte{0x2f, 0x66, 0x6f, 0x6f, 0x2f, 0x62, 0x61, 0x72},
 				// "www.google.com:8080"
@@ -177,7 +184,10 @@ func TestProbeConvertEvent(t *testing.T) {
 					span.Attributes(),
 					semconv.HTTPRequestMethodKey.String("GET"),
 					semconv.URLPath("/foo/bar"),
+					semconv.URLScheme("http"),
 					semconv.HTTPResponseStatusCodeKey.Int(400),
+					semconv.ClientAddress("www.google.com"),
+					semconv.ClientPort(8080),
 					semconv.NetworkPeerAddress("www.google.com"),
 					semconv.NetworkPeerPort(8080),
 					semconv.ServerAddress("localhost"),
@@ -198,7 +208,7 @@ func TestProbeConvertEvent(t *testing.T) {
 				},
 				StatusCode: 500,
 				// "GET"
-				Method: [8]byte{0x47, 0x45, 0x54},
+				Method: [16]byte{0x47, 0x45, 0x54},
 				// "/foo/bar"
 				Path: [128]byte{0x2f, 0x66, 0x6f, 0x6f, 0x2f, 0x62, 0x61, 0x72},
 				// "www.google.com:8080"
@@ -229,7 +239,11 @@ func TestProbeConvertEvent(t *testing.T) {
 					span.Attributes(),
 					semconv.HTTPRequestMethodKey.String("GET"),
 					semconv.URLPath("/foo/bar"),
+					semconv.URLScheme("http"),
 					semconv.HTTPResponseStatusCodeKey.Int(500),
+					semconv.ErrorTypeKey.String("500"),
+					semconv.ClientAddress("www.google.com"),
+					semconv.ClientPort(8080),
 					semconv.NetworkPeerAddress("www.google.com"),
 					semconv.NetworkPeerPort(8080),
 					semconv.ServerAddress("localhost"),
@@ -244,8 +258,52 @@ func TestProbeConvertEvent(t *testing.T) {
 
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
-			out := processFn(tt.event)
+			out := processFn(http.DefaultRedactor(), false, tt.event)
 			assert.Equal(t, tt.expected, out)
 		})
 	}
 }
+
+func TestProbeConvertEventRequestAttributes(t *testing.T) {
+	e := &event{
+		BaseSpanProperties: context.BaseSpanProperties{
+			SpanContext: context.EBPFSpanContext{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{1}},
+		},
+		StatusCode:           503,
+		RequestContentLength: 42,
+		ResponseBodySize:     1024,
+		TLS:                  1,
+	}
+	copy(e.Method[:], "PURGE")
+	copy(e.Path[:], "/foo")
+	copy(e.RawQuery[:], "q=1&sig=secret")
+	copy(e.RemoteAddr[:], "10.0.0.1:8080")
+	copy(e.Host[:], "localhost:8443")
+	copy(e.KnownHeaders.UserAgent[:], "curl/8.5.0")
+	copy(e.KnownHeaders.ForwardedFor[:], "203.0.113.195, 70.41.3.18")
+
+	span := processFn(http.DefaultRedactor(), true, e).At(0)
+	assert.Equal(t, "HTTP", span.Name())
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, map[string]any{
+		"http.request.method":          "_OTHER",
+		"http.request.method_original": "PURGE",
+		"url.path":                     "/foo",
+		"url.scheme":                   "https",
+		"url.query":                    "q=1&sig=REDACTED",
+		"http.response.status_code":    int64(503),
+		"error.type":                   "503",
+		"client.address":               "203.0.113.195",
+		"network.peer.address":         "10.0.0.1",
+		"network.peer.port":            int64(8080),
+		"server.address":               "localhost",
+		"server.port":                  int64(8443),
+		"user_agent.original":          "curl/8.5.0",
+		"http.request.body.size":       int64(42),
+		"http.response.body.size":      int64(1024),
+	}, span.Attributes().AsRaw())
+
+	span = processFn(http.DefaultRedactor(), false, e).At(0)
+	_, ok := span.Attributes().Get("url.query")
+	assert.False(t, ok, "url.query recorded without opt-in")
+}
@@ -0,0 +1,75 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+#include "arguments.h"
+#include "trace/span_context.h"
+#include "go_context.h"
+
+char __license[] SEC("license") = "Dual MIT/GPL";
+
+// The maximum number of goroutines concurrently started. The goroutines are
+// started on the system stack, so this is bounded by the number of threads.
+#define MAX_CONCURRENT_NEWPROC 1024
+
+// The goroutine starting a new goroutine, keyed by the goroutine running
+// newproc1.
+struct {
+    __uint(type, BPF_MAP_TYPE_LRU_HASH);
+    __type(key, void *);
+    __type(value, void *);
+    __uint(max_entries, MAX_CONCURRENT_NEWPROC);
+} newproc_callers SEC(".maps");
+
+// This instrumentation attaches uprobe to the following function:
+// func newproc1(fn *funcval, callergp *g, callerpc uintptr, parked bool, waitreason waitReason) *g
+// The parked and waitreason arguments were added in Go 1.21.
+SEC("uprobe/newproc1")
+int uprobe_newproc1(struct pt_regs *ctx) {
+    void *callergp = get_argument(ctx, 2);
+    if (callergp == NULL || get_goroutine_span_context(callergp) == NULL) {
+        // The caller has no span context to inherit.
+        return 0;
+    }
+
+    // newproc1 is run on the system stack, the key is its g0.
+    void *key = (void *)GOROUTINE(ctx);
+    bpf_map_update_elem(&newproc_callers, &key, &callergp, BPF_ANY);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the return of the following function:
+// func newproc1(fn *funcval, callergp *g, callerpc uintptr, parked bool, waitreason waitReason) *g
+SEC("uprobe/newproc1")
+int uprobe_newproc1_Returns(struct pt_regs *ctx) {
+    void *key = (void *)GOROUTINE(ctx);
+    void **callergp_ptr = bpf_map_lookup_elem(&newproc_callers, &key);
+    if (callergp_ptr == NULL) {
+        return 0;
+    }
+    void *callergp = *callergp_ptr;
+    bpf_map_delete_elem(&newproc_callers, &key);
+
+    void *newg = get_argument(ctx, 1);
+    if (newg == NULL) {
+        return 0;
+    }
+
+    struct span_context *sc = get_goroutine_span_context(callergp);
+    if (sc == NULL) {
+        return 0;
+    }
+    // Copy the span context, the update can evict the entry it is read from.
+    struct span_context inherited = *sc;
+    start_tracking_goroutine_span(newg, &inherited);
+    return 0;
+}
+
+// This instrumentation attaches uprobe to the following function:
+// func goexit1()
+// It is run on the exiting goroutine, whose g is reused for new goroutines.
+SEC("uprobe/goexit1")
+int uprobe_goexit1(struct pt_regs *ctx) {
+    void *goroutine = (void *)GOROUTINE(ctx);
+    bpf_map_delete_elem(&goroutine_to_sc, &goroutine);
+    return 0;
+}
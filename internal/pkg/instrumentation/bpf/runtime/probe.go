@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package runtime provides an instrumentation probe tracking the goroutines
+// started by the [runtime] so they inherit the span context of the goroutine
+// starting them.
+//
+// Spans started in a goroutine without a span context in their
+// [context.Context], like outgoing requests made without the context of the
+// request being handled, are parented to the span inherited by the goroutine.
+//
+// The inherited span context is only used while the span it was inherited
+// from has not ended. Long-lived goroutines started while a request is
+// handled, like the ones of a worker pool or of the connections of an
+// [net/http.Transport], no longer parent the spans they start without a span
+// context to the span of that request once it has ended.
+package runtime
+
+import (
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
+)
+
+//go:generate go run github.com/cilium/ebpf/cmd/bpf2go -target amd64,arm64 bpf ./bpf/probe.bpf.c
+
+// pkg is the package being instrumented.
+const pkg = "runtime"
+
+// New returns a new [probe.Probe].
+func New(logger *slog.Logger) probe.Probe {
+	id := probe.ID{
+		SpanKind:        trace.SpanKindInternal,
+		InstrumentedPkg: pkg,
+	}
+	return &probe.Tracker[bpfObjects]{
+		Base: probe.Base[bpfObjects, struct{}]{
+			ID:     id,
+			Logger: logger,
+			Uprobes: []*probe.Uprobe{
+				{
+					Sym:         "runtime.newproc1",
+					EntryProbe:  "uprobe_newproc1",
+					ReturnProbe: "uprobe_newproc1_Returns",
+					FailureMode: probe.FailureModeWarn,
+				},
+				{
+					Sym:         "runtime.goexit1",
+					EntryProbe:  "uprobe_goexit1",
+					FailureMode: probe.FailureModeWarn,
+				},
+			},
+			SpecFn: loadBpf,
+		},
+	}
+}
@@ -5,6 +5,7 @@ package instrumentation
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 
@@ -26,6 +27,22 @@ type Library struct {
 	// TracesEnabled determines whether traces are enabled for the instrumentation library.
 	// if nil - take DefaultTracesDisabled value.
 	TracesEnabled *bool
+	// MinDuration is the minimum duration of the spans exported by the
+	// instrumentation library. Shorter spans are dropped in eBPF, but their
+	// context is still propagated. If zero, all spans are exported.
+	MinDuration time.Duration
+	// CaptureRequestHeaders are the names of the request headers recorded as
+	// span attributes by the instrumentation library. Names are matched
+	// case-insensitively.
+	CaptureRequestHeaders []string
+	// CaptureResponseHeaders are the names of the response headers recorded
+	// as span attributes by the instrumentation library. Names are matched
+	// case-insensitively.
+	CaptureResponseHeaders []string
+	// RedactHeaders are the names of the captured headers whose values are
+	// replaced by "REDACTED". The Authorization, Proxy-Authorization, Cookie
+	// and Set-Cookie headers are always redacted.
+	RedactHeaders []string
 }
 
 // Config is used to configure instrumentation.
@@ -4,7 +4,21 @@
 // Package context contains tracing types used among probes.
 package context // nolint:revive  // Internal package name.
 
-import "go.opentelemetry.io/otel/trace"
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// traceStateMaxLen is the maximum length of the tracestate list members
+	// recorded by eBPF other than the "ot" one.
+	traceStateMaxLen = 128
+
+	// baggageMaxLen is the maximum length of the baggage recorded by eBPF.
+	baggageMaxLen = 128
+)
 
 // BaseSpanProperties contains the basic attributes filled by all probes.
 type BaseSpanProperties struct {
@@ -12,6 +26,13 @@ type BaseSpanProperties struct {
 	EndTime           uint64
 	SpanContext       EBPFSpanContext
 	ParentSpanContext EBPFSpanContext
+	State             SpanState
+}
+
+// Baggage returns the W3C baggage header value propagated with the span of
+// p, or an empty string if there is none.
+func (p *BaseSpanProperties) Baggage() string {
+	return p.State.Baggage.String()
 }
 
 // EBPFSpanContext is the the span context representation within the eBPF
@@ -22,3 +43,83 @@ type EBPFSpanContext struct {
 	TraceFlags trace.TraceFlags
 	_          [7]byte // padding
 }
+
+// SpanState is the state propagated with a span context, as recorded by eBPF.
+type SpanState struct {
+	OT      OTTraceState
+	Members TraceStateMembers
+	Baggage Baggage
+}
+
+// TraceState returns the W3C tracestate header value of s, or an empty
+// string if s has none. The "ot" list member, if any, is the first one,
+// followed by the other members propagated to the process.
+func (s *SpanState) TraceState() string {
+	ot, members := s.OT.String(), s.Members.String()
+	switch {
+	case ot == "":
+		return members
+	case members == "":
+		return ot
+	}
+	return ot + "," + members
+}
+
+// OTTraceState holds the values of the OpenTelemetry "ot" tracestate entry
+// used by consistent probability sampling.
+type OTTraceState struct {
+	// Threshold is the 56-bit rejection threshold used to sample the span.
+	Threshold uint64
+	// Randomness is the explicit 56-bit randomness of the trace.
+	Randomness    uint64
+	HasThreshold  uint8
+	HasRandomness uint8
+	_             [6]byte
+}
+
+// String returns the "ot" tracestate entry of s, or an empty string if s has
+// no values.
+func (s OTTraceState) String() string {
+	var subkeys []string
+	if s.HasThreshold != 0 {
+		th := strings.TrimRight(fmt.Sprintf("%014x", s.Threshold), "0")
+		if th == "" {
+			th = "0"
+		}
+		subkeys = append(subkeys, "th:"+th)
+	}
+	if s.HasRandomness != 0 {
+		subkeys = append(subkeys, fmt.Sprintf("rv:%014x", s.Randomness))
+	}
+	if len(subkeys) == 0 {
+		return ""
+	}
+	return "ot=" + strings.Join(subkeys, ";")
+}
+
+// TraceStateMembers holds the comma separated tracestate list members other
+// than the "ot" one.
+type TraceStateMembers struct {
+	Len     uint32
+	_       [4]byte
+	Entries [traceStateMaxLen]byte
+}
+
+// String returns the list members of m.
+func (m TraceStateMembers) String() string {
+	n := min(int(m.Len), len(m.Entries))
+	return string(m.Entries[:n])
+}
+
+// Baggage holds the W3C baggage list members propagated with a span context.
+type Baggage struct {
+	Len   uint32
+	_     [4]byte
+	Value [baggageMaxLen]byte
+}
+
+// String returns the W3C baggage header value of b.
+func (b Baggage) String() string {
+	n := min(int(b.Len), len(b.Value))
+	return string(b.Value[:n])
+}
@@ -0,0 +1,105 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package context // nolint:revive  // Internal package name.
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOTTraceStateString(t *testing.T) {
+	tests := []struct {
+		name string
+		s    OTTraceState
+		want string
+	}{
+		{name: "Empty", want: ""},
+		{
+			name: "Threshold",
+			s:    OTTraceState{Threshold: 0xc0000000000000, HasThreshold: 1},
+			want: "ot=th:c",
+		},
+		{
+			name: "ZeroThreshold",
+			s:    OTTraceState{HasThreshold: 1},
+			want: "ot=th:0",
+		},
+		{
+			name: "Randomness",
+			s:    OTTraceState{Randomness: 0x0123456789abcd, HasRandomness: 1},
+			want: "ot=rv:0123456789abcd",
+		},
+		{
+			name: "Both",
+			s: OTTraceState{
+				Threshold:     0xfd700000000000,
+				Randomness:    0xffffffffffffff,
+				HasThreshold:  1,
+				HasRandomness: 1,
+			},
+			want: "ot=th:fd7;rv:ffffffffffffff",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.s.String())
+		})
+	}
+}
+
+func TestTraceStateMembersString(t *testing.T) {
+	var m TraceStateMembers
+	assert.Equal(t, "", m.String())
+
+	n := copy(m.Entries[:], "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7")
+	m.Len = uint32(n)
+	assert.Equal(t, "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7", m.String())
+
+	// Invalid lengths are bounded by the buffer.
+	m.Len = traceStateMaxLen + 1
+	assert.Len(t, m.String(), traceStateMaxLen)
+}
+
+func TestBaggageString(t *testing.T) {
+	var b Baggage
+	assert.Equal(t, "", b.String())
+
+	b.Len = uint32(copy(b.Value[:], "tenant.id=acme"))
+	assert.Equal(t, "tenant.id=acme", b.String())
+
+	// Invalid lengths are bounded by the buffer.
+	b.Len = baggageMaxLen + 1
+	assert.Len(t, b.String(), baggageMaxLen)
+}
+
+func TestSpanStateTraceState(t *testing.T) {
+	ot := OTTraceState{Threshold: 0xc0000000000000, HasThreshold: 1}
+	var members TraceStateMembers
+	members.Len = uint32(copy(members.Entries[:], "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7"))
+
+	tests := []struct {
+		name string
+		s    SpanState
+		want string
+	}{
+		{name: "Empty"},
+		{name: "OT", s: SpanState{OT: ot}, want: "ot=th:c"},
+		{
+			name: "Members",
+			s:    SpanState{Members: members},
+			want: "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7",
+		},
+		{
+			name: "Both",
+			s:    SpanState{OT: ot, Members: members},
+			want: "ot=th:c,congo=t61rcWkgMzE,rojo=00f067aa0ba902b7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.s.TraceState())
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"log/slog"
 	"sync"
+	"time"
 
 	"github.com/cilium/ebpf/link"
 	"github.com/cilium/ebpf/rlimit"
@@ -187,6 +188,42 @@ func isProbeEnabled(id probe.ID, c Config) bool {
 	return !c.DefaultTracesDisabled
 }
 
+func probeMinDuration(id probe.ID, c Config) time.Duration {
+	pc, _ := getProbeConfig(id, c)
+	return pc.MinDuration
+}
+
+// updateMinDuration sets the minimum span duration configured in c for the
+// loaded probe p with id.
+func (m *Manager) updateMinDuration(id probe.ID, p probe.Probe, c Config) error {
+	fu, ok := p.(probe.SpanFilterUpdater)
+	if !ok {
+		return nil
+	}
+	m.logger.Debug("Updating probe minimum span duration", "id", id)
+	return fu.UpdateMinDuration(probeMinDuration(id, c))
+}
+
+func probeHeaderCapture(id probe.ID, c Config) probe.HeaderCapture {
+	pc, _ := getProbeConfig(id, c)
+	return probe.HeaderCapture{
+		Request:  pc.CaptureRequestHeaders,
+		Response: pc.CaptureResponseHeaders,
+		Redact:   pc.RedactHeaders,
+	}
+}
+
+// updateHeaderCapture sets the headers to capture configured in c for the
+// loaded probe p with id.
+func (m *Manager) updateHeaderCapture(id probe.ID, p probe.Probe, c Config) error {
+	hu, ok := p.(probe.HeaderCaptureUpdater)
+	if !ok {
+		return nil
+	}
+	m.logger.Debug("Updating probe captured headers", "id", id)
+	return hu.UpdateHeaderCapture(probeHeaderCapture(id, c))
+}
+
 func (m *Manager) applyConfig(c Config) error {
 	if m.proc == nil {
 		return errors.New("failed to apply config: target details not set")
@@ -217,10 +254,33 @@ func (m *Manager) applyConfig(c Config) error {
 			m.logger.Info("Enabling probe", "id", id)
 			err = errors.Join(err, p.Load(m.exe, m.proc, c.SamplingConfig))
 			if err == nil {
+				if probeMinDuration(id, c) > 0 {
+					err = errors.Join(err, m.updateMinDuration(id, p, c))
+				}
+				if !probeHeaderCapture(id, c).Empty() {
+					err = errors.Join(err, m.updateHeaderCapture(id, p, c))
+				}
 				m.runProbe(p)
 			}
 			continue
 		}
+
+		if currentlyEnabled && newEnabled && c.SamplingConfig != nil {
+			if su, ok := p.(probe.SamplingUpdater); ok {
+				m.logger.Debug("Updating probe sampling", "id", id)
+				err = errors.Join(err, su.UpdateSampling(c.SamplingConfig))
+			}
+		}
+
+		if currentlyEnabled && newEnabled &&
+			probeMinDuration(id, m.currentConfig) != probeMinDuration(id, c) {
+			err = errors.Join(err, m.updateMinDuration(id, p, c))
+		}
+
+		if currentlyEnabled && newEnabled &&
+			!probeHeaderCapture(id, m.currentConfig).Equal(probeHeaderCapture(id, c)) {
+			err = errors.Join(err, m.updateHeaderCapture(id, p, c))
+		}
 	}
 
 	return nil
@@ -391,6 +451,16 @@ func (m *Manager) loadProbes() error {
 				)
 				return errors.Join(err, m.cleanup())
 			}
+			if probeMinDuration(name, m.currentConfig) > 0 {
+				if err := m.updateMinDuration(name, i, m.currentConfig); err != nil {
+					m.logger.Error("failed to set minimum span duration", "error", err, "name", name)
+				}
+			}
+			if !probeHeaderCapture(name, m.currentConfig).Empty() {
+				if err := m.updateHeaderCapture(name, i, m.currentConfig); err != nil {
+					m.logger.Error("failed to set captured headers", "error", err, "name", name)
+				}
+			}
 		}
 	}
 
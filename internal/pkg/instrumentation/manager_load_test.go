@@ -19,6 +19,8 @@ import (
 
 	"go.opentelemetry.io/auto/internal/pkg/inject"
 	dbSql "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/database/sql"
+	pgx "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/jackc/pgx"
+	goRedis "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/redis/go-redis"
 	kafkaConsumer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/segmentio/kafka-go/consumer"
 	kafkaProducer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/segmentio/kafka-go/producer"
 	autosdk "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/go.opentelemetry.io/auto/sdk"
@@ -27,6 +29,7 @@ import (
 	grpcServer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/google.golang.org/grpc/server"
 	httpClient "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/net/http/client"
 	httpServer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/net/http/server"
+	goRuntime "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/runtime"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/bpffs"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/debug"
 	"go.opentelemetry.io/auto/internal/pkg/instrumentation/kernel"
@@ -60,10 +63,14 @@ func TestLoadProbes(t *testing.T) {
 		httpServer.New(logger, ""),
 		httpClient.New(logger, ""),
 		dbSql.New(logger, ""),
+		pgx.New(logger, ""),
+		goRedis.New(logger, ""),
+		goRedis.NewV8(logger, ""),
 		kafkaProducer.New(logger, ""),
 		kafkaConsumer.New(logger, ""),
 		autosdk.New(logger),
 		otelTraceGlobal.New(logger),
+		goRuntime.New(logger),
 	}
 
 	for _, p := range probes {
@@ -82,9 +89,9 @@ func TestLoadProbes(t *testing.T) {
 		})
 	}
 
-	// The grpcClient, grpcServer, httpClient, dbSql, kafkaProducer,
-	// kafkaConsumer, autosdk, and otelTraceGlobal all allocate. Ensure it has
-	// been called.
+	// The grpcClient, grpcServer, httpClient, dbSql, pgx, goRedis,
+	// kafkaProducer, kafkaConsumer, autosdk, and otelTraceGlobal all allocate.
+	// Ensure it has been called.
 	a, err := info.Alloc(logger)
 	require.NoError(t, err)
 	assert.NotEmpty(t, a.StartAddr, "memory not allocated")
@@ -23,6 +23,8 @@ import (
 	"go.opentelemetry.io/otel/trace"
 
 	dbSql "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/database/sql"
+	pgx "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/jackc/pgx"
+	goRedis "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/redis/go-redis"
 	kafkaConsumer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/segmentio/kafka-go/consumer"
 	kafkaProducer "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/github.com/segmentio/kafka-go/producer"
 	autosdk "go.opentelemetry.io/auto/internal/pkg/instrumentation/bpf/go.opentelemetry.io/auto/sdk"
@@ -158,6 +160,9 @@ func fakeManager(fnNames ...string) *Manager {
 		httpServer.New(logger, ""),
 		httpClient.New(logger, ""),
 		dbSql.New(logger, ""),
+		pgx.New(logger, ""),
+		goRedis.New(logger, ""),
+		goRedis.NewV8(logger, ""),
 		kafkaProducer.New(logger, ""),
 		kafkaConsumer.New(logger, ""),
 		autosdk.New(logger),
@@ -339,9 +344,17 @@ func (p slowProbe) Close() error {
 
 type noopProbe struct {
 	loaded, running, closed atomic.Bool
+	sampling                atomic.Pointer[sampling.Config]
+	minDuration             atomic.Int64
+	headerCapture           atomic.Pointer[probe.HeaderCapture]
 }
 
-var _ probe.Probe = (*noopProbe)(nil)
+var (
+	_ probe.Probe                = (*noopProbe)(nil)
+	_ probe.SamplingUpdater      = (*noopProbe)(nil)
+	_ probe.SpanFilterUpdater    = (*noopProbe)(nil)
+	_ probe.HeaderCaptureUpdater = (*noopProbe)(nil)
+)
 
 func (p *noopProbe) Load(*link.Executable, *process.Info, *sampling.Config) error {
 	p.loaded.Store(true)
@@ -359,6 +372,21 @@ func (p *noopProbe) Close() error {
 	return nil
 }
 
+func (p *noopProbe) UpdateSampling(c *sampling.Config) error {
+	p.sampling.Store(c)
+	return nil
+}
+
+func (p *noopProbe) UpdateMinDuration(d time.Duration) error {
+	p.minDuration.Store(int64(d))
+	return nil
+}
+
+func (p *noopProbe) UpdateHeaderCapture(c probe.HeaderCapture) error {
+	p.headerCapture.Store(&c)
+	return nil
+}
+
 func (p *noopProbe) Manifest() probe.Manifest {
 	return probe.Manifest{}
 }
@@ -490,6 +518,60 @@ func TestConfigProvider(t *testing.T) {
 			probeRunning(somePackageProducerProbeID)
 	}, time.Second, 10*time.Millisecond)
 
+	// Send a new config that updates the sampler of running probes
+	sc := &sampling.Config{
+		Samplers: map[sampling.SamplerID]sampling.SamplerConfig{
+			sampling.AlwaysOffID: {SamplerType: sampling.SamplerAlwaysOff},
+		},
+		ActiveSampler: sampling.AlwaysOffID,
+	}
+	m.cp.(*dummyProvider).sendConfig(Config{SamplingConfig: sc})
+	assert.Eventually(t, func() bool {
+		for _, p := range m.probes {
+			if p.(*noopProbe).sampling.Load() != sc {
+				return false
+			}
+		}
+		return true
+	}, time.Second, 10*time.Millisecond)
+
+	// Send a new config that sets the minimum span duration of net/http
+	m.cp.(*dummyProvider).sendConfig(Config{
+		InstrumentationLibraryConfigs: map[LibraryID]Library{
+			netHTTPLibID: {MinDuration: 50 * time.Millisecond},
+		},
+	})
+	minDuration := func(id probe.ID) time.Duration {
+		return time.Duration(m.probes[id].(*noopProbe).minDuration.Load())
+	}
+	assert.Eventually(t, func() bool {
+		return minDuration(netHTTPClientProbeID) == 50*time.Millisecond &&
+			minDuration(netHTTPServerProbeID) == 50*time.Millisecond
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, time.Duration(0), minDuration(somePackageProducerProbeID))
+
+	// Send a new config that captures headers of net/http
+	m.cp.(*dummyProvider).sendConfig(Config{
+		InstrumentationLibraryConfigs: map[LibraryID]Library{
+			netHTTPLibID: {
+				CaptureRequestHeaders:  []string{"User-Agent"},
+				CaptureResponseHeaders: []string{"Content-Type"},
+			},
+		},
+	})
+	want := probe.HeaderCapture{
+		Request:  []string{"User-Agent"},
+		Response: []string{"Content-Type"},
+	}
+	headerCapture := func(id probe.ID) *probe.HeaderCapture {
+		return m.probes[id].(*noopProbe).headerCapture.Load()
+	}
+	assert.Eventually(t, func() bool {
+		c, s := headerCapture(netHTTPClientProbeID), headerCapture(netHTTPServerProbeID)
+		return c != nil && c.Equal(want) && s != nil && s.Equal(want)
+	}, time.Second, 10*time.Millisecond)
+	assert.Nil(t, headerCapture(somePackageProducerProbeID))
+
 	cancel()
 	assert.Eventually(t, func() bool {
 		select {
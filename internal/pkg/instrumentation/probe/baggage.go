@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// baggageCarrier is implemented by the events holding the baggage propagated
+// with their spans.
+type baggageCarrier interface {
+	// Baggage returns the W3C baggage header value propagated with the
+	// spans of the event.
+	Baggage() string
+}
+
+// setBaggageAttributes sets the values of the configured baggage keys
+// propagated with the spans of event as attributes of each span in spans.
+// Existing attributes are not overwritten.
+func (i *Base[BPFObj, BPFEvent]) setBaggageAttributes(event *BPFEvent, spans ptrace.SpanSlice) {
+	if len(i.baggageKeys) == 0 {
+		return
+	}
+	c, ok := any(event).(baggageCarrier)
+	if !ok {
+		return
+	}
+	raw := c.Baggage()
+	if raw == "" {
+		return
+	}
+	for k := 0; k < spans.Len(); k++ {
+		putBaggageAttributes(spans.At(k).Attributes(), raw, i.baggageKeys)
+	}
+}
+
+// putBaggageAttributes puts the values of the members of the W3C baggage raw
+// with a key in keys into attrs. Invalid members and member properties are
+// ignored.
+func putBaggageAttributes(attrs pcommon.Map, raw string, keys []string) {
+	for _, member := range strings.Split(raw, ",") {
+		member, _, _ = strings.Cut(member, ";")
+		key, val, ok := strings.Cut(member, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key == "" || !slices.Contains(keys, key) {
+			continue
+		}
+		if _, exists := attrs.Get(key); exists {
+			continue
+		}
+		val, err := url.PathUnescape(strings.TrimSpace(val))
+		if err != nil {
+			continue
+		}
+		attrs.PutStr(key, val)
+	}
+}
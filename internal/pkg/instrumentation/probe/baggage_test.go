@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/context"
+)
+
+type baggageEvent struct {
+	context.BaseSpanProperties
+}
+
+func TestSetBaggageAttributes(t *testing.T) {
+	p := &Base[struct{}, baggageEvent]{}
+	p.SetBaggageAttributes([]string{"tenant.id"})
+
+	var e baggageEvent
+	e.State.Baggage.Len = uint32(copy(e.State.Baggage.Value[:], "tenant.id=acme,user.id=42"))
+
+	spans := ptrace.NewSpanSlice()
+	spans.AppendEmpty()
+	spans.AppendEmpty()
+	p.setBaggageAttributes(&e, spans)
+	for k := 0; k < spans.Len(); k++ {
+		assert.Equal(t, map[string]any{"tenant.id": "acme"}, spans.At(k).Attributes().AsRaw())
+	}
+
+	// Events without baggage, or not holding baggage, set no attributes.
+	spans = ptrace.NewSpanSlice()
+	spans.AppendEmpty()
+	p.setBaggageAttributes(&baggageEvent{}, spans)
+	assert.Equal(t, 0, spans.At(0).Attributes().Len())
+
+	q := &Base[struct{}, testEvent]{}
+	q.SetBaggageAttributes([]string{"tenant.id"})
+	q.setBaggageAttributes(&testEvent{}, spans)
+	assert.Equal(t, 0, spans.At(0).Attributes().Len())
+}
+
+func TestPutBaggageAttributes(t *testing.T) {
+	tests := []struct {
+		name     string
+		raw      string
+		keys     []string
+		existing map[string]any
+		want     map[string]any
+	}{
+		{
+			name: "NoKeys",
+			raw:  "tenant.id=acme",
+			want: map[string]any{},
+		},
+		{
+			name: "Allowlisted",
+			raw:  "tenant.id=acme,user.id=42",
+			keys: []string{"tenant.id"},
+			want: map[string]any{"tenant.id": "acme"},
+		},
+		{
+			name: "Whitespace",
+			raw:  " tenant.id = acme , user.id=42",
+			keys: []string{"tenant.id", "user.id"},
+			want: map[string]any{"tenant.id": "acme", "user.id": "42"},
+		},
+		{
+			name: "Properties",
+			raw:  "tenant.id=acme;prop=1",
+			keys: []string{"tenant.id"},
+			want: map[string]any{"tenant.id": "acme"},
+		},
+		{
+			name: "Escaped",
+			raw:  "tenant.id=acme%20corp%2Ceu",
+			keys: []string{"tenant.id"},
+			want: map[string]any{"tenant.id": "acme corp,eu"},
+		},
+		{
+			name: "Invalid",
+			raw:  "tenant.id,user.id=%zz,=x",
+			keys: []string{"tenant.id", "user.id", ""},
+			want: map[string]any{},
+		},
+		{
+			name:     "Existing",
+			raw:      "tenant.id=acme",
+			keys:     []string{"tenant.id"},
+			existing: map[string]any{"tenant.id": "other"},
+			want:     map[string]any{"tenant.id": "other"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attrs := pcommon.NewMap()
+			assert.NoError(t, attrs.FromRaw(tt.existing))
+			putBaggageAttributes(attrs, tt.raw, tt.keys)
+			assert.Equal(t, tt.want, attrs.AsRaw())
+		})
+	}
+}
@@ -0,0 +1,355 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"crypto/tls"
+	"log/slog"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/Masterminds/semver/v3"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"golang.org/x/sys/unix"
+
+	"go.opentelemetry.io/auto/internal/pkg/inject"
+	"go.opentelemetry.io/auto/internal/pkg/process"
+	"go.opentelemetry.io/auto/internal/pkg/structfield"
+)
+
+// goErrorMaxDepth is the maximum depth of the wrapped errors read by eBPF.
+const goErrorMaxDepth = 3
+
+// httpErrorMaxVersion is the first Go version not defining *net/http.httpError.
+var httpErrorMaxVersion = semver.New(1, 23, 0, "", "")
+
+// Kinds of the Go errors read by eBPF. They need to match the ones of the
+// eBPF programs.
+const (
+	goErrorOther uint8 = iota + 1
+	goErrorString
+	goErrorDeadlineExceeded
+	goErrorPollDeadlineExceeded
+	goErrorHTTP
+	goErrorHTTPTimeout
+	goErrorTLSHandshakeTimeout
+	goErrorOp
+	goErrorDNS
+	goErrorSyscall
+	goErrorErrno
+	goErrorTLSAlert
+	goErrorTLSCert
+)
+
+// goErrorTypes are the Go types of the known error kinds.
+var goErrorTypes = map[uint8]string{
+	goErrorString:               "*errors.errorString",
+	goErrorDeadlineExceeded:     "context.deadlineExceededError",
+	goErrorPollDeadlineExceeded: "*internal/poll.DeadlineExceededError",
+	goErrorHTTP:                 "*net/http.httpError",
+	goErrorHTTPTimeout:          "*net/http.timeoutError",
+	goErrorTLSHandshakeTimeout:  "net/http.tlsHandshakeTimeoutError",
+	goErrorOp:                   "*net.OpError",
+	goErrorDNS:                  "*net.DNSError",
+	goErrorSyscall:              "*os.SyscallError",
+	goErrorErrno:                "syscall.Errno",
+	goErrorTLSAlert:             "crypto/tls.alert",
+	goErrorTLSCert:              "*crypto/tls.CertificateVerificationError",
+}
+
+// canceledMessages are the messages of the errors returned when a request is
+// canceled.
+var canceledMessages = []string{
+	"context canceled",
+	"net/http: request canceled",
+	"net/http: request canceled while waiting for connection",
+}
+
+// GoError is the value of a Go error returned by an instrumented function,
+// read by eBPF. Only the fields needed to rebuild the message of known error
+// types are read.
+type GoError struct {
+	Kinds   [goErrorMaxDepth]uint8
+	Timeout uint8
+	Port    uint32
+	IPLen   uint8
+	_       [7]byte
+	Code    uint64
+	Op      [16]byte
+	Net     [8]byte
+	IP      [16]byte
+	Name    [64]byte
+	Msg     [128]byte
+}
+
+// Valid returns whether e holds a non-nil error.
+func (e *GoError) Valid() bool {
+	return e.Kinds[0] != 0
+}
+
+// Type returns the Go type of the error, or an empty string if it is unknown.
+func (e *GoError) Type() string {
+	return goErrorTypes[e.Kinds[0]]
+}
+
+// Message returns the message of the error, as returned by its Error method.
+// The message is partial, or empty, if it wraps errors of unknown types.
+func (e *GoError) Message() string {
+	return e.message(0)
+}
+
+func (e *GoError) message(i int) string {
+	if i >= len(e.Kinds) {
+		return ""
+	}
+	switch e.Kinds[i] {
+	case goErrorString, goErrorHTTP, goErrorHTTPTimeout:
+		return unix.ByteSliceToString(e.Msg[:])
+	case goErrorDeadlineExceeded:
+		return "context deadline exceeded"
+	case goErrorPollDeadlineExceeded:
+		return "i/o timeout"
+	case goErrorTLSHandshakeTimeout:
+		return "net/http: TLS handshake timeout"
+	case goErrorOp:
+		s := unix.ByteSliceToString(e.Op[:])
+		if n := unix.ByteSliceToString(e.Net[:]); n != "" {
+			s += " " + n
+		}
+		if e.IPLen == 4 || e.IPLen == 16 {
+			ip := net.IP(e.IP[:e.IPLen]).String()
+			s += " " + net.JoinHostPort(ip, strconv.FormatUint(uint64(e.Port), 10))
+		}
+		return wrapMessage(s, e.message(i+1))
+	case goErrorDNS:
+		return "lookup " + unix.ByteSliceToString(e.Name[:]) + ": " + unix.ByteSliceToString(e.Msg[:])
+	case goErrorSyscall:
+		return wrapMessage(unix.ByteSliceToString(e.Name[:]), e.message(i+1))
+	case goErrorErrno:
+		return syscall.Errno(e.Code).Error()
+	case goErrorTLSAlert:
+		return tls.AlertError(e.Code).Error() //nolint:gosec  // Read from a uint8.
+	case goErrorTLSCert:
+		return "tls: failed to verify certificate"
+	}
+	return ""
+}
+
+// wrapMessage returns the message of an error with the prefix wrapping an
+// error with the message msg.
+func wrapMessage(prefix, msg string) string {
+	if msg == "" {
+		return prefix
+	}
+	return prefix + ": " + msg
+}
+
+// OpErrorOp returns the operation of the error if it is a *net.OpError, or an
+// empty string otherwise.
+func (e *GoError) OpErrorOp() string {
+	if e.Kinds[0] != goErrorOp {
+		return ""
+	}
+	return unix.ByteSliceToString(e.Op[:])
+}
+
+// IsTimeout returns whether the error, or an error it wraps, is a timeout.
+func (e *GoError) IsTimeout() bool {
+	for _, k := range e.Kinds {
+		switch k {
+		case goErrorDeadlineExceeded, goErrorPollDeadlineExceeded,
+			goErrorHTTPTimeout, goErrorTLSHandshakeTimeout:
+			return true
+		case goErrorHTTP, goErrorDNS:
+			if e.Timeout != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsCanceled returns whether the error, or an error it wraps, is returned
+// because the operation was canceled.
+func (e *GoError) IsCanceled() bool {
+	for _, k := range e.Kinds {
+		if k != goErrorString {
+			continue
+		}
+		if slices.Contains(canceledMessages, unix.ByteSliceToString(e.Msg[:])) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTLS returns whether the error, or an error it wraps, is a TLS error.
+func (e *GoError) IsTLS() bool {
+	for _, k := range e.Kinds {
+		switch k {
+		case goErrorTLSAlert, goErrorTLSCert:
+			return true
+		case goErrorString:
+			if strings.HasPrefix(unix.ByteSliceToString(e.Msg[:]), "tls: ") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Record records the error, if any, on span: its status is set to Error, an
+// exception event describing the error is added, and the error.type attribute
+// is set to the Go type of the error, or "_OTHER" if it is unknown.
+func (e *GoError) Record(span ptrace.Span) {
+	if !e.Valid() {
+		return
+	}
+	e.RecordException(span)
+	typ := e.Type()
+	if typ == "" {
+		typ = semconv.ErrorTypeOther.Value.AsString()
+	}
+	span.Attributes().PutStr(string(semconv.ErrorTypeKey), typ)
+}
+
+// RecordException sets the status of span to Error with the message of the
+// error and adds an exception event describing it at the end of span. No
+// event is added if neither the type nor the message of the error is known.
+func (e *GoError) RecordException(span ptrace.Span) {
+	RecordException(span, e.Type(), e.Message())
+}
+
+// RecordException sets the status of span to Error with the error message msg
+// and adds an exception event describing the error of type typ at the end of
+// span. Unknown values are empty, no event is added if both are.
+func RecordException(span ptrace.Span, typ, msg string) {
+	span.Status().SetCode(ptrace.StatusCodeError)
+	if msg != "" {
+		span.Status().SetMessage(msg)
+	}
+	if msg == "" && typ == "" {
+		return
+	}
+
+	event := span.Events().AppendEmpty()
+	event.SetName(semconv.ExceptionEventName)
+	event.SetTimestamp(span.EndTimestamp())
+	if typ != "" {
+		event.Attributes().PutStr(string(semconv.ExceptionTypeKey), typ)
+	}
+	if msg != "" {
+		event.Attributes().PutStr(string(semconv.ExceptionMessageKey), msg)
+	}
+}
+
+// ItabConst is a [Const] for the address of the itab of the concrete type
+// Type implementing the interface Interface in the target binary. Zero is
+// injected if the itab is not found.
+type ItabConst struct {
+	Key       string
+	Type      string
+	Interface string
+
+	logger *slog.Logger
+}
+
+var _ setLogger = ItabConst{}
+
+// SetLogger sets the Logger for ItabConst operations.
+func (c ItabConst) SetLogger(l *slog.Logger) Const {
+	c.logger = l
+	return c
+}
+
+// InjectOption returns the appropriately configured [inject.WithKeyValue].
+func (c ItabConst) InjectOption(info *process.Info) (inject.Option, error) {
+	addr, err := info.Itab(c.Type, c.Interface)
+	if c.logger != nil {
+		switch {
+		case err != nil:
+			c.logger.Warn("itab not resolved", "key", c.Key, "type", c.Type, "error", err)
+		case addr == 0:
+			c.logger.Debug("itab not found", "key", c.Key, "type", c.Type)
+		}
+	}
+	return inject.WithKeyValue(c.Key, addr), nil
+}
+
+// errorItab returns the ItabConst of the type implementing error.
+func errorItab(key, typ string) ItabConst {
+	return ItabConst{Key: key, Type: typ, Interface: "error"}
+}
+
+// GoErrorConsts are the [Const] needed by the eBPF programs reading Go errors.
+var GoErrorConsts = []Const{
+	errorItab("error_string_itab", goErrorTypes[goErrorString]),
+	errorItab("deadline_exceeded_itab", goErrorTypes[goErrorDeadlineExceeded]),
+	errorItab("poll_deadline_exceeded_itab", goErrorTypes[goErrorPollDeadlineExceeded]),
+	errorItab("http_error_itab", goErrorTypes[goErrorHTTP]),
+	errorItab("http_timeout_error_itab", goErrorTypes[goErrorHTTPTimeout]),
+	errorItab("tls_handshake_timeout_itab", goErrorTypes[goErrorTLSHandshakeTimeout]),
+	errorItab("op_error_itab", goErrorTypes[goErrorOp]),
+	errorItab("dns_error_itab", goErrorTypes[goErrorDNS]),
+	errorItab("syscall_error_itab", goErrorTypes[goErrorSyscall]),
+	errorItab("errno_itab", goErrorTypes[goErrorErrno]),
+	errorItab("tls_alert_itab", goErrorTypes[goErrorTLSAlert]),
+	errorItab("tls_cert_error_itab", goErrorTypes[goErrorTLSCert]),
+	ItabConst{Key: "tcp_addr_itab", Type: "*net.TCPAddr", Interface: "net.Addr"},
+	StructFieldConst{
+		Key: "op_error_op_pos",
+		ID:  structfield.NewID("std", "net", "OpError", "Op"),
+	},
+	StructFieldConst{
+		Key: "op_error_net_pos",
+		ID:  structfield.NewID("std", "net", "OpError", "Net"),
+	},
+	StructFieldConst{
+		Key: "op_error_addr_pos",
+		ID:  structfield.NewID("std", "net", "OpError", "Addr"),
+	},
+	StructFieldConst{
+		Key: "op_error_err_pos",
+		ID:  structfield.NewID("std", "net", "OpError", "Err"),
+	},
+	StructFieldConst{
+		Key: "dns_error_err_pos",
+		ID:  structfield.NewID("std", "net", "DNSError", "Err"),
+	},
+	StructFieldConst{
+		Key: "dns_error_name_pos",
+		ID:  structfield.NewID("std", "net", "DNSError", "Name"),
+	},
+	StructFieldConst{
+		Key: "dns_error_is_timeout_pos",
+		ID:  structfield.NewID("std", "net", "DNSError", "IsTimeout"),
+	},
+	StructFieldConst{
+		Key: "syscall_error_syscall_pos",
+		ID:  structfield.NewID("std", "os", "SyscallError", "Syscall"),
+	},
+	StructFieldConst{
+		Key: "syscall_error_err_pos",
+		ID:  structfield.NewID("std", "os", "SyscallError", "Err"),
+	},
+	StructFieldConstMaxVersion{
+		StructField: StructFieldConst{
+			Key: "http_error_timeout_pos",
+			ID:  structfield.NewID("std", "net/http", "httpError", "timeout"),
+		},
+		MaxVersion: httpErrorMaxVersion,
+	},
+	StructFieldConst{
+		Key: "TCPAddr_IP_offset",
+		ID:  structfield.NewID("std", "net", "TCPAddr", "IP"),
+	},
+	StructFieldConst{
+		Key: "TCPAddr_Port_offset",
+		ID:  structfield.NewID("std", "net", "TCPAddr", "Port"),
+	},
+}
@@ -0,0 +1,202 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+func goError(kinds ...uint8) *GoError {
+	var e GoError
+	copy(e.Kinds[:], kinds)
+	return &e
+}
+
+func TestGoErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  func() *GoError
+		typ  string
+		msg  string
+	}{
+		{
+			name: "nil",
+			err:  func() *GoError { return goError() },
+		},
+		{
+			name: "unknown",
+			err:  func() *GoError { return goError(goErrorOther) },
+		},
+		{
+			name: "errorString",
+			err: func() *GoError {
+				e := goError(goErrorString)
+				copy(e.Msg[:], "context canceled")
+				return e
+			},
+			typ: "*errors.errorString",
+			msg: "context canceled",
+		},
+		{
+			name: "connection refused",
+			err: func() *GoError {
+				e := goError(goErrorOp, goErrorSyscall, goErrorErrno)
+				copy(e.Op[:], "dial")
+				copy(e.Net[:], "tcp")
+				e.IPLen = 4
+				copy(e.IP[:], []byte{127, 0, 0, 1})
+				e.Port = 8080
+				copy(e.Name[:], "connect")
+				e.Code = 111 // ECONNREFUSED
+				return e
+			},
+			typ: "*net.OpError",
+			msg: "dial tcp 127.0.0.1:8080: connect: connection refused",
+		},
+		{
+			name: "DNS",
+			err: func() *GoError {
+				e := goError(goErrorOp, goErrorDNS)
+				copy(e.Op[:], "dial")
+				copy(e.Net[:], "tcp")
+				copy(e.Name[:], "example.invalid")
+				copy(e.Msg[:], "no such host")
+				return e
+			},
+			typ: "*net.OpError",
+			msg: "dial tcp: lookup example.invalid: no such host",
+		},
+		{
+			name: "wrapped unknown",
+			err: func() *GoError {
+				e := goError(goErrorOp, goErrorOther)
+				copy(e.Op[:], "read")
+				copy(e.Net[:], "tcp")
+				return e
+			},
+			typ: "*net.OpError",
+			msg: "read tcp",
+		},
+		{
+			name: "TLS alert",
+			err: func() *GoError {
+				e := goError(goErrorOp, goErrorTLSAlert)
+				copy(e.Op[:], "remote error")
+				e.Code = 42
+				return e
+			},
+			typ: "*net.OpError",
+			msg: "remote error: tls: bad certificate",
+		},
+		{
+			name: "httpError",
+			err: func() *GoError {
+				e := goError(goErrorHTTP)
+				copy(e.Msg[:], "net/http: timeout awaiting response headers")
+				e.Timeout = 1
+				return e
+			},
+			typ: "*net/http.httpError",
+			msg: "net/http: timeout awaiting response headers",
+		},
+		{
+			name: "timeoutError",
+			err: func() *GoError {
+				e := goError(goErrorHTTPTimeout)
+				copy(e.Msg[:], "net/http: timeout awaiting response headers")
+				return e
+			},
+			typ: "*net/http.timeoutError",
+			msg: "net/http: timeout awaiting response headers",
+		},
+		{
+			name: "deadline exceeded",
+			err:  func() *GoError { return goError(goErrorDeadlineExceeded) },
+			typ:  "context.deadlineExceededError",
+			msg:  "context deadline exceeded",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := tc.err()
+			assert.Equal(t, tc.typ, e.Type())
+			assert.Equal(t, tc.msg, e.Message())
+		})
+	}
+}
+
+func TestGoErrorClassification(t *testing.T) {
+	e := goError(goErrorString)
+	copy(e.Msg[:], "net/http: request canceled")
+	assert.True(t, e.IsCanceled())
+	assert.False(t, e.IsTimeout())
+	assert.False(t, e.IsTLS())
+
+	e = goError(goErrorOp, goErrorPollDeadlineExceeded)
+	copy(e.Op[:], "dial")
+	assert.True(t, e.IsTimeout())
+	assert.Equal(t, "dial", e.OpErrorOp())
+
+	e = goError(goErrorOp, goErrorDNS)
+	assert.False(t, e.IsTimeout())
+	e.Timeout = 1
+	assert.True(t, e.IsTimeout())
+
+	e = goError(goErrorHTTP)
+	assert.False(t, e.IsTimeout())
+	e.Timeout = 1
+	assert.True(t, e.IsTimeout())
+
+	e = goError(goErrorHTTPTimeout)
+	assert.True(t, e.IsTimeout())
+
+	e = goError(goErrorTLSCert)
+	assert.True(t, e.IsTLS())
+	assert.Empty(t, e.OpErrorOp())
+}
+
+func TestGoErrorRecordException(t *testing.T) {
+	e := goError(goErrorDeadlineExceeded)
+	span := ptrace.NewSpan()
+	e.RecordException(span)
+
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, "context deadline exceeded", span.Status().Message())
+	assert.Equal(t, 1, span.Events().Len())
+	event := span.Events().At(0)
+	assert.Equal(t, "exception", event.Name())
+	assert.Equal(t, map[string]any{
+		"exception.type":    "context.deadlineExceededError",
+		"exception.message": "context deadline exceeded",
+	}, event.Attributes().AsRaw())
+
+	span = ptrace.NewSpan()
+	goError(goErrorOther).RecordException(span)
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, 0, span.Events().Len())
+}
+
+func TestGoErrorRecord(t *testing.T) {
+	span := ptrace.NewSpan()
+	goError(goErrorDeadlineExceeded).Record(span)
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, 1, span.Events().Len())
+	assert.Equal(t, map[string]any{
+		"error.type": "context.deadlineExceededError",
+	}, span.Attributes().AsRaw())
+
+	span = ptrace.NewSpan()
+	goError(goErrorOther).Record(span)
+	assert.Equal(t, ptrace.StatusCodeError, span.Status().Code())
+	assert.Equal(t, map[string]any{"error.type": "_OTHER"}, span.Attributes().AsRaw())
+
+	span = ptrace.NewSpan()
+	goError().Record(span)
+	assert.Equal(t, ptrace.StatusCodeUnset, span.Status().Code())
+	assert.Equal(t, 0, span.Attributes().Len())
+}
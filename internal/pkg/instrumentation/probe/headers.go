@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/cilium/ebpf"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"go.opentelemetry.io/auto/internal/pkg/inject"
+	"go.opentelemetry.io/auto/internal/pkg/process"
+)
+
+const (
+	headerCaptureMapName = "header_capture_map"
+
+	// HeaderNameMaxLen is the maximum length of a captured header name.
+	HeaderNameMaxLen = 32
+	// HeaderValueMaxLen is the maximum length of a captured header value.
+	// Longer values are truncated.
+	HeaderValueMaxLen = 128
+	// MaxCapturedHeaders is the maximum number of headers captured per
+	// request or response.
+	MaxCapturedHeaders = 8
+
+	// RedactedHeaderValue replaces the value of redacted headers.
+	RedactedHeaderValue = "REDACTED"
+
+	// GRPCRequestMetadataPrefix and GRPCResponseMetadataPrefix are the
+	// prefixes of the attribute keys of the captured gRPC metadata.
+	GRPCRequestMetadataPrefix  = "rpc.grpc.request.metadata."
+	GRPCResponseMetadataPrefix = "rpc.grpc.response.metadata."
+)
+
+// Flags of the header capture configuration. They need to match the ones of
+// the eBPF programs.
+const (
+	headerCaptureRequest  uint8 = 0x1
+	headerCaptureResponse uint8 = 0x2
+	headerCaptureRedact   uint8 = 0x4
+)
+
+// sensitiveHeaders are the headers always redacted when captured.
+var sensitiveHeaders = []string{
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"set-cookie",
+}
+
+// HeaderCapture is the configuration of the request and response headers
+// recorded as span attributes. Header names are matched case-insensitively.
+type HeaderCapture struct {
+	// Request are the names of the request headers captured.
+	Request []string
+	// Response are the names of the response headers captured.
+	Response []string
+	// Redact are the names of the captured headers whose values are replaced
+	// by [RedactedHeaderValue]. The Authorization, Proxy-Authorization,
+	// Cookie and Set-Cookie headers are always redacted.
+	Redact []string
+}
+
+// Empty returns whether no header is captured with c.
+func (c HeaderCapture) Empty() bool {
+	return len(c.Request) == 0 && len(c.Response) == 0
+}
+
+// Equal returns whether c and o capture the same headers.
+func (c HeaderCapture) Equal(o HeaderCapture) bool {
+	return slices.Equal(c.Request, o.Request) &&
+		slices.Equal(c.Response, o.Response) &&
+		slices.Equal(c.Redact, o.Redact)
+}
+
+// flags returns the eBPF configuration flags of the headers captured with c,
+// keyed by lowercase header name. It returns an error for the names that are
+// too long to be matched.
+func (c HeaderCapture) flags() (map[string]uint8, error) {
+	var err error
+	flags := make(map[string]uint8)
+	add := func(names []string, flag uint8) {
+		for _, name := range names {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" {
+				continue
+			}
+			if len(name) > HeaderNameMaxLen {
+				err = errors.Join(err, fmt.Errorf("header name too long: %q", name))
+				continue
+			}
+			flags[name] |= flag
+		}
+	}
+	add(c.Request, headerCaptureRequest)
+	add(c.Response, headerCaptureResponse)
+
+	for name, f := range flags {
+		redact := slices.Contains(sensitiveHeaders, name) ||
+			slices.ContainsFunc(c.Redact, func(r string) bool {
+				return strings.EqualFold(strings.TrimSpace(r), name)
+			})
+		if redact {
+			flags[name] = f | headerCaptureRedact
+		}
+	}
+	return flags, err
+}
+
+// headerCaptureKey returns the eBPF map key of the lowercase header name.
+func headerCaptureKey(name string) [HeaderNameMaxLen]byte {
+	var k [HeaderNameMaxLen]byte
+	copy(k[:], name)
+	return k
+}
+
+// HeaderCaptureUpdater is a [Probe] whose captured headers can be updated
+// after it is loaded.
+type HeaderCaptureUpdater interface {
+	// UpdateHeaderCapture replaces the headers captured by the loaded Probe
+	// with the ones of c.
+	UpdateHeaderCapture(c HeaderCapture) error
+}
+
+// UpdateHeaderCapture replaces the headers captured by the loaded probe with
+// the ones of c.
+func (i *Base[BPFObj, BPFEvent]) UpdateHeaderCapture(c HeaderCapture) error {
+	if i.collection == nil {
+		return errors.New("probe not loaded")
+	}
+	m, ok := i.collection.Maps[headerCaptureMapName]
+	if !ok {
+		return fmt.Errorf("%s map not found", headerCaptureMapName)
+	}
+	flags, err := c.flags()
+	return errors.Join(err, writeHeaderCapture(m, flags))
+}
+
+// writeHeaderCapture replaces the content of the eBPF header capture map m
+// with flags. The entry with an empty name is set to the union of all flags.
+func writeHeaderCapture(m *ebpf.Map, flags map[string]uint8) error {
+	var (
+		err   error
+		all   uint8
+		key   [HeaderNameMaxLen]byte
+		value uint8
+		stale [][HeaderNameMaxLen]byte
+	)
+	iter := m.Iterate()
+	for iter.Next(&key, &value) {
+		stale = append(stale, key)
+	}
+	err = errors.Join(err, iter.Err())
+
+	for name, f := range flags {
+		all |= f
+		err = errors.Join(err, m.Put(headerCaptureKey(name), f))
+	}
+	err = errors.Join(err, m.Put(headerCaptureKey(""), all))
+
+	for _, k := range stale {
+		name := strings.TrimRight(string(k[:]), "\x00")
+		if _, ok := flags[name]; ok || name == "" {
+			continue
+		}
+		if e := m.Delete(k); e != nil && !errors.Is(e, ebpf.ErrKeyNotExist) {
+			err = errors.Join(err, e)
+		}
+	}
+	return err
+}
+
+// CapturedHeader is a header captured by eBPF.
+type CapturedHeader struct {
+	Name     [HeaderNameMaxLen]byte
+	Value    [HeaderValueMaxLen]byte
+	ValueLen uint32
+	Redacted uint8
+	_        [3]byte
+}
+
+// CapturedHeaders are the headers of a request or response captured by eBPF.
+type CapturedHeaders struct {
+	Count   uint32
+	_       [4]byte
+	Headers [MaxCapturedHeaders]CapturedHeader
+}
+
+// PutAttributes puts the captured headers into attrs as string slice
+// attributes with the header name appended to prefix as key.
+func (h *CapturedHeaders) PutAttributes(attrs pcommon.Map, prefix string) {
+	n := min(int(h.Count), len(h.Headers))
+	for _, c := range h.Headers[:n] {
+		name := strings.TrimRight(string(c.Name[:]), "\x00")
+		if name == "" {
+			continue
+		}
+		value := RedactedHeaderValue
+		if c.Redacted == 0 {
+			value = string(c.Value[:min(int(c.ValueLen), len(c.Value))])
+		}
+		attrs.PutEmptySlice(prefix + name).AppendEmpty().SetStr(value)
+	}
+}
+
+// KnownHeaders are the first values of the well-known request headers used
+// for span attributes, read by eBPF.
+type KnownHeaders struct {
+	UserAgent    [HeaderValueMaxLen]byte
+	ForwardedFor [HeaderValueMaxLen]byte
+	Forwarded    [HeaderValueMaxLen]byte
+}
+
+// GoSwissMapsVersion is the Go version introducing the swiss map layout.
+var GoSwissMapsVersion = semver.New(1, 24, 0, "", "")
+
+// SwissMapsConst is a [Const] for whether the target process uses the swiss
+// map layout introduced in Go 1.24.
+type SwissMapsConst struct{}
+
+// InjectOption returns the appropriately configured [inject.WithKeyValue].
+func (SwissMapsConst) InjectOption(info *process.Info) (inject.Option, error) {
+	swiss := info.GoVersion.GreaterThanEqual(GoSwissMapsVersion)
+	return inject.WithKeyValue("swiss_maps_used", swiss), nil
+}
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestHeaderCaptureFlags(t *testing.T) {
+	c := HeaderCapture{
+		Request:  []string{"X-Request-ID", " user-agent ", "Authorization", ""},
+		Response: []string{"Content-Type", "x-request-id", "X-Api-Key"},
+		Redact:   []string{"x-api-key"},
+	}
+	got, err := c.flags()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]uint8{
+		"x-request-id":  headerCaptureRequest | headerCaptureResponse,
+		"user-agent":    headerCaptureRequest,
+		"authorization": headerCaptureRequest | headerCaptureRedact,
+		"content-type":  headerCaptureResponse,
+		"x-api-key":     headerCaptureResponse | headerCaptureRedact,
+	}, got)
+
+	long := strings.Repeat("x", HeaderNameMaxLen+1)
+	got, err = HeaderCapture{Request: []string{long, "Accept"}}.flags()
+	assert.Error(t, err)
+	assert.Equal(t, map[string]uint8{"accept": headerCaptureRequest}, got)
+}
+
+func TestHeaderCaptureEqual(t *testing.T) {
+	var c HeaderCapture
+	assert.True(t, c.Empty())
+	assert.True(t, c.Equal(HeaderCapture{}))
+
+	c.Redact = []string{"X-Api-Key"}
+	assert.True(t, c.Empty())
+	assert.False(t, c.Equal(HeaderCapture{}))
+
+	c.Request = []string{"Accept"}
+	assert.False(t, c.Empty())
+	assert.True(t, c.Equal(HeaderCapture{
+		Request: []string{"Accept"},
+		Redact:  []string{"X-Api-Key"},
+	}))
+}
+
+func capturedHeader(name, value string, redacted bool) CapturedHeader {
+	var h CapturedHeader
+	copy(h.Name[:], name)
+	h.ValueLen = uint32(copy(h.Value[:], value)) //nolint:gosec  // Bounded.
+	if redacted {
+		h.Redacted = 1
+	}
+	return h
+}
+
+func TestCapturedHeadersPutAttributes(t *testing.T) {
+	var h CapturedHeaders
+	attrs := pcommon.NewMap()
+	h.PutAttributes(attrs, "http.request.header.")
+	assert.Equal(t, 0, attrs.Len())
+
+	long := strings.Repeat("v", HeaderValueMaxLen)
+	h.Headers[0] = capturedHeader("content-type", "application/json", false)
+	h.Headers[1] = capturedHeader("authorization", "", true)
+	h.Headers[2] = capturedHeader("x-long", long, false)
+	h.Headers[3] = capturedHeader("x-not-counted", "value", false)
+	h.Count = 3
+
+	h.PutAttributes(attrs, "http.response.header.")
+	assert.Equal(t, map[string]any{
+		"http.response.header.content-type":  []any{"application/json"},
+		"http.response.header.authorization": []any{RedactedHeaderValue},
+		"http.response.header.x-long":        []any{long},
+	}, attrs.AsRaw())
+
+	h.Count = MaxCapturedHeaders + 1
+	attrs = pcommon.NewMap()
+	h.PutAttributes(attrs, "http.response.header.")
+	assert.Equal(t, 4, attrs.Len())
+}
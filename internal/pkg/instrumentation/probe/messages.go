@@ -0,0 +1,63 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/kernel"
+)
+
+// MaxRPCMessages is the maximum number of messages recorded per RPC.
+const MaxRPCMessages = 8
+
+// rpcMessageEventName is the name of the span events describing messages.
+const rpcMessageEventName = "rpc.message"
+
+// RPCMessage is a message sent or received during an RPC, read by eBPF.
+type RPCMessage struct {
+	// Time is the boot time the message was sent or received at.
+	Time uint64
+	// ID is the sequence number of the message in its direction, starting
+	// at 1.
+	ID uint32
+	// Size is the length of the message, compressed if Compressed is not 0.
+	Size       uint32
+	Sent       uint8
+	Compressed uint8
+	_          [6]byte
+}
+
+// RPCMessages are the messages sent and received during an RPC, read by eBPF.
+// Only the first [MaxRPCMessages] messages are recorded, the counters count
+// all of them.
+type RPCMessages struct {
+	Sent     uint32
+	Received uint32
+	Events   [MaxRPCMessages]RPCMessage
+}
+
+// AddSpanEvents adds an rpc.message event for each recorded message to span.
+func (m *RPCMessages) AddSpanEvents(span ptrace.Span) {
+	n := min(int(m.Sent)+int(m.Received), len(m.Events))
+	for _, msg := range m.Events[:n] {
+		event := span.Events().AppendEmpty()
+		event.SetName(rpcMessageEventName)
+		event.SetTimestamp(kernel.BootOffsetToTimestamp(msg.Time))
+
+		attrs := event.Attributes()
+		typ := semconv.RPCMessageTypeReceived
+		if msg.Sent != 0 {
+			typ = semconv.RPCMessageTypeSent
+		}
+		attrs.PutStr(string(typ.Key), typ.Value.AsString())
+		attrs.PutInt(string(semconv.RPCMessageIDKey), int64(msg.ID))
+		sizeKey := semconv.RPCMessageUncompressedSizeKey
+		if msg.Compressed != 0 {
+			sizeKey = semconv.RPCMessageCompressedSizeKey
+		}
+		attrs.PutInt(string(sizeKey), int64(msg.Size))
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/kernel"
+)
+
+func TestRPCMessagesAddSpanEvents(t *testing.T) {
+	m := RPCMessages{Sent: 2, Received: 1}
+	m.Events[0] = RPCMessage{Time: 10, ID: 1, Size: 42, Sent: 1}
+	m.Events[1] = RPCMessage{Time: 20, ID: 1, Size: 7}
+	m.Events[2] = RPCMessage{Time: 30, ID: 2, Size: 15, Sent: 1, Compressed: 1}
+
+	span := ptrace.NewSpan()
+	m.AddSpanEvents(span)
+
+	events := span.Events()
+	require.Equal(t, 3, events.Len())
+	for i := range events.Len() {
+		assert.Equal(t, "rpc.message", events.At(i).Name())
+		assert.Equal(t, kernel.BootOffsetToTimestamp(m.Events[i].Time), events.At(i).Timestamp())
+	}
+	assert.Equal(t, map[string]any{
+		"rpc.message.type":              "SENT",
+		"rpc.message.id":                int64(1),
+		"rpc.message.uncompressed_size": int64(42),
+	}, events.At(0).Attributes().AsRaw())
+	assert.Equal(t, map[string]any{
+		"rpc.message.type":              "RECEIVED",
+		"rpc.message.id":                int64(1),
+		"rpc.message.uncompressed_size": int64(7),
+	}, events.At(1).Attributes().AsRaw())
+	assert.Equal(t, map[string]any{
+		"rpc.message.type":            "SENT",
+		"rpc.message.id":              int64(2),
+		"rpc.message.compressed_size": int64(15),
+	}, events.At(2).Attributes().AsRaw())
+}
+
+func TestRPCMessagesAddSpanEventsTruncated(t *testing.T) {
+	m := RPCMessages{Sent: MaxRPCMessages + 3, Received: 2}
+	for i := range m.Events {
+		m.Events[i] = RPCMessage{Time: uint64(i + 1), ID: uint32(i + 1), Sent: 1} //nolint:gosec  // Bounded.
+	}
+
+	span := ptrace.NewSpan()
+	m.AddSpanEvents(span)
+	assert.Equal(t, MaxRPCMessages, span.Events().Len())
+
+	span = ptrace.NewSpan()
+	(&RPCMessages{}).AddSpanEvents(span)
+	assert.Equal(t, 0, span.Events().Len())
+}
@@ -12,6 +12,7 @@ import (
 	"log/slog"
 	"os"
 	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/Masterminds/semver/v3"
@@ -51,6 +52,23 @@ type Probe interface {
 	Close() error
 }
 
+// SamplingUpdater is a [Probe] whose sampling configuration can be updated
+// after it is loaded.
+type SamplingUpdater interface {
+	// UpdateSampling replaces the sampling configuration of the loaded Probe
+	// with c.
+	UpdateSampling(c *sampling.Config) error
+}
+
+// SpanFilterUpdater is a [Probe] whose span filtering can be updated after
+// it is loaded.
+type SpanFilterUpdater interface {
+	// UpdateMinDuration sets the minimum duration of the spans output by the
+	// loaded Probe. Shorter spans are dropped, but their context is still
+	// propagated. If d is zero, all spans are output.
+	UpdateMinDuration(d time.Duration) error
+}
+
 // Base is a base implementation of [Probe].
 //
 // This type can be returned by instrumentation directly. Instrumentation can
@@ -74,13 +92,18 @@ type Base[BPFObj any, BPFEvent any] struct {
 	SpecFn func() (*ebpf.CollectionSpec, error)
 	// ProcessRecord is an optional processing function for the probe. If nil,
 	// all records will be read directly into a new BPFEvent using the
-	// encoding/binary package.
+	// encoding/binary package. Changes it makes to the raw sample of the
+	// record are recorded.
 	ProcessRecord func(perf.Record) (*BPFEvent, error)
 
 	reader          *perf.Reader
 	collection      *ebpf.Collection
 	closers         []io.Closer
 	samplingManager *sampling.Manager
+	recorder        *Recorder
+	layout          string
+	propagators     []Propagator
+	baggageKeys     []string
 }
 
 const (
@@ -90,6 +113,9 @@ const (
 	// DefaultBufferMapName is the default name of the eBPF map used to pass
 	// events from the eBPF program to userspace.
 	DefaultBufferMapName = "events"
+
+	spanOutputConfigMapName = "span_output_config_map"
+	droppedSpansMapName     = "dropped_spans_map"
 )
 
 // Manifest returns the Probe's instrumentation Manifest.
@@ -132,6 +158,12 @@ func (i *Base[BPFObj, BPFEvent]) Load(
 		return err
 	}
 
+	if i.recorder != nil {
+		if err := i.recorder.recordProcess(info); err != nil {
+			i.Logger.Error("failed to record process metadata", "error", err)
+		}
+	}
+
 	i.collection, err = i.buildEBPFCollection(info, spec)
 	if err != nil {
 		return err
@@ -157,6 +189,50 @@ func (i *Base[BPFObj, BPFEvent]) Load(
 	return nil
 }
 
+// UpdateSampling replaces the sampling configuration of the loaded probe with
+// c.
+func (i *Base[BPFObj, BPFEvent]) UpdateSampling(c *sampling.Config) error {
+	if i.samplingManager == nil {
+		return errors.New("probe not loaded")
+	}
+	return i.samplingManager.ApplyConfig(c)
+}
+
+// UpdateMinDuration sets the minimum duration of the spans output by the
+// loaded probe. Shorter spans are dropped in eBPF.
+func (i *Base[BPFObj, BPFEvent]) UpdateMinDuration(d time.Duration) error {
+	if i.collection == nil {
+		return errors.New("probe not loaded")
+	}
+	m, ok := i.collection.Maps[spanOutputConfigMapName]
+	if !ok {
+		return fmt.Errorf("%s map not found", spanOutputConfigMapName)
+	}
+	d = max(d, 0)
+	return m.Put(uint32(0), uint64(d)) //nolint:gosec  // Non-negative.
+}
+
+// DroppedSpans returns the number of sampled spans the loaded probe dropped
+// because they were shorter than the minimum duration.
+func (i *Base[BPFObj, BPFEvent]) DroppedSpans() (uint64, error) {
+	if i.collection == nil {
+		return 0, errors.New("probe not loaded")
+	}
+	m, ok := i.collection.Maps[droppedSpansMapName]
+	if !ok {
+		return 0, fmt.Errorf("%s map not found", droppedSpansMapName)
+	}
+	var perCPU []uint64
+	if err := m.Lookup(uint32(0), &perCPU); err != nil {
+		return 0, err
+	}
+	var n uint64
+	for _, v := range perCPU {
+		n += v
+	}
+	return n, nil
+}
+
 func (i *Base[BPFObj, BPFEvent]) InjectConsts(info *process.Info, spec *ebpf.CollectionSpec) error {
 	var err error
 	var opts []inject.Option
@@ -164,6 +240,9 @@ func (i *Base[BPFObj, BPFEvent]) InjectConsts(info *process.Info, spec *ebpf.Col
 		if l, ok := cnst.(setLogger); ok {
 			cnst = l.SetLogger(i.Logger)
 		}
+		if p, ok := cnst.(setPropagators); ok {
+			cnst = p.SetPropagators(i.propagators)
+		}
 
 		o, e := cnst.InjectOption(info)
 		err = errors.Join(err, e)
@@ -298,7 +377,29 @@ func (i *Base[BPFObj, BPFEvent]) read() (*BPFEvent, error) {
 		return nil, err
 	}
 
-	var event *BPFEvent
+	return i.readRecord(record)
+}
+
+// readRecord decodes record into a new BPFEvent and records its raw sample if
+// a Recorder is set.
+func (i *Base[BPFObj, BPFEvent]) readRecord(record perf.Record) (*BPFEvent, error) {
+	event, err := i.decode(record)
+	if i.recorder != nil {
+		// The sample is recorded once decoded so ProcessRecord can remove
+		// sensitive data from it.
+		if rErr := i.recorder.record(i.ID, i.layout, record.RawSample); rErr != nil {
+			i.Logger.Error("failed to record sample", "error", rErr)
+		}
+	}
+	return event, err
+}
+
+// decode decodes record into a new BPFEvent.
+func (i *Base[BPFObj, BPFEvent]) decode(record perf.Record) (*BPFEvent, error) {
+	var (
+		event *BPFEvent
+		err   error
+	)
 	if i.ProcessRecord != nil {
 		event, err = i.ProcessRecord(record)
 	} else {
@@ -319,9 +420,69 @@ func (i *Base[BPFObj, BPFEvent]) read() (*BPFEvent, error) {
 	return event, nil
 }
 
+// SetRecorder sets the Recorder all raw samples read are recorded to.
+//
+// This needs to be called before Load to have the target process metadata
+// recorded.
+func (i *Base[BPFObj, BPFEvent]) SetRecorder(r *Recorder) {
+	i.recorder = r
+	if r != nil && i.layout == "" {
+		i.layout = eventLayout[BPFEvent]()
+	}
+}
+
+// SetPropagators sets the propagators used by the eBPF programs of the probe,
+// in priority order. It is only used if the probe has a [PropagatorsConst].
+//
+// This needs to be called before Load.
+func (i *Base[BPFObj, BPFEvent]) SetPropagators(p []Propagator) {
+	i.propagators = p
+}
+
+// SetBaggageAttributes sets the baggage keys whose values are set as
+// attributes of the produced spans. It is only used if the eBPF programs of
+// the probe propagate baggage.
+func (i *Base[BPFObj, BPFEvent]) SetBaggageAttributes(keys []string) {
+	i.baggageKeys = keys
+}
+
+// PrepareReplay prepares the probe to replay samples recorded from the target
+// process described by info.
+//
+// Constants that only configure the eBPF programs are not resolved. All other
+// constants are resolved so any probe state derived from the target process
+// is restored.
+func (i *Base[BPFObj, BPFEvent]) PrepareReplay(info *process.Info) {
+	for _, cnst := range i.Consts {
+		switch cnst.(type) {
+		case AllocationConst, StructFieldConst, StructFieldConstMinVersion,
+			StructFieldConstMaxVersion, StructFieldConstOptional, KeyValConst,
+			PropagatorsConst, SwissMapsConst, ItabConst:
+			continue
+		}
+		if _, err := cnst.InjectOption(info); err != nil {
+			i.Logger.Debug("failed to resolve constant for replay", "probe", i.ID, "error", err)
+		}
+	}
+}
+
+// replayEvent decodes the recorded sample s into a new BPFEvent.
+func (i *Base[BPFObj, BPFEvent]) replayEvent(s RecordedSample) (*BPFEvent, error) {
+	if s.Probe != i.ID {
+		return nil, fmt.Errorf("sample recorded by probe %s, not %s", s.Probe, i.ID)
+	}
+	if layout := eventLayout[BPFEvent](); s.Layout != layout {
+		return nil, fmt.Errorf("%w: recorded %s, probe %s", errLayoutMismatch, s.Layout, layout)
+	}
+	return i.decode(perf.Record{RawSample: s.Sample})
+}
+
 // Close stops the Probe.
 func (i *Base[BPFObj, BPFEvent]) Close() error {
 	if i.collection != nil {
+		if n, err := i.DroppedSpans(); err == nil && n > 0 {
+			i.Logger.Info("dropped spans shorter than the minimum duration", "Probe", i.ID, "dropped", n)
+		}
 		i.collection.Close()
 	}
 	var err error
@@ -367,8 +528,31 @@ func (i *SpanProducer[BPFObj, BPFEvent]) Run(h *pipeline.Handler) {
 			continue
 		}
 
-		handler.Trace(i.ProcessFn(event))
+		handler.Trace(i.spans(event))
+	}
+}
+
+// spans returns the spans produced from event. Both the events read and the
+// ones replayed are processed by it, so they produce the same spans.
+func (i *SpanProducer[BPFObj, BPFEvent]) spans(event *BPFEvent) ptrace.SpanSlice {
+	spans := i.ProcessFn(event)
+	i.setBaggageAttributes(event, spans)
+	return spans
+}
+
+// Replay processes the recorded sample s as if it was read from the eBPF
+// programs of the probe. The produced spans are passed to h.
+func (i *SpanProducer[BPFObj, BPFEvent]) Replay(h *pipeline.Handler, s RecordedSample) error {
+	event, err := i.replayEvent(s)
+	if err != nil {
+		return err
 	}
+
+	scope := pcommon.NewInstrumentationScope()
+	scope.SetName("go.opentelemetry.io/auto/" + i.ID.InstrumentedPkg)
+	scope.SetVersion(i.Version)
+	h.WithScope(scope, i.SchemaURL).Trace(i.spans(event))
+	return nil
 }
 
 type TraceProducer[BPFObj any, BPFEvent any] struct {
@@ -402,6 +586,58 @@ func (i *TraceProducer[BPFObj, BPFEvent]) Run(h *pipeline.Handler) {
 	}
 }
 
+// Replay processes the recorded sample s as if it was read from the eBPF
+// programs of the probe. The produced spans are passed to h.
+func (i *TraceProducer[BPFObj, BPFEvent]) Replay(h *pipeline.Handler, s RecordedSample) error {
+	event, err := i.replayEvent(s)
+	if err != nil {
+		return err
+	}
+
+	if h.TraceHandler != nil {
+		scope, url, spans := i.ProcessFn(event)
+		h.TraceHandler.HandleTrace(scope, url, spans)
+	}
+	return nil
+}
+
+// Tracker is a [Probe] that only tracks state in the eBPF maps shared with the
+// other probes of the target process. It does not produce telemetry, so it
+// has no events read nor sampling configuration.
+type Tracker[BPFObj any] struct {
+	Base[BPFObj, struct{}]
+}
+
+// Load loads the eBPF programs and maps of the Tracker and attaches them to
+// the target process.
+func (i *Tracker[BPFObj]) Load(exec *link.Executable, info *process.Info, _ *sampling.Config) error {
+	spec, err := i.SpecFn()
+	if err != nil {
+		return err
+	}
+
+	err = i.InjectConsts(info, spec)
+	if err != nil {
+		return err
+	}
+
+	i.collection, err = i.buildEBPFCollection(info, spec)
+	if err != nil {
+		return err
+	}
+
+	return i.loadUprobes(exec, info)
+}
+
+// UpdateSampling does nothing, a Tracker has no sampling configuration.
+func (i *Tracker[BPFObj]) UpdateSampling(*sampling.Config) error {
+	return nil
+}
+
+// Run does nothing, a Tracker produces no telemetry. The tracking is done by
+// its eBPF programs until it is closed.
+func (i *Tracker[BPFObj]) Run(*pipeline.Handler) {}
+
 // Uprobe is an eBPF program that is attached in the entry point and/or the return of a function.
 type Uprobe struct {
 	// Sym is the symbol name of the function to attach the eBPF program to.
@@ -609,6 +845,40 @@ func (c StructFieldConstMinVersion) InjectOption(info *process.Info) (inject.Opt
 	return sf.InjectOption(info)
 }
 
+// StructFieldConstOptional is a [Const] for a struct field offset of a module
+// the target process may not use. No offset is injected, and the eBPF program
+// sees a zero value, if the module is not used or the offset is not found.
+type StructFieldConstOptional struct {
+	StructField StructFieldConst
+}
+
+var _ setLogger = StructFieldConstOptional{}
+
+// SetLogger sets the Logger for StructFieldConstOptional operations.
+func (c StructFieldConstOptional) SetLogger(l *slog.Logger) Const {
+	c.StructField.logger = l
+	return c
+}
+
+// InjectOption returns the appropriately configured [inject.WithOffset] if the
+// struct field module is used by the target process and the offset is found.
+// Otherwise, no offset is injected and no error is returned.
+func (c StructFieldConstOptional) InjectOption(info *process.Info) (inject.Option, error) {
+	sf := c.StructField
+	if _, ok := info.Modules[sf.ID.ModPath]; !ok {
+		return nil, nil
+	}
+
+	opt, err := sf.InjectOption(info)
+	if err != nil {
+		if sf.logger != nil {
+			sf.logger.Debug("optional offset not injected", "key", sf.Key, "error", err)
+		}
+		return nil, nil
+	}
+	return opt, nil
+}
+
 // AllocationConst is a [Const] for all the allocation details that need to be
 // injected into an eBPF program.
 type AllocationConst struct {
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"math"
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.opentelemetry.io/auto/internal/pkg/process"
+	"go.opentelemetry.io/auto/internal/pkg/structfield"
+)
+
+func TestStructFieldConstOptional(t *testing.T) {
+	info := &process.Info{
+		// No process has this ID, offsets cannot be found from its binary.
+		ID:        process.ID(math.MaxInt32),
+		GoVersion: semver.MustParse("1.24.0"),
+		Modules: map[string]*semver.Version{
+			"std":               semver.MustParse("1.24.0"),
+			"github.com/lib/pq": semver.MustParse("1.10.9"),
+		},
+	}
+
+	tests := []struct {
+		name   string
+		id     structfield.ID
+		inject bool
+	}{
+		{
+			name:   "cached",
+			id:     structfield.NewID("std", "database/sql", "Stmt", "query"),
+			inject: true,
+		},
+		{
+			name: "unknown module",
+			id:   structfield.NewID("github.com/jackc/pgconn", "github.com/jackc/pgconn", "PgError", "Code"),
+		},
+		{
+			name:   "cached module",
+			id:     structfield.NewID("github.com/lib/pq", "github.com/lib/pq", "Error", "Code"),
+			inject: true,
+		},
+		{
+			name: "not found",
+			id:   structfield.NewID("github.com/lib/pq", "github.com/lib/pq", "Error", "Detail"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := StructFieldConstOptional{StructField: StructFieldConst{Key: "pos", ID: tt.id}}
+			opt, err := c.InjectOption(info)
+			require.NoError(t, err)
+			assert.Equal(t, tt.inject, opt != nil)
+		})
+	}
+}
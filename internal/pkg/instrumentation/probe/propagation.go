@@ -0,0 +1,100 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"go.opentelemetry.io/auto/internal/pkg/inject"
+	"go.opentelemetry.io/auto/internal/pkg/process"
+)
+
+// Propagator is a context propagation format supported by the eBPF programs.
+//
+// The values need to match the propagator enum of the eBPF programs.
+type Propagator uint8
+
+const (
+	// PropagatorTraceContext is the W3C Trace Context format, the
+	// traceparent and tracestate headers.
+	PropagatorTraceContext Propagator = 1
+	// PropagatorB3 is the Zipkin B3 single header format.
+	PropagatorB3 Propagator = 2
+	// PropagatorB3Multi is the Zipkin B3 multiple header format.
+	PropagatorB3Multi Propagator = 3
+	// PropagatorJaeger is the Jaeger uber-trace-id header format.
+	PropagatorJaeger Propagator = 4
+	// PropagatorBaggage is the W3C Baggage format. It is propagated with the
+	// span context of the other propagators.
+	PropagatorBaggage Propagator = 5
+)
+
+const (
+	propagatorBits = 4
+	propagatorsKey = "propagators"
+)
+
+// DefaultPropagators are the propagators used if none are set.
+var DefaultPropagators = []Propagator{PropagatorTraceContext, PropagatorBaggage}
+
+// Propagating is a [Probe] that extracts and injects span context in the
+// requests it instruments.
+type Propagating interface {
+	// SetPropagators sets the propagators used, in priority order. The span
+	// context is extracted using the first propagator with headers found and
+	// injected using all of them. No context is propagated if p is empty.
+	//
+	// This needs to be called before Load.
+	SetPropagators(p []Propagator)
+
+	// SetBaggageAttributes sets the baggage keys whose values are set as
+	// attributes of the spans of the requests the baggage is propagated with.
+	SetBaggageAttributes(keys []string)
+}
+
+// encodePropagators returns the propagators constant value of the eBPF
+// programs for p. Each propagator is encoded in propagatorBits bits, in order
+// starting from the least significant bits. Duplicates are ignored.
+func encodePropagators(p []Propagator) uint64 {
+	var (
+		v    uint64
+		n    int
+		seen [PropagatorBaggage + 1]bool
+	)
+	for _, prop := range p {
+		if prop < PropagatorTraceContext || prop > PropagatorBaggage || seen[prop] {
+			continue
+		}
+		seen[prop] = true
+		v |= uint64(prop) << (n * propagatorBits)
+		n++
+	}
+	return v
+}
+
+// PropagatorsConst is a [Const] for the propagators used by the eBPF
+// programs. The propagators are the ones set on the [Base] using the Const,
+// [DefaultPropagators] if none are set.
+type PropagatorsConst struct {
+	propagators []Propagator
+}
+
+type setPropagators interface {
+	SetPropagators([]Propagator) Const
+}
+
+var _ setPropagators = PropagatorsConst{}
+
+// SetPropagators sets the propagators of the PropagatorsConst.
+func (c PropagatorsConst) SetPropagators(p []Propagator) Const {
+	c.propagators = p
+	return c
+}
+
+// InjectOption returns the appropriately configured [inject.WithKeyValue].
+func (c PropagatorsConst) InjectOption(*process.Info) (inject.Option, error) {
+	p := c.propagators
+	if p == nil {
+		p = DefaultPropagators
+	}
+	return inject.WithKeyValue(propagatorsKey, encodePropagators(p)), nil
+}
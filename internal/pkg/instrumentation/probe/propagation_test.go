@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodePropagators(t *testing.T) {
+	tests := []struct {
+		name string
+		p    []Propagator
+		want uint64
+	}{
+		{name: "Empty", want: 0},
+		{name: "Default", p: DefaultPropagators, want: 0x51},
+		{
+			name: "Order",
+			p:    []Propagator{PropagatorJaeger, PropagatorB3, PropagatorTraceContext},
+			want: 0x124,
+		},
+		{
+			name: "All",
+			p: []Propagator{
+				PropagatorTraceContext,
+				PropagatorB3,
+				PropagatorB3Multi,
+				PropagatorJaeger,
+				PropagatorBaggage,
+			},
+			want: 0x54321,
+		},
+		{
+			name: "Duplicates",
+			p:    []Propagator{PropagatorB3Multi, PropagatorB3Multi, PropagatorB3},
+			want: 0x23,
+		},
+		{
+			name: "Invalid",
+			p:    []Propagator{0, PropagatorB3, 6, 0xFF},
+			want: 0x2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, encodePropagators(tt.p))
+		})
+	}
+}
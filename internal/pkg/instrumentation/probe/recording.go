@@ -0,0 +1,312 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Masterminds/semver/v3"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/auto/internal/pkg/process"
+	"go.opentelemetry.io/auto/pipeline"
+)
+
+// recordingVersion is the version of the recording format written by a
+// Recorder. It needs to be incremented whenever an incompatible change is
+// made to the format.
+const recordingVersion = 1
+
+// errLayoutMismatch is returned when a recorded sample was produced for a
+// different event layout than the one used by the replaying probe.
+var errLayoutMismatch = errors.New("event layout mismatch")
+
+// Recordable is a [Probe] that can record the raw samples it reads from its
+// eBPF programs.
+type Recordable interface {
+	// SetRecorder sets the Recorder all raw samples read are recorded to.
+	// Recording is disabled if r is nil.
+	SetRecorder(r *Recorder)
+}
+
+// Replayer is a [Probe] that can process recorded raw samples without loading
+// any eBPF programs.
+type Replayer interface {
+	// PrepareReplay prepares the Probe to replay samples recorded from the
+	// target process described by info.
+	PrepareReplay(info *process.Info)
+	// Replay processes the recorded sample s as if it was read from the eBPF
+	// programs of the Probe. All telemetry produced is passed to h.
+	Replay(h *pipeline.Handler, s RecordedSample) error
+}
+
+// ProcessMetadata is the metadata of the target process a recording was made
+// from.
+type ProcessMetadata struct {
+	// PID is the process ID of the target process.
+	PID int `json:"pid"`
+	// Exe is the path of the executable run by the target process.
+	Exe string `json:"exe,omitempty"`
+	// GoVersion is the version of Go run by the target process.
+	GoVersion string `json:"go_version,omitempty"`
+	// Modules are the versions of the modules of the target process.
+	Modules map[string]string `json:"modules,omitempty"`
+}
+
+func newProcessMetadata(info *process.Info) *ProcessMetadata {
+	md := &ProcessMetadata{PID: int(info.ID)}
+	if exe, err := info.ID.ExeLink(); err == nil {
+		md.Exe = exe
+	}
+	if info.GoVersion != nil {
+		md.GoVersion = info.GoVersion.String()
+	}
+	if len(info.Modules) > 0 {
+		md.Modules = make(map[string]string, len(info.Modules))
+		for mod, ver := range info.Modules {
+			if ver != nil {
+				md.Modules[mod] = ver.String()
+			}
+		}
+	}
+	return md
+}
+
+// Info returns the [process.Info] described by md. Only the ID, GoVersion,
+// and Modules fields are set. Versions that cannot be parsed are ignored.
+func (md *ProcessMetadata) Info() *process.Info {
+	info := &process.Info{ID: process.ID(md.PID)}
+	if v, err := semver.NewVersion(md.GoVersion); err == nil {
+		info.GoVersion = v
+	}
+	info.Modules = make(map[string]*semver.Version, len(md.Modules))
+	for mod, ver := range md.Modules {
+		if v, err := semver.NewVersion(ver); err == nil {
+			info.Modules[mod] = v
+		}
+	}
+	return info
+}
+
+// RecordedConfig is the configuration of the probes a recording was made with
+// that affects the telemetry produced from the recorded samples.
+type RecordedConfig struct {
+	// Propagators are the values of the propagators used, in priority order.
+	// It is nil if the default propagators were used.
+	Propagators []int `json:"propagators"`
+	// BaggageAttributes are the baggage keys set as span attributes.
+	BaggageAttributes []string `json:"baggage_attributes,omitempty"`
+}
+
+func newRecordedConfig(propagators []Propagator, baggageKeys []string) *RecordedConfig {
+	cfg := &RecordedConfig{BaggageAttributes: baggageKeys}
+	if propagators != nil {
+		cfg.Propagators = make([]int, len(propagators))
+		for n, p := range propagators {
+			cfg.Propagators[n] = int(p)
+		}
+	}
+	return cfg
+}
+
+// Apply configures p the same way the probes recorded were.
+func (c *RecordedConfig) Apply(p Propagating) {
+	if c.Propagators != nil {
+		props := make([]Propagator, len(c.Propagators))
+		for n, v := range c.Propagators {
+			props[n] = Propagator(v)
+		}
+		p.SetPropagators(props)
+	}
+	p.SetBaggageAttributes(c.BaggageAttributes)
+}
+
+// RecordedSample is a raw sample read from the eBPF programs of a probe.
+type RecordedSample struct {
+	// Probe is the ID of the probe that read the sample.
+	Probe ID
+	// Layout is the version of the event struct layout the sample was
+	// encoded with.
+	Layout string
+	// Sample is the raw sample data.
+	Sample []byte
+}
+
+// recordingEntry is a single line of a recording.
+type recordingEntry struct {
+	Version int              `json:"version,omitempty"`
+	Process *ProcessMetadata `json:"process,omitempty"`
+	Config  *RecordedConfig  `json:"config,omitempty"`
+	Pkg     string           `json:"pkg,omitempty"`
+	Kind    trace.SpanKind   `json:"kind,omitempty"`
+	Layout  string           `json:"layout,omitempty"`
+	Sample  []byte           `json:"sample,omitempty"`
+}
+
+// Recorder records raw samples read by probes, along with the metadata of the
+// target process they were read from.
+//
+// The recording is written as newline-delimited JSON. It can be read with a
+// [RecordingReader].
+//
+// A Recorder is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	enc     *json.Encoder
+	started bool
+	proc    *ProcessMetadata
+	cfg     *RecordedConfig
+}
+
+// NewRecorder returns a new [Recorder] that writes the recording to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w)}
+}
+
+func (r *Recorder) writeLocked(e recordingEntry) error {
+	if !r.started {
+		if err := r.enc.Encode(recordingEntry{Version: recordingVersion}); err != nil {
+			return err
+		}
+		if r.cfg != nil {
+			if err := r.enc.Encode(recordingEntry{Config: r.cfg}); err != nil {
+				return err
+			}
+		}
+		r.started = true
+	}
+	return r.enc.Encode(e)
+}
+
+// SetConfig sets the propagators and baggage keys the probes recorded are
+// configured with. They are recorded so the samples are replayed with the
+// same configuration.
+//
+// This needs to be called before anything is recorded.
+func (r *Recorder) SetConfig(propagators []Propagator, baggageKeys []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cfg = newRecordedConfig(propagators, baggageKeys)
+}
+
+// recordProcess records the metadata of the target process described by info
+// if it differs from the last recorded one.
+func (r *Recorder) recordProcess(info *process.Info) error {
+	md := newProcessMetadata(info)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.proc != nil && reflect.DeepEqual(r.proc, md) {
+		return nil
+	}
+	r.proc = md
+	return r.writeLocked(recordingEntry{Process: md})
+}
+
+// record records the raw sample read by the probe with id.
+func (r *Recorder) record(id ID, layout string, sample []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.writeLocked(recordingEntry{
+		Pkg:    id.InstrumentedPkg,
+		Kind:   id.SpanKind,
+		Layout: layout,
+		Sample: sample,
+	})
+}
+
+// RecordingReader reads a recording written by a [Recorder].
+type RecordingReader struct {
+	dec  *json.Decoder
+	proc *ProcessMetadata
+	cfg  *RecordedConfig
+}
+
+// NewRecordingReader returns a new [RecordingReader] reading from r. An error
+// is returned if r does not contain a supported recording.
+func NewRecordingReader(r io.Reader) (*RecordingReader, error) {
+	dec := json.NewDecoder(r)
+
+	var header recordingEntry
+	if err := dec.Decode(&header); err != nil {
+		return nil, fmt.Errorf("invalid recording header: %w", err)
+	}
+	if header.Version != recordingVersion {
+		return nil, fmt.Errorf("unsupported recording version: %d", header.Version)
+	}
+	return &RecordingReader{dec: dec}, nil
+}
+
+// Process returns the metadata of the target process the last sample returned
+// by Next was recorded from. It returns nil if no metadata was recorded.
+func (r *RecordingReader) Process() *ProcessMetadata {
+	return r.proc
+}
+
+// Config returns the configuration of the probes the samples were recorded
+// with. It returns nil if no configuration was recorded.
+func (r *RecordingReader) Config() *RecordedConfig {
+	return r.cfg
+}
+
+// Next returns the next recorded sample. It returns [io.EOF] when there are no
+// more samples.
+func (r *RecordingReader) Next() (RecordedSample, error) {
+	for {
+		var e recordingEntry
+		if err := r.dec.Decode(&e); err != nil {
+			return RecordedSample{}, err
+		}
+		if e.Process != nil {
+			r.proc = e.Process
+			continue
+		}
+		if e.Config != nil {
+			r.cfg = e.Config
+			continue
+		}
+		return RecordedSample{
+			Probe:  ID{SpanKind: e.Kind, InstrumentedPkg: e.Pkg},
+			Layout: e.Layout,
+			Sample: e.Sample,
+		}, nil
+	}
+}
+
+// eventLayout returns the layout version of T. The version changes whenever
+// the name, type, or offset of any of the fields of T change.
+func eventLayout[T any]() string {
+	var b strings.Builder
+	writeLayout(&b, reflect.TypeFor[T](), 0)
+
+	h := fnv.New64a()
+	_, _ = io.WriteString(h, b.String())
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func writeLayout(b *strings.Builder, t reflect.Type, offset uintptr) {
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := range t.NumField() {
+			f := t.Field(i)
+			fmt.Fprintf(b, "%s@%d;", f.Name, offset+f.Offset)
+			writeLayout(b, f.Type, offset+f.Offset)
+		}
+	case reflect.Array:
+		fmt.Fprintf(b, "[%d]", t.Len())
+		writeLayout(b, t.Elem(), offset)
+	default:
+		fmt.Fprintf(b, "%s:%d;", t.Kind(), t.Size())
+	}
+}
@@ -0,0 +1,207 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package probe
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"testing"
+	"unsafe"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/cilium/ebpf/perf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/context"
+	"go.opentelemetry.io/auto/internal/pkg/process"
+	"go.opentelemetry.io/auto/pipeline"
+)
+
+type testEvent struct {
+	StartTime uint64
+	EndTime   uint64
+}
+
+type traceHandler struct {
+	scopes []pcommon.InstrumentationScope
+	spans  []ptrace.SpanSlice
+}
+
+func (h *traceHandler) HandleTrace(scope pcommon.InstrumentationScope, _ string, spans ptrace.SpanSlice) {
+	h.scopes = append(h.scopes, scope)
+	h.spans = append(h.spans, spans)
+}
+
+func newTestSpanProducer(id ID) *SpanProducer[struct{}, testEvent] {
+	return &SpanProducer[struct{}, testEvent]{
+		Base: Base[struct{}, testEvent]{
+			ID:     id,
+			Logger: slog.New(slog.DiscardHandler),
+		},
+		Version: "v1.0.0",
+		ProcessFn: func(e *testEvent) ptrace.SpanSlice {
+			spans := ptrace.NewSpanSlice()
+			span := spans.AppendEmpty()
+			span.SetName("test")
+			span.SetStartTimestamp(pcommon.Timestamp(e.StartTime))
+			span.SetEndTimestamp(pcommon.Timestamp(e.EndTime))
+			return spans
+		},
+	}
+}
+
+func TestRecordingRoundTrip(t *testing.T) {
+	id := ID{SpanKind: trace.SpanKindClient, InstrumentedPkg: "pkg"}
+	p := newTestSpanProducer(id)
+
+	var buf bytes.Buffer
+	r := NewRecorder(&buf)
+	p.SetRecorder(r)
+
+	info := &process.Info{
+		ID:        1,
+		GoVersion: semver.MustParse("1.24.0"),
+		Modules:   map[string]*semver.Version{"std": semver.MustParse("1.24.0")},
+	}
+	require.NoError(t, r.recordProcess(info))
+	// Duplicate process metadata is not recorded.
+	require.NoError(t, r.recordProcess(info))
+
+	sample := make([]byte, 16)
+	binary.NativeEndian.PutUint64(sample[0:], 1)
+	binary.NativeEndian.PutUint64(sample[8:], 2)
+	require.NoError(t, r.record(id, p.layout, sample))
+
+	assert.Equal(t, 3, bytes.Count(buf.Bytes(), []byte("\n")), "header, process, sample")
+
+	rr, err := NewRecordingReader(&buf)
+	require.NoError(t, err)
+
+	s, err := rr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, id, s.Probe)
+	assert.Equal(t, sample, s.Sample)
+
+	require.NotNil(t, rr.Process())
+	got := rr.Process().Info()
+	assert.Equal(t, info.ID, got.ID)
+	assert.True(t, info.GoVersion.Equal(got.GoVersion))
+	assert.Contains(t, got.Modules, "std")
+
+	_, err = rr.Next()
+	assert.ErrorIs(t, err, io.EOF)
+
+	th := new(traceHandler)
+	require.NoError(t, p.Replay(&pipeline.Handler{TraceHandler: th}, s))
+	require.Len(t, th.spans, 1)
+	assert.Equal(t, "go.opentelemetry.io/auto/pkg", th.scopes[0].Name())
+	assert.Equal(t, "v1.0.0", th.scopes[0].Version())
+	span := th.spans[0].At(0)
+	assert.Equal(t, pcommon.Timestamp(1), span.StartTimestamp())
+	assert.Equal(t, pcommon.Timestamp(2), span.EndTimestamp())
+}
+
+func newBaggageSpanProducer(id ID) *SpanProducer[struct{}, baggageEvent] {
+	return &SpanProducer[struct{}, baggageEvent]{
+		Base: Base[struct{}, baggageEvent]{
+			ID:     id,
+			Logger: slog.New(slog.DiscardHandler),
+		},
+		Version: "v1.0.0",
+		ProcessFn: func(e *baggageEvent) ptrace.SpanSlice {
+			spans := ptrace.NewSpanSlice()
+			span := spans.AppendEmpty()
+			span.SetName("test")
+			span.SetStartTimestamp(pcommon.Timestamp(e.StartTime))
+			span.SetEndTimestamp(pcommon.Timestamp(e.EndTime))
+			span.TraceState().FromRaw(e.State.TraceState())
+			return spans
+		},
+	}
+}
+
+func TestReplayMatchesRun(t *testing.T) {
+	id := ID{SpanKind: trace.SpanKindServer, InstrumentedPkg: "pkg"}
+	props := []Propagator{PropagatorB3, PropagatorBaggage}
+	keys := []string{"tenant.id"}
+
+	live := newBaggageSpanProducer(id)
+	live.SetPropagators(props)
+	live.SetBaggageAttributes(keys)
+
+	var buf bytes.Buffer
+	r := NewRecorder(&buf)
+	r.SetConfig(props, keys)
+	live.SetRecorder(r)
+
+	e := baggageEvent{}
+	e.StartTime, e.EndTime = 1, 2
+	e.State.OT = context.OTTraceState{Randomness: 0x1, HasRandomness: 1}
+	e.State.Members.Len = uint32(copy(e.State.Members.Entries[:], "vendor=value"))
+	e.State.Baggage.Len = uint32(copy(e.State.Baggage.Value[:], "tenant.id=acme,user.id=42"))
+	raw := bytes.Clone(unsafe.Slice((*byte)(unsafe.Pointer(&e)), unsafe.Sizeof(e)))
+
+	event, err := live.readRecord(perf.Record{RawSample: raw})
+	require.NoError(t, err)
+	want := live.spans(event)
+	require.Equal(t, 1, want.Len())
+	assert.Equal(t, "ot=rv:00000000000001,vendor=value", want.At(0).TraceState().AsRaw())
+	assert.Equal(t, map[string]any{"tenant.id": "acme"}, want.At(0).Attributes().AsRaw())
+
+	rr, err := NewRecordingReader(&buf)
+	require.NoError(t, err)
+	s, err := rr.Next()
+	require.NoError(t, err)
+
+	replay := newBaggageSpanProducer(id)
+	require.NotNil(t, rr.Config())
+	rr.Config().Apply(replay)
+	assert.Equal(t, props, replay.propagators)
+	assert.Equal(t, keys, replay.baggageKeys)
+
+	th := new(traceHandler)
+	require.NoError(t, replay.Replay(&pipeline.Handler{TraceHandler: th}, s))
+	require.Len(t, th.spans, 1)
+	assert.Equal(t, want, th.spans[0])
+}
+
+func TestReplayErrors(t *testing.T) {
+	id := ID{SpanKind: trace.SpanKindClient, InstrumentedPkg: "pkg"}
+	p := newTestSpanProducer(id)
+	h := &pipeline.Handler{TraceHandler: new(traceHandler)}
+	layout := eventLayout[testEvent]()
+
+	err := p.Replay(h, RecordedSample{Probe: id, Layout: "unknown", Sample: make([]byte, 16)})
+	assert.ErrorIs(t, err, errLayoutMismatch)
+
+	other := ID{SpanKind: trace.SpanKindServer, InstrumentedPkg: "pkg"}
+	err = p.Replay(h, RecordedSample{Probe: other, Layout: layout, Sample: make([]byte, 16)})
+	assert.Error(t, err, "probe ID mismatch")
+
+	err = p.Replay(h, RecordedSample{Probe: id, Layout: layout, Sample: make([]byte, 8)})
+	assert.Error(t, err, "short sample")
+}
+
+func TestNewRecordingReaderInvalid(t *testing.T) {
+	_, err := NewRecordingReader(bytes.NewBufferString(""))
+	assert.Error(t, err, "empty")
+
+	_, err = NewRecordingReader(bytes.NewBufferString(`{"version":999}` + "\n"))
+	assert.Error(t, err, "unsupported version")
+}
+
+func TestEventLayout(t *testing.T) {
+	type other struct {
+		StartTime uint64
+		EndTime   uint32
+	}
+	assert.Equal(t, eventLayout[testEvent](), eventLayout[testEvent]())
+	assert.NotEqual(t, eventLayout[testEvent](), eventLayout[other]())
+}
@@ -11,6 +11,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strings"
+	"time"
 
 	"github.com/cilium/ebpf"
 )
@@ -25,7 +27,10 @@ const (
 	SamplerTraceIDRatio
 	SamplerParentBased
 
-	// Custom samplers TODO.
+	// Custom samplers.
+	SamplerRuleBased
+	SamplerRateLimiting
+	SamplerProbability
 )
 
 type TraceIDRatioConfig struct {
@@ -42,6 +47,170 @@ func NewTraceIDRatioConfig(ratio float64) (TraceIDRatioConfig, error) {
 	return TraceIDRatioConfig{numerator}, nil
 }
 
+// AttributeKind is the kind of probe-supplied span attribute value a rule of a
+// rule-based sampler matches.
+type AttributeKind uint8
+
+const (
+	// AttributeNone matches any span.
+	AttributeNone AttributeKind = iota
+	// AttributeHTTPPath is the URL path of an HTTP request.
+	AttributeHTTPPath
+	// AttributeRPCMethod is the full method name of a gRPC call (e.g.
+	// "/pkg.Service/Method").
+	AttributeRPCMethod
+	// AttributeMessagingDestination is the topic of a Kafka message.
+	AttributeMessagingDestination
+	// AttributeDBOperation is the database query text. It is matched with
+	// [MatchWord].
+	AttributeDBOperation
+)
+
+// MatchType is the way an attribute value is matched by a rule.
+type MatchType uint8
+
+const (
+	// MatchExact matches values equal to the rule value.
+	MatchExact MatchType = iota
+	// MatchPrefix matches values starting with the rule value.
+	MatchPrefix
+	// MatchWord matches values starting with the rule value, compared
+	// case-insensitively, followed by a non-word character or the end of the
+	// value.
+	MatchWord
+)
+
+// Rule is a rule of a rule-based sampler.
+type Rule struct {
+	// Method is the HTTP method matched exactly. If empty, any method
+	// matches.
+	Method string
+	// Kind is the kind of attribute value matched. If AttributeNone, Value
+	// and Match are ignored.
+	Kind AttributeKind
+	// Match is how Value is matched.
+	Match MatchType
+	// Value is the attribute value matched.
+	Value string
+	// Delegate is the ID of the sampler used when the rule matches.
+	Delegate SamplerID
+}
+
+// RuleConfig is the eBPF representation of a [Rule].
+type RuleConfig struct {
+	Delegate  SamplerID
+	Kind      AttributeKind
+	Match     MatchType
+	ValueLen  uint8
+	MethodLen uint8
+	Method    [MaxRuleMethodSize]byte
+	Value     [MaxRuleValueSize]byte
+}
+
+// RuleBasedConfig holds the configuration for the rule-based sampler.
+//
+// The first matching rule selects the sampler used. If no rule matches, the
+// Fallback sampler is used. Only samplers that do not delegate to other
+// samplers can be used by rules or as Fallback.
+type RuleBasedConfig struct {
+	RulesCount uint32
+	Fallback   SamplerID
+	Rules      [MaxRules]RuleConfig
+}
+
+// NewRuleBasedConfig returns a new [RuleBasedConfig] for rules, evaluated in
+// order, and the fallback sampler ID.
+func NewRuleBasedConfig(rules []Rule, fallback SamplerID) (RuleBasedConfig, error) {
+	if len(rules) > MaxRules {
+		return RuleBasedConfig{}, fmt.Errorf("too many sampling rules: %d (max %d)", len(rules), MaxRules)
+	}
+
+	c := RuleBasedConfig{RulesCount: uint32(len(rules)), Fallback: fallback} //nolint:gosec  // Bound checked.
+	for i, r := range rules {
+		if len(r.Method) > MaxRuleMethodSize {
+			return RuleBasedConfig{}, fmt.Errorf("sampling rule %d: method longer than %d bytes", i, MaxRuleMethodSize)
+		}
+		if r.Kind != AttributeNone && len(r.Value) > MaxRuleValueSize {
+			return RuleBasedConfig{}, fmt.Errorf("sampling rule %d: value longer than %d bytes", i, MaxRuleValueSize)
+		}
+
+		rc := RuleConfig{
+			Delegate:  r.Delegate,
+			Kind:      r.Kind,
+			MethodLen: uint8(len(r.Method)), //nolint:gosec  // Bound checked.
+		}
+		copy(rc.Method[:], r.Method)
+		if r.Kind != AttributeNone {
+			rc.Match = r.Match
+			value := r.Value
+			if r.Match == MatchWord {
+				value = strings.ToLower(value)
+			}
+			rc.ValueLen = uint8(len(value)) //nolint:gosec  // Bound checked.
+			copy(rc.Value[:], value)
+		}
+		c.Rules[i] = rc
+	}
+	return c, nil
+}
+
+// RateLimitingConfig holds the configuration for the rate-limiting sampler.
+//
+// The sampler is a token bucket where the tokens are the time credit accrued.
+// A span is sampled if at least IntervalNs of credit is available, which is
+// then consumed.
+type RateLimitingConfig struct {
+	// IntervalNs is the time, in nanoseconds, it takes to accrue the credit
+	// for one span. If zero, no span is sampled.
+	IntervalNs uint64
+	// MaxCreditNs is the maximum credit, in nanoseconds, that can be
+	// accrued.
+	MaxCreditNs uint64
+}
+
+// NewRateLimitingConfig returns a new [RateLimitingConfig] that samples at
+// most spansPerSecond spans per second. Up to one second worth of spans can be
+// sampled in a burst.
+func NewRateLimitingConfig(spansPerSecond float64) (RateLimitingConfig, error) {
+	if math.IsNaN(spansPerSecond) || spansPerSecond < 0 {
+		return RateLimitingConfig{}, fmt.Errorf("invalid spans per second: %v", spansPerSecond)
+	}
+	if spansPerSecond == 0 {
+		return RateLimitingConfig{}, nil
+	}
+
+	const (
+		second = float64(time.Second)
+		// Bound the interval so the credit accrued cannot overflow.
+		maxInterval = float64(math.MaxUint64 / 4)
+	)
+	interval := second / spansPerSecond
+	if interval > maxInterval {
+		return RateLimitingConfig{}, fmt.Errorf("spans per second too small: %v", spansPerSecond)
+	}
+	c := RateLimitingConfig{IntervalNs: max(uint64(interval), 1)}
+	c.MaxCreditNs = max(c.IntervalNs, uint64(time.Second))
+	return c, nil
+}
+
+// ProbabilityConfig holds the configuration for the consistent probability
+// sampler.
+type ProbabilityConfig struct {
+	// Threshold is the 56-bit rejection threshold. A span is sampled if the
+	// randomness of its trace is greater than or equal to it. If
+	// MaxThreshold, no span is sampled.
+	Threshold uint64
+}
+
+// NewProbabilityConfig returns a new [ProbabilityConfig] that samples traces
+// with probability p, which needs to be in the closed interval [0, 1].
+func NewProbabilityConfig(p float64) (ProbabilityConfig, error) {
+	if math.IsNaN(p) || p < 0 || p > 1 {
+		return ProbabilityConfig{}, fmt.Errorf("invalid probability: %v", p)
+	}
+	return ProbabilityConfig{Threshold: uint64(math.Round((1 - p) * MaxThreshold))}, nil
+}
+
 // SamplerID is a unique identifier for a sampler. It is used as a key in the samplers config map,
 // and as a value in the active sampler map. In addition samplers can reference other samplers in their configuration by their ID.
 type SamplerID uint32
@@ -94,6 +263,18 @@ const (
 	// This value can limit the precision of the sampling rate, hence setting it to a high value should be enough in terms of precision.
 	samplingRateDenominator = math.MaxUint32
 	maxSamplers             = 32
+
+	// MaxRules is the maximum number of rules of a rule-based sampler.
+	MaxRules = 5
+	// MaxRuleMethodSize is the maximum length of a rule method.
+	MaxRuleMethodSize = 8
+	// MaxRuleValueSize is the maximum length of a rule value. Only this many
+	// bytes of the attribute values are captured by the probes.
+	MaxRuleValueSize = 32
+
+	// MaxThreshold is the rejection threshold of the consistent probability
+	// sampler with which no span is sampled.
+	MaxThreshold = 1 << 56
 )
 
 // The spec-defined samplers have a constant ID, and are always available.
@@ -104,6 +285,20 @@ const (
 	ParentBasedID  SamplerID = 3
 )
 
+// The custom samplers have a constant ID.
+const (
+	RuleBasedID    SamplerID = 4
+	RateLimitingID SamplerID = 5
+	ProbabilityID  SamplerID = 6
+)
+
+// RuleBasedDelegateID returns the ID of the i-th sampler delegated to by the
+// rule-based sampler.
+func RuleBasedDelegateID(i int) SamplerID {
+	const first = 16
+	return SamplerID(first + i) //nolint:gosec  // Bounded by MaxRules.
+}
+
 // SamplerConfig holds the configuration for a specific sampler. data for samplers is a union of all possible sampler configurations.
 // the size of the data is fixed, and the actual configuration is stored in the first part of the data.
 // the rest of the data is padding to make sure the size is fixed.
@@ -164,6 +359,27 @@ func (sc *SamplerConfig) UnmarshalBinary(data []byte) error {
 			return err
 		}
 		sc.Config = parentBased
+	case SamplerRuleBased:
+		var ruleBased RuleBasedConfig
+		err := binary.Read(readingBuffer, binary.NativeEndian, &ruleBased)
+		if err != nil {
+			return err
+		}
+		sc.Config = ruleBased
+	case SamplerRateLimiting:
+		var rateLimiting RateLimitingConfig
+		err := binary.Read(readingBuffer, binary.NativeEndian, &rateLimiting)
+		if err != nil {
+			return err
+		}
+		sc.Config = rateLimiting
+	case SamplerProbability:
+		var probability ProbabilityConfig
+		err := binary.Read(readingBuffer, binary.NativeEndian, &probability)
+		if err != nil {
+			return err
+		}
+		sc.Config = probability
 	}
 
 	return nil
@@ -221,6 +437,11 @@ func NewSamplingManager(c *ebpf.Collection, conf *Config) (*Manager, error) {
 	return m, nil
 }
 
+// ApplyConfig replaces the sampling configuration used by eBPF with conf.
+func (m *Manager) ApplyConfig(conf *Config) error {
+	return m.applyConfig(conf)
+}
+
 func (m *Manager) applyConfig(conf *Config) error {
 	if conf == nil {
 		return errors.New("cannot apply nil config")
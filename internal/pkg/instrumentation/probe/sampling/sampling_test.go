@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package sampling
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleBasedConfigBinary(t *testing.T) {
+	rbc, err := NewRuleBasedConfig([]Rule{
+		{Method: "GET", Kind: AttributeHTTPPath, Match: MatchExact, Value: "/healthz", Delegate: 16},
+		{Kind: AttributeDBOperation, Match: MatchWord, Value: "Select", Delegate: 17},
+	}, 18)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(2), rbc.RulesCount)
+	assert.Equal(t, "select", string(rbc.Rules[1].Value[:rbc.Rules[1].ValueLen]))
+
+	sc := SamplerConfig{SamplerType: SamplerRuleBased, Config: rbc}
+	b, err := sc.MarshalBinary()
+	require.NoError(t, err)
+	assert.Len(t, b, sampleConfigSize)
+
+	var got SamplerConfig
+	require.NoError(t, got.UnmarshalBinary(b))
+	assert.Equal(t, sc, got)
+}
+
+func TestNewRuleBasedConfigErrors(t *testing.T) {
+	_, err := NewRuleBasedConfig(make([]Rule, MaxRules+1), AlwaysOnID)
+	assert.Error(t, err, "too many rules")
+
+	_, err = NewRuleBasedConfig([]Rule{{Method: "TOOLONGMETHOD"}}, AlwaysOnID)
+	assert.Error(t, err, "method too long")
+
+	_, err = NewRuleBasedConfig([]Rule{{
+		Kind:  AttributeHTTPPath,
+		Value: strings.Repeat("a", MaxRuleValueSize+1),
+	}}, AlwaysOnID)
+	assert.Error(t, err, "value too long")
+}
+
+func TestNewRateLimitingConfig(t *testing.T) {
+	c, err := NewRateLimitingConfig(10)
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitingConfig{
+		IntervalNs:  uint64(100 * time.Millisecond),
+		MaxCreditNs: uint64(time.Second),
+	}, c)
+
+	// Rates lower than one span per second still allow one span.
+	c, err = NewRateLimitingConfig(0.5)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2*time.Second), c.IntervalNs)
+	assert.Equal(t, c.IntervalNs, c.MaxCreditNs)
+
+	c, err = NewRateLimitingConfig(0)
+	require.NoError(t, err)
+	assert.Equal(t, RateLimitingConfig{}, c)
+
+	sc := SamplerConfig{SamplerType: SamplerRateLimiting, Config: c}
+	b, err := sc.MarshalBinary()
+	require.NoError(t, err)
+	var got SamplerConfig
+	require.NoError(t, got.UnmarshalBinary(b))
+	assert.Equal(t, sc, got)
+
+	_, err = NewRateLimitingConfig(-1)
+	assert.Error(t, err, "negative")
+	_, err = NewRateLimitingConfig(math.NaN())
+	assert.Error(t, err, "NaN")
+	_, err = NewRateLimitingConfig(1e-12)
+	assert.Error(t, err, "too small")
+}
+
+func TestNewProbabilityConfig(t *testing.T) {
+	tests := []struct {
+		p    float64
+		want uint64
+	}{
+		{p: 1, want: 0},
+		{p: 0.5, want: 0x80000000000000},
+		{p: 0.25, want: 0xc0000000000000},
+		{p: 0, want: MaxThreshold},
+	}
+	for _, tt := range tests {
+		c, err := NewProbabilityConfig(tt.p)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, c.Threshold, "probability %v", tt.p)
+	}
+
+	for _, p := range []float64{-0.1, 1.1, math.NaN()} {
+		_, err := NewProbabilityConfig(p)
+		assert.Error(t, err, "probability %v", p)
+	}
+
+	c, err := NewProbabilityConfig(0.1)
+	require.NoError(t, err)
+	sc := SamplerConfig{SamplerType: SamplerProbability, Config: c}
+	b, err := sc.MarshalBinary()
+	require.NoError(t, err)
+	var got SamplerConfig
+	require.NoError(t, got.UnmarshalBinary(b))
+	assert.Equal(t, sc, got)
+}
@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package binary
+
+import (
+	"debug/elf"
+	"strings"
+)
+
+// Prefixes of the symbols of the itabs generated by the Go linker. The "go."
+// prefix was used before Go 1.20.
+var itabPrefixes = []string{"go:itab.", "go.itab."}
+
+// FindItabs returns the addresses of the itabs found in the symbol table of
+// elfF, keyed by "<type>,<interface>". For example, the itab of the
+// *net.OpError type implementing the error interface is keyed by
+// "*net.OpError,error".
+//
+// An [elf.ErrNoSymbols] error is returned if the binary is stripped.
+func FindItabs(elfF *elf.File) (map[string]uint64, error) {
+	symbols, err := elfF.Symbols()
+	if err != nil {
+		return nil, err
+	}
+
+	itabs := make(map[string]uint64)
+	for _, s := range symbols {
+		for _, prefix := range itabPrefixes {
+			if name, ok := strings.CutPrefix(s.Name, prefix); ok {
+				itabs[name] = s.Value
+				break
+			}
+		}
+	}
+	return itabs, nil
+}
@@ -4,13 +4,16 @@
 package process
 
 import (
+	"bufio"
 	"debug/buildinfo"
+	"debug/elf"
 	"errors"
 	"fmt"
 	"io/fs"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"syscall"
 )
 
@@ -61,6 +64,9 @@ func (id ID) ExePath() string { return id.dir() + "/exe" }
 // taskPath returns the file path for the tasks directory of the process ID.
 func (id ID) taskPath() string { return id.dir() + "/task" }
 
+// mapsPath returns the file path for the memory mappings of the process ID.
+func (id ID) mapsPath() string { return id.dir() + "/maps" }
+
 // ExeLink returns the resolved absolute path to the linked executable being
 // run by the process.
 func (id ID) ExeLink() (string, error) {
@@ -91,3 +97,60 @@ func (id ID) BuildInfo() (*buildinfo.BuildInfo, error) {
 }
 
 var buildinfoReadFile = buildinfo.ReadFile
+
+// loadBias returns the difference between the runtime addresses of the
+// process ID and the virtual addresses of its executable elfF. It is 0 if the
+// executable is not position independent.
+func (id ID) loadBias(elfF *elf.File) (uint64, error) {
+	if elfF.Type != elf.ET_DYN {
+		return 0, nil
+	}
+
+	// The virtual address of the start of the executable file.
+	var vaddr uint64
+	found := false
+	for _, p := range elfF.Progs {
+		if p.Type == elf.PT_LOAD && p.Off == 0 {
+			vaddr, found = p.Vaddr, true
+			break
+		}
+	}
+	if !found {
+		return 0, errors.New("no loadable segment at the start of the executable")
+	}
+
+	exe, err := id.ExeLink()
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Open(id.mapsPath())
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	// Lines are formatted as:
+	//
+	//	<start>-<end> <perms> <offset> <dev> <inode> <path>
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || strings.Join(fields[5:], " ") != exe {
+			continue
+		}
+		if off, err := strconv.ParseUint(fields[2], 16, 64); err != nil || off != 0 {
+			continue
+		}
+		start, _, _ := strings.Cut(fields[0], "-")
+		addr, err := strconv.ParseUint(start, 16, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid mapping address %q: %w", start, err)
+		}
+		return addr - vaddr, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, fmt.Errorf("executable %s not mapped", exe)
+}
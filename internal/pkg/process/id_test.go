@@ -5,6 +5,7 @@ package process
 
 import (
 	"debug/buildinfo"
+	"debug/elf"
 	"os"
 	"path/filepath"
 	"runtime/debug"
@@ -184,3 +185,46 @@ func TestIDBuildInfo(t *testing.T) {
 	want := &debug.BuildInfo{Path: app.Name(), GoVersion: ver}
 	assert.Equal(t, want, got)
 }
+
+func TestIDLoadBias(t *testing.T) {
+	const pid = 100
+	app := setup(t, pid)
+
+	ln := filepath.Join(procDir(pid), "exe")
+	require.NoError(t, os.Symlink(app.Name(), ln))
+
+	const other = "55d1a4a00000-55d1a4c00000 r--p 00000000 08:01 1234 /usr/lib/other\n"
+	maps := other +
+		"7f0e7e200000-7f0e7e4f1000 r--p 00000000 08:01 5678 " + app.Name() + "\n" +
+		"7f0e7e4f1000-7f0e7e8b5000 r-xp 002f1000 08:01 5678 " + app.Name() + "\n" +
+		"7ffd3b1c0000-7ffd3b1e1000 rw-p 00000000 00:00 0 [stack]\n"
+	require.NoError(t, os.WriteFile(ID(pid).mapsPath(), []byte(maps), 0o600))
+
+	elfFile := func(typ elf.Type, vaddr uint64) *elf.File {
+		return &elf.File{
+			FileHeader: elf.FileHeader{Type: typ},
+			Progs: []*elf.Prog{
+				{ProgHeader: elf.ProgHeader{Type: elf.PT_PHDR, Off: 0x40, Vaddr: vaddr + 0x40}},
+				{ProgHeader: elf.ProgHeader{Type: elf.PT_LOAD, Off: 0, Vaddr: vaddr}},
+			},
+		}
+	}
+
+	t.Run("Executable", func(t *testing.T) {
+		got, err := ID(pid).loadBias(elfFile(elf.ET_EXEC, 0x400000))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), got)
+	})
+
+	t.Run("PositionIndependent", func(t *testing.T) {
+		got, err := ID(pid).loadBias(elfFile(elf.ET_DYN, 0))
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0x7f0e7e200000), got)
+	})
+
+	t.Run("NotMapped", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(ID(pid).mapsPath(), []byte(other), 0o600))
+		_, err := ID(pid).loadBias(elfFile(elf.ET_DYN, 0))
+		assert.Error(t, err)
+	})
+}
@@ -34,6 +34,10 @@ type Info struct {
 	aDone atomic.Bool
 	aMu   sync.Mutex
 	a     *Allocation
+
+	itabsOnce sync.Once
+	itabs     map[string]uint64
+	itabsErr  error
 }
 
 // Alloc allocates memory for the process described by Info i.
@@ -189,6 +193,45 @@ func findFunctions(elfF *elf.File, relevantFuncs map[string]any) ([]*binary.Func
 	return found, nil
 }
 
+// Itab returns the address of the itab of the concrete type typ implementing
+// the interface iface in the binary of the process described by Info i. Type
+// names are fully qualified, e.g. "*net.OpError" implementing "error".
+//
+// Zero is returned if typ is never converted to iface in the binary. An error
+// is returned if the binary cannot be read or its symbol table is stripped.
+// The address is the runtime one, relocated by the load address of the binary
+// if it is position independent.
+//
+// It is safe to call this method concurrently.
+func (i *Info) Itab(typ, iface string) (uint64, error) {
+	i.itabsOnce.Do(func() {
+		i.itabs, i.itabsErr = i.findItabs()
+	})
+	return i.itabs[typ+","+iface], i.itabsErr
+}
+
+func (i *Info) findItabs() (map[string]uint64, error) {
+	elfF, err := elf.Open(i.ID.ExePath())
+	if err != nil {
+		return nil, err
+	}
+	defer elfF.Close()
+
+	itabs, err := binary.FindItabs(elfF)
+	if err != nil {
+		return nil, err
+	}
+
+	bias, err := i.ID.loadBias(elfF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the load address: %w", err)
+	}
+	for k, v := range itabs {
+		itabs[k] = v + bias
+	}
+	return itabs, nil
+}
+
 // GetFunctionOffset returns the offset for of the function with name.
 func (i *Info) GetFunctionOffset(name string) (uint64, error) {
 	for _, f := range i.Functions {
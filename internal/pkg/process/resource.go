@@ -0,0 +1,281 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+// Detector is a [resource.Detector] that detects the resource attributes of
+// the target process, its container, and its host by reading /proc/<pid>.
+//
+// The detected attributes describe the target process, not the process
+// running the instrumentation.
+type Detector struct {
+	ID ID
+}
+
+var _ resource.Detector = Detector{}
+
+// Detect returns a [resource.Resource] describing the process d is bound to.
+//
+// If some attributes cannot be detected, a resource with all detected
+// attributes is returned along with an error.
+func (d Detector) Detect(ctx context.Context) (*resource.Resource, error) {
+	attrs, err := d.Attributes(ctx)
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), err
+}
+
+// Attributes returns the detected resource attributes of the process d is
+// bound to.
+//
+// The service.name attribute is set to "unknown_service:" followed by the
+// executable name of the process. It needs to be overridden by any user
+// provided service name.
+//
+// If some attributes cannot be detected, all detected attributes are returned
+// along with an error.
+func (d Detector) Attributes(ctx context.Context) ([]attribute.KeyValue, error) {
+	id := d.ID
+	attrs := []attribute.KeyValue{semconv.ProcessPID(int(id))}
+
+	var err error
+	if exe, e := id.ExeLink(); e != nil {
+		err = errors.Join(err, fmt.Errorf("executable: %w", e))
+	} else {
+		name := filepath.Base(exe)
+		attrs = append(
+			attrs,
+			semconv.ProcessExecutablePath(exe),
+			semconv.ProcessExecutableName(name),
+			semconv.ServiceName("unknown_service:"+name),
+		)
+	}
+
+	if args, e := id.cmdline(); e != nil {
+		err = errors.Join(err, fmt.Errorf("command args: %w", e))
+	} else if len(args) > 0 {
+		attrs = append(attrs, semconv.ProcessCommandArgs(args...))
+	}
+
+	if s, e := id.status(); e != nil {
+		err = errors.Join(err, fmt.Errorf("status: %w", e))
+	} else {
+		if ppid, e := strconv.Atoi(s["PPid"]); e == nil {
+			attrs = append(attrs, semconv.ProcessParentPID(ppid))
+		}
+		// The first Uid value is the real user ID.
+		if uid, _, _ := strings.Cut(s["Uid"], "\t"); uid != "" {
+			attrs = append(attrs, semconv.ProcessOwner(id.owner(uid)))
+		}
+	}
+
+	if cID := id.containerID(); cID != "" {
+		attrs = append(attrs, semconv.ContainerID(cID))
+	}
+
+	if bi, e := id.BuildInfo(); e != nil {
+		err = errors.Join(err, fmt.Errorf("build info: %w", e))
+	} else {
+		attrs = append(attrs, semconv.ProcessRuntimeVersion(bi.GoVersion))
+
+		var compiler string
+		for _, setting := range bi.Settings {
+			if setting.Key == "-compiler" {
+				compiler = setting.Value
+				break
+			}
+		}
+		switch compiler {
+		case "":
+			// Ignore empty.
+		case "gc":
+			attrs = append(attrs, semconv.ProcessRuntimeName("go"))
+		default:
+			attrs = append(attrs, semconv.ProcessRuntimeName(compiler))
+		}
+
+		if v := bi.Main.Version; v != "" && v != "(devel)" {
+			attrs = append(attrs, semconv.ServiceVersion(v))
+		}
+	}
+
+	attrs = append(attrs, hostArch())
+	host, e := hostResource(ctx, id)
+	if e != nil {
+		err = errors.Join(err, fmt.Errorf("host: %w", e))
+	}
+	if host != nil {
+		attrs = append(attrs, host.Attributes()...)
+	}
+
+	return attrs, err
+}
+
+// Used for testing.
+var hostResource = hostResourceFn
+
+func hostResourceFn(ctx context.Context, id ID) (*resource.Resource, error) {
+	// The target process is run on the same host as the instrumentation, but
+	// possibly in another UTS namespace with its own host name.
+	opts := []resource.Option{resource.WithHostID()}
+	name, err := id.hostname()
+	if err == nil {
+		opts = append(opts, resource.WithAttributes(semconv.HostName(name)))
+	}
+	res, e := resource.New(ctx, opts...)
+	return res, errors.Join(err, e)
+}
+
+func hostArch() attribute.KeyValue {
+	switch runtime.GOARCH {
+	case "amd64":
+		return semconv.HostArchAMD64
+	case "arm64":
+		return semconv.HostArchARM64
+	case "arm":
+		return semconv.HostArchARM32
+	case "386":
+		return semconv.HostArchX86
+	case "ppc64", "ppc64le":
+		return semconv.HostArchPPC64
+	case "s390x":
+		return semconv.HostArchS390x
+	default:
+		return semconv.HostArchKey.String(runtime.GOARCH)
+	}
+}
+
+// cmdline returns the command line arguments of the process.
+func (id ID) cmdline() ([]string, error) {
+	b, err := os.ReadFile(id.dir() + "/cmdline")
+	if err != nil {
+		return nil, err
+	}
+	b = bytes.TrimRight(b, "\x00")
+	if len(b) == 0 {
+		return nil, nil
+	}
+	return strings.Split(string(b), "\x00"), nil
+}
+
+// owner returns the name of the user with the ID uid, looked up in the passwd
+// file of the root file system of the process. The uid is returned if it is
+// not found.
+func (id ID) owner(uid string) string {
+	f, err := os.Open(id.dir() + "/root/etc/passwd")
+	if err != nil {
+		return uid
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// name:password:UID:GID:GECOS:directory:shell
+		fields := strings.SplitN(scanner.Text(), ":", 4)
+		if len(fields) >= 3 && fields[0] != "" && fields[2] == uid {
+			return fields[0]
+		}
+	}
+	return uid
+}
+
+// hostname returns the host name of the UTS namespace of the process. It is
+// read from the root file system of the process if the namespace is not the
+// one of the instrumentation.
+func (id ID) hostname() (string, error) {
+	ns, err := os.Readlink(id.dir() + "/ns/uts")
+	if err != nil {
+		return "", err
+	}
+	if self, err := os.Readlink(ID(os.Getpid()).dir() + "/ns/uts"); err == nil && self == ns {
+		return os.Hostname()
+	}
+
+	b, err := os.ReadFile(id.dir() + "/root/etc/hostname")
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSpace(string(b))
+	if name == "" {
+		return "", errors.New("empty host name")
+	}
+	return name, nil
+}
+
+// status returns the key-value pairs of the status file of the process.
+func (id ID) status() (map[string]string, error) {
+	f, err := os.Open(id.dir() + "/status")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(scanner.Text(), ":")
+		if ok {
+			s[k] = strings.TrimSpace(v)
+		}
+	}
+	return s, scanner.Err()
+}
+
+var (
+	// cgroupContainerIDRe matches the container ID in a cgroup path. For
+	// example:
+	//
+	//	0::/system.slice/docker-<id>.scope
+	//	12:pids:/docker/<id>
+	//	0::/kubepods/burstable/pod<uid>/<id>
+	//	0::/system.slice/crio-conmon-<id>.scope
+	cgroupContainerIDRe = regexp.MustCompile(`^.*/(?:.*[-:])?([0-9a-f]{64})(?:\.|\s*$)`)
+	// mountinfoContainerIDRe matches the container ID in the container
+	// runtime managed files mounted in a container when cgroup v2 namespaces
+	// hide it from the cgroup path. For example:
+	//
+	//	/var/lib/docker/containers/<id>/hostname
+	mountinfoContainerIDRe = regexp.MustCompile(`/containers/([0-9a-f]{64})/`)
+)
+
+// containerID returns the ID of the container the process is run in. An empty
+// string is returned if the process is not run in a container or the ID
+// cannot be determined.
+func (id ID) containerID() string {
+	if cID := scanContainerID(id.dir()+"/cgroup", cgroupContainerIDRe); cID != "" {
+		return cID
+	}
+	return scanContainerID(id.dir()+"/mountinfo", mountinfoContainerIDRe)
+}
+
+func scanContainerID(path string, re *regexp.Regexp) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := re.FindStringSubmatch(scanner.Text()); len(m) > 1 {
+			return m[1]
+		}
+	}
+	return ""
+}
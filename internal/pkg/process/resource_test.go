@@ -0,0 +1,173 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package process
+
+import (
+	"context"
+	"debug/buildinfo"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+)
+
+const containerID = "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestDetector(t *testing.T) {
+	const pid = 100
+	app := setup(t, pid)
+	dir := procDir(pid)
+
+	require.NoError(t, os.Symlink(app.Name(), filepath.Join(dir, "exe")))
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+	write("cmdline", "app\x00-flag\x00value\x00")
+	write("status", "Name:\tapp\nPPid:\t42\nUid:\t1000\t1000\t1000\t1000\n")
+	write("cgroup", "0::/system.slice/docker-"+containerID+".scope\n")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "root", "etc"), 0o755))
+	write("root/etc/passwd", "gopher:x:1000:1000::/home/gopher:/bin/sh\n")
+
+	t.Cleanup(func(orig func(context.Context, ID) (*resource.Resource, error)) func() {
+		hostResource = func(_ context.Context, id ID) (*resource.Resource, error) {
+			assert.Equal(t, ID(pid), id)
+			return resource.NewSchemaless(semconv.HostName("host")), nil
+		}
+		return func() { hostResource = orig }
+	}(hostResource))
+
+	t.Cleanup(func(orig func(string) (*buildinfo.BuildInfo, error)) func() {
+		buildinfoReadFile = func(string) (*buildinfo.BuildInfo, error) {
+			return &debug.BuildInfo{
+				GoVersion: "go1.24.0",
+				Main:      debug.Module{Path: "example.com/app", Version: "v1.2.3"},
+				Settings:  []debug.BuildSetting{{Key: "-compiler", Value: "gc"}},
+			}, nil
+		}
+		return func() { buildinfoReadFile = orig }
+	}(buildinfoReadFile))
+
+	res, err := Detector{ID: pid}.Detect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, semconv.SchemaURL, res.SchemaURL())
+
+	got := res.Attributes()
+	assert.Contains(t, got, semconv.ProcessPID(pid))
+	assert.Contains(t, got, semconv.ProcessExecutablePath(app.Name()))
+	assert.Contains(t, got, semconv.ProcessExecutableName("app"))
+	assert.Contains(t, got, semconv.ServiceName("unknown_service:app"))
+	assert.Contains(t, got, semconv.ProcessCommandArgs("app", "-flag", "value"))
+	assert.Contains(t, got, semconv.ProcessParentPID(42))
+	assert.Contains(t, got, semconv.ProcessOwner("gopher"))
+	assert.Contains(t, got, semconv.ContainerID(containerID))
+	assert.Contains(t, got, semconv.ProcessRuntimeVersion("go1.24.0"))
+	assert.Contains(t, got, semconv.ProcessRuntimeName("go"))
+	assert.Contains(t, got, semconv.ServiceVersion("v1.2.3"))
+	assert.Contains(t, got, semconv.HostName("host"))
+	assert.Contains(t, got, hostArch())
+}
+
+func TestDetectorPartial(t *testing.T) {
+	const pid = 100
+	_ = setup(t, pid)
+
+	attrs, err := Detector{ID: pid}.Attributes(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, attrs, semconv.ProcessPID(pid))
+	assert.Contains(t, attrs, hostArch())
+}
+
+func TestOwner(t *testing.T) {
+	const pid = 100
+	_ = setup(t, pid)
+
+	// The user is not resolved with the passwd file of the instrumentation.
+	assert.Equal(t, "0", ID(pid).owner("0"))
+
+	dir := filepath.Join(procDir(pid), "root", "etc")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	passwd := "root:x:0:0:root:/root:/bin/sh\n# comment\napp:x:1000:1000::/app:/bin/sh\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "passwd"), []byte(passwd), 0o600))
+
+	assert.Equal(t, "root", ID(pid).owner("0"))
+	assert.Equal(t, "app", ID(pid).owner("1000"))
+	assert.Equal(t, "1001", ID(pid).owner("1001"))
+}
+
+func TestHostname(t *testing.T) {
+	const pid = 100
+	_ = setup(t, pid)
+
+	_, err := ID(pid).hostname()
+	assert.Error(t, err, "no UTS namespace")
+
+	dir := procDir(pid)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "ns"), 0o755))
+	require.NoError(t, os.Symlink("uts:[4026532000]", filepath.Join(dir, "ns", "uts")))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "root", "etc"), 0o755))
+	hostname := filepath.Join(dir, "root", "etc", "hostname")
+	require.NoError(t, os.WriteFile(hostname, []byte("app-host\n"), 0o600))
+
+	got, err := ID(pid).hostname()
+	require.NoError(t, err)
+	assert.Equal(t, "app-host", got)
+
+	require.NoError(t, os.WriteFile(hostname, []byte("\n"), 0o600))
+	_, err = ID(pid).hostname()
+	assert.Error(t, err, "empty host name")
+}
+
+func TestContainerID(t *testing.T) {
+	tests := []struct {
+		name      string
+		cgroup    string
+		mountinfo string
+		want      string
+	}{
+		{
+			name:   "cgroup v1",
+			cgroup: "12:pids:/docker/" + containerID + "\n",
+			want:   containerID,
+		},
+		{
+			name:   "kubepods",
+			cgroup: "0::/kubepods/burstable/pod1234/" + containerID + "\n",
+			want:   containerID,
+		},
+		{
+			name:   "crio",
+			cgroup: "0::/system.slice/crio-conmon-" + containerID + ".scope\n",
+			want:   containerID,
+		},
+		{
+			name:      "mountinfo",
+			cgroup:    "0::/\n",
+			mountinfo: "1 2 0:3 /var/lib/docker/containers/" + containerID + "/hostname /etc/hostname rw - ext4 /dev/sda1 rw\n",
+			want:      containerID,
+		},
+		{
+			name:   "none",
+			cgroup: "0::/user.slice/user-1000.slice\n",
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const pid = 100
+			_ = setup(t, pid)
+			dir := procDir(pid)
+
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "cgroup"), []byte(tt.cgroup), 0o600))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "mountinfo"), []byte(tt.mountinfo), 0o600))
+
+			assert.Equal(t, tt.want, ID(pid).containerID())
+		})
+	}
+}
@@ -48,24 +48,24 @@ func TestIntegration(t *testing.T) {
 
 	selectS, err := e2e.SpanByName(scopes, "SELECT contacts")
 	require.NoError(t, err)
-	t.Run("SELECT", verify(selectS, runS, "SELECT * FROM contacts"))
+	t.Run("SELECT", verify(selectS, runS, "select * from contacts"))
 
 	insertS, err := e2e.SpanByName(scopes, "INSERT contacts")
 	require.NoError(t, err)
-	t.Run("INSERT", verify(insertS, runS, "INSERT INTO contacts (first_name) VALUES ('Mike')"))
+	t.Run("INSERT", verify(insertS, runS, "insert into contacts(first_name) values (?)"))
 
 	updateS, err := e2e.SpanByName(scopes, "UPDATE contacts")
 	require.NoError(t, err)
 	t.Run(
 		"UPDATE",
-		verify(updateS, runS, "UPDATE contacts SET last_name = 'Santa' WHERE first_name = 'Mike'"),
+		verify(updateS, runS, "update contacts set last_name = ? where first_name = ?"),
 	)
 
 	deleteS, err := e2e.SpanByName(scopes, "DELETE contacts")
 	require.NoError(t, err)
-	t.Run("DELETE", verify(deleteS, runS, "DELETE FROM contacts WHERE first_name = 'Mike'"))
+	t.Run("DELETE", verify(deleteS, runS, "delete from contacts where first_name = ?"))
 
-	query := "DROP TABLE contacts"
+	query := "drop table contacts"
 	dropS, err := e2e.SelectSpan(scopes, func(s ptrace.Span) bool {
 		name := s.Name()
 		vIface, ok := e2e.AttributesMap(s.Attributes())[queryTextKey]
@@ -0,0 +1,108 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package goroutine is a testing application for the span context inherited
+// by goroutines.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+
+	"go.opentelemetry.io/auto/internal/test/trigger"
+)
+
+const addr = "http://localhost:8080"
+
+// get sends a GET request to path with ctx.
+func get(ctx context.Context, path string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, addr+path, http.NoBody)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+// goGet sends a GET request to path with ctx from a new goroutine and waits
+// for it to exit.
+func goGet(ctx context.Context, path string) {
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		get(ctx, path)
+	}()
+	wg.Wait()
+}
+
+var (
+	// work is closed to have the worker started by the parent handler send
+	// its request.
+	work = make(chan struct{})
+	// workDone is closed by the worker once its request is sent.
+	workDone = make(chan struct{})
+)
+
+func parent(w http.ResponseWriter, _ *http.Request) {
+	// The request is made without the context of the handled request, its
+	// span is parented to the span inherited by the goroutine.
+	goGet(context.Background(), "/child")
+
+	// The worker outlives the handled request, its request is sent once the
+	// span it inherits has ended.
+	go func() {
+		defer close(workDone)
+		<-work
+		get(context.Background(), "/ended")
+	}()
+	fmt.Fprintf(w, "parent\n")
+}
+
+func hello(w http.ResponseWriter, _ *http.Request) {
+	fmt.Fprintf(w, "hello\n")
+}
+
+func main() {
+	var trig trigger.Flag
+	flag.Var(&trig, "trigger", trig.Docs())
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	http.HandleFunc("/parent", parent)
+	http.HandleFunc("/child", hello)
+	http.HandleFunc("/orphan", hello)
+	http.HandleFunc("/ended", hello)
+	go func() {
+		_ = http.ListenAndServe(":8080", nil) //nolint:gosec  // Testing server.
+	}()
+
+	// Wait for auto-instrumentation.
+	err := trig.Wait(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	get(ctx, "/parent")
+	close(work)
+	<-workDone
+
+	// The goroutine is started without a span in progress. It likely reuses
+	// the g of the exited goroutine of the parent handler, whose span context
+	// must not be inherited.
+	goGet(context.Background(), "/orphan")
+}
@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package goroutine provides an integration test for the span context
+// inherited by goroutines.
+package goroutine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.uber.org/goleak"
+
+	"go.opentelemetry.io/auto/internal/test/e2e"
+)
+
+// scopeName defines the instrumentation scope name used in the trace.
+const scopeName = "go.opentelemetry.io/auto/net/http"
+
+// clientSpan returns the client span of the request sent to path.
+func clientSpan(t *testing.T, scopes []ptrace.ScopeSpans, path string) ptrace.Span {
+	t.Helper()
+
+	span, err := e2e.SelectSpan(scopes, func(s ptrace.Span) bool {
+		if s.Kind() != ptrace.SpanKindClient {
+			return false
+		}
+		v, ok := s.Attributes().Get(string(semconv.URLPathKey))
+		return ok && v.AsString() == path
+	})
+	require.NoError(t, err, "client span of %s", path)
+	return span
+}
+
+func TestIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping long-running integration test in short mode.")
+	}
+
+	defer goleak.VerifyNone(t)
+
+	traces := e2e.RunInstrumentation(t, "./cmd")
+	scopes := e2e.ScopeSpansByName(traces, scopeName)
+	require.NotEmpty(t, scopes)
+
+	parentSpan, err := e2e.SpanByName(scopes, "GET /parent")
+	require.NoError(t, err)
+
+	t.Run("Inherited", func(t *testing.T) {
+		span := clientSpan(t, scopes, "/child")
+		assert.Equal(t, parentSpan.TraceID(), span.TraceID(), "trace ID")
+		assert.Equal(t, parentSpan.SpanID(), span.ParentSpanID(), "parent span ID")
+	})
+
+	t.Run("Ended", func(t *testing.T) {
+		span := clientSpan(t, scopes, "/ended")
+		e2e.AssertTraceID(t, span.TraceID(), "trace ID")
+		assert.NotEqual(t, parentSpan.TraceID(), span.TraceID(), "trace ID")
+		assert.True(t, span.ParentSpanID().IsEmpty(), "parent span ID")
+	})
+
+	t.Run("Exited", func(t *testing.T) {
+		span := clientSpan(t, scopes, "/orphan")
+		e2e.AssertTraceID(t, span.TraceID(), "trace ID")
+		assert.NotEqual(t, parentSpan.TraceID(), span.TraceID(), "trace ID")
+		assert.True(t, span.ParentSpanID().IsEmpty(), "parent span ID")
+	})
+}
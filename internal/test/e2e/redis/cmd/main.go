@@ -0,0 +1,138 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis is a testing application for the
+// [github.com/redis/go-redis/v9] package.
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"go.opentelemetry.io/auto/internal/test/trigger"
+)
+
+// readLength reads a line of r holding the length, prefixed by prefix, of an
+// array or a bulk string.
+func readLength(r *bufio.Reader, prefix byte) (int, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	if len(line) < 3 || line[0] != prefix {
+		return 0, fmt.Errorf("unexpected line: %q", line)
+	}
+	return strconv.Atoi(strings.TrimSuffix(line[1:], "\r\n"))
+}
+
+// readCommand reads the arguments of a command, sent as an array of bulk
+// strings, from r.
+func readCommand(r *bufio.Reader) ([]string, error) {
+	n, err := readLength(r, '*')
+	if err != nil {
+		return nil, err
+	}
+	if n <= 0 {
+		return nil, errors.New("empty command")
+	}
+
+	args := make([]string, n)
+	for i := range args {
+		l, err := readLength(r, '$')
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, l+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:l])
+	}
+	return args, nil
+}
+
+// serve answers the commands sent on conn like a Redis server not supporting
+// the RESP3 protocol. The client initializes the connection with a HELLO
+// command, then with a pipeline of AUTH and SELECT commands.
+func serve(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			return
+		}
+
+		var reply string
+		switch strings.ToUpper(args[0]) {
+		case "HELLO":
+			reply = "-ERR unknown command 'HELLO'\r\n"
+		case "GET":
+			reply = "$5\r\nvalue\r\n"
+		default:
+			reply = "+OK\r\n"
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+func main() {
+	var trig trigger.Flag
+	flag.Var(&trig, "trigger", trig.Docs())
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+
+	// Wait for auto-instrumentation.
+	err = trig.Wait(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     ln.Addr().String(),
+		Password: "secret",
+		DB:       1,
+	})
+	defer rdb.Close()
+
+	// The first command opens a new connection, initialized by commands that
+	// are not traced.
+	if err := rdb.Set(ctx, "key", "value", 0).Err(); err != nil {
+		log.Fatal(err)
+	}
+	if err := rdb.Get(ctx, "key").Err(); err != nil {
+		log.Fatal(err)
+	}
+}
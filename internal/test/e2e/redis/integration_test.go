@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package redis provides an integration test for the go-redis probe.
+package redis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.uber.org/goleak"
+
+	"go.opentelemetry.io/auto/internal/test/e2e"
+)
+
+// scopeName defines the instrumentation scope name used in the trace.
+const scopeName = "go.opentelemetry.io/auto/github.com/redis/go-redis/v9"
+
+func TestIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping long-running integration test in short mode.")
+	}
+
+	defer goleak.VerifyNone(t)
+
+	traces := e2e.RunInstrumentation(t, "./cmd")
+	scopes := e2e.ScopeSpansByName(traces, scopeName)
+	require.NotEmpty(t, scopes)
+
+	t.Run("NewConnection", func(t *testing.T) {
+		// The commands initializing the connection are not traced, and do not
+		// end the span of the command opening it.
+		var names []string
+		for _, s := range scopes {
+			for i := 0; i < s.Spans().Len(); i++ {
+				names = append(names, s.Spans().At(i).Name())
+			}
+		}
+		assert.ElementsMatch(t, []string{"SET", "GET"}, names)
+	})
+
+	for _, name := range []string{"SET", "GET"} {
+		t.Run(name, func(t *testing.T) {
+			span, err := e2e.SpanByName(scopes, name)
+			require.NoError(t, err)
+			assert.Equal(t, ptrace.SpanKindClient, span.Kind())
+			assert.Equal(t, ptrace.StatusCodeUnset, span.Status().Code())
+
+			attrs := span.Attributes().AsRaw()
+			assert.Equal(t, "redis", attrs[string(semconv.DBSystemNameKey)])
+			assert.Equal(t, "127.0.0.1", attrs[string(semconv.ServerAddressKey)])
+			assert.Equal(t, "1", attrs[string(semconv.DBNamespaceKey)])
+			assert.Equal(t, name, attrs[string(semconv.DBOperationNameKey)])
+		})
+	}
+}
@@ -75,6 +75,36 @@ func manifests() ([]inspect.Manifest, error) {
 		return nil, fmt.Errorf("failed to get \"github.com/segmentio/kafka-go\" versions: %w", err)
 	}
 
+	pgxVers, err := PkgVersions("github.com/jackc/pgx/v5")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get \"github.com/jackc/pgx/v5\" versions: %w", err)
+	}
+
+	redisV9Vers, err := PkgVersions("github.com/redis/go-redis/v9")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get \"github.com/redis/go-redis/v9\" versions: %w", err)
+	}
+
+	redisV8Vers, err := PkgVersions("github.com/go-redis/redis/v8")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get \"github.com/go-redis/redis/v8\" versions: %w", err)
+	}
+
+	pqVers, err := PkgVersions("github.com/lib/pq")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get \"github.com/lib/pq\" versions: %w", err)
+	}
+
+	pgconnVers, err := PkgVersions("github.com/jackc/pgconn")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get \"github.com/jackc/pgconn\" versions: %w", err)
+	}
+
+	mysqlVers, err := PkgVersions("github.com/go-sql-driver/mysql")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get \"github.com/go-sql-driver/mysql\" versions: %w", err)
+	}
+
 	ren := func(src string) inspect.Renderer {
 		return inspect.NewRenderer(logger, src, inspect.DefaultFS)
 	}
@@ -90,6 +120,20 @@ func manifests() ([]inspect.Manifest, error) {
 				structfield.NewID("std", "runtime", "hmap", "buckets"),
 			},
 		},
+		{
+			Application: inspect.Application{
+				Renderer:  ren("templates/database/sql/*.tmpl"),
+				GoVerions: goVers,
+			},
+			StructFields: []structfield.ID{
+				structfield.NewID("std", "database/sql", "Stmt", "query"),
+				structfield.NewID("std", "database/sql", "Stmt", "db"),
+				structfield.NewID("std", "database/sql", "Tx", "db"),
+				structfield.NewID("std", "database/sql", "Conn", "db"),
+				structfield.NewID("std", "database/sql", "DB", "connector"),
+				structfield.NewID("std", "database/sql", "dsnConnector", "driver"),
+			},
+		},
 		{
 			Application: inspect.Application{
 				Renderer:  ren("templates/net/http/*.tmpl"),
@@ -101,15 +145,20 @@ func manifests() ([]inspect.Manifest, error) {
 				structfield.NewID("std", "net/http", "Request", "RemoteAddr"),
 				structfield.NewID("std", "net/http", "Request", "Header"),
 				structfield.NewID("std", "net/http", "Request", "ctx"),
+				structfield.NewID("std", "net/http", "Request", "ContentLength"),
+				structfield.NewID("std", "net/http", "Request", "TLS"),
 				structfield.NewID("std", "net/http", "Response", "StatusCode"),
+				structfield.NewID("std", "net/http", "Response", "Header"),
 				structfield.NewID("std", "net/http", "response", "req"),
 				structfield.NewID("std", "net/http", "response", "status"),
+				structfield.NewID("std", "net/http", "response", "written"),
 				structfield.NewID("std", "net/http", "Request", "Proto"),
 				structfield.NewID("std", "net/http", "Request", "RequestURI"),
 				structfield.NewID("std", "net/http", "Request", "Host"),
 				structfield.NewID("std", "net/http", "Request", "Pattern"),
 				structfield.NewID("std", "net/http", "Request", "pat"),
 				structfield.NewID("std", "net/http", "pattern", "str"),
+				structfield.NewID("std", "net/http", "httpError", "timeout"),
 				structfield.NewID("std", "net/url", "URL", "Path"),
 				structfield.NewID("std", "net/url", "URL", "Scheme"),
 				structfield.NewID("std", "net/url", "URL", "Opaque"),
@@ -126,6 +175,15 @@ func manifests() ([]inspect.Manifest, error) {
 				structfield.NewID("std", "bufio", "Writer", "n"),
 				structfield.NewID("std", "net", "TCPAddr", "IP"),
 				structfield.NewID("std", "net", "TCPAddr", "Port"),
+				structfield.NewID("std", "net", "OpError", "Op"),
+				structfield.NewID("std", "net", "OpError", "Net"),
+				structfield.NewID("std", "net", "OpError", "Addr"),
+				structfield.NewID("std", "net", "OpError", "Err"),
+				structfield.NewID("std", "net", "DNSError", "Err"),
+				structfield.NewID("std", "net", "DNSError", "Name"),
+				structfield.NewID("std", "net", "DNSError", "IsTimeout"),
+				structfield.NewID("std", "os", "SyscallError", "Syscall"),
+				structfield.NewID("std", "os", "SyscallError", "Err"),
 			},
 		},
 		{
@@ -164,6 +222,12 @@ func manifests() ([]inspect.Manifest, error) {
 					"ClientConn",
 					"target",
 				),
+				structfield.NewID(
+					"google.golang.org/grpc",
+					"google.golang.org/grpc",
+					"parser",
+					"header",
+				),
 				structfield.NewID(
 					"google.golang.org/grpc",
 					"google.golang.org/grpc/internal/transport",
@@ -218,6 +282,12 @@ func manifests() ([]inspect.Manifest, error) {
 					"Peer",
 					"LocalAddr",
 				),
+				structfield.NewID(
+					"google.golang.org/grpc",
+					"google.golang.org/grpc/peer",
+					"Peer",
+					"Addr",
+				),
 			},
 		},
 		{
@@ -358,6 +428,216 @@ func manifests() ([]inspect.Manifest, error) {
 				),
 			},
 		},
+		{
+			Application: inspect.Application{
+				Renderer: ren("templates/github.com/jackc/pgx/*.tmpl"),
+				Versions: pgxVers,
+			},
+			StructFields: []structfield.ID{
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5",
+					"Batch",
+					"QueuedQueries",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5",
+					"Batch",
+					"queuedQueries",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5",
+					"Conn",
+					"config",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5",
+					"ConnConfig",
+					"Config",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5/pgconn",
+					"Config",
+					"Host",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5/pgconn",
+					"Config",
+					"Port",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5/pgconn",
+					"Config",
+					"Database",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5/pgconn",
+					"PgError",
+					"Severity",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5/pgconn",
+					"PgError",
+					"Code",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgx/v5",
+					"github.com/jackc/pgx/v5/pgconn",
+					"PgError",
+					"Message",
+				),
+			},
+		},
+		{
+			Application: inspect.Application{
+				Renderer: ren("templates/github.com/redis/go-redis/*.tmpl"),
+				Versions: redisV9Vers,
+			},
+			StructFields: []structfield.ID{
+				structfield.NewID(
+					"github.com/redis/go-redis/v9",
+					"github.com/redis/go-redis/v9",
+					"baseClient",
+					"opt",
+				),
+				structfield.NewID(
+					"github.com/redis/go-redis/v9",
+					"github.com/redis/go-redis/v9",
+					"Options",
+					"Addr",
+				),
+				structfield.NewID(
+					"github.com/redis/go-redis/v9",
+					"github.com/redis/go-redis/v9",
+					"Options",
+					"DB",
+				),
+				structfield.NewID(
+					"github.com/redis/go-redis/v9",
+					"github.com/redis/go-redis/v9",
+					"baseCmd",
+					"args",
+				),
+			},
+		},
+		{
+			Application: inspect.Application{
+				Renderer: ren("templates/github.com/go-redis/redis/*.tmpl"),
+				Versions: redisV8Vers,
+			},
+			StructFields: []structfield.ID{
+				structfield.NewID(
+					"github.com/go-redis/redis/v8",
+					"github.com/go-redis/redis/v8",
+					"baseClient",
+					"opt",
+				),
+				structfield.NewID(
+					"github.com/go-redis/redis/v8",
+					"github.com/go-redis/redis/v8",
+					"Options",
+					"Addr",
+				),
+				structfield.NewID(
+					"github.com/go-redis/redis/v8",
+					"github.com/go-redis/redis/v8",
+					"Options",
+					"DB",
+				),
+				structfield.NewID(
+					"github.com/go-redis/redis/v8",
+					"github.com/go-redis/redis/v8",
+					"baseCmd",
+					"args",
+				),
+			},
+		},
+		{
+			Application: inspect.Application{
+				Renderer: ren("templates/github.com/lib/pq/*.tmpl"),
+				Versions: pqVers,
+			},
+			StructFields: []structfield.ID{
+				structfield.NewID(
+					"github.com/lib/pq",
+					"github.com/lib/pq",
+					"Error",
+					"Severity",
+				),
+				structfield.NewID(
+					"github.com/lib/pq",
+					"github.com/lib/pq",
+					"Error",
+					"Code",
+				),
+				structfield.NewID(
+					"github.com/lib/pq",
+					"github.com/lib/pq",
+					"Error",
+					"Message",
+				),
+			},
+		},
+		{
+			Application: inspect.Application{
+				Renderer: ren("templates/github.com/jackc/pgconn/*.tmpl"),
+				Versions: pgconnVers,
+			},
+			StructFields: []structfield.ID{
+				structfield.NewID(
+					"github.com/jackc/pgconn",
+					"github.com/jackc/pgconn",
+					"PgError",
+					"Severity",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgconn",
+					"github.com/jackc/pgconn",
+					"PgError",
+					"Code",
+				),
+				structfield.NewID(
+					"github.com/jackc/pgconn",
+					"github.com/jackc/pgconn",
+					"PgError",
+					"Message",
+				),
+			},
+		},
+		{
+			Application: inspect.Application{
+				Renderer: ren("templates/github.com/go-sql-driver/mysql/*.tmpl"),
+				Versions: mysqlVers,
+			},
+			StructFields: []structfield.ID{
+				structfield.NewID(
+					"github.com/go-sql-driver/mysql",
+					"github.com/go-sql-driver/mysql",
+					"MySQLError",
+					"Number",
+				),
+				structfield.NewID(
+					"github.com/go-sql-driver/mysql",
+					"github.com/go-sql-driver/mysql",
+					"MySQLError",
+					"SQLState",
+				),
+				structfield.NewID(
+					"github.com/go-sql-driver/mysql",
+					"github.com/go-sql-driver/mysql",
+					"MySQLError",
+					"Message",
+				),
+			},
+		},
 	}, nil
 }
 
@@ -22,6 +22,10 @@ import (
 //go:embed templates/runtime/*.tmpl
 //go:embed templates/go.opentelemetry.io/otel/traceglobal/*.tmpl
 //go:embed templates/github.com/segmentio/kafka-go/*.tmpl
+//go:embed templates/database/sql/*.tmpl
+//go:embed templates/github.com/jackc/pgx/*.tmpl
+//go:embed templates/github.com/redis/go-redis/*.tmpl
+//go:embed templates/github.com/go-redis/redis/*.tmpl
 var DefaultFS embed.FS
 
 // Renderer renders templates from an fs.FS.
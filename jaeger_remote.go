@@ -0,0 +1,579 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe/sampling"
+	"go.opentelemetry.io/auto/internal/pkg/process"
+)
+
+const (
+	samplerNameJaegerRemote = "jaeger_remote"
+
+	// defaultJaegerRemoteEndpoint is the default endpoint serving sampling
+	// strategies. This is the HTTP sampling endpoint of the Jaeger agent.
+	defaultJaegerRemoteEndpoint = "http://localhost:5778/sampling"
+	// defaultJaegerRemotePollingInterval is the default interval sampling
+	// strategies are polled at.
+	defaultJaegerRemotePollingInterval = time.Minute
+	// defaultJaegerRemoteInitialSamplingRate is the default sampling rate
+	// used until a sampling strategy is received.
+	defaultJaegerRemoteInitialSamplingRate = 0.001
+	// jaegerRemoteRequestTimeout is the timeout of a sampling strategy
+	// request.
+	jaegerRemoteRequestTimeout = 10 * time.Second
+)
+
+// JaegerRemoteOption configures a [JaegerRemoteConfigProvider].
+type JaegerRemoteOption interface {
+	apply(jaegerRemoteConfig) jaegerRemoteConfig
+}
+
+type jaegerRemoteOptionFunc func(jaegerRemoteConfig) jaegerRemoteConfig
+
+func (f jaegerRemoteOptionFunc) apply(c jaegerRemoteConfig) jaegerRemoteConfig { return f(c) }
+
+type jaegerRemoteConfig struct {
+	endpoint       string
+	interval       time.Duration
+	initialSampler Sampler
+	client         *http.Client
+	logger         *slog.Logger
+}
+
+// WithJaegerRemoteEndpoint returns a [JaegerRemoteOption] that sets the URL
+// of the HTTP endpoint sampling strategies are requested from. The service
+// name is added to the URL as the "service" query parameter.
+//
+// By default, "http://localhost:5778/sampling" is used.
+func WithJaegerRemoteEndpoint(endpoint string) JaegerRemoteOption {
+	return jaegerRemoteOptionFunc(func(c jaegerRemoteConfig) jaegerRemoteConfig {
+		c.endpoint = endpoint
+		return c
+	})
+}
+
+// WithJaegerRemotePollingInterval returns a [JaegerRemoteOption] that sets
+// the interval sampling strategies are requested at.
+//
+// By default, 1 minute is used.
+func WithJaegerRemotePollingInterval(d time.Duration) JaegerRemoteOption {
+	return jaegerRemoteOptionFunc(func(c jaegerRemoteConfig) jaegerRemoteConfig {
+		c.interval = d
+		return c
+	})
+}
+
+// WithJaegerRemoteInitialSampler returns a [JaegerRemoteOption] that sets the
+// Sampler used until a sampling strategy is received.
+//
+// By default, a [ParentBasedSampler] with a [TraceIDRatioSampler] root
+// sampling 0.1% of traces is used.
+func WithJaegerRemoteInitialSampler(s Sampler) JaegerRemoteOption {
+	return jaegerRemoteOptionFunc(func(c jaegerRemoteConfig) jaegerRemoteConfig {
+		c.initialSampler = s
+		return c
+	})
+}
+
+// WithJaegerRemoteHTTPClient returns a [JaegerRemoteOption] that sets the
+// HTTP client used to request sampling strategies.
+//
+// By default, an [http.Client] with a 10 second timeout is used.
+func WithJaegerRemoteHTTPClient(client *http.Client) JaegerRemoteOption {
+	return jaegerRemoteOptionFunc(func(c jaegerRemoteConfig) jaegerRemoteConfig {
+		c.client = client
+		return c
+	})
+}
+
+// WithJaegerRemoteLogger returns a [JaegerRemoteOption] that sets the logger
+// used to report errors requesting sampling strategies.
+//
+// By default, [slog.Default] is used.
+func WithJaegerRemoteLogger(l *slog.Logger) JaegerRemoteOption {
+	return jaegerRemoteOptionFunc(func(c jaegerRemoteConfig) jaegerRemoteConfig {
+		c.logger = l
+		return c
+	})
+}
+
+// JaegerRemoteConfigProvider is a [ConfigProvider] that periodically requests
+// the sampling strategy of a service from an HTTP endpoint implementing the
+// Jaeger remote sampling protocol.
+//
+// Probabilistic strategies are translated into a [TraceIDRatioSampler] and
+// rate-limiting strategies into a [RateLimitingSampler]. Per-operation
+// strategies are translated into a [RuleBasedSampler] with a rule for each
+// supported operation: HTTP operations named "<method> <path>" or "<method>"
+// and gRPC operations named with the full method name
+// ("/<package>.<service>/<method>"). Other operations, the operations that do
+// not fit in the rules limit, and the lower bound rates of per-operation
+// strategies are ignored. All of these samplers are used as the root of a
+// [ParentBasedSampler].
+//
+// Updates are only provided when the sampling strategy changes. If a
+// strategy cannot be requested, the last one received is kept.
+type JaegerRemoteConfigProvider struct {
+	cfg     jaegerRemoteConfig
+	service string
+
+	ch        chan InstrumentationConfig
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+	stopped   chan struct{}
+
+	mu   sync.Mutex
+	last Sampler
+}
+
+var _ ConfigProvider = (*JaegerRemoteConfigProvider)(nil)
+
+// NewJaegerRemoteConfigProvider returns a new [JaegerRemoteConfigProvider]
+// requesting the sampling strategy of the service named serviceName.
+func NewJaegerRemoteConfigProvider(
+	serviceName string,
+	options ...JaegerRemoteOption,
+) (*JaegerRemoteConfigProvider, error) {
+	if serviceName == "" {
+		return nil, errors.New("empty service name")
+	}
+
+	c := jaegerRemoteConfig{
+		endpoint: defaultJaegerRemoteEndpoint,
+		interval: defaultJaegerRemotePollingInterval,
+	}
+	for _, o := range options {
+		if o != nil {
+			c = o.apply(c)
+		}
+	}
+
+	if _, err := url.Parse(c.endpoint); err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	if c.interval <= 0 {
+		return nil, fmt.Errorf("invalid polling interval: %s", c.interval)
+	}
+	if c.initialSampler == nil {
+		c.initialSampler = jaegerRemoteRoot(
+			TraceIDRatioSampler{Fraction: defaultJaegerRemoteInitialSamplingRate},
+		)
+	}
+	if err := c.initialSampler.validate(); err != nil {
+		return nil, fmt.Errorf("invalid initial sampler: %w", err)
+	}
+	if c.client == nil {
+		c.client = &http.Client{Timeout: jaegerRemoteRequestTimeout}
+	}
+	if c.logger == nil {
+		c.logger = slog.Default()
+	}
+
+	return &JaegerRemoteConfigProvider{
+		cfg:     c,
+		service: serviceName,
+		ch:      make(chan InstrumentationConfig),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+		last:    c.initialSampler,
+	}, nil
+}
+
+// InitialConfig returns a configuration using the current sampling strategy
+// of the service. If it cannot be requested, the initial sampler is used.
+func (p *JaegerRemoteConfigProvider) InitialConfig(ctx context.Context) InstrumentationConfig {
+	if s, err := p.fetch(ctx); err != nil {
+		p.cfg.logger.Warn("failed to get Jaeger remote sampling strategy", "error", err)
+	} else {
+		p.mu.Lock()
+		p.last = s
+		p.mu.Unlock()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return InstrumentationConfig{Sampler: p.last}
+}
+
+// Watch returns a channel that receives a configuration each time the
+// sampling strategy of the service changes.
+func (p *JaegerRemoteConfigProvider) Watch() <-chan InstrumentationConfig {
+	p.startOnce.Do(func() { go p.poll() })
+	return p.ch
+}
+
+func (p *JaegerRemoteConfigProvider) poll() {
+	defer close(p.stopped)
+	defer close(p.ch)
+
+	ticker := time.NewTicker(p.cfg.interval)
+	defer ticker.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-p.stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		s, err := p.fetch(ctx)
+		if err != nil {
+			p.cfg.logger.Warn("failed to get Jaeger remote sampling strategy", "error", err)
+			continue
+		}
+
+		p.mu.Lock()
+		changed := !reflect.DeepEqual(s, p.last)
+		p.last = s
+		p.mu.Unlock()
+		if !changed {
+			continue
+		}
+
+		select {
+		case p.ch <- InstrumentationConfig{Sampler: s}:
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+// Shutdown stops requesting sampling strategies.
+func (p *JaegerRemoteConfigProvider) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+		// Close the channel if polling was never started.
+		p.startOnce.Do(func() {
+			close(p.ch)
+			close(p.stopped)
+		})
+	})
+
+	select {
+	case <-p.stopped:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fetch requests the sampling strategy of the service and returns the
+// equivalent Sampler.
+func (p *JaegerRemoteConfigProvider) fetch(ctx context.Context) (Sampler, error) {
+	u, err := url.Parse(p.cfg.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	q.Set("service", p.service)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.cfg.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	var strategy jaegerStrategyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&strategy); err != nil {
+		return nil, fmt.Errorf("decode sampling strategy: %w", err)
+	}
+	s, err := strategy.sampler(p.cfg.logger)
+	if err != nil {
+		return nil, err
+	}
+	// Ensure the strategy can be used before replacing the current one.
+	if _, err := convertSamplerToConfig(s); err != nil {
+		return nil, fmt.Errorf("invalid sampling strategy: %w", err)
+	}
+	return s, nil
+}
+
+// jaegerStrategyType is the type of a Jaeger sampling strategy. It is encoded
+// either as the name or the number of the enum value.
+type jaegerStrategyType int
+
+const (
+	jaegerStrategyProbabilistic jaegerStrategyType = 0
+	jaegerStrategyRateLimiting  jaegerStrategyType = 1
+)
+
+func (t *jaegerStrategyType) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err == nil {
+		switch strings.ToUpper(name) {
+		case "PROBABILISTIC":
+			*t = jaegerStrategyProbabilistic
+		case "RATE_LIMITING":
+			*t = jaegerStrategyRateLimiting
+		default:
+			return fmt.Errorf("unknown strategy type: %q", name)
+		}
+		return nil
+	}
+
+	var n int
+	if err := json.Unmarshal(b, &n); err != nil {
+		return fmt.Errorf("invalid strategy type: %s", b)
+	}
+	*t = jaegerStrategyType(n)
+	return nil
+}
+
+type jaegerProbabilistic struct {
+	SamplingRate float64 `json:"samplingRate"`
+}
+
+type jaegerRateLimiting struct {
+	MaxTracesPerSecond float64 `json:"maxTracesPerSecond"`
+}
+
+type jaegerOperationStrategy struct {
+	Operation             string              `json:"operation"`
+	ProbabilisticSampling jaegerProbabilistic `json:"probabilisticSampling"`
+}
+
+type jaegerPerOperation struct {
+	DefaultSamplingProbability       float64                   `json:"defaultSamplingProbability"`
+	DefaultLowerBoundTracesPerSecond float64                   `json:"defaultLowerBoundTracesPerSecond"`
+	PerOperationStrategies           []jaegerOperationStrategy `json:"perOperationStrategies"`
+}
+
+// jaegerStrategyResponse is the response of the Jaeger remote sampling
+// endpoint.
+type jaegerStrategyResponse struct {
+	StrategyType          jaegerStrategyType   `json:"strategyType"`
+	ProbabilisticSampling *jaegerProbabilistic `json:"probabilisticSampling"`
+	RateLimitingSampling  *jaegerRateLimiting  `json:"rateLimitingSampling"`
+	OperationSampling     *jaegerPerOperation  `json:"operationSampling"`
+}
+
+func (r jaegerStrategyResponse) sampler(logger *slog.Logger) (Sampler, error) {
+	if r.OperationSampling != nil {
+		return jaegerRemoteRoot(r.OperationSampling.sampler(logger)), nil
+	}
+
+	switch r.StrategyType {
+	case jaegerStrategyProbabilistic:
+		if r.ProbabilisticSampling == nil {
+			return nil, errors.New("missing probabilistic sampling strategy")
+		}
+		return jaegerRemoteRoot(TraceIDRatioSampler{
+			Fraction: r.ProbabilisticSampling.SamplingRate,
+		}), nil
+	case jaegerStrategyRateLimiting:
+		if r.RateLimitingSampling == nil {
+			return nil, errors.New("missing rate-limiting sampling strategy")
+		}
+		return jaegerRemoteRoot(RateLimitingSampler{
+			SpansPerSecond: r.RateLimitingSampling.MaxTracesPerSecond,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown strategy type: %d", r.StrategyType)
+	}
+}
+
+func (s jaegerPerOperation) sampler(logger *slog.Logger) Sampler {
+	rbs := RuleBasedSampler{
+		Fallback: TraceIDRatioSampler{Fraction: s.DefaultSamplingProbability},
+	}
+	for _, op := range s.PerOperationStrategies {
+		rule, ok := jaegerOperationRule(op.Operation)
+		if !ok {
+			logger.Debug("ignoring unsupported Jaeger sampling operation", "operation", op.Operation)
+			continue
+		}
+		if len(rbs.Rules) == sampling.MaxRules {
+			logger.Warn(
+				"ignoring Jaeger sampling operation, too many operations",
+				"operation", op.Operation,
+				"max", sampling.MaxRules,
+			)
+			continue
+		}
+		rule.Sampler = TraceIDRatioSampler{Fraction: op.ProbabilisticSampling.SamplingRate}
+		rbs.Rules = append(rbs.Rules, rule)
+	}
+	return rbs
+}
+
+// jaegerOperationRule returns the SamplingRule matching spans of the Jaeger
+// operation op. False is returned if op cannot be matched.
+func jaegerOperationRule(op string) (SamplingRule, bool) {
+	var rule SamplingRule
+	method, path, hasPath := strings.Cut(op, " ")
+	switch {
+	case isHTTPMethod(method) && !hasPath:
+		rule.HTTPMethod = method
+	case isHTTPMethod(method) && strings.HasPrefix(path, "/"):
+		rule.HTTPMethod, rule.HTTPPath = method, path
+	case !hasPath && strings.HasPrefix(op, "/") && strings.Count(op, "/") == 2:
+		rule.RPCMethod = op
+	default:
+		return SamplingRule{}, false
+	}
+
+	if len(rule.HTTPMethod) > sampling.MaxRuleMethodSize ||
+		len(rule.HTTPPath) > sampling.MaxRuleValueSize ||
+		len(rule.RPCMethod) > sampling.MaxRuleValueSize {
+		return SamplingRule{}, false
+	}
+	return rule, true
+}
+
+func isHTTPMethod(m string) bool {
+	switch m {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodConnect,
+		http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// jaegerRemoteRoot returns the default parent-based sampler with root as its
+// Root.
+func jaegerRemoteRoot(root Sampler) Sampler {
+	s := DefaultSampler().(ParentBasedSampler)
+	s.Root = root
+	return s
+}
+
+// jaegerRemoteEnv is a [JaegerRemoteConfigProvider] configured from the
+// environment.
+type jaegerRemoteEnv struct {
+	service string
+	opts    []JaegerRemoteOption
+}
+
+// jaegerRemoteFromEnv returns the configuration of the jaeger_remote sampler
+// if it is set as the OTEL_TRACES_SAMPLER. If it is not set, false is
+// returned.
+func jaegerRemoteFromEnv(lookupEnv func(string) (string, bool)) (*jaegerRemoteEnv, bool, error) {
+	name, _ := lookupEnv(tracesSamplerKey)
+	if strings.ToLower(strings.TrimSpace(name)) != samplerNameJaegerRemote {
+		return nil, false, nil
+	}
+
+	arg, _ := lookupEnv(tracesSamplerArgKey)
+	opts, err := parseJaegerRemoteArg(arg)
+	return &jaegerRemoteEnv{service: envServiceName(lookupEnv), opts: opts}, true, err
+}
+
+// envServiceName returns the service name defined by OTEL_SERVICE_NAME or
+// OTEL_RESOURCE_ATTRIBUTES. An empty string is returned if it is not defined.
+func envServiceName(lookupEnv func(string) (string, bool)) string {
+	if v, ok := lookupEnv("OTEL_SERVICE_NAME"); ok && v != "" {
+		return v
+	}
+	attrs, _ := lookupEnv("OTEL_RESOURCE_ATTRIBUTES")
+	for _, pair := range strings.Split(attrs, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) != "service.name" {
+			continue
+		}
+		if decoded, err := url.PathUnescape(strings.TrimSpace(v)); err == nil {
+			return decoded
+		}
+	}
+	return ""
+}
+
+// provider returns the JaegerRemoteConfigProvider for the process with pid.
+// If no service name is defined, the unknown service name of the process is
+// used.
+func (e *jaegerRemoteEnv) provider(pid process.ID, logger *slog.Logger) (*JaegerRemoteConfigProvider, error) {
+	service := e.service
+	if service == "" {
+		service = "unknown_service"
+		if pid >= 0 {
+			if exe, err := pid.ExeLink(); err == nil {
+				service += ":" + filepath.Base(exe)
+			}
+		}
+	}
+	opts := append([]JaegerRemoteOption{WithJaegerRemoteLogger(logger)}, e.opts...)
+	return NewJaegerRemoteConfigProvider(service, opts...)
+}
+
+// parseJaegerRemoteArg parses the OTEL_TRACES_SAMPLER_ARG value of the
+// jaeger_remote sampler. It is a comma-separated list of key=value pairs with
+// the keys "endpoint", "pollingIntervalMs", and "initialSamplingRate".
+func parseJaegerRemoteArg(arg string) ([]JaegerRemoteOption, error) {
+	var (
+		opts []JaegerRemoteOption
+		err  error
+	)
+	for _, pair := range strings.Split(arg, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			err = errors.Join(err, fmt.Errorf("invalid jaeger_remote argument: %q", pair))
+			continue
+		}
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		switch k {
+		case "endpoint":
+			opts = append(opts, WithJaegerRemoteEndpoint(v))
+		case "pollingIntervalMs":
+			ms, e := strconv.ParseInt(v, 10, 64)
+			if e != nil {
+				err = errors.Join(err, fmt.Errorf("invalid pollingIntervalMs: %w", e))
+				continue
+			}
+			opts = append(opts, WithJaegerRemotePollingInterval(time.Duration(ms)*time.Millisecond))
+		case "initialSamplingRate":
+			rate, e := strconv.ParseFloat(v, 64)
+			if e != nil {
+				err = errors.Join(err, fmt.Errorf("invalid initialSamplingRate: %w", e))
+				continue
+			}
+			opts = append(opts, WithJaegerRemoteInitialSampler(
+				jaegerRemoteRoot(TraceIDRatioSampler{Fraction: rate}),
+			))
+		default:
+			err = errors.Join(err, fmt.Errorf("unknown jaeger_remote argument: %q", k))
+		}
+	}
+	return opts, err
+}
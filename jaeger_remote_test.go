@@ -0,0 +1,189 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type strategyServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	strategy string
+	service  string
+}
+
+func newStrategyServer(t *testing.T, strategy string) *strategyServer {
+	t.Helper()
+
+	s := &strategyServer{strategy: strategy}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.service = r.URL.Query().Get("service")
+		if s.strategy == "" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(s.strategy))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *strategyServer) set(strategy string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.strategy = strategy
+}
+
+func parentBased(root Sampler) Sampler {
+	s := DefaultSampler().(ParentBasedSampler)
+	s.Root = root
+	return s
+}
+
+func TestJaegerRemoteStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		want     Sampler
+	}{
+		{
+			name:     "Probabilistic",
+			strategy: `{"strategyType":"PROBABILISTIC","probabilisticSampling":{"samplingRate":0.25}}`,
+			want:     parentBased(TraceIDRatioSampler{Fraction: 0.25}),
+		},
+		{
+			name:     "RateLimiting",
+			strategy: `{"strategyType":1,"rateLimitingSampling":{"maxTracesPerSecond":10}}`,
+			want:     parentBased(RateLimitingSampler{SpansPerSecond: 10}),
+		},
+		{
+			name: "PerOperation",
+			strategy: `{
+				"strategyType":"PROBABILISTIC",
+				"probabilisticSampling":{"samplingRate":0.5},
+				"operationSampling":{
+					"defaultSamplingProbability":0.1,
+					"perOperationStrategies":[
+						{"operation":"GET /healthz","probabilisticSampling":{"samplingRate":0}},
+						{"operation":"/pkg.Service/Method","probabilisticSampling":{"samplingRate":1}},
+						{"operation":"POST","probabilisticSampling":{"samplingRate":0.5}},
+						{"operation":"unsupported","probabilisticSampling":{"samplingRate":1}}
+					]
+				}
+			}`,
+			want: parentBased(RuleBasedSampler{
+				Rules: []SamplingRule{
+					{HTTPMethod: "GET", HTTPPath: "/healthz", Sampler: TraceIDRatioSampler{Fraction: 0}},
+					{RPCMethod: "/pkg.Service/Method", Sampler: TraceIDRatioSampler{Fraction: 1}},
+					{HTTPMethod: "POST", Sampler: TraceIDRatioSampler{Fraction: 0.5}},
+				},
+				Fallback: TraceIDRatioSampler{Fraction: 0.1},
+			}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newStrategyServer(t, tt.strategy)
+			p, err := NewJaegerRemoteConfigProvider("my-service", WithJaegerRemoteEndpoint(srv.URL))
+			require.NoError(t, err)
+			t.Cleanup(func() { _ = p.Shutdown(context.Background()) })
+
+			got := p.InitialConfig(context.Background())
+			assert.Equal(t, tt.want, got.Sampler)
+			assert.Equal(t, "my-service", srv.service)
+
+			_, err = convertSamplerToConfig(got.Sampler)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestJaegerRemoteUpdates(t *testing.T) {
+	srv := newStrategyServer(t, "")
+	initial := parentBased(TraceIDRatioSampler{Fraction: 0.5})
+	p, err := NewJaegerRemoteConfigProvider(
+		"my-service",
+		WithJaegerRemoteEndpoint(srv.URL),
+		WithJaegerRemotePollingInterval(10*time.Millisecond),
+		WithJaegerRemoteInitialSampler(initial),
+	)
+	require.NoError(t, err)
+
+	// The initial sampler is used when the strategy cannot be requested.
+	assert.Equal(t, initial, p.InitialConfig(context.Background()).Sampler)
+
+	srv.set(`{"strategyType":"RATE_LIMITING","rateLimitingSampling":{"maxTracesPerSecond":5}}`)
+	ch := p.Watch()
+	select {
+	case c := <-ch:
+		assert.Equal(t, parentBased(RateLimitingSampler{SpansPerSecond: 5}), c.Sampler)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update received")
+	}
+
+	require.NoError(t, p.Shutdown(context.Background()))
+	for range ch {
+		// Drain until closed.
+	}
+}
+
+func TestJaegerRemoteShutdownBeforeWatch(t *testing.T) {
+	p, err := NewJaegerRemoteConfigProvider("my-service")
+	require.NoError(t, err)
+	require.NoError(t, p.Shutdown(context.Background()))
+	_, ok := <-p.Watch()
+	assert.False(t, ok, "channel not closed")
+}
+
+func TestJaegerRemoteFromEnv(t *testing.T) {
+	srv := newStrategyServer(t, `{"strategyType":"PROBABILISTIC","probabilisticSampling":{"samplingRate":0.25}}`)
+	mockEnv(t, map[string]string{
+		tracesSamplerKey:           samplerNameJaegerRemote,
+		tracesSamplerArgKey:        "endpoint=" + srv.URL + ",pollingIntervalMs=5000,initialSamplingRate=0.5",
+		"OTEL_RESOURCE_ATTRIBUTES": "service.name=env-service,k=v",
+	})
+
+	c, err := newInstConfig(context.Background(), []InstrumentationOption{WithEnv()})
+	require.NoError(t, err)
+	p, ok := c.cp.(*JaegerRemoteConfigProvider)
+	require.True(t, ok)
+	t.Cleanup(func() { _ = p.Shutdown(context.Background()) })
+
+	assert.Equal(t, "env-service", p.service)
+	assert.Equal(t, srv.URL, p.cfg.endpoint)
+	assert.Equal(t, 5*time.Second, p.cfg.interval)
+	assert.Equal(t, parentBased(TraceIDRatioSampler{Fraction: 0.5}), p.cfg.initialSampler)
+
+	t.Run("WithSamplerOverrides", func(t *testing.T) {
+		c, err := newInstConfig(context.Background(), []InstrumentationOption{
+			WithEnv(),
+			WithSampler(AlwaysOnSampler{}),
+		})
+		require.NoError(t, err)
+		_, ok := c.cp.(*JaegerRemoteConfigProvider)
+		assert.False(t, ok)
+	})
+
+	t.Run("InvalidArg", func(t *testing.T) {
+		mockEnv(t, map[string]string{
+			tracesSamplerKey:    samplerNameJaegerRemote,
+			tracesSamplerArgKey: "pollingIntervalMs=soon,unknown=1",
+		})
+		_, err := newInstConfig(context.Background(), []InstrumentationOption{WithEnv()})
+		assert.Error(t, err)
+	})
+}
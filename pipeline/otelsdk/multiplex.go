@@ -5,12 +5,11 @@ package otelsdk
 
 import (
 	"context"
-	"debug/buildinfo"
-	"strconv"
 
 	"go.opentelemetry.io/otel/attribute"
 	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
 
+	"go.opentelemetry.io/auto/internal/pkg/process"
 	"go.opentelemetry.io/auto/pipeline"
 )
 
@@ -57,47 +56,30 @@ func (m Multiplexer) Shutdown(ctx context.Context) error {
 }
 
 // withProcResAttrs returns a copy of the Multiplexer's config with additional
-// resource attributes based on Go runtime build information for the process
-// identified by pid.
+// resource attributes describing the process identified by pid, its
+// container, and its host.
 //
-// It attempts to read the build info of the Go executable at /proc/<pid>/exe
-// and extracts semantic convention attributes such as the runtime version and
-// compiler name. If this fails, an error is logged and no extra attributes
-// are added.
+// The attributes are read from /proc/<pid>. Attributes that cannot be
+// detected are logged and skipped.
 func (m Multiplexer) withProcResAttrs(pid int) (c config) {
 	c = m.cfg // Make a shallow copy to modify attributes.
 
-	var attrs []attribute.KeyValue
-
-	path := "/proc/" + strconv.Itoa(pid) + "/exe"
-	bi, err := buildinfo.ReadFile(path)
+	attrs, err := process.Detector{ID: process.ID(pid)}.Attributes(context.Background())
 	if err != nil {
-		c.logger.Error("failed to get Go proc build info", "error", err)
-		return c
+		c.Logger().Debug("failed to detect all process resource attributes", "error", err, "pid", pid)
 	}
 
-	// Add Go runtime version as a semantic attribute.
-	attrs = append(attrs, semconv.ProcessRuntimeVersion(bi.GoVersion))
-
-	// Try to determine which Go compiler was used.
-	var compiler string
-	for _, setting := range bi.Settings {
-		if setting.Key == "-compiler" {
-			compiler = setting.Value
-			break
+	// The service name detected from the process replaces the default one.
+	resAttrs := make([]attribute.KeyValue, 0, len(c.resAttrs))
+	defaultName := semconv.ServiceName(defaultServiceName())
+	for _, attr := range c.resAttrs {
+		if attr != defaultName {
+			resAttrs = append(resAttrs, attr)
 		}
 	}
-	switch compiler {
-	case "":
-		c.logger.Debug("failed to identify Go compiler")
-	case "gc":
-		attrs = append(attrs, semconv.ProcessRuntimeName("go"))
-	default:
-		attrs = append(attrs, semconv.ProcessRuntimeName(compiler))
-	}
 
 	// Prepend process-specific attributes so user-provided ones have priority.
-	c.resAttrs = append(attrs, c.resAttrs...)
+	c.resAttrs = append(attrs, resAttrs...)
 
 	return c
 }
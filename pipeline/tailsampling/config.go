@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// defaultDecisionWait is the default duration spans of a trace are
+	// buffered before a sampling decision is made.
+	defaultDecisionWait = 10 * time.Second
+	// defaultMaxTraces is the default maximum number of traces buffered.
+	defaultMaxTraces = 10_000
+	// defaultMaxSpans is the default maximum number of spans buffered.
+	defaultMaxSpans = 100_000
+)
+
+// Option configures a [TraceHandler] via [NewTraceHandler].
+type Option interface {
+	apply(config) config
+}
+
+type fnOpt func(config) config
+
+func (o fnOpt) apply(c config) config { return o(c) }
+
+type config struct {
+	decisionWait     time.Duration
+	maxTraces        int
+	maxSpans         int
+	latencyThreshold time.Duration
+	attributes       map[string]map[string]struct{}
+	probability      float64
+	meterProvider    metric.MeterProvider
+}
+
+func newConfig(options []Option) (config, error) {
+	c := config{
+		decisionWait: defaultDecisionWait,
+		maxTraces:    defaultMaxTraces,
+		maxSpans:     defaultMaxSpans,
+	}
+	for _, opt := range options {
+		if opt != nil {
+			c = opt.apply(c)
+		}
+	}
+
+	if c.meterProvider == nil {
+		c.meterProvider = otel.GetMeterProvider()
+	}
+
+	return c, c.validate()
+}
+
+func (c config) validate() error {
+	var err error
+	if c.decisionWait <= 0 {
+		err = errors.Join(err, fmt.Errorf("invalid decision wait: %s", c.decisionWait))
+	}
+	if c.maxTraces <= 0 {
+		err = errors.Join(err, fmt.Errorf("invalid max traces: %d", c.maxTraces))
+	}
+	if c.maxSpans <= 0 {
+		err = errors.Join(err, fmt.Errorf("invalid max spans: %d", c.maxSpans))
+	}
+	if c.latencyThreshold < 0 {
+		err = errors.Join(err, fmt.Errorf("invalid latency threshold: %s", c.latencyThreshold))
+	}
+	if c.probability < 0 || c.probability > 1 {
+		err = errors.Join(err, fmt.Errorf("invalid probability: %v", c.probability))
+	}
+	return err
+}
+
+// WithDecisionWait returns an [Option] that sets the duration spans of a trace
+// are buffered, from when the first span of the trace is received, before a
+// sampling decision for the trace is made.
+//
+// By default, 10 seconds is used.
+func WithDecisionWait(d time.Duration) Option {
+	return fnOpt(func(c config) config {
+		c.decisionWait = d
+		return c
+	})
+}
+
+// WithMaxTraces returns an [Option] that sets the maximum number of traces
+// buffered. When this limit is reached, the oldest buffered trace is evicted:
+// a sampling decision is made for it early, using only the spans received.
+//
+// By default, 10,000 traces are buffered at most.
+func WithMaxTraces(n int) Option {
+	return fnOpt(func(c config) config {
+		c.maxTraces = n
+		return c
+	})
+}
+
+// WithMaxSpans returns an [Option] that sets the maximum number of spans
+// buffered across all traces. When this limit is reached, the oldest buffered
+// traces are evicted the same way as with [WithMaxTraces].
+//
+// By default, 100,000 spans are buffered at most.
+func WithMaxSpans(n int) Option {
+	return fnOpt(func(c config) config {
+		c.maxSpans = n
+		return c
+	})
+}
+
+// WithLatencyThreshold returns an [Option] that configures traces containing a
+// span with a duration greater than d to be sampled.
+//
+// By default, or if d is zero, span latency is not used to sample traces.
+func WithLatencyThreshold(d time.Duration) Option {
+	return fnOpt(func(c config) config {
+		c.latencyThreshold = d
+		return c
+	})
+}
+
+// WithAttribute returns an [Option] that configures traces containing a span
+// with an attribute key whose value, in its string representation, is one of
+// values to be sampled. If no values are provided, traces containing a span
+// with the attribute key set to any value are sampled.
+//
+// This option can be provided multiple times to match multiple attributes.
+func WithAttribute(key string, values ...string) Option {
+	return fnOpt(func(c config) config {
+		// Copy to not modify the attributes of other configs.
+		attrs := make(map[string]map[string]struct{}, len(c.attributes)+1)
+		maps.Copy(attrs, c.attributes)
+
+		var set map[string]struct{}
+		if len(values) > 0 {
+			set = make(map[string]struct{}, len(values))
+			for _, v := range values {
+				set[v] = struct{}{}
+			}
+		}
+		attrs[key] = set
+		c.attributes = attrs
+		return c
+	})
+}
+
+// WithProbability returns an [Option] that sets the probability, in the range
+// [0, 1], traces not sampled by any other criteria are sampled with. The
+// decision is based on the trace ID so it is consistent with other
+// probabilistic samplers.
+//
+// By default, traces not sampled by any other criteria are dropped.
+func WithProbability(p float64) Option {
+	return fnOpt(func(c config) config {
+		c.probability = p
+		return c
+	})
+}
+
+// WithMeterProvider returns an [Option] that sets the MeterProvider used to
+// report metrics about the tail sampling process.
+//
+// By default, the global MeterProvider is used.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return fnOpt(func(c config) config {
+		c.meterProvider = mp
+		return c
+	})
+}
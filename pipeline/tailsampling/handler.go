@@ -0,0 +1,421 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tailsampling provides a [pipeline.TraceHandler] that makes sampling
+// decisions for whole traces after their spans have been generated.
+package tailsampling
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"go.opentelemetry.io/auto/pipeline"
+)
+
+const meterName = "go.opentelemetry.io/auto/pipeline/tailsampling"
+
+// Sampling decision policies. These are used as the value of the policy
+// attribute of decision metrics.
+const (
+	policyError         = "error"
+	policyLatency       = "latency"
+	policyAttribute     = "attribute"
+	policyProbabilistic = "probabilistic"
+)
+
+// TraceHandler buffers spans by trace ID and passes all spans of a trace to
+// the next [pipeline.TraceHandler] only if the trace is sampled.
+//
+// A trace is sampled if any of its spans has an error status, a duration
+// greater than the configured latency threshold, or a matching attribute.
+// Otherwise, the trace is sampled based on the configured probability.
+//
+// Spans received for a trace after its sampling decision was made follow that
+// decision.
+type TraceHandler struct {
+	cfg  config
+	next pipeline.TraceHandler
+	now  func() time.Time
+
+	decisions metric.Int64Counter
+	evicted   metric.Int64Counter
+	late      metric.Int64Counter
+	buffered  metric.Int64UpDownCounter
+
+	mu       sync.Mutex
+	traces   map[pcommon.TraceID]*trace
+	queue    []pcommon.TraceID
+	nSpans   int
+	decided  map[pcommon.TraceID]bool
+	history  []pcommon.TraceID
+	histIdx  int
+	shutdown bool
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+var _ pipeline.TraceHandler = (*TraceHandler)(nil)
+
+// trace is the buffered state of a trace.
+type trace struct {
+	deadline time.Time
+	batches  []batch
+	nSpans   int
+}
+
+// batch holds spans of a trace received with the same scope and schema URL.
+type batch struct {
+	scope pcommon.InstrumentationScope
+	url   string
+	spans ptrace.SpanSlice
+}
+
+// NewTraceHandler returns a new [TraceHandler] that passes sampled traces to
+// next.
+//
+// Shutdown needs to be called when the TraceHandler is no longer used to
+// release resources and flush buffered traces.
+func NewTraceHandler(next pipeline.TraceHandler, options ...Option) (*TraceHandler, error) {
+	if next == nil {
+		return nil, errors.New("nil next handler")
+	}
+
+	c, err := newConfig(options)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &TraceHandler{
+		cfg:     c,
+		next:    next,
+		now:     time.Now,
+		traces:  make(map[pcommon.TraceID]*trace),
+		decided: make(map[pcommon.TraceID]bool),
+		history: make([]pcommon.TraceID, c.maxTraces),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	if err := h.initMetrics(); err != nil {
+		return nil, err
+	}
+
+	go h.run(tickInterval(c.decisionWait))
+	return h, nil
+}
+
+func (h *TraceHandler) initMetrics() error {
+	m := h.cfg.meterProvider.Meter(meterName)
+
+	var err, e error
+	h.decisions, e = m.Int64Counter(
+		"tail_sampling.decisions",
+		metric.WithDescription("Number of traces a sampling decision was made for."),
+		metric.WithUnit("{trace}"),
+	)
+	err = errors.Join(err, e)
+	h.evicted, e = m.Int64Counter(
+		"tail_sampling.traces.evicted",
+		metric.WithDescription("Number of traces decided before their decision wait ended because buffer limits were reached."),
+		metric.WithUnit("{trace}"),
+	)
+	err = errors.Join(err, e)
+	h.late, e = m.Int64Counter(
+		"tail_sampling.spans.late",
+		metric.WithDescription("Number of spans received after the sampling decision of their trace was made."),
+		metric.WithUnit("{span}"),
+	)
+	err = errors.Join(err, e)
+	h.buffered, e = m.Int64UpDownCounter(
+		"tail_sampling.spans.buffered",
+		metric.WithDescription("Number of spans buffered awaiting a sampling decision."),
+		metric.WithUnit("{span}"),
+	)
+	err = errors.Join(err, e)
+	return err
+}
+
+// tickInterval returns the interval buffered traces are checked for an ended
+// decision wait.
+func tickInterval(wait time.Duration) time.Duration {
+	const minInterval = 10 * time.Millisecond
+	return max(wait/10, minInterval)
+}
+
+func (h *TraceHandler) run(interval time.Duration) {
+	defer close(h.stopped)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+			h.decideExpired()
+		}
+	}
+}
+
+// HandleTrace buffers the spans until a sampling decision is made for their
+// trace.
+func (h *TraceHandler) HandleTrace(scope pcommon.InstrumentationScope, url string, spans ptrace.SpanSlice) {
+	var forward []batch
+
+	h.mu.Lock()
+	if h.shutdown {
+		h.mu.Unlock()
+		return
+	}
+
+	now := h.now()
+	for i := range spans.Len() {
+		span := spans.At(i)
+		tID := span.TraceID()
+
+		if keep, ok := h.decided[tID]; ok {
+			h.late.Add(context.Background(), 1)
+			if keep {
+				forward = appendSpan(forward, scope, url, span)
+			}
+			continue
+		}
+
+		t, ok := h.traces[tID]
+		if !ok {
+			t = &trace{deadline: now.Add(h.cfg.decisionWait)}
+			h.traces[tID] = t
+			h.queue = append(h.queue, tID)
+		}
+		t.batches = appendSpan(t.batches, scope, url, span)
+		t.nSpans++
+		h.nSpans++
+		h.buffered.Add(context.Background(), 1)
+	}
+
+	forward = append(forward, h.evictLocked()...)
+	h.mu.Unlock()
+
+	h.forward(forward)
+}
+
+// appendSpan appends a copy of span to the last batch in batches if it has
+// the same scope and schema URL. Otherwise, a new batch is appended.
+func appendSpan(batches []batch, scope pcommon.InstrumentationScope, url string, span ptrace.Span) []batch {
+	if n := len(batches); n > 0 {
+		last := batches[n-1]
+		if last.url == url && sameScope(last.scope, scope) {
+			span.CopyTo(last.spans.AppendEmpty())
+			return batches
+		}
+	}
+	b := batch{scope: scope, url: url, spans: ptrace.NewSpanSlice()}
+	span.CopyTo(b.spans.AppendEmpty())
+	return append(batches, b)
+}
+
+func sameScope(a, b pcommon.InstrumentationScope) bool {
+	return a.Name() == b.Name() && a.Version() == b.Version()
+}
+
+// evictLocked decides the oldest buffered traces while buffer limits are
+// exceeded. It returns the spans to forward. h.mu must be held.
+func (h *TraceHandler) evictLocked() []batch {
+	var forward []batch
+	for len(h.traces) > h.cfg.maxTraces || h.nSpans > h.cfg.maxSpans {
+		tID, ok := h.popLocked()
+		if !ok {
+			break
+		}
+		h.evicted.Add(context.Background(), 1)
+		forward = append(forward, h.decideLocked(tID)...)
+	}
+	return forward
+}
+
+// decideExpired decides all buffered traces whose decision wait has ended.
+func (h *TraceHandler) decideExpired() {
+	var forward []batch
+
+	h.mu.Lock()
+	now := h.now()
+	for len(h.queue) > 0 {
+		t, ok := h.traces[h.queue[0]]
+		if ok && t.deadline.After(now) {
+			// The queue is ordered by deadline.
+			break
+		}
+		tID, _ := h.popLocked()
+		if ok {
+			forward = append(forward, h.decideLocked(tID)...)
+		}
+	}
+	h.mu.Unlock()
+
+	h.forward(forward)
+}
+
+// popLocked removes and returns the oldest buffered trace ID. h.mu must be
+// held.
+func (h *TraceHandler) popLocked() (pcommon.TraceID, bool) {
+	if len(h.queue) == 0 {
+		return pcommon.TraceID{}, false
+	}
+	tID := h.queue[0]
+	h.queue[0] = pcommon.TraceID{}
+	h.queue = h.queue[1:]
+	return tID, true
+}
+
+// decideLocked makes the sampling decision for the buffered trace with tID.
+// The spans to forward are returned if the trace is sampled. h.mu must be
+// held.
+func (h *TraceHandler) decideLocked(tID pcommon.TraceID) []batch {
+	t, ok := h.traces[tID]
+	if !ok {
+		return nil
+	}
+	delete(h.traces, tID)
+	h.nSpans -= t.nSpans
+	h.buffered.Add(context.Background(), -int64(t.nSpans))
+
+	policy, keep := h.sample(tID, t)
+	h.rememberLocked(tID, keep)
+
+	h.decisions.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(
+			attribute.Bool("sampled", keep),
+			attribute.String("policy", policy),
+		),
+	)
+
+	if !keep {
+		return nil
+	}
+	return t.batches
+}
+
+// rememberLocked records the sampling decision for tID so late spans of the
+// trace follow it. Only the last maxTraces decisions are remembered. h.mu must
+// be held.
+func (h *TraceHandler) rememberLocked(tID pcommon.TraceID, keep bool) {
+	if old := h.history[h.histIdx]; !old.IsEmpty() {
+		delete(h.decided, old)
+	}
+	h.history[h.histIdx] = tID
+	h.histIdx = (h.histIdx + 1) % len(h.history)
+	h.decided[tID] = keep
+}
+
+// sample returns if the trace t with tID is sampled and the policy that
+// decided it.
+func (h *TraceHandler) sample(tID pcommon.TraceID, t *trace) (string, bool) {
+	var latency, attr bool
+	for _, b := range t.batches {
+		for i := range b.spans.Len() {
+			span := b.spans.At(i)
+			if span.Status().Code() == ptrace.StatusCodeError {
+				return policyError, true
+			}
+			if !latency && h.cfg.latencyThreshold > 0 {
+				d := time.Duration(span.EndTimestamp() - span.StartTimestamp())
+				latency = span.EndTimestamp() >= span.StartTimestamp() && d > h.cfg.latencyThreshold
+			}
+			if !attr && len(h.cfg.attributes) > 0 {
+				attr = h.matchAttributes(span.Attributes())
+			}
+		}
+	}
+
+	switch {
+	case latency:
+		return policyLatency, true
+	case attr:
+		return policyAttribute, true
+	}
+	return policyProbabilistic, h.sampleProbabilistic(tID)
+}
+
+func (h *TraceHandler) matchAttributes(attrs pcommon.Map) bool {
+	var match bool
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		values, ok := h.cfg.attributes[k]
+		if !ok {
+			return true
+		}
+		if values == nil {
+			match = true
+			return false
+		}
+		_, match = values[v.AsString()]
+		return !match
+	})
+	return match
+}
+
+// sampleProbabilistic returns if the trace with tID is sampled based on the
+// configured probability. This uses the same algorithm as the OpenTelemetry
+// Go SDK TraceIDRatioBased sampler.
+func (h *TraceHandler) sampleProbabilistic(tID pcommon.TraceID) bool {
+	switch {
+	case h.cfg.probability >= 1:
+		return true
+	case h.cfg.probability <= 0:
+		return false
+	}
+	bound := uint64(h.cfg.probability * (1 << 63))
+	x := binary.BigEndian.Uint64(tID[8:16]) >> 1
+	return x < bound
+}
+
+func (h *TraceHandler) forward(batches []batch) {
+	for _, b := range batches {
+		h.next.HandleTrace(b.scope, b.url, b.spans)
+	}
+}
+
+// Shutdown makes a sampling decision for all buffered traces and stops the
+// TraceHandler. All spans received after Shutdown is called are dropped.
+//
+// The next handler is not shut down.
+func (h *TraceHandler) Shutdown(ctx context.Context) error {
+	h.mu.Lock()
+	if h.shutdown {
+		h.mu.Unlock()
+		return nil
+	}
+	h.shutdown = true
+	h.mu.Unlock()
+
+	close(h.stop)
+	select {
+	case <-h.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	var forward []batch
+	h.mu.Lock()
+	for {
+		tID, ok := h.popLocked()
+		if !ok {
+			break
+		}
+		forward = append(forward, h.decideLocked(tID)...)
+	}
+	h.mu.Unlock()
+
+	h.forward(forward)
+	return ctx.Err()
+}
@@ -0,0 +1,243 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package tailsampling
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	spans []ptrace.Span
+}
+
+func (r *recorder) HandleTrace(_ pcommon.InstrumentationScope, _ string, spans ptrace.SpanSlice) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range spans.Len() {
+		r.spans = append(r.spans, spans.At(i))
+	}
+}
+
+func (r *recorder) names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]string, len(r.spans))
+	for i, s := range r.spans {
+		out[i] = s.Name()
+	}
+	return out
+}
+
+type spanOpt func(ptrace.Span)
+
+func withError(s ptrace.Span) { s.Status().SetCode(ptrace.StatusCodeError) }
+
+func withDuration(d time.Duration) spanOpt {
+	return func(s ptrace.Span) {
+		s.SetStartTimestamp(1)
+		s.SetEndTimestamp(pcommon.Timestamp(1 + d))
+	}
+}
+
+func withAttr(k, v string) spanOpt {
+	return func(s ptrace.Span) { s.Attributes().PutStr(k, v) }
+}
+
+func spans(tID byte, name string, opts ...spanOpt) ptrace.SpanSlice {
+	ss := ptrace.NewSpanSlice()
+	s := ss.AppendEmpty()
+	s.SetName(name)
+	s.SetTraceID(pcommon.TraceID{tID})
+	s.SetSpanID(pcommon.SpanID{1})
+	for _, o := range opts {
+		o(s)
+	}
+	return ss
+}
+
+func newTestHandler(t *testing.T, next *recorder, opts ...Option) (*TraceHandler, *sdkmetric.ManualReader, *time.Time) {
+	t.Helper()
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	opts = append([]Option{
+		// Decide manually in tests.
+		WithDecisionWait(time.Hour),
+		WithMeterProvider(mp),
+	}, opts...)
+
+	h, err := NewTraceHandler(next, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = h.Shutdown(context.Background()) })
+
+	now := time.Unix(0, 0)
+	h.now = func() time.Time { return now }
+	return h, reader, &now
+}
+
+func TestTraceHandlerPolicies(t *testing.T) {
+	next := new(recorder)
+	h, _, now := newTestHandler(
+		t,
+		next,
+		WithLatencyThreshold(time.Second),
+		WithAttribute("http.route", "/checkout"),
+		WithAttribute("debug"),
+	)
+
+	scope := pcommon.NewInstrumentationScope()
+	h.HandleTrace(scope, "", spans(1, "ok"))
+	h.HandleTrace(scope, "", spans(1, "error", withError))
+	h.HandleTrace(scope, "", spans(2, "fast", withDuration(time.Millisecond)))
+	h.HandleTrace(scope, "", spans(3, "slow", withDuration(2*time.Second)))
+	h.HandleTrace(scope, "", spans(4, "other", withAttr("http.route", "/home")))
+	h.HandleTrace(scope, "", spans(5, "checkout", withAttr("http.route", "/checkout")))
+	h.HandleTrace(scope, "", spans(6, "debug", withAttr("debug", "anything")))
+
+	h.decideExpired()
+	assert.Empty(t, next.names(), "decided before decision wait")
+
+	*now = now.Add(2 * time.Hour)
+	h.decideExpired()
+	assert.ElementsMatch(t, []string{"ok", "error", "slow", "checkout", "debug"}, next.names())
+
+	// Late spans follow the decision of their trace.
+	h.HandleTrace(scope, "", spans(1, "late kept"))
+	h.HandleTrace(scope, "", spans(2, "late dropped", withError))
+	assert.Contains(t, next.names(), "late kept")
+	assert.NotContains(t, next.names(), "late dropped")
+}
+
+func TestTraceHandlerProbability(t *testing.T) {
+	for _, p := range []float64{0, 1} {
+		next := new(recorder)
+		h, _, _ := newTestHandler(t, next, WithProbability(p))
+		h.HandleTrace(pcommon.NewInstrumentationScope(), "", spans(1, "span"))
+		require.NoError(t, h.Shutdown(context.Background()))
+		assert.Len(t, next.names(), int(p), "probability %v", p)
+	}
+
+	h := &TraceHandler{cfg: config{probability: 0.5}}
+	assert.True(t, h.sampleProbabilistic(pcommon.TraceID{8: 0x00}))
+	assert.False(t, h.sampleProbabilistic(pcommon.TraceID{8: 0xff}))
+}
+
+func TestTraceHandlerEviction(t *testing.T) {
+	next := new(recorder)
+	h, reader, _ := newTestHandler(t, next, WithMaxTraces(2), WithMaxSpans(3))
+
+	scope := pcommon.NewInstrumentationScope()
+	h.HandleTrace(scope, "", spans(1, "a", withError))
+	h.HandleTrace(scope, "", spans(2, "b", withError))
+	assert.Empty(t, next.names())
+
+	// Exceeds max traces, evicting trace 1.
+	h.HandleTrace(scope, "", spans(3, "c", withError))
+	assert.Equal(t, []string{"a"}, next.names())
+
+	// Exceeds max spans, evicting trace 2.
+	h.HandleTrace(scope, "", spans(3, "d", withError))
+	h.HandleTrace(scope, "", spans(3, "e", withError))
+	assert.Equal(t, []string{"a", "b"}, next.names())
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	var got metricdata.Metrics
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name == "tail_sampling.traces.evicted" {
+			got = m
+		}
+	}
+	want := metricdata.Metrics{
+		Name:        "tail_sampling.traces.evicted",
+		Description: "Number of traces decided before their decision wait ended because buffer limits were reached.",
+		Unit:        "{trace}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints:  []metricdata.DataPoint[int64]{{Value: 2}},
+		},
+	}
+	metricdatatest.AssertEqual(t, want, got, metricdatatest.IgnoreTimestamp())
+
+	require.NoError(t, h.Shutdown(context.Background()))
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, next.names())
+
+	// Dropped after shutdown.
+	h.HandleTrace(scope, "", spans(4, "f", withError))
+	assert.Len(t, next.names(), 5)
+}
+
+func TestTraceHandlerDecisionMetrics(t *testing.T) {
+	next := new(recorder)
+	h, reader, _ := newTestHandler(t, next)
+
+	scope := pcommon.NewInstrumentationScope()
+	h.HandleTrace(scope, "", spans(1, "a", withError))
+	h.HandleTrace(scope, "", spans(2, "b"))
+	require.NoError(t, h.Shutdown(context.Background()))
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+
+	var got metricdata.Metrics
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		if m.Name == "tail_sampling.decisions" {
+			got = m
+		}
+	}
+	want := metricdata.Metrics{
+		Name:        "tail_sampling.decisions",
+		Description: "Number of traces a sampling decision was made for.",
+		Unit:        "{trace}",
+		Data: metricdata.Sum[int64]{
+			Temporality: metricdata.CumulativeTemporality,
+			IsMonotonic: true,
+			DataPoints: []metricdata.DataPoint[int64]{
+				{
+					Attributes: attribute.NewSet(
+						attribute.Bool("sampled", true),
+						attribute.String("policy", policyError),
+					),
+					Value: 1,
+				},
+				{
+					Attributes: attribute.NewSet(
+						attribute.Bool("sampled", false),
+						attribute.String("policy", policyProbabilistic),
+					),
+					Value: 1,
+				},
+			},
+		},
+	}
+	metricdatatest.AssertEqual(t, want, got, metricdatatest.IgnoreTimestamp())
+}
+
+func TestNewTraceHandlerErrors(t *testing.T) {
+	_, err := NewTraceHandler(nil)
+	assert.Error(t, err, "nil next")
+
+	_, err = NewTraceHandler(new(recorder), WithProbability(2))
+	assert.Error(t, err, "invalid probability")
+
+	_, err = NewTraceHandler(new(recorder), WithMaxTraces(0))
+	assert.Error(t, err, "invalid max traces")
+}
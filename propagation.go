@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
+)
+
+const (
+	// envPropagatorsKey is the key for the environment variable value
+	// containing the comma-separated list of propagators.
+	envPropagatorsKey = "OTEL_PROPAGATORS"
+	// envBaggageAttributesKey is the key for the environment variable value
+	// containing the comma-separated list of baggage keys set as span
+	// attributes.
+	envBaggageAttributesKey = "OTEL_GO_AUTO_BAGGAGE_ATTRIBUTES"
+)
+
+// Propagator is a format used to extract and inject span context in the
+// requests and messages instrumented. The values are the ones of the
+// OTEL_PROPAGATORS environment variable.
+type Propagator string
+
+const (
+	// PropagatorTraceContext is the W3C Trace Context format, the traceparent
+	// and tracestate headers.
+	PropagatorTraceContext Propagator = "tracecontext"
+	// PropagatorB3 is the Zipkin B3 single header format.
+	PropagatorB3 Propagator = "b3"
+	// PropagatorB3Multi is the Zipkin B3 multiple header format.
+	PropagatorB3Multi Propagator = "b3multi"
+	// PropagatorJaeger is the Jaeger uber-trace-id header format.
+	PropagatorJaeger Propagator = "jaeger"
+	// PropagatorBaggage is the W3C Baggage format, the baggage header. The
+	// baggage is propagated with the span context extracted by the other
+	// propagators.
+	PropagatorBaggage Propagator = "baggage"
+	// PropagatorNone disables context propagation when it is the only
+	// propagator used. It is ignored otherwise.
+	PropagatorNone Propagator = "none"
+)
+
+var errUnsupportedPropagator = errors.New("unsupported propagator")
+
+// WithPropagators returns an [InstrumentationOption] that will configure an
+// [Instrumentation] to propagate span context using the propagators, in
+// priority order. The span context of incoming requests is extracted using
+// the first propagator whose headers are found, and the span context of
+// outgoing requests is injected using all the propagators.
+//
+// If no propagators are passed, or only [PropagatorNone], no span context is
+// propagated. An error is returned if an unsupported propagator is passed.
+//
+// If this option is not used, [PropagatorTraceContext] and
+// [PropagatorBaggage] are used.
+//
+// This option may conflict with [WithEnv] if the OTEL_PROPAGATORS environment
+// variable is defined. If both of these options are used, the last one
+// provided to an [Instrumentation] will be used.
+func WithPropagators(propagators ...Propagator) InstrumentationOption {
+	return fnOpt(func(_ context.Context, c instConfig) (instConfig, error) {
+		p, err := convertPropagators(propagators)
+		if err != nil {
+			return c, err
+		}
+		c.propagators = p
+		return c, nil
+	})
+}
+
+// convertPropagators returns the probe propagators of propagators. The
+// returned slice is not nil, it is empty if nothing is propagated.
+func convertPropagators(propagators []Propagator) ([]probe.Propagator, error) {
+	out := make([]probe.Propagator, 0, len(propagators))
+	var err error
+	for _, p := range propagators {
+		switch p {
+		case PropagatorTraceContext:
+			out = append(out, probe.PropagatorTraceContext)
+		case PropagatorB3:
+			out = append(out, probe.PropagatorB3)
+		case PropagatorB3Multi:
+			out = append(out, probe.PropagatorB3Multi)
+		case PropagatorJaeger:
+			out = append(out, probe.PropagatorJaeger)
+		case PropagatorBaggage:
+			out = append(out, probe.PropagatorBaggage)
+		case PropagatorNone:
+		default:
+			err = errors.Join(err, fmt.Errorf("%w: %q", errUnsupportedPropagator, p))
+		}
+	}
+	return out, err
+}
+
+// propagatorsFromEnv returns the propagators of the OTEL_PROPAGATORS
+// environment variable. The returned bool is false if the variable is not
+// set.
+func propagatorsFromEnv(lookupEnv func(string) (string, bool)) ([]probe.Propagator, bool, error) {
+	val, ok := lookupEnv(envPropagatorsKey)
+	if !ok {
+		return nil, false, nil
+	}
+
+	var propagators []Propagator
+	for _, name := range strings.Split(val, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		propagators = append(propagators, Propagator(name))
+	}
+	if len(propagators) == 0 {
+		// An empty value is treated as unset.
+		return nil, false, nil
+	}
+
+	p, err := convertPropagators(propagators)
+	if err != nil {
+		return nil, true, fmt.Errorf("parse %s: %w", envPropagatorsKey, err)
+	}
+	return p, true, nil
+}
+
+// WithBaggageAttributes returns an [InstrumentationOption] that will configure
+// an [Instrumentation] to set the values of the baggage members with the keys
+// as attributes of the spans of the requests the baggage is propagated with.
+// The attribute keys are the baggage keys. Existing span attributes are not
+// overwritten.
+//
+// Baggage is only propagated if [PropagatorBaggage] is used, see
+// [WithPropagators].
+//
+// This option may conflict with [WithEnv] if the
+// OTEL_GO_AUTO_BAGGAGE_ATTRIBUTES environment variable is defined. If both of
+// these options are used, the last one provided to an [Instrumentation] will
+// be used.
+func WithBaggageAttributes(keys ...string) InstrumentationOption {
+	return fnOpt(func(_ context.Context, c instConfig) (instConfig, error) {
+		c.baggageAttributes = keys
+		return c, nil
+	})
+}
+
+// baggageAttributesFromEnv returns the baggage keys of the
+// OTEL_GO_AUTO_BAGGAGE_ATTRIBUTES environment variable. The returned bool is
+// false if the variable is not set.
+func baggageAttributesFromEnv(lookupEnv func(string) (string, bool)) ([]string, bool) {
+	val, ok := lookupEnv(envBaggageAttributesKey)
+	if !ok {
+		return nil, false
+	}
+
+	var keys []string
+	for _, key := range strings.Split(val, ",") {
+		key = strings.TrimSpace(key)
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, true
+}
@@ -6,6 +6,7 @@ package auto
 import (
 	"context"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/otel/trace"
 
@@ -27,6 +28,27 @@ type InstrumentationLibrary struct {
 	// TracesEnabled determines whether traces are enabled for the instrumentation library.
 	// if nil - take DefaultTracesDisabled value.
 	TracesEnabled *bool
+	// MinDuration is the minimum duration of the spans exported by the
+	// instrumentation library. Shorter spans are dropped before they are
+	// sent from the kernel, but their context is still propagated. If zero,
+	// all spans are exported.
+	MinDuration time.Duration
+	// CaptureRequestHeaders are the names of the request headers recorded as
+	// span attributes by the instrumentation library, e.g. as
+	// "http.request.header.<name>" or "rpc.grpc.request.metadata.<name>".
+	// Names are matched case-insensitively and values longer than 128 bytes
+	// are truncated.
+	CaptureRequestHeaders []string
+	// CaptureResponseHeaders are the names of the response headers recorded
+	// as span attributes by the instrumentation library, e.g. as
+	// "http.response.header.<name>" or "rpc.grpc.response.metadata.<name>".
+	// Names are matched case-insensitively and values longer than 128 bytes
+	// are truncated.
+	CaptureResponseHeaders []string
+	// RedactHeaders are the names of the captured headers whose values are
+	// replaced by "REDACTED". The Authorization, Proxy-Authorization, Cookie
+	// and Set-Cookie headers are always redacted.
+	RedactHeaders []string
 }
 
 // InstrumentationConfig is used to configure instrumentation.
@@ -127,7 +149,11 @@ func (c *converter) instrumentationConfig(ic InstrumentationConfig) instrumentat
 				SpanKind:        k.SpanKind,
 			}
 			out.InstrumentationLibraryConfigs[id] = instrumentation.Library{
-				TracesEnabled: v.TracesEnabled,
+				TracesEnabled:          v.TracesEnabled,
+				MinDuration:            v.MinDuration,
+				CaptureRequestHeaders:  v.CaptureRequestHeaders,
+				CaptureResponseHeaders: v.CaptureResponseHeaders,
+				RedactHeaders:          v.RedactHeaders,
 			}
 		}
 	}
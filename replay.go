@@ -0,0 +1,146 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package auto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"go.opentelemetry.io/auto/internal/pkg/instrumentation/probe"
+	"go.opentelemetry.io/auto/pipeline"
+)
+
+// WithRecording returns an [InstrumentationOption] that will configure an
+// [Instrumentation] to record all raw eBPF events read from the target process
+// to w, along with the metadata of the target process.
+//
+// The recording can be replayed using [Replay]. It is intended to be used for
+// offline debugging of the instrumentation and may contain sensitive data
+// read from the target process.
+//
+// Writes to w are not buffered. It is the caller's responsibility to close w
+// after the [Instrumentation] is closed.
+func WithRecording(w io.Writer) InstrumentationOption {
+	return fnOpt(func(_ context.Context, c instConfig) (instConfig, error) {
+		if w == nil {
+			return c, errors.New("nil recording writer")
+		}
+		c.recorder = probe.NewRecorder(w)
+		return c, nil
+	})
+}
+
+// ReplayOption applies a configuration option to [Replay].
+type ReplayOption interface {
+	applyReplay(replayConfig) replayConfig
+}
+
+type replayConfig struct {
+	logger *slog.Logger
+}
+
+type replayOptFunc func(replayConfig) replayConfig
+
+func (f replayOptFunc) applyReplay(c replayConfig) replayConfig { return f(c) }
+
+// WithReplayLogger returns a [ReplayOption] that will configure [Replay] to
+// use the provided logger.
+//
+// If this option is not used, an [slog.Logger] backed by an
+// [slog.JSONHandler] outputting to STDERR is used.
+func WithReplayLogger(logger *slog.Logger) ReplayOption {
+	return replayOptFunc(func(c replayConfig) replayConfig {
+		c.logger = logger
+		return c
+	})
+}
+
+// Replay replays the recording read from r, made using [WithRecording], and
+// passes all telemetry generated to h.
+//
+// The recorded eBPF events are processed by the same instrumentation that
+// read them, configured with the same propagators and baggage attributes. This does not require eBPF support, elevated privileges, or the
+// target process to be running.
+//
+// An error is returned if the recording is invalid or was made by an
+// incompatible version of the instrumentation. Events that cannot be
+// processed are logged and skipped.
+func Replay(ctx context.Context, r io.Reader, h *pipeline.Handler, opts ...ReplayOption) error {
+	if h == nil {
+		return errors.New("nil handler")
+	}
+
+	var c replayConfig
+	for _, opt := range opts {
+		if opt != nil {
+			c = opt.applyReplay(c)
+		}
+	}
+	if c.logger == nil {
+		c.logger = newLogger(nil)
+	}
+
+	rr, err := probe.NewRecordingReader(r)
+	if err != nil {
+		return err
+	}
+
+	replayers := make(map[probe.ID]probe.Replayer)
+	for _, p := range newProbes(c.logger) {
+		if rp, ok := p.(probe.Replayer); ok {
+			replayers[p.Manifest().ID] = rp
+		}
+	}
+
+	var (
+		proc   *probe.ProcessMetadata
+		cfg    *probe.RecordedConfig
+		failed int
+	)
+	for ctx.Err() == nil {
+		s, err := rr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("invalid recording: %w", err)
+		}
+
+		if md := rr.Process(); md != nil && md != proc {
+			proc = md
+			info := md.Info()
+			for _, rp := range replayers {
+				rp.PrepareReplay(info)
+			}
+		}
+
+		if rc := rr.Config(); rc != nil && rc != cfg {
+			cfg = rc
+			for _, rp := range replayers {
+				if pp, ok := rp.(probe.Propagating); ok {
+					cfg.Apply(pp)
+				}
+			}
+		}
+
+		rp, ok := replayers[s.Probe]
+		if !ok {
+			c.logger.Warn("no instrumentation for recorded event", "probe", s.Probe)
+			failed++
+			continue
+		}
+		if err := rp.Replay(h, s); err != nil {
+			c.logger.Warn("failed to replay event", "probe", s.Probe, "error", err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		c.logger.Info("replay completed with skipped events", "skipped", failed)
+	}
+	return ctx.Err()
+}
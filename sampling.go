@@ -5,7 +5,9 @@ package auto
 
 import (
 	"errors"
+	"fmt"
 	"maps"
+	"math"
 	"strconv"
 	"strings"
 
@@ -29,6 +31,16 @@ const (
 	samplerNameParentBasedAlwaysOn     = "parentbased_always_on"
 	samplerNameParsedBasedAlwaysOff    = "parentbased_always_off"
 	samplerNameParentBasedTraceIDRatio = "parentbased_traceidratio"
+
+	// Samplers not defined by the specification.
+	samplerNameRateLimiting            = "ratelimiting"
+	samplerNameParentBasedRateLimiting = "parentbased_ratelimiting"
+	samplerNameProbability             = "probability"
+	samplerNameParentBasedProbability  = "parentbased_probability"
+
+	// defaultSpansPerSecond is the rate used by rate-limiting samplers
+	// configured from the environment without an argument.
+	defaultSpansPerSecond = 100
 )
 
 // AlwaysOnSampler is a Sampler that samples every trace.
@@ -108,6 +120,91 @@ func (t TraceIDRatioSampler) convert() (*sampling.Config, error) {
 	}, nil
 }
 
+// RateLimitingSampler is a [Sampler] that samples at most SpansPerSecond
+// traces per second, regardless of the traffic received.
+//
+// The rate is enforced with a token bucket shared by all probes of the
+// instrumented process. Up to one second worth of traces can be sampled in a
+// burst. Because the decision is made without synchronization between CPUs,
+// slightly more traces than the configured rate can be sampled under high
+// concurrency.
+//
+// To respect the parent trace's SampledFlag, the RateLimitingSampler should be
+// used as the Root of a [ParentBasedSampler].
+type RateLimitingSampler struct {
+	// SpansPerSecond is the maximum number of traces sampled per second. It
+	// needs to be non-negative. If zero, no traces are sampled.
+	SpansPerSecond float64
+}
+
+var _ Sampler = RateLimitingSampler{}
+
+func (r RateLimitingSampler) validate() error {
+	if math.IsNaN(r.SpansPerSecond) || r.SpansPerSecond < 0 {
+		return errors.New("spans per second in RateLimitingSampler must be non-negative")
+	}
+	return nil
+}
+
+func (r RateLimitingSampler) convert() (*sampling.Config, error) {
+	rlConfig, err := sampling.NewRateLimitingConfig(r.SpansPerSecond)
+	if err != nil {
+		return nil, err
+	}
+	return &sampling.Config{
+		Samplers: map[sampling.SamplerID]sampling.SamplerConfig{
+			sampling.RateLimitingID: {
+				SamplerType: sampling.SamplerRateLimiting,
+				Config:      rlConfig,
+			},
+		},
+		ActiveSampler: sampling.RateLimitingID,
+	}, nil
+}
+
+// ProbabilitySampler is a [Sampler] that samples a given fraction of traces
+// following the OpenTelemetry consistent probability sampling specification.
+//
+// Unlike [TraceIDRatioSampler], the decision is consistent across services:
+// the randomness of the trace is read from the rv value of the incoming W3C
+// tracestate "ot" entry, if present, or from the least significant 56 bits of
+// the trace ID. The rejection threshold used is recorded in the th value of
+// the tracestate propagated to downstream services and of the exported spans
+// so the adjusted count of sampled spans can be computed.
+//
+// To respect the parent trace's SampledFlag, the ProbabilitySampler should be
+// used as the Root of a [ParentBasedSampler].
+type ProbabilitySampler struct {
+	// Probability is the probability a trace is sampled. It needs to be in
+	// the closed interval [0, 1].
+	Probability float64
+}
+
+var _ Sampler = ProbabilitySampler{}
+
+func (p ProbabilitySampler) validate() error {
+	if math.IsNaN(p.Probability) || p.Probability < 0 || p.Probability > 1 {
+		return errors.New("probability in ProbabilitySampler must be in the range [0, 1]")
+	}
+	return nil
+}
+
+func (p ProbabilitySampler) convert() (*sampling.Config, error) {
+	pConfig, err := sampling.NewProbabilityConfig(p.Probability)
+	if err != nil {
+		return nil, err
+	}
+	return &sampling.Config{
+		Samplers: map[sampling.SamplerID]sampling.SamplerConfig{
+			sampling.ProbabilityID: {
+				SamplerType: sampling.SamplerProbability,
+				Config:      pConfig,
+			},
+		},
+		ActiveSampler: sampling.ProbabilityID,
+	}, nil
+}
+
 // ParentBasedSampler is a [Sampler] which behaves differently,
 // based on the parent of the span. If the span has no parent,
 // the Root sampler is used to make sampling decision. If the span has
@@ -211,6 +308,172 @@ func (p ParentBasedSampler) convert() (*sampling.Config, error) {
 	}, nil
 }
 
+// RuleBasedSampler is a [Sampler] that selects the Sampler used to make the
+// sampling decision based on the span being created. Rules are evaluated in
+// order and the Sampler of the first matching rule is used. If no rule
+// matches, the Fallback sampler is used.
+//
+// Rules are evaluated in eBPF when the span is started, using only the
+// attribute values captured by the instrumentation probes. At most 5 rules
+// can be configured.
+//
+// The samplers of rules and the Fallback sampler can only be an
+// [AlwaysOnSampler], [AlwaysOffSampler], [TraceIDRatioSampler],
+// [RateLimitingSampler], or [ProbabilitySampler].
+//
+// For example, to never sample health checks, always sample checkout
+// requests, and sample 5% of all other traces:
+//
+//	RuleBasedSampler{
+//		Rules: []SamplingRule{
+//			{HTTPMethod: "GET", HTTPPath: "/healthz", Sampler: AlwaysOffSampler{}},
+//			{HTTPPathPrefix: "/checkout", Sampler: AlwaysOnSampler{}},
+//		},
+//		Fallback: TraceIDRatioSampler{Fraction: 0.05},
+//	}
+//
+// To respect the parent trace's SampledFlag, the RuleBasedSampler should be
+// used as the Root of a [ParentBasedSampler].
+type RuleBasedSampler struct {
+	// Rules are the sampling rules evaluated in order.
+	Rules []SamplingRule
+	// Fallback is the Sampler used when no rule matches. If nil,
+	// [AlwaysOnSampler] is used.
+	Fallback Sampler
+}
+
+// SamplingRule is a rule of a [RuleBasedSampler].
+//
+// A rule matches a span if all its non-empty fields match the span. At most
+// one of HTTPPath, HTTPPathPrefix, RPCMethod, MessagingDestination, and
+// DBOperation can be set. A rule with no fields set matches all spans.
+//
+// Only the first 32 bytes of attribute values are captured by the probes.
+// Values matched exactly need to be shorter than that.
+type SamplingRule struct {
+	// HTTPMethod is the HTTP request method matched exactly (e.g. "GET").
+	HTTPMethod string
+	// HTTPPath is the HTTP request URL path matched exactly.
+	HTTPPath string
+	// HTTPPathPrefix is a prefix of the HTTP request URL path.
+	HTTPPathPrefix string
+	// RPCMethod is the full gRPC method name matched exactly (e.g.
+	// "/pkg.Service/Method").
+	RPCMethod string
+	// MessagingDestination is the Kafka topic matched exactly.
+	MessagingDestination string
+	// DBOperation is the database operation name (e.g. "SELECT") the query
+	// starts with. It is matched case-insensitively.
+	DBOperation string
+
+	// Sampler is the Sampler used when the rule matches.
+	Sampler Sampler
+}
+
+var _ Sampler = RuleBasedSampler{}
+
+func (r SamplingRule) rule() (sampling.Rule, error) {
+	out := sampling.Rule{Method: r.HTTPMethod}
+	var n int
+	set := func(kind sampling.AttributeKind, match sampling.MatchType, v string) {
+		if v == "" {
+			return
+		}
+		n++
+		out.Kind, out.Match, out.Value = kind, match, v
+	}
+	set(sampling.AttributeHTTPPath, sampling.MatchExact, r.HTTPPath)
+	set(sampling.AttributeHTTPPath, sampling.MatchPrefix, r.HTTPPathPrefix)
+	set(sampling.AttributeRPCMethod, sampling.MatchExact, r.RPCMethod)
+	set(sampling.AttributeMessagingDestination, sampling.MatchExact, r.MessagingDestination)
+	set(sampling.AttributeDBOperation, sampling.MatchWord, r.DBOperation)
+	if n > 1 {
+		return sampling.Rule{}, errors.New("sampling rule can only match one attribute")
+	}
+	return out, nil
+}
+
+func validateRuleBasedDelegate(s Sampler) error {
+	switch s.(type) {
+	case nil:
+		return errors.New("rule-based sampler delegate is nil")
+	case AlwaysOnSampler, AlwaysOffSampler, TraceIDRatioSampler, RateLimitingSampler, ProbabilitySampler:
+		return s.validate()
+	default:
+		return errors.New("rule-based sampler can only delegate to always-on, always-off, trace-ID-ratio, rate-limiting, or probability samplers")
+	}
+}
+
+func (r RuleBasedSampler) validate() error {
+	if len(r.Rules) > sampling.MaxRules {
+		return fmt.Errorf("rule-based sampler has too many rules: %d (max %d)", len(r.Rules), sampling.MaxRules)
+	}
+
+	var err error
+	for i, rule := range r.Rules {
+		if e := validateRuleBasedDelegate(rule.Sampler); e != nil {
+			err = errors.Join(err, fmt.Errorf("rule %d: %w", i, e))
+		}
+		if _, e := rule.rule(); e != nil {
+			err = errors.Join(err, fmt.Errorf("rule %d: %w", i, e))
+		}
+	}
+	if r.Fallback != nil {
+		err = errors.Join(err, validateRuleBasedDelegate(r.Fallback))
+	}
+	return err
+}
+
+func (r RuleBasedSampler) convert() (*sampling.Config, error) {
+	samplers := make(map[sampling.SamplerID]sampling.SamplerConfig)
+	// addDelegate adds the sampler s as the i-th delegate.
+	addDelegate := func(i int, s Sampler) (sampling.SamplerID, error) {
+		c, err := convertSamplerToConfig(s)
+		if err != nil {
+			return 0, err
+		}
+		id := sampling.RuleBasedDelegateID(i)
+		samplers[id] = c.Samplers[c.ActiveSampler]
+		return id, nil
+	}
+
+	rules := make([]sampling.Rule, len(r.Rules))
+	for i, rule := range r.Rules {
+		var err error
+		rules[i], err = rule.rule()
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+		rules[i].Delegate, err = addDelegate(i, rule.Sampler)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", i, err)
+		}
+	}
+
+	fallback := r.Fallback
+	if fallback == nil {
+		fallback = AlwaysOnSampler{}
+	}
+	fallbackID, err := addDelegate(len(r.Rules), fallback)
+	if err != nil {
+		return nil, fmt.Errorf("fallback: %w", err)
+	}
+
+	rbc, err := sampling.NewRuleBasedConfig(rules, fallbackID)
+	if err != nil {
+		return nil, err
+	}
+	samplers[sampling.RuleBasedID] = sampling.SamplerConfig{
+		SamplerType: sampling.SamplerRuleBased,
+		Config:      rbc,
+	}
+
+	return &sampling.Config{
+		Samplers:      samplers,
+		ActiveSampler: sampling.RuleBasedID,
+	}, nil
+}
+
 // DefaultSampler returns a ParentBased sampler with the following defaults:
 //   - Root: AlwaysOn
 //   - RemoteSampled: AlwaysOn
@@ -272,11 +535,53 @@ func newSamplerFromEnv(lookupEnv func(string) (string, bool)) (Sampler, error) {
 		}
 		defaultSampler.Root = TraceIDRatioSampler{Fraction: ratio}
 		return defaultSampler, nil
+	case samplerNameRateLimiting:
+		rate, err := parseSpansPerSecond(samplerArg, hasSamplerArg)
+		if err != nil {
+			return nil, err
+		}
+		return RateLimitingSampler{SpansPerSecond: rate}, nil
+	case samplerNameParentBasedRateLimiting:
+		rate, err := parseSpansPerSecond(samplerArg, hasSamplerArg)
+		if err != nil {
+			return nil, err
+		}
+		defaultSampler.Root = RateLimitingSampler{SpansPerSecond: rate}
+		return defaultSampler, nil
+	case samplerNameProbability:
+		if !hasSamplerArg {
+			return ProbabilitySampler{Probability: 1}, nil
+		}
+		p, err := strconv.ParseFloat(samplerArg, 64)
+		if err != nil {
+			return nil, err
+		}
+		return ProbabilitySampler{Probability: p}, nil
+	case samplerNameParentBasedProbability:
+		if !hasSamplerArg {
+			defaultSampler.Root = ProbabilitySampler{Probability: 1}
+			return defaultSampler, nil
+		}
+		p, err := strconv.ParseFloat(samplerArg, 64)
+		if err != nil {
+			return nil, err
+		}
+		defaultSampler.Root = ProbabilitySampler{Probability: p}
+		return defaultSampler, nil
 	default:
 		return nil, errors.New("unknown sampler name")
 	}
 }
 
+// parseSpansPerSecond parses the rate-limiting sampler argument. If no
+// argument is set, defaultSpansPerSecond is returned.
+func parseSpansPerSecond(arg string, ok bool) (float64, error) {
+	if !ok || arg == "" {
+		return defaultSpansPerSecond, nil
+	}
+	return strconv.ParseFloat(arg, 64)
+}
+
 // convertSamplerToConfig converts a Sampler its internal representation.
 func convertSamplerToConfig(s Sampler) (*sampling.Config, error) {
 	if s == nil {